@@ -2,8 +2,11 @@ package api
 
 import (
 	v1 "github.com/jcpribeiro/TransactionApp/api/v1"
+	v2 "github.com/jcpribeiro/TransactionApp/api/v2"
 	"github.com/jcpribeiro/TransactionApp/app"
 	"github.com/jcpribeiro/TransactionApp/internal/cache"
+	"github.com/jcpribeiro/TransactionApp/internal/ratelimit"
+	"github.com/jcpribeiro/TransactionApp/internal/tenant"
 
 	"github.com/labstack/echo/v4"
 	"github.com/sirupsen/logrus"
@@ -14,11 +17,18 @@ type Options struct {
 	Group *echo.Group
 	Apps  *app.Container
 	Cache cache.Cache
+	// CachePolicies of the endpoints, the missing ones use their default
+	CachePolicies map[string]cache.Policy
+	// Resolver resolves the account of the requests to the account scoped routes
+	Resolver tenant.Resolver
+	// RateLimit limits the requests of each client, nil disables it
+	RateLimit *ratelimit.Policy
 }
 
 // Register api instance
 func Register(opts Options) {
-	v1.Register(opts.Group, opts.Apps, opts.Cache)
+	v1.Register(opts.Group, opts.Apps, opts.Cache, opts.CachePolicies, opts.Resolver, opts.RateLimit)
+	v2.Register(opts.Group, opts.Apps, opts.Cache, opts.CachePolicies, opts.Resolver, opts.RateLimit)
 	// healthz.Register(opts.Root, opts.Apps)
 
 	logrus.Info("Registered API")
@@ -0,0 +1,126 @@
+package rpc
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+	"strings"
+
+	"github.com/jcpribeiro/TransactionApp/api/rpc/pb"
+	"github.com/jcpribeiro/TransactionApp/internal/auth"
+
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// Authenticate returns the principal of an API key or an authorization header, nil when they are missing or invalid.
+// It is satisfied by auth.Authenticator.Authenticate.
+type Authenticate func(ctx context.Context, key, header string) (*auth.Principal, error)
+
+// methodScopes are the scopes required by the account scoped methods.
+// The methods missing from it, such as health checking and reflection, are public.
+var methodScopes = map[string]string{
+	pb.TransactionService_InsertTransactions_FullMethodName:              auth.ScopeTransactionWrite,
+	pb.TransactionService_GetTransactions_FullMethodName:                 auth.ScopeTransactionRead,
+	pb.TransactionService_StreamTransactionsByPeriod_FullMethodName:      auth.ScopeTransactionRead,
+	pb.TransactionService_StreamTransactionsByPeriodEpoch_FullMethodName: auth.ScopeTransactionRead,
+	pb.TransactionService_GetTransactionsSummary_FullMethodName:          auth.ScopeTransactionRead,
+	pb.TransactionService_SearchTransactions_FullMethodName:              auth.ScopeTransactionRead,
+	pb.ConversionService_GetExchange_FullMethodName:                      auth.ScopeTransactionRead,
+}
+
+type principalKey struct{}
+
+// principalFrom returns the principal of the call, set by the interceptor on the account scoped methods
+func principalFrom(ctx context.Context) *auth.Principal {
+	principal, _ := ctx.Value(principalKey{}).(*auth.Principal)
+	return principal
+}
+
+// accountOf returns the account of the call
+func accountOf(ctx context.Context) string {
+	if principal := principalFrom(ctx); principal != nil {
+		return principal.AccountId
+	}
+
+	return ""
+}
+
+// interceptor authenticates the calls as the REST api does and recovers their panics
+type interceptor struct {
+	authenticate Authenticate
+	log          logrus.Logger
+}
+
+func (i *interceptor) unary(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
+	defer i.recover(info.FullMethod, &err)
+
+	ctx, err = i.authorize(ctx, info.FullMethod)
+	if err != nil {
+		return nil, err
+	}
+
+	return handler(ctx, req)
+}
+
+func (i *interceptor) stream(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+	defer i.recover(info.FullMethod, &err)
+
+	ctx, err := i.authorize(ss.Context(), info.FullMethod)
+	if err != nil {
+		return err
+	}
+
+	return handler(srv, &serverStream{ServerStream: ss, ctx: ctx})
+}
+
+// authorize stores the principal of an account scoped method in the context,
+// rejecting the calls without credentials or the scope of the method
+func (i *interceptor) authorize(ctx context.Context, method string) (context.Context, error) {
+	scope, ok := methodScopes[method]
+	if !ok {
+		return ctx, nil
+	}
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	principal, err := i.authenticate(ctx, first(md, strings.ToLower(auth.HeaderAPIKey)), first(md, "authorization"))
+	if err != nil {
+		return nil, statusOf(err)
+	}
+	if principal == nil || len(principal.AccountId) == 0 {
+		return nil, status.Error(codes.Unauthenticated, "unauthorized")
+	}
+	if !principal.HasScope(scope) {
+		return nil, status.Error(codes.PermissionDenied, "missing scope "+scope)
+	}
+
+	return context.WithValue(ctx, principalKey{}, principal), nil
+}
+
+func (i *interceptor) recover(method string, err *error) {
+	if r := recover(); r != nil {
+		i.log.Error(fmt.Sprintf("panic in %s: %v\n%s", method, r, debug.Stack()))
+		*err = status.Error(codes.Internal, "internal error")
+	}
+}
+
+func first(md metadata.MD, key string) string {
+	if values := md.Get(key); len(values) > 0 {
+		return values[0]
+	}
+
+	return ""
+}
+
+// serverStream carries the context of the interceptor to the stream handler
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
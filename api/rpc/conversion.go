@@ -0,0 +1,41 @@
+package rpc
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jcpribeiro/TransactionApp/api/rpc/pb"
+	"github.com/jcpribeiro/TransactionApp/app"
+	"github.com/jcpribeiro/TransactionApp/app/fiscaldata"
+	"github.com/jcpribeiro/TransactionApp/internal/util"
+	"github.com/jcpribeiro/TransactionApp/model"
+)
+
+type conversionServer struct {
+	pb.UnimplementedConversionServiceServer
+	apps *app.Container
+}
+
+func (s *conversionServer) GetExchange(ctx context.Context, req *pb.GetExchangeRequest) (*pb.Exchange, error) {
+	if len(req.Currency) == 0 {
+		return nil, statusOf(fmt.Errorf("%w: currency is required", errInvalidRequest))
+	}
+	if _, err := time.Parse(model.DateLayout, req.Date); err != nil {
+		return nil, statusOf(fmt.Errorf("%w: date must be formatted as YYYY-MM-DD, got %q", errInvalidRequest, req.Date))
+	}
+
+	exchange, err := fiscaldata.Triangulate(s.apps.FiscalData, req.SourceCurrency, req.Currency, req.Date)
+	if err != nil {
+		return nil, statusOf(err)
+	}
+
+	conversion := exchange.Conversion(0)
+	return &pb.Exchange{
+		Rate:               util.RoundFloat(exchange.Rate(), 2),
+		ExchangeRate:       conversion.ExchangeRate,
+		RecordDate:         conversion.RecordDate,
+		SourceExchangeRate: conversion.SourceExchangeRate,
+		SourceRecordDate:   conversion.SourceRecordDate,
+	}, nil
+}
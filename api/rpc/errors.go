@@ -0,0 +1,52 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jcpribeiro/TransactionApp/app/fiscaldata"
+	"github.com/jcpribeiro/TransactionApp/app/transaction"
+
+	"github.com/sirupsen/logrus"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// errorDomain is the domain of the ErrorInfo detail naming a broken business rule
+const errorDomain = "transactionapp"
+
+// errInvalidRequest is returned when a request fails the validation of its REST params
+var errInvalidRequest = errors.New("invalid request")
+
+// statusOf maps the errors the REST api answers with 400 to InvalidArgument, a broken business
+// rule carries an ErrorInfo whose reason is the rule. Unexpected errors are logged and hidden.
+func statusOf(err error) error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+
+	var ruleErr *transaction.RuleError
+	switch {
+	case errors.As(err, &ruleErr):
+		st := status.New(codes.InvalidArgument, err.Error())
+		if detailed, detailErr := st.WithDetails(&errdetails.ErrorInfo{Reason: ruleErr.Rule, Domain: errorDomain}); detailErr == nil {
+			st = detailed
+		}
+		return st.Err()
+	case errors.Is(err, transaction.ErrInvalidTransaction), errors.Is(err, transaction.ErrInvalidFilter), errors.Is(err, errInvalidRequest):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, fiscaldata.ErrRateNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, context.Canceled):
+		return status.Error(codes.Canceled, err.Error())
+	case errors.Is(err, context.DeadlineExceeded):
+		return status.Error(codes.DeadlineExceeded, err.Error())
+	default:
+		logrus.Error(err)
+		return status.Error(codes.Internal, "internal error")
+	}
+}
@@ -0,0 +1,453 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             (unknown)
+// source: transactionapp/v1/transaction.proto
+
+package pb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	TransactionService_InsertTransactions_FullMethodName              = "/transactionapp.v1.TransactionService/InsertTransactions"
+	TransactionService_GetTransactions_FullMethodName                 = "/transactionapp.v1.TransactionService/GetTransactions"
+	TransactionService_StreamTransactionsByPeriod_FullMethodName      = "/transactionapp.v1.TransactionService/StreamTransactionsByPeriod"
+	TransactionService_StreamTransactionsByPeriodEpoch_FullMethodName = "/transactionapp.v1.TransactionService/StreamTransactionsByPeriodEpoch"
+	TransactionService_GetTransactionsSummary_FullMethodName          = "/transactionapp.v1.TransactionService/GetTransactionsSummary"
+	TransactionService_SearchTransactions_FullMethodName              = "/transactionapp.v1.TransactionService/SearchTransactions"
+)
+
+// TransactionServiceClient is the client API for TransactionService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type TransactionServiceClient interface {
+	// InsertTransactions stores the transactions, all or none. The source_currency defaults to USD.
+	InsertTransactions(ctx context.Context, in *InsertTransactionsRequest, opts ...grpc.CallOption) (*InsertTransactionsResponse, error)
+	// GetTransactions returns the transactions in the order of the ids, converted to each currency
+	GetTransactions(ctx context.Context, in *GetTransactionsRequest, opts ...grpc.CallOption) (*GetTransactionsResponse, error)
+	// StreamTransactionsByPeriod sends the transactions of a period, both dates included
+	StreamTransactionsByPeriod(ctx context.Context, in *StreamTransactionsByPeriodRequest, opts ...grpc.CallOption) (TransactionService_StreamTransactionsByPeriodClient, error)
+	// StreamTransactionsByPeriodEpoch sends the transactions of a period in unix seconds, both included
+	StreamTransactionsByPeriodEpoch(ctx context.Context, in *StreamTransactionsByPeriodEpochRequest, opts ...grpc.CallOption) (TransactionService_StreamTransactionsByPeriodEpochClient, error)
+	// GetTransactionsSummary returns the purchase amount totals of a period
+	GetTransactionsSummary(ctx context.Context, in *GetTransactionsSummaryRequest, opts ...grpc.CallOption) (*GetTransactionsSummaryResponse, error)
+	// SearchTransactions returns a page of the transactions matching the filters
+	SearchTransactions(ctx context.Context, in *SearchTransactionsRequest, opts ...grpc.CallOption) (*SearchTransactionsResponse, error)
+}
+
+type transactionServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewTransactionServiceClient(cc grpc.ClientConnInterface) TransactionServiceClient {
+	return &transactionServiceClient{cc}
+}
+
+func (c *transactionServiceClient) InsertTransactions(ctx context.Context, in *InsertTransactionsRequest, opts ...grpc.CallOption) (*InsertTransactionsResponse, error) {
+	out := new(InsertTransactionsResponse)
+	err := c.cc.Invoke(ctx, TransactionService_InsertTransactions_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *transactionServiceClient) GetTransactions(ctx context.Context, in *GetTransactionsRequest, opts ...grpc.CallOption) (*GetTransactionsResponse, error) {
+	out := new(GetTransactionsResponse)
+	err := c.cc.Invoke(ctx, TransactionService_GetTransactions_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *transactionServiceClient) StreamTransactionsByPeriod(ctx context.Context, in *StreamTransactionsByPeriodRequest, opts ...grpc.CallOption) (TransactionService_StreamTransactionsByPeriodClient, error) {
+	stream, err := c.cc.NewStream(ctx, &TransactionService_ServiceDesc.Streams[0], TransactionService_StreamTransactionsByPeriod_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &transactionServiceStreamTransactionsByPeriodClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type TransactionService_StreamTransactionsByPeriodClient interface {
+	Recv() (*Transaction, error)
+	grpc.ClientStream
+}
+
+type transactionServiceStreamTransactionsByPeriodClient struct {
+	grpc.ClientStream
+}
+
+func (x *transactionServiceStreamTransactionsByPeriodClient) Recv() (*Transaction, error) {
+	m := new(Transaction)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *transactionServiceClient) StreamTransactionsByPeriodEpoch(ctx context.Context, in *StreamTransactionsByPeriodEpochRequest, opts ...grpc.CallOption) (TransactionService_StreamTransactionsByPeriodEpochClient, error) {
+	stream, err := c.cc.NewStream(ctx, &TransactionService_ServiceDesc.Streams[1], TransactionService_StreamTransactionsByPeriodEpoch_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &transactionServiceStreamTransactionsByPeriodEpochClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type TransactionService_StreamTransactionsByPeriodEpochClient interface {
+	Recv() (*Transaction, error)
+	grpc.ClientStream
+}
+
+type transactionServiceStreamTransactionsByPeriodEpochClient struct {
+	grpc.ClientStream
+}
+
+func (x *transactionServiceStreamTransactionsByPeriodEpochClient) Recv() (*Transaction, error) {
+	m := new(Transaction)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *transactionServiceClient) GetTransactionsSummary(ctx context.Context, in *GetTransactionsSummaryRequest, opts ...grpc.CallOption) (*GetTransactionsSummaryResponse, error) {
+	out := new(GetTransactionsSummaryResponse)
+	err := c.cc.Invoke(ctx, TransactionService_GetTransactionsSummary_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *transactionServiceClient) SearchTransactions(ctx context.Context, in *SearchTransactionsRequest, opts ...grpc.CallOption) (*SearchTransactionsResponse, error) {
+	out := new(SearchTransactionsResponse)
+	err := c.cc.Invoke(ctx, TransactionService_SearchTransactions_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// TransactionServiceServer is the server API for TransactionService service.
+// All implementations must embed UnimplementedTransactionServiceServer
+// for forward compatibility
+type TransactionServiceServer interface {
+	// InsertTransactions stores the transactions, all or none. The source_currency defaults to USD.
+	InsertTransactions(context.Context, *InsertTransactionsRequest) (*InsertTransactionsResponse, error)
+	// GetTransactions returns the transactions in the order of the ids, converted to each currency
+	GetTransactions(context.Context, *GetTransactionsRequest) (*GetTransactionsResponse, error)
+	// StreamTransactionsByPeriod sends the transactions of a period, both dates included
+	StreamTransactionsByPeriod(*StreamTransactionsByPeriodRequest, TransactionService_StreamTransactionsByPeriodServer) error
+	// StreamTransactionsByPeriodEpoch sends the transactions of a period in unix seconds, both included
+	StreamTransactionsByPeriodEpoch(*StreamTransactionsByPeriodEpochRequest, TransactionService_StreamTransactionsByPeriodEpochServer) error
+	// GetTransactionsSummary returns the purchase amount totals of a period
+	GetTransactionsSummary(context.Context, *GetTransactionsSummaryRequest) (*GetTransactionsSummaryResponse, error)
+	// SearchTransactions returns a page of the transactions matching the filters
+	SearchTransactions(context.Context, *SearchTransactionsRequest) (*SearchTransactionsResponse, error)
+	mustEmbedUnimplementedTransactionServiceServer()
+}
+
+// UnimplementedTransactionServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedTransactionServiceServer struct {
+}
+
+func (UnimplementedTransactionServiceServer) InsertTransactions(context.Context, *InsertTransactionsRequest) (*InsertTransactionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method InsertTransactions not implemented")
+}
+func (UnimplementedTransactionServiceServer) GetTransactions(context.Context, *GetTransactionsRequest) (*GetTransactionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTransactions not implemented")
+}
+func (UnimplementedTransactionServiceServer) StreamTransactionsByPeriod(*StreamTransactionsByPeriodRequest, TransactionService_StreamTransactionsByPeriodServer) error {
+	return status.Errorf(codes.Unimplemented, "method StreamTransactionsByPeriod not implemented")
+}
+func (UnimplementedTransactionServiceServer) StreamTransactionsByPeriodEpoch(*StreamTransactionsByPeriodEpochRequest, TransactionService_StreamTransactionsByPeriodEpochServer) error {
+	return status.Errorf(codes.Unimplemented, "method StreamTransactionsByPeriodEpoch not implemented")
+}
+func (UnimplementedTransactionServiceServer) GetTransactionsSummary(context.Context, *GetTransactionsSummaryRequest) (*GetTransactionsSummaryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTransactionsSummary not implemented")
+}
+func (UnimplementedTransactionServiceServer) SearchTransactions(context.Context, *SearchTransactionsRequest) (*SearchTransactionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SearchTransactions not implemented")
+}
+func (UnimplementedTransactionServiceServer) mustEmbedUnimplementedTransactionServiceServer() {}
+
+// UnsafeTransactionServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to TransactionServiceServer will
+// result in compilation errors.
+type UnsafeTransactionServiceServer interface {
+	mustEmbedUnimplementedTransactionServiceServer()
+}
+
+func RegisterTransactionServiceServer(s grpc.ServiceRegistrar, srv TransactionServiceServer) {
+	s.RegisterService(&TransactionService_ServiceDesc, srv)
+}
+
+func _TransactionService_InsertTransactions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(InsertTransactionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TransactionServiceServer).InsertTransactions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TransactionService_InsertTransactions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TransactionServiceServer).InsertTransactions(ctx, req.(*InsertTransactionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TransactionService_GetTransactions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTransactionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TransactionServiceServer).GetTransactions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TransactionService_GetTransactions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TransactionServiceServer).GetTransactions(ctx, req.(*GetTransactionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TransactionService_StreamTransactionsByPeriod_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StreamTransactionsByPeriodRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(TransactionServiceServer).StreamTransactionsByPeriod(m, &transactionServiceStreamTransactionsByPeriodServer{stream})
+}
+
+type TransactionService_StreamTransactionsByPeriodServer interface {
+	Send(*Transaction) error
+	grpc.ServerStream
+}
+
+type transactionServiceStreamTransactionsByPeriodServer struct {
+	grpc.ServerStream
+}
+
+func (x *transactionServiceStreamTransactionsByPeriodServer) Send(m *Transaction) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _TransactionService_StreamTransactionsByPeriodEpoch_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StreamTransactionsByPeriodEpochRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(TransactionServiceServer).StreamTransactionsByPeriodEpoch(m, &transactionServiceStreamTransactionsByPeriodEpochServer{stream})
+}
+
+type TransactionService_StreamTransactionsByPeriodEpochServer interface {
+	Send(*Transaction) error
+	grpc.ServerStream
+}
+
+type transactionServiceStreamTransactionsByPeriodEpochServer struct {
+	grpc.ServerStream
+}
+
+func (x *transactionServiceStreamTransactionsByPeriodEpochServer) Send(m *Transaction) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _TransactionService_GetTransactionsSummary_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTransactionsSummaryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TransactionServiceServer).GetTransactionsSummary(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TransactionService_GetTransactionsSummary_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TransactionServiceServer).GetTransactionsSummary(ctx, req.(*GetTransactionsSummaryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TransactionService_SearchTransactions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SearchTransactionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TransactionServiceServer).SearchTransactions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TransactionService_SearchTransactions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TransactionServiceServer).SearchTransactions(ctx, req.(*SearchTransactionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// TransactionService_ServiceDesc is the grpc.ServiceDesc for TransactionService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var TransactionService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "transactionapp.v1.TransactionService",
+	HandlerType: (*TransactionServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "InsertTransactions",
+			Handler:    _TransactionService_InsertTransactions_Handler,
+		},
+		{
+			MethodName: "GetTransactions",
+			Handler:    _TransactionService_GetTransactions_Handler,
+		},
+		{
+			MethodName: "GetTransactionsSummary",
+			Handler:    _TransactionService_GetTransactionsSummary_Handler,
+		},
+		{
+			MethodName: "SearchTransactions",
+			Handler:    _TransactionService_SearchTransactions_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamTransactionsByPeriod",
+			Handler:       _TransactionService_StreamTransactionsByPeriod_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "StreamTransactionsByPeriodEpoch",
+			Handler:       _TransactionService_StreamTransactionsByPeriodEpoch_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "transactionapp/v1/transaction.proto",
+}
+
+const (
+	ConversionService_GetExchange_FullMethodName = "/transactionapp.v1.ConversionService/GetExchange"
+)
+
+// ConversionServiceClient is the client API for ConversionService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type ConversionServiceClient interface {
+	// GetExchange returns both legs of a conversion on a date, triangulated through USD
+	GetExchange(ctx context.Context, in *GetExchangeRequest, opts ...grpc.CallOption) (*Exchange, error)
+}
+
+type conversionServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewConversionServiceClient(cc grpc.ClientConnInterface) ConversionServiceClient {
+	return &conversionServiceClient{cc}
+}
+
+func (c *conversionServiceClient) GetExchange(ctx context.Context, in *GetExchangeRequest, opts ...grpc.CallOption) (*Exchange, error) {
+	out := new(Exchange)
+	err := c.cc.Invoke(ctx, ConversionService_GetExchange_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ConversionServiceServer is the server API for ConversionService service.
+// All implementations must embed UnimplementedConversionServiceServer
+// for forward compatibility
+type ConversionServiceServer interface {
+	// GetExchange returns both legs of a conversion on a date, triangulated through USD
+	GetExchange(context.Context, *GetExchangeRequest) (*Exchange, error)
+	mustEmbedUnimplementedConversionServiceServer()
+}
+
+// UnimplementedConversionServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedConversionServiceServer struct {
+}
+
+func (UnimplementedConversionServiceServer) GetExchange(context.Context, *GetExchangeRequest) (*Exchange, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetExchange not implemented")
+}
+func (UnimplementedConversionServiceServer) mustEmbedUnimplementedConversionServiceServer() {}
+
+// UnsafeConversionServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ConversionServiceServer will
+// result in compilation errors.
+type UnsafeConversionServiceServer interface {
+	mustEmbedUnimplementedConversionServiceServer()
+}
+
+func RegisterConversionServiceServer(s grpc.ServiceRegistrar, srv ConversionServiceServer) {
+	s.RegisterService(&ConversionService_ServiceDesc, srv)
+}
+
+func _ConversionService_GetExchange_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetExchangeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ConversionServiceServer).GetExchange(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ConversionService_GetExchange_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ConversionServiceServer).GetExchange(ctx, req.(*GetExchangeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// ConversionService_ServiceDesc is the grpc.ServiceDesc for ConversionService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var ConversionService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "transactionapp.v1.ConversionService",
+	HandlerType: (*ConversionServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetExchange",
+			Handler:    _ConversionService_GetExchange_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "transactionapp/v1/transaction.proto",
+}
@@ -0,0 +1,74 @@
+package rpc
+
+import (
+	"net"
+
+	"github.com/jcpribeiro/TransactionApp/api/rpc/pb"
+	"github.com/jcpribeiro/TransactionApp/app"
+	"github.com/jcpribeiro/TransactionApp/internal/cache"
+
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+)
+
+// Options struct for creating the gRPC server
+type Options struct {
+	Apps  *app.Container
+	Cache cache.Cache
+	// Authenticate returns the principal of the x-api-key or authorization metadata
+	Authenticate Authenticate
+	Log          logrus.Logger
+}
+
+// Server serves the transaction and conversion services with health checking and reflection
+type Server struct {
+	grpc   *grpc.Server
+	health *health.Server
+}
+
+// NewServer registers the services on a new gRPC server
+func NewServer(opts Options) *Server {
+	interceptor := &interceptor{
+		authenticate: opts.Authenticate,
+		log:          opts.Log,
+	}
+
+	s := &Server{
+		grpc: grpc.NewServer(
+			grpc.ChainUnaryInterceptor(interceptor.unary),
+			grpc.ChainStreamInterceptor(interceptor.stream),
+		),
+		health: health.NewServer(),
+	}
+
+	pb.RegisterTransactionServiceServer(s.grpc, &transactionServer{
+		apps:  opts.Apps,
+		cache: opts.Cache,
+	})
+	pb.RegisterConversionServiceServer(s.grpc, &conversionServer{
+		apps: opts.Apps,
+	})
+	healthpb.RegisterHealthServer(s.grpc, s.health)
+	reflection.Register(s.grpc)
+
+	for service := range s.grpc.GetServiceInfo() {
+		s.health.SetServingStatus(service, healthpb.HealthCheckResponse_SERVING)
+	}
+
+	opts.Log.Info("Registered gRPC API")
+	return s
+}
+
+// Serve accepts the connections of the listener until Stop
+func (s *Server) Serve(listener net.Listener) error {
+	return s.grpc.Serve(listener)
+}
+
+// Stop reports the services as not serving and waits for the pending calls
+func (s *Server) Stop() {
+	s.health.Shutdown()
+	s.grpc.GracefulStop()
+}
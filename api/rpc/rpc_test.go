@@ -0,0 +1,378 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"testing"
+
+	"github.com/jcpribeiro/TransactionApp/api/rpc/pb"
+	"github.com/jcpribeiro/TransactionApp/app"
+	"github.com/jcpribeiro/TransactionApp/app/fiscaldata"
+	"github.com/jcpribeiro/TransactionApp/app/transaction"
+	"github.com/jcpribeiro/TransactionApp/internal/auth"
+	"github.com/jcpribeiro/TransactionApp/internal/cache"
+	"github.com/jcpribeiro/TransactionApp/model"
+
+	"github.com/golang/mock/gomock"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+const (
+	testAccountId = "652d34910a8fc425116b84aa"
+	testKey       = "test-key"
+	testReadKey   = "test-read-key"
+)
+
+type strucTest struct {
+	transactionApp *transaction.MockApp
+	fiscalDataApp  *fiscaldata.MockApp
+	cache          *cache.MockCache
+	conn           *grpc.ClientConn
+}
+
+// testAuthenticate grants every scope to testKey and the read scope to testReadKey
+func testAuthenticate(ctx context.Context, key, header string) (*auth.Principal, error) {
+	switch key {
+	case testKey:
+		return &auth.Principal{AccountId: testAccountId, Scopes: []string{auth.ScopeTransactionRead, auth.ScopeTransactionWrite}}, nil
+	case testReadKey:
+		return &auth.Principal{AccountId: testAccountId, Scopes: []string{auth.ScopeTransactionRead}}, nil
+	default:
+		return nil, nil
+	}
+}
+
+func setUpTest(t *testing.T) strucTest {
+	ctrl := gomock.NewController(t)
+	testObj := strucTest{
+		transactionApp: transaction.NewMockApp(ctrl),
+		fiscalDataApp:  fiscaldata.NewMockApp(ctrl),
+		cache:          cache.NewMockCache(ctrl),
+	}
+
+	server := NewServer(Options{
+		Apps: &app.Container{
+			Transaction: testObj.transactionApp,
+			FiscalData:  testObj.fiscalDataApp,
+		},
+		Cache:        testObj.cache,
+		Authenticate: testAuthenticate,
+		Log:          *logrus.New(),
+	})
+
+	listener := bufconn.Listen(1024 * 1024)
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	testObj.conn = conn
+
+	return testObj
+}
+
+func withKey(key string) context.Context {
+	return metadata.AppendToOutgoingContext(context.Background(), "x-api-key", key)
+}
+
+func TestAuthorize(t *testing.T) {
+	t.Run("this test simulate a call without credentials", func(t *testing.T) {
+		testObj := setUpTest(t)
+
+		_, err := pb.NewTransactionServiceClient(testObj.conn).GetTransactions(context.Background(), &pb.GetTransactionsRequest{})
+
+		assert.Equal(t, status.Code(err), codes.Unauthenticated)
+	})
+
+	t.Run("this test simulate an insert with a read only key", func(t *testing.T) {
+		testObj := setUpTest(t)
+
+		_, err := pb.NewTransactionServiceClient(testObj.conn).InsertTransactions(withKey(testReadKey), &pb.InsertTransactionsRequest{})
+
+		assert.Equal(t, status.Code(err), codes.PermissionDenied)
+	})
+
+	t.Run("this test simulate a health check without credentials", func(t *testing.T) {
+		testObj := setUpTest(t)
+
+		response, err := healthpb.NewHealthClient(testObj.conn).Check(context.Background(), &healthpb.HealthCheckRequest{
+			Service: pb.TransactionService_ServiceDesc.ServiceName,
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, response.Status, healthpb.HealthCheckResponse_SERVING)
+	})
+
+	t.Run("this test simulate every account scoped method requiring a scope", func(t *testing.T) {
+		for _, desc := range []grpc.ServiceDesc{pb.TransactionService_ServiceDesc, pb.ConversionService_ServiceDesc} {
+			for _, method := range desc.Methods {
+				assert.Contains(t, methodScopes, fmt.Sprintf("/%s/%s", desc.ServiceName, method.MethodName))
+			}
+			for _, stream := range desc.Streams {
+				assert.Contains(t, methodScopes, fmt.Sprintf("/%s/%s", desc.ServiceName, stream.StreamName))
+			}
+		}
+	})
+}
+
+func TestInsertTransactions(t *testing.T) {
+	t.Run("this test simulate a successful transactions insert", func(t *testing.T) {
+		testObj := setUpTest(t)
+		testObj.transactionApp.EXPECT().InsertTransactions(gomock.Any(), testAccountId, []*model.Transaction{{
+			PurchaseAmount: 23.70,
+			Description:    "Test",
+			PurchaseDate:   "2023-10-15",
+			Tags:           []string{"work"},
+		}}).Return([]string{"652d34910a8fc425116b84d9"}, nil)
+		testObj.cache.EXPECT().Incr(gomock.Any(), testAccountId+":period-generation").Return(int64(4), nil)
+
+		response, err := pb.NewTransactionServiceClient(testObj.conn).InsertTransactions(withKey(testKey), &pb.InsertTransactionsRequest{
+			Transactions: []*pb.NewTransaction{{
+				PurchaseAmount: 23.70,
+				Description:    "Test",
+				PurchaseDate:   "2023-10-15",
+				Tags:           []string{"work"},
+			}},
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, response.Ids, []string{"652d34910a8fc425116b84d9"})
+	})
+
+	t.Run("this test simulate a transactions insert breaking a business rule", func(t *testing.T) {
+		testObj := setUpTest(t)
+		testObj.transactionApp.EXPECT().InsertTransactions(gomock.Any(), testAccountId, gomock.Any()).Return(nil, fmt.Errorf("%s 0: %w", transaction.ErrInvalidTransaction, &transaction.RuleError{
+			Rule:   transaction.RuleDateWindow,
+			Reason: "purchase_date 3023-01-01 is after 2023-10-16",
+		}))
+
+		_, err := pb.NewTransactionServiceClient(testObj.conn).InsertTransactions(withKey(testKey), &pb.InsertTransactionsRequest{
+			Transactions: []*pb.NewTransaction{{PurchaseAmount: 23.70, Description: "Test", PurchaseDate: "3023-01-01"}},
+		})
+
+		st := status.Convert(err)
+		assert.Equal(t, st.Code(), codes.InvalidArgument)
+		assert.Len(t, st.Details(), 1)
+		assert.Equal(t, st.Details()[0].(*errdetails.ErrorInfo).Reason, transaction.RuleDateWindow)
+	})
+
+	t.Run("this test simulate an insert without transactions", func(t *testing.T) {
+		testObj := setUpTest(t)
+
+		_, err := pb.NewTransactionServiceClient(testObj.conn).InsertTransactions(withKey(testKey), &pb.InsertTransactionsRequest{})
+
+		assert.Equal(t, status.Code(err), codes.InvalidArgument)
+	})
+
+	t.Run("this test simulate an error during a transactions insert", func(t *testing.T) {
+		testObj := setUpTest(t)
+		testObj.transactionApp.EXPECT().InsertTransactions(gomock.Any(), testAccountId, gomock.Any()).Return(nil, errors.New("an error has ocurred"))
+
+		_, err := pb.NewTransactionServiceClient(testObj.conn).InsertTransactions(withKey(testKey), &pb.InsertTransactionsRequest{
+			Transactions: []*pb.NewTransaction{{PurchaseAmount: 23.70, Description: "Test"}},
+		})
+
+		assert.Equal(t, status.Code(err), codes.Internal)
+	})
+}
+
+func TestGetTransactions(t *testing.T) {
+	t.Run("this test simulate transactions converted in the order of the ids", func(t *testing.T) {
+		testObj := setUpTest(t)
+		testObj.transactionApp.EXPECT().GetTransactions(gomock.Any(), testAccountId, []string{"652d34910a8fc425116b84d9", "652d34910a8fc425116b84d8"}).Return([]*model.TransactionResponse{
+			{Id: "652d34910a8fc425116b84d8", PurchaseAmount: 10, Description: "Test2", PurchaseDate: "2023-10-14"},
+			{Id: "652d34910a8fc425116b84d9", PurchaseAmount: 23.70, Description: "Test1", PurchaseDate: "2023-10-15"},
+		}, nil)
+		testObj.fiscalDataApp.EXPECT().GetRatesOfExchange("Brazil-Real", gomock.Any()).Return(&fiscaldata.Data{
+			CurrencyDescription: "Brazil-Real",
+			ExchangeRate:        5,
+			RecordDate:          "2023-09-30",
+		}, nil).Times(2)
+
+		response, err := pb.NewTransactionServiceClient(testObj.conn).GetTransactions(withKey(testKey), &pb.GetTransactionsRequest{
+			Ids:        []string{"652d34910a8fc425116b84d9", "invalid", "652d34910a8fc425116b84d8"},
+			Currencies: []string{"Brazil-Real"},
+		})
+
+		assert.NoError(t, err)
+		assert.Len(t, response.Transactions, 2)
+		assert.Equal(t, response.Transactions[0].Id, "652d34910a8fc425116b84d9")
+		assert.Equal(t, response.Transactions[0].SourceCurrency, model.CurrencyUSD)
+		assert.Equal(t, response.Transactions[0].Conversions["Brazil-Real"].ConvertedPurchaseAmount, 118.5)
+		assert.Equal(t, response.NotFound, []string{"invalid"})
+	})
+
+	t.Run("this test simulate a request without currencies", func(t *testing.T) {
+		testObj := setUpTest(t)
+
+		_, err := pb.NewTransactionServiceClient(testObj.conn).GetTransactions(withKey(testKey), &pb.GetTransactionsRequest{
+			Ids: []string{"652d34910a8fc425116b84d9"},
+		})
+
+		assert.Equal(t, status.Code(err), codes.InvalidArgument)
+	})
+}
+
+func TestStreamTransactionsByPeriod(t *testing.T) {
+	t.Run("this test simulate a period streamed with a missing rate", func(t *testing.T) {
+		testObj := setUpTest(t)
+		testObj.transactionApp.EXPECT().GetTransactionsByPeriod(gomock.Any(), testAccountId, "2023-10-01", "2023-10-31", "America/Sao_Paulo", "", &model.AttributeParams{Category: "food"}).Return([]*model.TransactionResponse{
+			{Id: "652d34910a8fc425116b84d9", PurchaseAmount: 23.70, Description: "Test1", PurchaseDate: "2023-10-15"},
+			{Id: "652d34910a8fc425116b84d8", PurchaseAmount: 10, Description: "Test2", PurchaseDate: "2023-10-14"},
+		}, nil)
+		testObj.fiscalDataApp.EXPECT().GetRatesOfExchange("Brazil-Real", "2023-10-15").Return(&fiscaldata.Data{
+			CurrencyDescription: "Brazil-Real",
+			ExchangeRate:        5,
+			RecordDate:          "2023-09-30",
+		}, nil)
+		testObj.fiscalDataApp.EXPECT().GetRatesOfExchange("Brazil-Real", "2023-10-14").Return(nil, nil)
+
+		stream, err := pb.NewTransactionServiceClient(testObj.conn).StreamTransactionsByPeriod(withKey(testKey), &pb.StreamTransactionsByPeriodRequest{
+			StartDate:  "2023-10-01",
+			EndDate:    "2023-10-31",
+			Timezone:   "America/Sao_Paulo",
+			Currency:   "Brazil-Real",
+			Attributes: &pb.Attributes{Category: "food"},
+		})
+		assert.NoError(t, err)
+
+		var received []*pb.Transaction
+		for {
+			transaction, err := stream.Recv()
+			if err == io.EOF {
+				break
+			}
+			assert.NoError(t, err)
+			received = append(received, transaction)
+		}
+
+		assert.Len(t, received, 2)
+		assert.Equal(t, received[0].Conversions["Brazil-Real"].ConvertedPurchaseAmount, 118.5)
+		assert.Equal(t, received[1].Conversions["Brazil-Real"].Error, "failed to get rates exchange")
+	})
+
+	t.Run("this test simulate a period with an invalid timezone", func(t *testing.T) {
+		testObj := setUpTest(t)
+		testObj.transactionApp.EXPECT().GetTransactionsByPeriod(gomock.Any(), testAccountId, "2023-10-01", "2023-10-31", "Mars/Olympus", "", gomock.Any()).Return(nil, fmt.Errorf("%w: unknown timezone", transaction.ErrInvalidFilter))
+
+		stream, err := pb.NewTransactionServiceClient(testObj.conn).StreamTransactionsByPeriod(withKey(testKey), &pb.StreamTransactionsByPeriodRequest{
+			StartDate: "2023-10-01",
+			EndDate:   "2023-10-31",
+			Timezone:  "Mars/Olympus",
+			Currency:  "Brazil-Real",
+		})
+		assert.NoError(t, err)
+
+		_, err = stream.Recv()
+		assert.Equal(t, status.Code(err), codes.InvalidArgument)
+	})
+
+	t.Run("this test simulate an epoch period without a currency", func(t *testing.T) {
+		testObj := setUpTest(t)
+
+		stream, err := pb.NewTransactionServiceClient(testObj.conn).StreamTransactionsByPeriodEpoch(withKey(testKey), &pb.StreamTransactionsByPeriodEpochRequest{
+			StartDate: 1697150153,
+			EndDate:   1697409353,
+		})
+		assert.NoError(t, err)
+
+		_, err = stream.Recv()
+		assert.Equal(t, status.Code(err), codes.InvalidArgument)
+	})
+}
+
+func TestGetTransactionsSummary(t *testing.T) {
+	t.Run("this test simulate a summary converted to the currency", func(t *testing.T) {
+		testObj := setUpTest(t)
+		testObj.transactionApp.EXPECT().GetTransactionsSummary(gomock.Any(), testAccountId, "2023-10-01", "2023-10-31", "", "", "month").Return([]*model.TransactionAggregate{
+			{Period: "2023-10", PurchaseDate: "2023-10-15", Count: 2, Sum: 30, Min: 10, Max: 20},
+		}, nil)
+		testObj.fiscalDataApp.EXPECT().GetRatesOfExchange("Brazil-Real", "2023-10-15").Return(&fiscaldata.Data{
+			CurrencyDescription: "Brazil-Real",
+			ExchangeRate:        5,
+			RecordDate:          "2023-09-30",
+		}, nil)
+
+		response, err := pb.NewTransactionServiceClient(testObj.conn).GetTransactionsSummary(withKey(testKey), &pb.GetTransactionsSummaryRequest{
+			StartDate: "2023-10-01",
+			EndDate:   "2023-10-31",
+			Currency:  "Brazil-Real",
+			GroupBy:   "month",
+		})
+
+		assert.NoError(t, err)
+		assert.Len(t, response.Summary, 1)
+		assert.Equal(t, response.Summary[0].Usd.Sum, 30.0)
+		assert.Equal(t, response.Summary[0].Converted.Average, 75.0)
+	})
+}
+
+func TestGetExchange(t *testing.T) {
+	t.Run("this test simulate an exchange triangulated through USD", func(t *testing.T) {
+		testObj := setUpTest(t)
+		testObj.fiscalDataApp.EXPECT().GetRatesOfExchange("Euro Zone-Euro", "2023-10-15").Return(&fiscaldata.Data{
+			CurrencyDescription: "Euro Zone-Euro",
+			ExchangeRate:        0.8,
+			RecordDate:          "2023-09-30",
+		}, nil)
+		testObj.fiscalDataApp.EXPECT().GetRatesOfExchange("Canada-Dollar", "2023-10-15").Return(&fiscaldata.Data{
+			CurrencyDescription: "Canada-Dollar",
+			ExchangeRate:        1.5,
+			RecordDate:          "2023-09-29",
+		}, nil)
+
+		response, err := pb.NewConversionServiceClient(testObj.conn).GetExchange(withKey(testReadKey), &pb.GetExchangeRequest{
+			SourceCurrency: "Euro Zone-Euro",
+			Currency:       "Canada-Dollar",
+			Date:           "2023-10-15",
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, response.Rate, 1.88)
+		assert.Equal(t, response.SourceRecordDate, "2023-09-30")
+		assert.Equal(t, response.RecordDate, "2023-09-29")
+	})
+
+	t.Run("this test simulate an exchange without a rate", func(t *testing.T) {
+		testObj := setUpTest(t)
+		testObj.fiscalDataApp.EXPECT().GetRatesOfExchange("Canada-Dollar", "2023-10-15").Return(nil, nil)
+
+		_, err := pb.NewConversionServiceClient(testObj.conn).GetExchange(withKey(testReadKey), &pb.GetExchangeRequest{
+			Currency: "Canada-Dollar",
+			Date:     "2023-10-15",
+		})
+
+		assert.Equal(t, status.Code(err), codes.NotFound)
+	})
+
+	t.Run("this test simulate an exchange with an invalid date", func(t *testing.T) {
+		testObj := setUpTest(t)
+
+		_, err := pb.NewConversionServiceClient(testObj.conn).GetExchange(withKey(testReadKey), &pb.GetExchangeRequest{
+			Currency: "Canada-Dollar",
+			Date:     "15/10/2023",
+		})
+
+		assert.Equal(t, status.Code(err), codes.InvalidArgument)
+	})
+}
@@ -0,0 +1,308 @@
+package rpc
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/jcpribeiro/TransactionApp/api/rpc/pb"
+	v1transaction "github.com/jcpribeiro/TransactionApp/api/v1/transaction"
+	"github.com/jcpribeiro/TransactionApp/app"
+	"github.com/jcpribeiro/TransactionApp/app/fiscaldata"
+	"github.com/jcpribeiro/TransactionApp/internal/cache"
+	"github.com/jcpribeiro/TransactionApp/internal/validate"
+	"github.com/jcpribeiro/TransactionApp/model"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var validator = validate.New()
+
+// transactionServer serves the transaction routes of the REST api. The reads are not cached,
+// the inserts make the cached periods of the account stale as the REST api does.
+type transactionServer struct {
+	pb.UnimplementedTransactionServiceServer
+	apps  *app.Container
+	cache cache.Cache
+}
+
+// validateParams validates the REST params built from a request
+func validateParams(params interface{}) error {
+	if err := validator.Validate(params); err != nil {
+		return fmt.Errorf("%w: %s", errInvalidRequest, err)
+	}
+
+	return nil
+}
+
+func (s *transactionServer) InsertTransactions(ctx context.Context, req *pb.InsertTransactionsRequest) (*pb.InsertTransactionsResponse, error) {
+	if len(req.Transactions) == 0 {
+		return nil, statusOf(fmt.Errorf("%w: transactions is required", errInvalidRequest))
+	}
+
+	transactions := make([]*model.Transaction, 0, len(req.Transactions))
+	for _, t := range req.Transactions {
+		transactions = append(transactions, &model.Transaction{
+			PurchaseAmount: t.PurchaseAmount,
+			SourceCurrency: t.SourceCurrency,
+			Description:    t.Description,
+			PurchaseDate:   t.PurchaseDate,
+			PurchasedAt:    t.PurchasedAt,
+			Category:       t.Category,
+			Tags:           t.Tags,
+			Merchant:       t.Merchant,
+			Metadata:       t.Metadata,
+		})
+	}
+
+	accountId := accountOf(ctx)
+	ids, err := s.apps.Transaction.InsertTransactions(ctx, accountId, transactions)
+	if err != nil {
+		return nil, statusOf(err)
+	}
+
+	v1transaction.InvalidatePeriods(ctx, s.cache, accountId)
+
+	return &pb.InsertTransactionsResponse{Ids: ids}, nil
+}
+
+func (s *transactionServer) GetTransactions(ctx context.Context, req *pb.GetTransactionsRequest) (*pb.GetTransactionsResponse, error) {
+	ids := uniqueList(req.Ids)
+	currencies := uniqueList(req.Currencies)
+	if len(ids) == 0 || len(currencies) == 0 {
+		return nil, statusOf(fmt.Errorf("%w: ids and currencies are required", errInvalidRequest))
+	}
+
+	// the invalid ids are reported as not found
+	fetchIds := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if _, err := primitive.ObjectIDFromHex(id); err == nil {
+			fetchIds = append(fetchIds, id)
+		}
+	}
+
+	fetched := make(map[string]*model.TransactionResponse, len(fetchIds))
+	if len(fetchIds) > 0 {
+		transactions, err := s.apps.Transaction.GetTransactions(ctx, accountOf(ctx), fetchIds)
+		if err != nil {
+			return nil, statusOf(err)
+		}
+		for _, r := range transactions {
+			fetched[r.Id] = r
+		}
+	}
+
+	response := &pb.GetTransactionsResponse{
+		Transactions: make([]*pb.Transaction, 0, len(ids)),
+	}
+	for _, id := range ids {
+		r, ok := fetched[id]
+		if !ok {
+			response.NotFound = append(response.NotFound, id)
+			continue
+		}
+
+		fiscaldata.ConvertTransaction(s.apps.FiscalData, r, currencies)
+		response.Transactions = append(response.Transactions, transactionOf(r))
+	}
+
+	return response, nil
+}
+
+func (s *transactionServer) StreamTransactionsByPeriod(req *pb.StreamTransactionsByPeriodRequest, stream pb.TransactionService_StreamTransactionsByPeriodServer) error {
+	params := &model.GetTransactionParamsByPeriod{
+		StartDate:       req.StartDate,
+		EndDate:         req.EndDate,
+		Timezone:        req.Timezone,
+		Field:           req.Field,
+		Currency:        req.Currency,
+		AttributeParams: attributesOf(req.Attributes),
+	}
+	if err := validateParams(params); err != nil {
+		return statusOf(err)
+	}
+
+	ctx := stream.Context()
+	transactions, err := s.apps.Transaction.GetTransactionsByPeriod(ctx, accountOf(ctx), params.StartDate, params.EndDate, params.Timezone, params.Field, &params.AttributeParams)
+	if err != nil {
+		return statusOf(err)
+	}
+
+	return s.sendConverted(stream.Send, transactions, params.Currency)
+}
+
+func (s *transactionServer) StreamTransactionsByPeriodEpoch(req *pb.StreamTransactionsByPeriodEpochRequest, stream pb.TransactionService_StreamTransactionsByPeriodEpochServer) error {
+	params := &model.GetTransactionParamsByPeriodEpoch{
+		StartDate:       req.StartDate,
+		EndDate:         req.EndDate,
+		Field:           req.Field,
+		Currency:        req.Currency,
+		AttributeParams: attributesOf(req.Attributes),
+	}
+	if err := validateParams(params); err != nil {
+		return statusOf(err)
+	}
+
+	ctx := stream.Context()
+	transactions, err := s.apps.Transaction.GetTransactionsByPeriodEpoch(ctx, accountOf(ctx), params.StartDate, params.EndDate, params.Field, &params.AttributeParams)
+	if err != nil {
+		return statusOf(err)
+	}
+
+	return s.sendConverted(stream.Send, transactions, params.Currency)
+}
+
+// sendConverted converts and sends the transactions one at a time. A transaction without
+// an exchange rate is sent with the error of its conversion instead of failing the stream.
+func (s *transactionServer) sendConverted(send func(*pb.Transaction) error, transactions []*model.TransactionResponse, currency string) error {
+	for _, r := range transactions {
+		fiscaldata.ConvertTransaction(s.apps.FiscalData, r, []string{currency})
+		if err := send(transactionOf(r)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *transactionServer) GetTransactionsSummary(ctx context.Context, req *pb.GetTransactionsSummaryRequest) (*pb.GetTransactionsSummaryResponse, error) {
+	params := &model.GetTransactionSummaryParams{
+		StartDate: req.StartDate,
+		EndDate:   req.EndDate,
+		Timezone:  req.Timezone,
+		Field:     req.Field,
+		Currency:  req.Currency,
+		GroupBy:   req.GroupBy,
+	}
+	if err := validateParams(params); err != nil {
+		return nil, statusOf(err)
+	}
+
+	aggregates, err := s.apps.Transaction.GetTransactionsSummary(ctx, accountOf(ctx), params.StartDate, params.EndDate, params.Timezone, params.Field, params.GroupBy)
+	if err != nil {
+		return nil, statusOf(err)
+	}
+
+	summaries, err := fiscaldata.Summarize(s.apps.FiscalData, aggregates, params.Currency)
+	if err != nil {
+		return nil, statusOf(err)
+	}
+
+	response := &pb.GetTransactionsSummaryResponse{
+		Summary: make([]*pb.Summary, 0, len(summaries)),
+	}
+	for _, summary := range summaries {
+		response.Summary = append(response.Summary, &pb.Summary{
+			Period:    summary.Period,
+			Currency:  summary.Currency,
+			Usd:       summaryValuesOf(summary.USD),
+			Converted: summaryValuesOf(summary.Converted),
+		})
+	}
+
+	return response, nil
+}
+
+func (s *transactionServer) SearchTransactions(ctx context.Context, req *pb.SearchTransactionsRequest) (*pb.SearchTransactionsResponse, error) {
+	currencies := uniqueList(req.Currencies)
+	params := &model.SearchTransactionParams{
+		Text:              req.Text,
+		MinAmount:         req.MinAmount,
+		MaxAmount:         req.MaxAmount,
+		StartDate:         req.StartDate,
+		EndDate:           req.EndDate,
+		Timezone:          req.Timezone,
+		Field:             req.Field,
+		PurchaseStartDate: req.PurchaseStartDate,
+		PurchaseEndDate:   req.PurchaseEndDate,
+		Currency:          strings.Join(currencies, ","),
+		Page:              req.Page,
+		PageSize:          req.PageSize,
+		AttributeParams:   attributesOf(req.Attributes),
+	}
+	if err := validateParams(params); err != nil {
+		return nil, statusOf(err)
+	}
+
+	transactions, err := s.apps.Transaction.SearchTransactions(ctx, accountOf(ctx), params)
+	if err != nil {
+		return nil, statusOf(err)
+	}
+
+	response := &pb.SearchTransactionsResponse{
+		Transactions: make([]*pb.Transaction, 0, len(transactions)),
+		Page:         params.Page,
+		PageSize:     params.PageSize,
+	}
+	for _, r := range transactions {
+		fiscaldata.ConvertTransaction(s.apps.FiscalData, r, currencies)
+		response.Transactions = append(response.Transactions, transactionOf(r))
+	}
+
+	return response, nil
+}
+
+func attributesOf(attributes *pb.Attributes) model.AttributeParams {
+	return model.AttributeParams{
+		Category: attributes.GetCategory(),
+		Tags:     attributes.GetTags(),
+		Merchant: attributes.GetMerchant(),
+		Metadata: attributes.GetMetadata(),
+	}
+}
+
+func transactionOf(r *model.TransactionResponse) *pb.Transaction {
+	t := &pb.Transaction{
+		Id:             r.Id,
+		PurchaseAmount: r.PurchaseAmount,
+		SourceCurrency: r.SourceCurrency,
+		Description:    r.Description,
+		PurchaseDate:   r.PurchaseDate,
+		PurchasedAt:    r.PurchasedAt,
+		CreatedAt:      r.CreatedAt,
+		Category:       r.Category,
+		Tags:           r.Tags,
+		Merchant:       r.Merchant,
+		Metadata:       r.Metadata,
+		Conversions:    make(map[string]*pb.Conversion, len(r.Conversions)),
+	}
+
+	for currency, c := range r.Conversions {
+		t.Conversions[currency] = &pb.Conversion{
+			ExchangeRate:            c.ExchangeRate,
+			RecordDate:              c.RecordDate,
+			SourceExchangeRate:      c.SourceExchangeRate,
+			SourceRecordDate:        c.SourceRecordDate,
+			ConvertedPurchaseAmount: c.ConvertedPurchaseAmount,
+			Error:                   c.Error,
+		}
+	}
+
+	return t
+}
+
+func summaryValuesOf(v model.SummaryValues) *pb.SummaryValues {
+	return &pb.SummaryValues{
+		Count:   v.Count,
+		Sum:     v.Sum,
+		Min:     v.Min,
+		Max:     v.Max,
+		Average: v.Average,
+	}
+}
+
+// uniqueList trims the values, ignoring blanks and duplicates
+func uniqueList(values []string) []string {
+	list := make([]string, 0, len(values))
+	seen := make(map[string]bool, len(values))
+	for _, value := range values {
+		value = strings.TrimSpace(value)
+		if len(value) == 0 || seen[value] {
+			continue
+		}
+		seen[value] = true
+		list = append(list, value)
+	}
+
+	return list
+}
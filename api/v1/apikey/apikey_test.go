@@ -0,0 +1,162 @@
+package apikey
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/jcpribeiro/TransactionApp/app"
+	apikeyApp "github.com/jcpribeiro/TransactionApp/app/apikey"
+	"github.com/jcpribeiro/TransactionApp/internal/tenant"
+	"github.com/jcpribeiro/TransactionApp/internal/validate"
+	"github.com/jcpribeiro/TransactionApp/model"
+	"github.com/jcpribeiro/TransactionApp/store/apikey"
+
+	"github.com/golang/mock/gomock"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+const testAccountId = "652d34910a8fc425116b84aa"
+
+type strucTest struct {
+	echo      *echo.Echo
+	apiKeyApp *apikeyApp.MockApp
+	h         handler
+}
+
+func setUpTest(t *testing.T) strucTest {
+	ctrl := gomock.NewController(t)
+	echo := echo.New()
+	echo.Validator = validate.New()
+	apiKeyApp := apikeyApp.NewMockApp(ctrl)
+
+	return strucTest{
+		echo:      echo,
+		apiKeyApp: apiKeyApp,
+		h: handler{
+			apps: &app.Container{
+				APIKey: apiKeyApp,
+			},
+		},
+	}
+}
+
+func TestCreateAPIKey(t *testing.T) {
+	t.Run("this test simulate a successful api key creation", func(t *testing.T) {
+		testObj := setUpTest(t)
+		body := `{"name": "integration", "scopes": ["transaction:read"]}`
+		req := httptest.NewRequest(http.MethodPost, "/v1/keys", strings.NewReader(body))
+		rec := httptest.NewRecorder()
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+		testObj.apiKeyApp.EXPECT().CreateAPIKey(gomock.Any(), testAccountId, "integration", []string{"transaction:read"}).Return(&model.CreatedAPIKey{
+			APIKey: &model.APIKey{
+				Id:        "652d34910a8fc425116b84d9",
+				AccountId: testAccountId,
+				Hash:      "hash",
+			},
+			Key: "tk_key",
+		}, nil)
+
+		ctx := testObj.echo.NewContext(req, rec)
+		tenant.SetAccount(ctx, testAccountId)
+		err := testObj.h.createAPIKey(ctx)
+
+		var resp map[string]interface{}
+		json.Unmarshal(rec.Body.Bytes(), &resp)
+		assert.NoError(t, err)
+		assert.Equal(t, rec.Code, http.StatusCreated)
+		assert.Equal(t, resp["key"], "tk_key")
+		assert.NotContains(t, resp, "hash")
+	})
+
+	t.Run("this test simulate an api key creation with an unknown scope", func(t *testing.T) {
+		testObj := setUpTest(t)
+		body := `{"name": "integration", "scopes": ["transaction:delete"]}`
+		req := httptest.NewRequest(http.MethodPost, "/v1/keys", strings.NewReader(body))
+		rec := httptest.NewRecorder()
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+		ctx := testObj.echo.NewContext(req, rec)
+		tenant.SetAccount(ctx, testAccountId)
+		err := testObj.h.createAPIKey(ctx)
+
+		assert.NoError(t, err)
+		assert.Equal(t, rec.Code, http.StatusBadRequest)
+	})
+}
+
+func TestListAPIKeys(t *testing.T) {
+	t.Run("This test simulates the process for listing the api keys", func(t *testing.T) {
+		testObj := setUpTest(t)
+		req := httptest.NewRequest(http.MethodGet, "/v1/keys", nil)
+		rec := httptest.NewRecorder()
+
+		testObj.apiKeyApp.EXPECT().ListAPIKeys(gomock.Any(), testAccountId).Return([]*model.APIKey{
+			{Id: "652d34910a8fc425116b84d9", Name: "integration"},
+		}, nil)
+
+		ctx := testObj.echo.NewContext(req, rec)
+		tenant.SetAccount(ctx, testAccountId)
+		err := testObj.h.listAPIKeys(ctx)
+
+		var resp map[string][]*model.APIKey
+		json.Unmarshal(rec.Body.Bytes(), &resp)
+		assert.NoError(t, err)
+		assert.Len(t, resp["keys"], 1)
+	})
+
+	t.Run("This test simulates an error when listing the api keys", func(t *testing.T) {
+		testObj := setUpTest(t)
+		req := httptest.NewRequest(http.MethodGet, "/v1/keys", nil)
+		rec := httptest.NewRecorder()
+
+		testObj.apiKeyApp.EXPECT().ListAPIKeys(gomock.Any(), testAccountId).Return(nil, errors.New("an error has ocurred"))
+
+		ctx := testObj.echo.NewContext(req, rec)
+		tenant.SetAccount(ctx, testAccountId)
+		err := testObj.h.listAPIKeys(ctx)
+
+		assert.Error(t, err)
+	})
+}
+
+func TestRevokeAPIKey(t *testing.T) {
+	t.Run("This test simulates revoking an api key", func(t *testing.T) {
+		testObj := setUpTest(t)
+		req := httptest.NewRequest(http.MethodDelete, "/v1/keys/652d34910a8fc425116b84d9", nil)
+		rec := httptest.NewRecorder()
+
+		testObj.apiKeyApp.EXPECT().RevokeAPIKey(gomock.Any(), testAccountId, "652d34910a8fc425116b84d9").Return(nil)
+
+		ctx := testObj.echo.NewContext(req, rec)
+		tenant.SetAccount(ctx, testAccountId)
+		ctx.SetParamNames("id")
+		ctx.SetParamValues("652d34910a8fc425116b84d9")
+		err := testObj.h.revokeAPIKey(ctx)
+
+		assert.NoError(t, err)
+		assert.Equal(t, rec.Code, http.StatusNoContent)
+	})
+
+	t.Run("This test simulates revoking an api key that does not exist", func(t *testing.T) {
+		testObj := setUpTest(t)
+		req := httptest.NewRequest(http.MethodDelete, "/v1/keys/652d34910a8fc425116b84d9", nil)
+		rec := httptest.NewRecorder()
+
+		testObj.apiKeyApp.EXPECT().RevokeAPIKey(gomock.Any(), testAccountId, "652d34910a8fc425116b84d9").Return(apikey.ErrAPIKeyNotFound)
+
+		ctx := testObj.echo.NewContext(req, rec)
+		tenant.SetAccount(ctx, testAccountId)
+		ctx.SetParamNames("id")
+		ctx.SetParamValues("652d34910a8fc425116b84d9")
+		err := testObj.h.revokeAPIKey(ctx)
+
+		assert.NoError(t, err)
+		assert.Equal(t, rec.Code, http.StatusNotFound)
+	})
+}
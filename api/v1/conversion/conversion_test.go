@@ -0,0 +1,97 @@
+package conversion
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/jcpribeiro/TransactionApp/app"
+	"github.com/jcpribeiro/TransactionApp/app/fiscaldata"
+	"github.com/jcpribeiro/TransactionApp/internal/validate"
+	"github.com/jcpribeiro/TransactionApp/model"
+
+	"github.com/golang/mock/gomock"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+type strucTest struct {
+	echo          *echo.Echo
+	fiscalDataApp *fiscaldata.MockApp
+	h             handler
+}
+
+func setUpTest(t *testing.T) strucTest {
+	ctrl := gomock.NewController(t)
+	echo := echo.New()
+	echo.Validator = validate.New()
+	fiscalDataApp := fiscaldata.NewMockApp(ctrl)
+
+	return strucTest{
+		echo:          echo,
+		fiscalDataApp: fiscalDataApp,
+		h: handler{
+			apps: &app.Container{
+				FiscalData: fiscalDataApp,
+			},
+		},
+	}
+}
+
+func TestConvertAmounts(t *testing.T) {
+	t.Run("this test simulate a successful batch conversion", func(t *testing.T) {
+		testObj := setUpTest(t)
+		body := `{"conversions": [
+			{"amount": 123.45, "date": "2023-07-01", "currency": "Brazil-Real"},
+			{"amount": 123.45, "source_currency": "USD", "date": "2023-07-01", "currency": "Brazil-Real"}
+		]}`
+		req := httptest.NewRequest(http.MethodPost, "/v1/conversions", strings.NewReader(body))
+		rec := httptest.NewRecorder()
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+		testObj.fiscalDataApp.EXPECT().GetRatesOfExchange("Brazil-Real", "2023-07-01").Return(&fiscaldata.Data{
+			CurrencyDescription: "Brazil-Real",
+			ExchangeRate:        4.8,
+			RecordDate:          "2023-06-30",
+		}, nil)
+
+		err := testObj.h.convertAmounts(testObj.echo.NewContext(req, rec))
+
+		var resp model.ConvertAmountsResponse
+		json.Unmarshal(rec.Body.Bytes(), &resp)
+		assert.NoError(t, err)
+		assert.Equal(t, rec.Code, http.StatusOK)
+		assert.Len(t, resp.Conversions, 2)
+		assert.Equal(t, resp.Conversions[0].ConvertedPurchaseAmount, 592.56)
+		assert.Equal(t, resp.Conversions[0].RecordDate, "2023-06-30")
+		assert.Equal(t, resp.Conversions[1].ConvertedPurchaseAmount, 592.56)
+	})
+
+	t.Run("this test simulate a conversion with an invalid date", func(t *testing.T) {
+		testObj := setUpTest(t)
+		body := `{"conversions": [{"amount": 123.45, "date": "01/07/2023", "currency": "Brazil-Real"}]}`
+		req := httptest.NewRequest(http.MethodPost, "/v1/conversions", strings.NewReader(body))
+		rec := httptest.NewRecorder()
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+		err := testObj.h.convertAmounts(testObj.echo.NewContext(req, rec))
+
+		assert.NoError(t, err)
+		assert.Equal(t, rec.Code, http.StatusBadRequest)
+		assert.JSONEq(t, rec.Body.String(), `{"error": "invalid amount, date or currency"}`)
+	})
+
+	t.Run("this test simulate an empty batch", func(t *testing.T) {
+		testObj := setUpTest(t)
+		req := httptest.NewRequest(http.MethodPost, "/v1/conversions", strings.NewReader(`{"conversions": []}`))
+		rec := httptest.NewRecorder()
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+		err := testObj.h.convertAmounts(testObj.echo.NewContext(req, rec))
+
+		assert.NoError(t, err)
+		assert.Equal(t, rec.Code, http.StatusBadRequest)
+	})
+}
@@ -0,0 +1,315 @@
+package job
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jcpribeiro/TransactionApp/app"
+	"github.com/jcpribeiro/TransactionApp/app/job"
+	"github.com/jcpribeiro/TransactionApp/internal/auth"
+	"github.com/jcpribeiro/TransactionApp/internal/tenant"
+	"github.com/jcpribeiro/TransactionApp/internal/validate"
+	"github.com/jcpribeiro/TransactionApp/model"
+	jobStore "github.com/jcpribeiro/TransactionApp/store/job"
+
+	"github.com/golang/mock/gomock"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+const testAccountId = "652d34910a8fc425116b84aa"
+
+var readPrincipal = &auth.Principal{
+	AccountId: testAccountId,
+	Scopes:    []string{auth.ScopeTransactionRead},
+}
+
+type strucTest struct {
+	echo   *echo.Echo
+	jobApp *job.MockApp
+	h      handler
+}
+
+func setUpTest(t *testing.T) strucTest {
+	ctrl := gomock.NewController(t)
+	echo := echo.New()
+	echo.Validator = validate.New()
+	jobApp := job.NewMockApp(ctrl)
+
+	return strucTest{
+		echo:   echo,
+		jobApp: jobApp,
+		h: handler{
+			apps: &app.Container{
+				Job: jobApp,
+			},
+			types: map[string]Type{
+				"transaction.export": {Scope: auth.ScopeTransactionRead, Submit: true},
+				"transaction.import": {Scope: auth.ScopeTransactionWrite},
+			},
+		},
+	}
+}
+
+func TestSubmitJob(t *testing.T) {
+	t.Run("this test simulate a successful job submit", func(t *testing.T) {
+		testObj := setUpTest(t)
+		body := `{"type": "transaction.export", "params": {"format": "csv"}}`
+		req := httptest.NewRequest(http.MethodPost, "/v1/jobs", strings.NewReader(body))
+		rec := httptest.NewRecorder()
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+		testObj.jobApp.EXPECT().SubmitJob(gomock.Any(), testAccountId, "transaction.export", map[string]string{"format": "csv"}).Return(&model.Job{
+			Id:     "652d34910a8fc425116b84d9",
+			Type:   "transaction.export",
+			Status: model.JobStatusPending,
+		}, nil)
+
+		ctx := testObj.echo.NewContext(req, rec)
+		tenant.SetAccount(ctx, testAccountId)
+		auth.SetPrincipal(ctx, readPrincipal)
+		err := testObj.h.submitJob(ctx)
+
+		var resp model.Job
+		json.Unmarshal(rec.Body.Bytes(), &resp)
+		assert.NoError(t, err)
+		assert.Equal(t, rec.Code, http.StatusAccepted)
+		assert.Equal(t, resp.Id, "652d34910a8fc425116b84d9")
+	})
+
+	t.Run("this test simulate a job submit with an unknown type", func(t *testing.T) {
+		testObj := setUpTest(t)
+		req := httptest.NewRequest(http.MethodPost, "/v1/jobs", strings.NewReader(`{"type": "unknown"}`))
+		rec := httptest.NewRecorder()
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+		ctx := testObj.echo.NewContext(req, rec)
+		tenant.SetAccount(ctx, testAccountId)
+		auth.SetPrincipal(ctx, readPrincipal)
+		err := testObj.h.submitJob(ctx)
+
+		assert.NoError(t, err)
+		assert.Equal(t, rec.Code, http.StatusBadRequest)
+	})
+
+	t.Run("this test simulate a job submit of a type that is not submittable", func(t *testing.T) {
+		testObj := setUpTest(t)
+		body := `{"type": "transaction.import", "params": {"file": "652d34910a8fc425116b84d9.csv"}}`
+		req := httptest.NewRequest(http.MethodPost, "/v1/jobs", strings.NewReader(body))
+		rec := httptest.NewRecorder()
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+		ctx := testObj.echo.NewContext(req, rec)
+		tenant.SetAccount(ctx, testAccountId)
+		auth.SetPrincipal(ctx, readPrincipal)
+		err := testObj.h.submitJob(ctx)
+
+		assert.NoError(t, err)
+		assert.Equal(t, rec.Code, http.StatusBadRequest)
+	})
+
+	t.Run("this test simulate a job submit without the job type scope", func(t *testing.T) {
+		testObj := setUpTest(t)
+		req := httptest.NewRequest(http.MethodPost, "/v1/jobs", strings.NewReader(`{"type": "transaction.export"}`))
+		rec := httptest.NewRecorder()
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+		ctx := testObj.echo.NewContext(req, rec)
+		tenant.SetAccount(ctx, testAccountId)
+		auth.SetPrincipal(ctx, &auth.Principal{
+			AccountId: testAccountId,
+			Scopes:    []string{auth.ScopeTransactionWrite},
+		})
+		err := testObj.h.submitJob(ctx)
+
+		assert.NoError(t, err)
+		assert.Equal(t, rec.Code, http.StatusForbidden)
+	})
+
+	t.Run("this test simulate a job submit without type", func(t *testing.T) {
+		testObj := setUpTest(t)
+		req := httptest.NewRequest(http.MethodPost, "/v1/jobs", strings.NewReader(`{}`))
+		rec := httptest.NewRecorder()
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+		ctx := testObj.echo.NewContext(req, rec)
+		tenant.SetAccount(ctx, testAccountId)
+		auth.SetPrincipal(ctx, readPrincipal)
+		err := testObj.h.submitJob(ctx)
+
+		assert.NoError(t, err)
+		assert.Equal(t, rec.Code, http.StatusBadRequest)
+	})
+}
+
+func TestGetJob(t *testing.T) {
+	t.Run("This test simulates the process for obtaining a job", func(t *testing.T) {
+		testObj := setUpTest(t)
+		req := httptest.NewRequest(http.MethodGet, "/v1/jobs/652d34910a8fc425116b84d9", nil)
+		rec := httptest.NewRecorder()
+
+		testObj.jobApp.EXPECT().GetJob(gomock.Any(), testAccountId, "652d34910a8fc425116b84d9").Return(&model.Job{
+			Id:       "652d34910a8fc425116b84d9",
+			Type:     "transaction.export",
+			Status:   model.JobStatusRunning,
+			Progress: 50,
+			Total:    100,
+		}, nil)
+
+		ctx := testObj.echo.NewContext(req, rec)
+		tenant.SetAccount(ctx, testAccountId)
+		auth.SetPrincipal(ctx, readPrincipal)
+		ctx.SetParamNames("id")
+		ctx.SetParamValues("652d34910a8fc425116b84d9")
+		err := testObj.h.getJob(ctx)
+
+		var resp model.Job
+		json.Unmarshal(rec.Body.Bytes(), &resp)
+		assert.NoError(t, err)
+		assert.Equal(t, resp.Progress, int64(50))
+	})
+
+	t.Run("This test simulates obtaining a job without the job type scope", func(t *testing.T) {
+		testObj := setUpTest(t)
+		req := httptest.NewRequest(http.MethodGet, "/v1/jobs/652d34910a8fc425116b84d9", nil)
+		rec := httptest.NewRecorder()
+
+		testObj.jobApp.EXPECT().GetJob(gomock.Any(), testAccountId, "652d34910a8fc425116b84d9").Return(&model.Job{
+			Id:     "652d34910a8fc425116b84d9",
+			Type:   "transaction.import",
+			Status: model.JobStatusRunning,
+		}, nil)
+
+		ctx := testObj.echo.NewContext(req, rec)
+		tenant.SetAccount(ctx, testAccountId)
+		auth.SetPrincipal(ctx, readPrincipal)
+		ctx.SetParamNames("id")
+		ctx.SetParamValues("652d34910a8fc425116b84d9")
+		err := testObj.h.getJob(ctx)
+
+		assert.NoError(t, err)
+		assert.Equal(t, rec.Code, http.StatusForbidden)
+	})
+
+	t.Run("This test simulates a job that does not exist", func(t *testing.T) {
+		testObj := setUpTest(t)
+		req := httptest.NewRequest(http.MethodGet, "/v1/jobs/652d34910a8fc425116b84d9", nil)
+		rec := httptest.NewRecorder()
+
+		testObj.jobApp.EXPECT().GetJob(gomock.Any(), testAccountId, "652d34910a8fc425116b84d9").Return(nil, jobStore.ErrJobNotFound)
+
+		ctx := testObj.echo.NewContext(req, rec)
+		tenant.SetAccount(ctx, testAccountId)
+		auth.SetPrincipal(ctx, readPrincipal)
+		ctx.SetParamNames("id")
+		ctx.SetParamValues("652d34910a8fc425116b84d9")
+		err := testObj.h.getJob(ctx)
+
+		assert.NoError(t, err)
+		assert.Equal(t, rec.Code, http.StatusNotFound)
+	})
+
+	t.Run("This test simulates an error when obtaining a job", func(t *testing.T) {
+		testObj := setUpTest(t)
+		req := httptest.NewRequest(http.MethodGet, "/v1/jobs/652d34910a8fc425116b84d9", nil)
+		rec := httptest.NewRecorder()
+
+		testObj.jobApp.EXPECT().GetJob(gomock.Any(), testAccountId, "652d34910a8fc425116b84d9").Return(nil, errors.New("an error has ocurred"))
+
+		ctx := testObj.echo.NewContext(req, rec)
+		tenant.SetAccount(ctx, testAccountId)
+		auth.SetPrincipal(ctx, readPrincipal)
+		ctx.SetParamNames("id")
+		ctx.SetParamValues("652d34910a8fc425116b84d9")
+		err := testObj.h.getJob(ctx)
+
+		assert.Error(t, err)
+	})
+}
+
+func TestCancelJob(t *testing.T) {
+	t.Run("This test simulates the cancellation of a job", func(t *testing.T) {
+		testObj := setUpTest(t)
+		req := httptest.NewRequest(http.MethodPost, "/v1/jobs/652d34910a8fc425116b84d9/cancel", nil)
+		rec := httptest.NewRecorder()
+
+		testObj.jobApp.EXPECT().GetJob(gomock.Any(), testAccountId, "652d34910a8fc425116b84d9").Return(&model.Job{
+			Id:     "652d34910a8fc425116b84d9",
+			Type:   "transaction.export",
+			Status: model.JobStatusRunning,
+		}, nil)
+		testObj.jobApp.EXPECT().CancelJob(gomock.Any(), testAccountId, "652d34910a8fc425116b84d9").Return(&model.Job{
+			Id:     "652d34910a8fc425116b84d9",
+			Type:   "transaction.export",
+			Status: model.JobStatusCanceled,
+		}, nil)
+
+		ctx := testObj.echo.NewContext(req, rec)
+		tenant.SetAccount(ctx, testAccountId)
+		auth.SetPrincipal(ctx, readPrincipal)
+		ctx.SetParamNames("id")
+		ctx.SetParamValues("652d34910a8fc425116b84d9")
+		err := testObj.h.cancelJob(ctx)
+
+		var resp model.Job
+		json.Unmarshal(rec.Body.Bytes(), &resp)
+		assert.NoError(t, err)
+		assert.Equal(t, resp.Status, model.JobStatusCanceled)
+	})
+}
+
+func TestGetJobResult(t *testing.T) {
+	t.Run("This test simulates the download of a job result", func(t *testing.T) {
+		testObj := setUpTest(t)
+		path := filepath.Join(t.TempDir(), "652d34910a8fc425116b84d9.csv")
+		os.WriteFile(path, []byte("id\n"), 0o644)
+		req := httptest.NewRequest(http.MethodGet, "/v1/jobs/652d34910a8fc425116b84d9/result", nil)
+		rec := httptest.NewRecorder()
+
+		testObj.jobApp.EXPECT().GetJob(gomock.Any(), testAccountId, "652d34910a8fc425116b84d9").Return(&model.Job{
+			Id:     "652d34910a8fc425116b84d9",
+			Type:   "transaction.export",
+			Status: model.JobStatusSucceeded,
+			Result: "652d34910a8fc425116b84d9.csv",
+		}, nil)
+		testObj.jobApp.EXPECT().FilePath("652d34910a8fc425116b84d9.csv").Return(path, nil)
+
+		ctx := testObj.echo.NewContext(req, rec)
+		tenant.SetAccount(ctx, testAccountId)
+		auth.SetPrincipal(ctx, readPrincipal)
+		ctx.SetParamNames("id")
+		ctx.SetParamValues("652d34910a8fc425116b84d9")
+		err := testObj.h.getJobResult(ctx)
+
+		assert.NoError(t, err)
+		assert.Equal(t, rec.Body.String(), "id\n")
+	})
+
+	t.Run("This test simulates the download of a running job result", func(t *testing.T) {
+		testObj := setUpTest(t)
+		req := httptest.NewRequest(http.MethodGet, "/v1/jobs/652d34910a8fc425116b84d9/result", nil)
+		rec := httptest.NewRecorder()
+
+		testObj.jobApp.EXPECT().GetJob(gomock.Any(), testAccountId, "652d34910a8fc425116b84d9").Return(&model.Job{
+			Id:     "652d34910a8fc425116b84d9",
+			Type:   "transaction.export",
+			Status: model.JobStatusRunning,
+		}, nil)
+
+		ctx := testObj.echo.NewContext(req, rec)
+		tenant.SetAccount(ctx, testAccountId)
+		auth.SetPrincipal(ctx, readPrincipal)
+		ctx.SetParamNames("id")
+		ctx.SetParamValues("652d34910a8fc425116b84d9")
+		err := testObj.h.getJobResult(ctx)
+
+		assert.NoError(t, err)
+		assert.Equal(t, rec.Code, http.StatusConflict)
+	})
+}
@@ -0,0 +1,230 @@
+package rate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/jcpribeiro/TransactionApp/app"
+	"github.com/jcpribeiro/TransactionApp/app/fiscaldata"
+	"github.com/jcpribeiro/TransactionApp/internal/auth"
+	"github.com/jcpribeiro/TransactionApp/internal/cache"
+	"github.com/jcpribeiro/TransactionApp/internal/httpcache"
+	"github.com/jcpribeiro/TransactionApp/internal/ratelimit"
+	"github.com/jcpribeiro/TransactionApp/model"
+
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+)
+
+// Register group rate. The rates are read from the Treasury api and are limited as a conversion.
+func Register(g *echo.Group, apps *app.Container, cache cache.Cache, policies map[string]cache.Policy, limits *ratelimit.Policy) {
+	h := &handler{
+		rates: NewLookup(apps, cache, policies),
+	}
+
+	g.GET("", h.getRates, auth.RequireScope(auth.ScopeTransactionRead), limits.Middleware(ratelimit.ClassConversion))
+}
+
+// CachePolicyRate is the cache policy of the rates, configured in cache.endpoints
+const CachePolicyRate = "rate"
+
+// defaultCachePolicy applies when the rates are missing from the configuration. The Treasury
+// records rates quarterly, a recent date may get a newer rate within a day.
+var defaultCachePolicy = cache.Policy{TTL: 24 * time.Hour, MaxAge: time.Hour}
+
+// ErrInvalidRange is returned when the params are neither a date nor a range of dates
+var ErrInvalidRange = errors.New("set either date, or startDate and endDate")
+
+// Lookup reads the rates through the cache, the v2 routes share it
+type Lookup struct {
+	apps   *app.Container
+	cache  cache.Cache
+	Policy cache.Policy
+}
+
+func NewLookup(apps *app.Container, cache cache.Cache, policies map[string]cache.Policy) *Lookup {
+	policy, ok := policies[CachePolicyRate]
+	if !ok {
+		policy = defaultCachePolicy
+	}
+
+	return &Lookup{
+		apps:   apps,
+		cache:  cache,
+		Policy: policy,
+	}
+}
+
+// CheckParams checks the params are either a date, or a range whose end is not before its start
+func CheckParams(params *model.GetRateParams) error {
+	if !params.IsSeries() {
+		if len(params.Date) == 0 {
+			return ErrInvalidRange
+		}
+		return nil
+	}
+
+	if len(params.Date) > 0 || len(params.StartDate) == 0 || len(params.EndDate) == 0 || params.EndDate < params.StartDate {
+		return ErrInvalidRange
+	}
+
+	return nil
+}
+
+// Rate gets the rate a conversion on date uses, chosen by GetRatesOfExchange.
+// It returns fiscaldata.ErrRateNotFound when there is no rate up to 6 months before the date.
+func (l *Lookup) Rate(ctx context.Context, currency, date string) (*model.RateResponse, error) {
+	var response *model.RateResponse
+	err := l.cached(ctx, rateCacheKey(currency, date), &response, func(ctx context.Context) (interface{}, error) {
+		data, err := l.apps.FiscalData.GetRatesOfExchange(currency, date)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get rates exchange: %w", err)
+		}
+		if data == nil || data.ExchangeRate == 0 {
+			return nil, fmt.Errorf("%w: %s", fiscaldata.ErrRateNotFound, currency)
+		}
+
+		response = &model.RateResponse{
+			Currency: currency,
+			Date:     date,
+			Rate: model.Rate{
+				ExchangeRate: data.ExchangeRate,
+				RecordDate:   data.RecordDate,
+			},
+		}
+		return response, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+// Series gets every rate recorded between the dates, oldest first
+func (l *Lookup) Series(ctx context.Context, currency, startDate, endDate string) (*model.RateSeriesResponse, error) {
+	var response *model.RateSeriesResponse
+	err := l.cached(ctx, seriesCacheKey(currency, startDate, endDate), &response, func(ctx context.Context) (interface{}, error) {
+		series, err := l.apps.FiscalData.GetRatesSeries(currency, startDate, endDate)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get rates series: %w", err)
+		}
+
+		rates := make([]*model.Rate, 0, len(series))
+		for _, data := range series {
+			rates = append(rates, &model.Rate{
+				ExchangeRate: data.ExchangeRate,
+				RecordDate:   data.RecordDate,
+			})
+		}
+
+		response = &model.RateSeriesResponse{
+			Currency:  currency,
+			StartDate: startDate,
+			EndDate:   endDate,
+			Rates:     rates,
+		}
+		return response, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+// cached runs load through the cache when the policy has a TTL. load must also assign the
+// response it returns, as a cache hit decodes into value instead.
+func (l *Lookup) cached(ctx context.Context, key string, value interface{}, load cache.Loader) error {
+	if l.Policy.TTL > 0 {
+		return l.cache.GetOrSet(ctx, key, value, l.Policy.TTL, load)
+	}
+
+	_, err := load(ctx)
+	return err
+}
+
+// rateCacheKey builds the cache key of a rate, the rates are not scoped to an account
+func rateCacheKey(currency, date string) string {
+	return fmt.Sprintf("rate:%s:%s", currency, date)
+}
+
+// seriesCacheKey builds the cache key of a rate series
+func seriesCacheKey(currency, startDate, endDate string) string {
+	return fmt.Sprintf("rate:%s:%s:%s", currency, startDate, endDate)
+}
+
+type handler struct {
+	rates *Lookup
+}
+
+// getRates swagger document
+// @Summary Get the Treasury rate of a currency on a date, or its series between two dates
+// @Description With date, the rate is the one a conversion on the date uses, the latest one up to 6 months before it.
+// @Description With startDate and endDate, the series has every rate recorded between the dates, oldest first.
+// @Description The rates are the amount of currency for one USD.
+// @Tags rate
+// @Accept  json
+// @Produce  json
+// @Param currency query string true "Currency id. E.g. Brazil-Real"
+// @Param date query string false "Date of the conversion. E.g. 2023-07-01"
+// @Param startDate query string false "Series start date, inclusive. E.g. 2023-01-01"
+// @Param endDate query string false "Series end date, inclusive. E.g. 2023-12-31"
+// @Param If-None-Match header string false "ETag of a previous response"
+// @Success 200 {object} model.RateResponse "The rate on date, or a model.RateSeriesResponse with startDate and endDate"
+// @Success 304 {string} string "The response did not change"
+// @Failure 400 {object} string
+// @Failure 401 {object} string
+// @Failure 403 {object} string
+// @Failure 404 {object} string
+// @Failure 429 {object} string
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /v1/rates [get]
+func (h *handler) getRates(c echo.Context) error {
+	params := new(model.GetRateParams)
+
+	if err := c.Bind(params); err != nil {
+		logrus.Error(err)
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid query params",
+		})
+	}
+
+	if err := c.Validate(params); err != nil {
+		logrus.Error(err)
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid currency or date",
+		})
+	}
+
+	if err := CheckParams(params); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": err.Error(),
+		})
+	}
+
+	if params.IsSeries() {
+		response, err := h.rates.Series(c.Request().Context(), params.Currency, params.StartDate, params.EndDate)
+		if err != nil {
+			return err
+		}
+
+		return httpcache.JSON(c, response, h.rates.Policy.MaxAge, time.Time{})
+	}
+
+	response, err := h.rates.Rate(c.Request().Context(), params.Currency, params.Date)
+	if errors.Is(err, fiscaldata.ErrRateNotFound) {
+		return c.JSON(http.StatusNotFound, map[string]string{
+			"error": "no exchange rate within 6 months before the date",
+		})
+	}
+	if err != nil {
+		return err
+	}
+
+	return httpcache.JSON(c, response, h.rates.Policy.MaxAge, time.Time{})
+}
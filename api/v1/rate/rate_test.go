@@ -0,0 +1,133 @@
+package rate
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jcpribeiro/TransactionApp/app"
+	"github.com/jcpribeiro/TransactionApp/app/fiscaldata"
+	"github.com/jcpribeiro/TransactionApp/internal/cache"
+	"github.com/jcpribeiro/TransactionApp/internal/validate"
+	"github.com/jcpribeiro/TransactionApp/model"
+
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+type strucTest struct {
+	echo     *echo.Echo
+	requests int
+	filters  []string
+	h        handler
+}
+
+// setUpTest serves the rates from a Treasury api answering data, through an in-process cache
+func setUpTest(t *testing.T, data []fiscaldata.Data) *strucTest {
+	testObj := &strucTest{
+		echo: echo.New(),
+	}
+	testObj.echo.Validator = validate.New()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		testObj.requests++
+		testObj.filters = append(testObj.filters, r.URL.Query().Get("filter"))
+		w.WriteHeader(200)
+		result, _ := json.Marshal(fiscaldata.RateOfExchangeResponse{
+			Data: data,
+		})
+		w.Write(result)
+	}))
+	t.Cleanup(server.Close)
+
+	apps := &app.Container{
+		FiscalData: fiscaldata.NewAppFiscalData(server.URL, *logrus.New()),
+	}
+	testObj.h = handler{
+		rates: NewLookup(apps, cache.NewCache(nil, cache.Options{}, *logrus.New()), nil),
+	}
+
+	return testObj
+}
+
+func (s *strucTest) get(url string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, url, nil)
+	rec := httptest.NewRecorder()
+	s.h.getRates(s.echo.NewContext(req, rec))
+	return rec
+}
+
+func TestGetRates(t *testing.T) {
+	t.Run("this test simulate the rate of a date read once", func(t *testing.T) {
+		testObj := setUpTest(t, []fiscaldata.Data{
+			{CurrencyDescription: "Brazil-Real", ExchangeRate: 4.8, RecordDate: "2023-06-30"},
+		})
+
+		rec := testObj.get("/v1/rates?currency=Brazil-Real&date=2023-07-01")
+		cached := testObj.get("/v1/rates?currency=Brazil-Real&date=2023-07-01")
+
+		assert.Equal(t, rec.Code, http.StatusOK)
+		assert.JSONEq(t, rec.Body.String(), `{"currency": "Brazil-Real", "date": "2023-07-01", "exchange_rate": 4.8, "record_date": "2023-06-30"}`)
+		assert.Equal(t, rec.Header().Get(echo.HeaderCacheControl), "private, max-age=3600")
+		assert.Equal(t, cached.Body.String(), rec.Body.String())
+		assert.Equal(t, testObj.requests, 1)
+		assert.Contains(t, testObj.filters[0], "country_currency_desc:eq:Brazil-Real,record_date:lte:2023-07-01")
+	})
+
+	t.Run("this test simulate a date without a rate in the last 6 months", func(t *testing.T) {
+		testObj := setUpTest(t, nil)
+
+		rec := testObj.get("/v1/rates?currency=Brazil-Real&date=2023-07-01")
+		testObj.get("/v1/rates?currency=Brazil-Real&date=2023-07-01")
+
+		assert.Equal(t, rec.Code, http.StatusNotFound)
+		assert.JSONEq(t, rec.Body.String(), `{"error": "no exchange rate within 6 months before the date"}`)
+		// a missing rate is not cached, it may be recorded later
+		assert.Equal(t, testObj.requests, 2)
+	})
+
+	t.Run("this test simulate the series between two dates", func(t *testing.T) {
+		testObj := setUpTest(t, []fiscaldata.Data{
+			{CurrencyDescription: "Brazil-Real", ExchangeRate: 5.06, RecordDate: "2023-03-31"},
+			{CurrencyDescription: "Brazil-Real", ExchangeRate: 4.8, RecordDate: "2023-06-30"},
+		})
+
+		rec := testObj.get("/v1/rates?currency=Brazil-Real&startDate=2023-01-01&endDate=2023-07-01")
+		testObj.get("/v1/rates?currency=Brazil-Real&startDate=2023-01-01&endDate=2023-07-01")
+
+		var response model.RateSeriesResponse
+		json.Unmarshal(rec.Body.Bytes(), &response)
+		assert.Equal(t, rec.Code, http.StatusOK)
+		assert.Equal(t, response, model.RateSeriesResponse{
+			Currency:  "Brazil-Real",
+			StartDate: "2023-01-01",
+			EndDate:   "2023-07-01",
+			Rates: []*model.Rate{
+				{ExchangeRate: 5.06, RecordDate: "2023-03-31"},
+				{ExchangeRate: 4.8, RecordDate: "2023-06-30"},
+			},
+		})
+		assert.Equal(t, testObj.requests, 1)
+	})
+
+	t.Run("this test simulate invalid params", func(t *testing.T) {
+		testObj := setUpTest(t, nil)
+
+		for url, message := range map[string]string{
+			"/v1/rates?date=2023-07-01":                                                              "invalid currency or date",
+			"/v1/rates?currency=Brazil-Real&date=01/07/2023":                                         "invalid currency or date",
+			"/v1/rates?currency=Brazil-Real":                                                         ErrInvalidRange.Error(),
+			"/v1/rates?currency=Brazil-Real&startDate=2023-01-01":                                    ErrInvalidRange.Error(),
+			"/v1/rates?currency=Brazil-Real&startDate=2023-07-01&endDate=2023-01-01":                 ErrInvalidRange.Error(),
+			"/v1/rates?currency=Brazil-Real&date=2023-07-01&startDate=2023-01-01&endDate=2023-07-01": ErrInvalidRange.Error(),
+		} {
+			rec := testObj.get(url)
+
+			assert.Equal(t, rec.Code, http.StatusBadRequest, url)
+			assert.JSONEq(t, rec.Body.String(), `{"error": "`+message+`"}`, url)
+		}
+		assert.Equal(t, testObj.requests, 0)
+	})
+}
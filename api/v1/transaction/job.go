@@ -0,0 +1,153 @@
+package transaction
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	jobApp "github.com/jcpribeiro/TransactionApp/app/job"
+	"github.com/jcpribeiro/TransactionApp/internal/export"
+	"github.com/jcpribeiro/TransactionApp/internal/validate"
+	"github.com/jcpribeiro/TransactionApp/model"
+)
+
+const (
+	JobTypeImport = "transaction.import"
+	JobTypeExport = "transaction.export"
+)
+
+// progressReader reports the bytes read from a file of known size
+type progressReader struct {
+	reader   io.Reader
+	read     int64
+	total    int64
+	progress jobApp.Progress
+}
+
+func (p *progressReader) Read(b []byte) (int, error) {
+	n, err := p.reader.Read(b)
+	p.read += int64(n)
+	p.progress(p.read, p.total)
+	return n, err
+}
+
+// importSource is the source of the rows of an import job, the same on every run of the job
+func importSource(jobId string) string {
+	return fmt.Sprintf("job:%s", jobId)
+}
+
+// runImportJob imports the uploaded file named by the file param and stores the import report as the result.
+// The job may run again after a restart, the rows are keyed by the job so the ones already inserted are skipped.
+func (h *handler) runImportJob(ctx context.Context, job *model.Job, progress jobApp.Progress) (string, error) {
+	path, err := h.apps.Job.FilePath(job.Params["file"])
+	if err != nil {
+		return "", err
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to open import file: %w", err)
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return "", fmt.Errorf("failed to read import file: %w", err)
+	}
+
+	report, err := h.apps.Transaction.ImportTransactions(ctx, job.AccountId, job.Params["format"], importSource(job.Id), &progressReader{
+		reader:   file,
+		total:    info.Size(),
+		progress: progress,
+	})
+	h.invalidatePeriods(ctx, job.AccountId)
+	if err != nil {
+		return "", err
+	}
+	report.JobId = job.Id
+
+	result := fmt.Sprintf("%s.json", job.Id)
+	data, err := json.Marshal(report)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal import report: %w", err)
+	}
+
+	if err := writeJobFile(h.apps.Job, result, func(w io.Writer) error {
+		_, err := w.Write(data)
+		return err
+	}); err != nil {
+		return "", err
+	}
+
+	if err := os.Remove(path); err != nil {
+		return "", fmt.Errorf("failed to remove import file: %w", err)
+	}
+
+	return result, nil
+}
+
+// runExportJob writes the export of the startDate, endDate, timezone, field, currency and format params to the result file
+func (h *handler) runExportJob(ctx context.Context, job *model.Job, progress jobApp.Progress) (string, error) {
+	params := &model.ExportTransactionParams{
+		StartDate: job.Params["startDate"],
+		EndDate:   job.Params["endDate"],
+		Timezone:  job.Params["timezone"],
+		Field:     job.Params["field"],
+		Currency:  job.Params["currency"],
+		Format:    job.Params["format"],
+	}
+	if err := validate.New().Validate(params); err != nil {
+		return "", fmt.Errorf("invalid export params: %w", err)
+	}
+
+	result := fmt.Sprintf("%s.%s", job.Id, params.Format)
+	err := writeJobFile(h.apps.Job, result, func(w io.Writer) error {
+		writer, err := export.NewWriter(params.Format, w)
+		if err != nil {
+			return err
+		}
+
+		err = h.writeExport(ctx, job.AccountId, params, writer, func(rows int64) {
+			progress(rows, 0)
+		})
+		if err != nil {
+			return err
+		}
+
+		return writer.Close()
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return result, nil
+}
+
+// writeJobFile writes a job result into a temporary file that is renamed when complete,
+// so a partial result is never served
+func writeJobFile(jobs jobApp.App, name string, write func(w io.Writer) error) error {
+	path, err := jobs.FilePath(name)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.Create(path + ".tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create job file: %w", err)
+	}
+
+	if err := write(file); err != nil {
+		file.Close()
+		os.Remove(file.Name())
+		return err
+	}
+
+	if err := file.Close(); err != nil {
+		os.Remove(file.Name())
+		return fmt.Errorf("failed to write job file: %w", err)
+	}
+
+	return os.Rename(file.Name(), path)
+}
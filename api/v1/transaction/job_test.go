@@ -0,0 +1,156 @@
+package transaction
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jcpribeiro/TransactionApp/app"
+	"github.com/jcpribeiro/TransactionApp/app/fiscaldata"
+	"github.com/jcpribeiro/TransactionApp/app/job"
+	"github.com/jcpribeiro/TransactionApp/model"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunExportJob(t *testing.T) {
+	t.Run("this test simulate an export job", func(t *testing.T) {
+		testObj := setUpTest(t)
+		jobApp := job.NewMockApp(gomock.NewController(t))
+		dir := t.TempDir()
+
+		jobApp.EXPECT().FilePath("652d34910a8fc425116b84d9.csv").Return(filepath.Join(dir, "652d34910a8fc425116b84d9.csv"), nil)
+		testObj.transactionApp.EXPECT().ExportTransactionsByPeriod(gomock.Any(), testAccountId, "2023-10-01", "2023-11-01", "", "", gomock.Any()).DoAndReturn(
+			func(ctx context.Context, accountId, startDate, endDate, timezone, field string, fn func(*model.TransactionResponse) error) error {
+				return fn(&model.TransactionResponse{
+					Id:             "652d34910a8fc425116b84d8",
+					PurchaseAmount: 10.00,
+					Description:    "Test1",
+					PurchaseDate:   "2023-10-15",
+				})
+			})
+		testObj.fiscalDataApp.EXPECT().GetRatesOfExchange("Canada-Dollar", "2023-10-15").Return(&fiscaldata.Data{
+			CurrencyDescription: "Canada-Dollar",
+			ExchangeRate:        1.5,
+			RecordDate:          "2023-09-30",
+		}, nil)
+
+		h := handler{
+			apps: &app.Container{
+				FiscalData:  testObj.fiscalDataApp,
+				Transaction: testObj.transactionApp,
+				Job:         jobApp,
+			},
+		}
+
+		var progress int64
+		result, err := h.runExportJob(context.Background(), &model.Job{
+			Id:        "652d34910a8fc425116b84d9",
+			AccountId: testAccountId,
+			Params: map[string]string{
+				"startDate": "2023-10-01",
+				"endDate":   "2023-11-01",
+				"currency":  "Canada-Dollar",
+				"format":    "csv",
+			},
+		}, func(done, total int64) {
+			progress = done
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, result, "652d34910a8fc425116b84d9.csv")
+		assert.Equal(t, progress, int64(1))
+
+		data, _ := os.ReadFile(filepath.Join(dir, result))
+		assert.Contains(t, string(data), "652d34910a8fc425116b84d8,Test1,2023-10-15,10,USD,Canada-Dollar,1.5,2023-09-30,,,15,")
+	})
+
+	t.Run("this test simulate an export job that fails", func(t *testing.T) {
+		testObj := setUpTest(t)
+		jobApp := job.NewMockApp(gomock.NewController(t))
+		dir := t.TempDir()
+
+		jobApp.EXPECT().FilePath("652d34910a8fc425116b84d9.ndjson").Return(filepath.Join(dir, "652d34910a8fc425116b84d9.ndjson"), nil)
+		testObj.transactionApp.EXPECT().ExportTransactionsByPeriod(gomock.Any(), testAccountId, gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(errors.New("an error has ocurred"))
+
+		h := handler{
+			apps: &app.Container{
+				FiscalData:  testObj.fiscalDataApp,
+				Transaction: testObj.transactionApp,
+				Job:         jobApp,
+			},
+		}
+
+		result, err := h.runExportJob(context.Background(), &model.Job{
+			Id:        "652d34910a8fc425116b84d9",
+			AccountId: testAccountId,
+			Params: map[string]string{
+				"startDate": "2023-10-01",
+				"endDate":   "2023-11-01",
+				"currency":  "Canada-Dollar",
+				"format":    "ndjson",
+			},
+		}, func(done, total int64) {})
+
+		assert.Error(t, err)
+		assert.Empty(t, result)
+
+		files, _ := os.ReadDir(dir)
+		assert.Empty(t, files)
+	})
+
+	t.Run("this test simulate an export job with invalid params", func(t *testing.T) {
+		h := handler{}
+
+		result, err := h.runExportJob(context.Background(), &model.Job{
+			Id:     "652d34910a8fc425116b84d9",
+			Params: map[string]string{"format": "pdf"},
+		}, func(done, total int64) {})
+
+		assert.Error(t, err)
+		assert.Empty(t, result)
+	})
+}
+
+func TestRunImportJob(t *testing.T) {
+	t.Run("this test simulate an import job", func(t *testing.T) {
+		testObj := setUpTest(t)
+		jobApp := job.NewMockApp(gomock.NewController(t))
+		dir := t.TempDir()
+		input := filepath.Join(dir, "upload.csv")
+		os.WriteFile(input, []byte("description,purchase_amount\nTest,1\n"), 0o644)
+
+		jobApp.EXPECT().FilePath("upload.csv").Return(input, nil)
+		jobApp.EXPECT().FilePath("652d34910a8fc425116b84d9.json").Return(filepath.Join(dir, "652d34910a8fc425116b84d9.json"), nil)
+		testObj.transactionApp.EXPECT().ImportTransactions(gomock.Any(), testAccountId, "csv", "job:652d34910a8fc425116b84d9", gomock.Any()).Return(&model.ImportReport{
+			Inserted: 1,
+			Errors:   []*model.ImportError{},
+		}, nil)
+		testObj.cache.EXPECT().Incr(gomock.Any(), testAccountId+":period-generation").Return(int64(4), nil)
+
+		h := handler{
+			apps: &app.Container{
+				Transaction: testObj.transactionApp,
+				Job:         jobApp,
+			},
+			cache: testObj.cache,
+		}
+
+		result, err := h.runImportJob(context.Background(), &model.Job{
+			Id:        "652d34910a8fc425116b84d9",
+			AccountId: testAccountId,
+			Params:    map[string]string{"file": "upload.csv", "format": "csv"},
+		}, func(done, total int64) {})
+
+		assert.NoError(t, err)
+		assert.Equal(t, result, "652d34910a8fc425116b84d9.json")
+
+		data, _ := os.ReadFile(filepath.Join(dir, result))
+		assert.JSONEq(t, `{"job_id":"652d34910a8fc425116b84d9","inserted":1,"failed":0,"errors":[]}`, string(data))
+		_, err = os.Stat(input)
+		assert.True(t, os.IsNotExist(err))
+	})
+}
@@ -1,45 +1,156 @@
 package transaction
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"io"
+	"mime"
 	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 	"github.com/jcpribeiro/TransactionApp/app"
+	"github.com/jcpribeiro/TransactionApp/app/fiscaldata"
+	"github.com/jcpribeiro/TransactionApp/app/transaction"
+	"github.com/jcpribeiro/TransactionApp/internal/auth"
 	"github.com/jcpribeiro/TransactionApp/internal/cache"
+	"github.com/jcpribeiro/TransactionApp/internal/export"
+	"github.com/jcpribeiro/TransactionApp/internal/httpcache"
+	"github.com/jcpribeiro/TransactionApp/internal/ratelimit"
+	"github.com/jcpribeiro/TransactionApp/internal/tenant"
 	"github.com/jcpribeiro/TransactionApp/internal/util"
 	"github.com/jcpribeiro/TransactionApp/model"
 
 	"github.com/labstack/echo/v4"
+	emiddleware "github.com/labstack/echo/v4/middleware"
 	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
-// Register group transaction
-func Register(g *echo.Group, apps *app.Container, cache cache.Cache) {
+// Register group transaction.
+// Every read converts currencies through the Treasury api and is limited as a conversion.
+func Register(g *echo.Group, apps *app.Container, cache cache.Cache, policies map[string]cache.Policy, limits *ratelimit.Policy) {
 	h := &handler{
-		apps:  apps,
-		cache: cache,
+		apps:     apps,
+		cache:    cache,
+		policies: policies,
 	}
 
-	g.POST("", h.insertTransactions)
-	g.GET("", h.getTransactions)
-	g.GET("/period", h.getTransactionsByPeriod)
-	g.GET("/epoch-period", h.getTransactionsByPeriodEpoch)
+	read := auth.RequireScope(auth.ScopeTransactionRead)
+	write := auth.RequireScope(auth.ScopeTransactionWrite)
+	conversionLimit := limits.Middleware(ratelimit.ClassConversion)
+	writeLimit := limits.Middleware(ratelimit.ClassWrite)
+
+	g.POST("", h.insertTransactions, write, writeLimit)
+	g.GET("", h.getTransactions, read, conversionLimit)
+	g.GET("/period", h.getTransactionsByPeriod, read, conversionLimit)
+	g.GET("/epoch-period", h.getTransactionsByPeriodEpoch, read, conversionLimit)
+	g.GET("/summary", h.getTransactionsSummary, read, conversionLimit)
+	g.GET("/search", h.searchTransactions, read, conversionLimit)
+	g.POST("/import", h.importTransactions, write, writeLimit, emiddleware.BodyLimit(importBodyLimit))
+	g.GET("/export", h.exportTransactions, read, conversionLimit)
+
+	apps.Job.RegisterRunner(JobTypeImport, h.runImportJob)
+	apps.Job.RegisterRunner(JobTypeExport, h.runExportJob)
+}
+
+const (
+	importBodyLimit = "1G"
+	exportFlushRows = 1000
+
+	missingTransactionTTL = time.Minute
+
+	mimeTextCSV = "text/csv"
+	mimeNDJSON  = "application/x-ndjson"
+)
+
+// Cache policies of the endpoints, configured in cache.endpoints
+const (
+	CachePolicyTransaction = "transaction"
+	CachePolicyPeriod      = "period"
+	CachePolicyEpochPeriod = "epoch_period"
+)
+
+// defaultCachePolicies apply to the endpoints missing from the configuration. A stored transaction and
+// its historical rate never change, a period changes when a transaction is inserted.
+var defaultCachePolicies = map[string]cache.Policy{
+	CachePolicyTransaction: {TTL: 24 * time.Hour, MaxAge: 5 * time.Minute},
+	CachePolicyPeriod:      {TTL: 5 * time.Minute},
+	CachePolicyEpochPeriod: {TTL: 5 * time.Minute},
 }
 
 type handler struct {
-	apps  *app.Container
-	cache cache.Cache
+	apps     *app.Container
+	cache    cache.Cache
+	policies map[string]cache.Policy
+}
+
+func (h *handler) policy(name string) cache.Policy {
+	if policy, ok := h.policies[name]; ok {
+		return policy
+	}
+
+	return defaultCachePolicies[name]
+}
+
+// cachedResponse runs load through the cache when the policy has a TTL and key is set. load must also
+// assign the response it returns, as a cache hit decodes into value instead.
+func (h *handler) cachedResponse(ctx context.Context, policy, key string, value interface{}, load cache.Loader) error {
+	if ttl := h.policy(policy).TTL; ttl > 0 && len(key) > 0 {
+		return h.cache.GetOrSet(ctx, key, value, ttl, load)
+	}
+
+	_, err := load(ctx)
+	return err
+}
+
+// invalidatePeriods makes the cached periods of the account stale after an insert
+func (h *handler) invalidatePeriods(ctx context.Context, accountId string) {
+	InvalidatePeriods(ctx, h.cache, accountId)
+}
+
+// InvalidatePeriods moves the cached periods of the account to a new generation. The entries of the
+// previous generations are no longer read and expire with their TTL.
+func InvalidatePeriods(ctx context.Context, c cache.Cache, accountId string) {
+	if _, err := c.Incr(ctx, periodGenerationKey(accountId)); err != nil {
+		logrus.Error(err)
+	}
+}
+
+// periodGeneration reads the generation of the cached periods of the account, false when the policy
+// does not cache or it cannot be read, as the cache may then serve a period from before the last insert
+func (h *handler) periodGeneration(ctx context.Context, policy, accountId string) (int64, bool) {
+	if h.policy(policy).TTL <= 0 {
+		return 0, false
+	}
+
+	generation, err := h.cache.Counter(ctx, periodGenerationKey(accountId))
+	if err != nil {
+		logrus.Error(err)
+		return 0, false
+	}
+
+	return generation, true
 }
 
 // insertTransactions swagger document
 // @Summary Store a purchase transaction
+// @Description The source_currency of a purchase defaults to USD. Other currencies are converted through USD.
+// @Description A transaction breaking a business rule is rejected with 400 and the rule that failed.
 // @Tags transaction
 // @Accept  json
 // @Produce  json
 // @Param transaction body []model.Transaction true "add new transaction"
 // @Success 200 {array} string
+// @Failure 400 {object} string
 // @Failure 401 {object} string
+// @Failure 403 {object} string
+// @Failure 429 {object} string
+// @Security ApiKeyAuth
+// @Security BearerAuth
 // @Router /v1/transaction [post]
 func (h *handler) insertTransactions(c echo.Context) error {
 	var transactions []*model.Transaction
@@ -50,25 +161,118 @@ func (h *handler) insertTransactions(c echo.Context) error {
 		})
 	}
 
-	response, err := h.apps.Transaction.InsertTransactions(c.Request().Context(), transactions)
+	response, err := h.apps.Transaction.InsertTransactions(c.Request().Context(), tenant.Account(c), transactions)
+	if errors.Is(err, transaction.ErrInvalidTransaction) {
+		body := map[string]string{
+			"error": err.Error(),
+		}
+		var ruleErr *transaction.RuleError
+		if errors.As(err, &ruleErr) {
+			body["rule"] = ruleErr.Rule
+		}
+		return c.JSON(http.StatusBadRequest, body)
+	}
 	if err != nil {
 		return err
 	}
+	h.invalidatePeriods(c.Request().Context(), tenant.Account(c))
 
 	return c.JSON(http.StatusOK, map[string][]string{
 		"ids": response,
 	})
 }
 
+// importTransactions swagger document
+// @Summary Import purchase transactions from a csv or ndjson file
+// @Description The csv must have a header with the purchase_amount and description columns, in any order.
+// @Description The purchase_date, purchased_at, source_currency, category, merchant and tags columns are optional, tags are separated by semicolons.
+// @Description A row without purchase_date and purchased_at is purchased at the current UTC time.
+// @Description Invalid rows are reported by line and do not fail the whole import.
+// @Tags transaction
+// @Accept  text/csv
+// @Accept  application/x-ndjson
+// @Produce  json
+// @Param file body string true "Transactions file"
+// @Param async query bool false "Run the import as a job. The response is the job and its result is the import report"
+// @Success 200 {object} model.ImportReport
+// @Success 202 {object} model.Job
+// @Failure 401 {object} string
+// @Failure 403 {object} string
+// @Failure 415 {object} string
+// @Failure 429 {object} string
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /v1/transaction/import [post]
+func (h *handler) importTransactions(c echo.Context) error {
+	var format string
+	mediaType, _, _ := mime.ParseMediaType(c.Request().Header.Get(echo.HeaderContentType))
+	switch mediaType {
+	case mimeTextCSV:
+		format = transaction.ImportFormatCSV
+	case mimeNDJSON:
+		format = transaction.ImportFormatNDJSON
+	default:
+		return c.JSON(http.StatusUnsupportedMediaType, map[string]string{
+			"error": "content type must be text/csv or application/x-ndjson",
+		})
+	}
+
+	if c.QueryParam("async") == "true" {
+		return h.submitImportJob(c, format)
+	}
+
+	response, err := h.apps.Transaction.ImportTransactions(c.Request().Context(), tenant.Account(c), format, "", c.Request().Body)
+	h.invalidatePeriods(c.Request().Context(), tenant.Account(c))
+	if errors.Is(err, echo.ErrStatusRequestEntityTooLarge) {
+		return err
+	}
+	if err != nil {
+		logrus.Error(err)
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid file",
+		})
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+// submitImportJob saves the uploaded file in the jobs directory and submits its import
+func (h *handler) submitImportJob(c echo.Context, format string) error {
+	name := fmt.Sprintf("%s.%s", primitive.NewObjectID().Hex(), format)
+	err := writeJobFile(h.apps.Job, name, func(w io.Writer) error {
+		_, err := io.Copy(w, c.Request().Body)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	response, err := h.apps.Job.SubmitJob(c.Request().Context(), tenant.Account(c), JobTypeImport, map[string]string{
+		"file":   name,
+		"format": format,
+	})
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusAccepted, response)
+}
+
 // getTransactions swagger document
 // @Summary Retrive stored a purchase transaction
 // @Tags transaction
 // @Accept  json
 // @Produce  json
 // @Param ids query string true "Transactions ids. If more than one id is provided, it must be separated by a comma. E.g. id1,id2"
-// @Param currency query string true "Currency ids. E.g. Argentina-Peso"
-// @Success 200 {array} model.TransactionResponse
+// @Param currency query string true "Currency ids. If more than one currency is provided, it must be separated by a comma. E.g. Euro Zone-Euro,United Kingdom-Pound"
+// @Param If-None-Match header string false "ETag of a previous response"
+// @Success 200 {object} model.GetTransactionsResponse
+// @Success 304 {string} string "The response did not change"
 // @Failure 401 {object} string
+// @Failure 403 {object} string
+// @Failure 429 {object} string
+// @Security ApiKeyAuth
+// @Security BearerAuth
 // @Router /v1/transaction [get]
 func (h *handler) getTransactions(c echo.Context) error {
 	params := new(model.GetTransactionParams)
@@ -87,50 +291,131 @@ func (h *handler) getTransactions(c echo.Context) error {
 		})
 	}
 
-	ids := strings.Split(strings.ReplaceAll(params.Ids, " ", ""), ",")
-	var err error
-	response := make([]*model.TransactionResponse, 0, len(ids))
+	accountId := tenant.Account(c)
+	ids := splitList(params.Ids)
+	currencies := splitList(params.Currency)
+	cacheKey := currencyCacheKey(currencies)
+
+	cached, missing := h.getCachedTransactions(c.Request().Context(), accountId, ids, cacheKey)
+
+	// ids are fetched once even when repeated, the invalid ones are reported as not found
+	fetch := make(map[string]bool)
+	var fetchIds []string
 	for _, id := range ids {
-		var value *model.TransactionResponse
-		h.cache.Get(c.Request().Context(), fmt.Sprintf("%s:transaction:%s", id, params.Currency), &value)
-		if value != nil {
-			response = append(response, value)
+		if _, ok := cached[id]; ok || missing[id] || fetch[id] {
+			continue
 		}
+		if _, err := primitive.ObjectIDFromHex(id); err != nil {
+			continue
+		}
+		fetch[id] = true
+		fetchIds = append(fetchIds, id)
 	}
 
-	if len(response) != len(ids) {
-		response, err = h.apps.Transaction.GetTransactions(c.Request().Context(), ids)
+	if len(fetchIds) > 0 {
+		fetched, err := h.apps.Transaction.GetTransactions(c.Request().Context(), accountId, fetchIds)
 		if err != nil {
 			return err
 		}
 
-		for _, r := range response {
-			data, err := h.apps.FiscalData.GetRatesOfExchange(params.Currency, r.PurchaseDate)
-			if err != nil || data == nil {
-				return fmt.Errorf("failed to get rates exchange")
+		converted := make(map[string]interface{}, len(fetched))
+		for _, r := range fetched {
+			delete(fetch, r.Id)
+			cached[r.Id] = r
+			if fiscaldata.ConvertTransaction(h.apps.FiscalData, r, currencies) {
+				converted[transactionCacheKey(accountId, r.Id, cacheKey)] = r
+			}
+		}
+		if len(converted) > 0 {
+			if err := h.cache.MSet(c.Request().Context(), converted, h.policy(CachePolicyTransaction).TTL); err != nil {
+				logrus.Error(err)
+			}
+		}
+
+		notFound := make(map[string]interface{}, len(fetch))
+		for id := range fetch {
+			notFound[transactionMissingCacheKey(accountId, id)] = true
+		}
+		if len(notFound) > 0 {
+			if err := h.cache.MSet(c.Request().Context(), notFound, missingTransactionTTL); err != nil {
+				logrus.Error(err)
 			}
-			r.PurchaseAmount = util.RoundFloat(r.PurchaseAmount, 2)
-			r.ExchangeRate = util.RoundFloat(data.ExchangeRate, 2)
-			r.ConvertedPurchaseAmount = util.RoundFloat(r.PurchaseAmount*data.ExchangeRate, 2)
-			h.cache.Set(c.Request().Context(), fmt.Sprintf("%s:transaction:%s", r.Id, params.Currency), r, 5*time.Minute)
 		}
 	}
 
-	return c.JSON(http.StatusOK, map[string][]*model.TransactionResponse{
-		"ids": response,
+	response := &model.GetTransactionsResponse{
+		Ids: make([]*model.TransactionResponse, 0, len(ids)),
+	}
+	for _, id := range ids {
+		if r, ok := cached[id]; ok {
+			response.Ids = append(response.Ids, r)
+		} else {
+			response.NotFound = append(response.NotFound, id)
+		}
+	}
+
+	return httpcache.JSON(c, response, h.policy(CachePolicyTransaction).MaxAge, lastModified(response.Ids))
+}
+
+// getCachedTransactions reads in one round trip the converted transactions and the ids known not to exist
+func (h *handler) getCachedTransactions(ctx context.Context, accountId string, ids []string, cacheKey string) (map[string]*model.TransactionResponse, map[string]bool) {
+	keys := make([]string, 0, 2*len(ids))
+	for _, id := range ids {
+		keys = append(keys, transactionCacheKey(accountId, id, cacheKey))
+	}
+	for _, id := range ids {
+		keys = append(keys, transactionMissingCacheKey(accountId, id))
+	}
+
+	values := make([]*model.TransactionResponse, len(ids))
+	flags := make([]bool, len(ids))
+	found, err := h.cache.MGet(ctx, keys, func(i int) interface{} {
+		if i < len(ids) {
+			return &values[i]
+		}
+		return &flags[i-len(ids)]
 	})
+	if err != nil {
+		logrus.Error(err)
+	}
+
+	cached := make(map[string]*model.TransactionResponse, len(ids))
+	missing := make(map[string]bool)
+	for i, id := range ids {
+		if i < len(found) && found[i] && values[i] != nil {
+			cached[id] = values[i]
+		}
+		if len(ids)+i < len(found) && found[len(ids)+i] && flags[i] {
+			missing[id] = true
+		}
+	}
+
+	return cached, missing
 }
 
 // getTransactionsByPeriod swagger document
 // @Summary Retrive stored a purchase transaction by period
+// @Description Both dates are included. Transactions with a purchase time are matched in the timezone, the others by their purchase date.
 // @Tags transaction
 // @Accept  json
 // @Produce  json
-// @Param startDate query string true "Period start date. E.g. 2023-10-12"
-// @Param endDate query string true "Period end date. E.g. 2023-10-14"
+// @Param startDate query string true "Period start date, inclusive. E.g. 2023-10-12"
+// @Param endDate query string true "Period end date, inclusive. E.g. 2023-10-14"
+// @Param timezone query string false "IANA timezone of the period dates. Default UTC. E.g. America/Sao_Paulo"
+// @Param field query string false "Time the period filters on. One of purchase or created. Default purchase. E.g. created"
 // @Param currency query string true "Currency ids. E.g. Argentina-Peso"
+// @Param category query string false "Transaction category. E.g. food"
+// @Param tags query string false "Tags the transactions must all have, separated by a comma. E.g. work,travel"
+// @Param merchant query string false "Merchant name. E.g. Coffee Shop"
+// @Param metadata query string false "Metadata key:value pairs the transactions must all have, separated by a comma. E.g. project:alpha"
+// @Param If-None-Match header string false "ETag of a previous response"
 // @Success 200 {array} model.TransactionResponse
+// @Success 304 {string} string "The response did not change"
 // @Failure 401 {object} string
+// @Failure 403 {object} string
+// @Failure 429 {object} string
+// @Security ApiKeyAuth
+// @Security BearerAuth
 // @Router /v1/transaction/period [get]
 func (h *handler) getTransactionsByPeriod(c echo.Context) error {
 	params := new(model.GetTransactionParamsByPeriod)
@@ -149,24 +434,34 @@ func (h *handler) getTransactionsByPeriod(c echo.Context) error {
 		})
 	}
 
-	response, err := h.apps.Transaction.GetTransactionsByPeriod(c.Request().Context(), params.StartDate, params.EndDate)
-	if err != nil {
-		return err
+	accountId := tenant.Account(c)
+	var key string
+	if generation, ok := h.periodGeneration(c.Request().Context(), CachePolicyPeriod, accountId); ok {
+		key = periodCacheKey(accountId, generation, "date", params.StartDate, params.EndDate, params.Currency, params.Timezone, params.Field, &params.AttributeParams)
 	}
 
-	for _, r := range response {
-		data, err := h.apps.FiscalData.GetRatesOfExchange(params.Currency, r.PurchaseDate)
-		if err != nil || data == nil {
-			return fmt.Errorf("failed to get rates exchange")
+	var response []*model.TransactionResponse
+	err := h.cachedResponse(c.Request().Context(), CachePolicyPeriod, key, &response, func(ctx context.Context) (interface{}, error) {
+		transactions, err := h.apps.Transaction.GetTransactionsByPeriod(ctx, accountId, params.StartDate, params.EndDate, params.Timezone, params.Field, &params.AttributeParams)
+		if err != nil {
+			return nil, err
 		}
-		r.PurchaseAmount = util.RoundFloat(r.PurchaseAmount, 2)
-		r.ExchangeRate = util.RoundFloat(data.ExchangeRate, 2)
-		r.ConvertedPurchaseAmount = util.RoundFloat(r.PurchaseAmount*data.ExchangeRate, 2)
+
+		response = transactions
+		return transactions, h.convertPeriod(transactions, params.Currency)
+	})
+	if errors.Is(err, transaction.ErrInvalidFilter) {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": err.Error(),
+		})
+	}
+	if err != nil {
+		return err
 	}
 
-	return c.JSON(http.StatusOK, map[string][]*model.TransactionResponse{
+	return httpcache.JSON(c, map[string][]*model.TransactionResponse{
 		"ids": response,
-	})
+	}, h.policy(CachePolicyPeriod).MaxAge, lastModified(response))
 }
 
 // getTransactionsByPeriodEpoch swagger document
@@ -174,11 +469,22 @@ func (h *handler) getTransactionsByPeriod(c echo.Context) error {
 // @Tags transaction
 // @Accept  json
 // @Produce  json
-// @Param startDate query string true "Period start date. E.g. 1697150153"
-// @Param endDate query string true "Period end date. E.g. 1697409353"
+// @Param startDate query string true "Period start time in unix seconds, inclusive. E.g. 1697150153"
+// @Param endDate query string true "Period end time in unix seconds, inclusive. E.g. 1697409353"
+// @Param field query string false "Time the period filters on. One of purchase or created. Default purchase. E.g. created"
 // @Param currency query string true "Currency ids. E.g. Argentina-Peso"
+// @Param category query string false "Transaction category. E.g. food"
+// @Param tags query string false "Tags the transactions must all have, separated by a comma. E.g. work,travel"
+// @Param merchant query string false "Merchant name. E.g. Coffee Shop"
+// @Param metadata query string false "Metadata key:value pairs the transactions must all have, separated by a comma. E.g. project:alpha"
+// @Param If-None-Match header string false "ETag of a previous response"
 // @Success 200 {array} model.TransactionResponse
+// @Success 304 {string} string "The response did not change"
 // @Failure 401 {object} string
+// @Failure 403 {object} string
+// @Failure 429 {object} string
+// @Security ApiKeyAuth
+// @Security BearerAuth
 // @Router /v1/transaction/epoch-period [get]
 func (h *handler) getTransactionsByPeriodEpoch(c echo.Context) error {
 	params := new(model.GetTransactionParamsByPeriodEpoch)
@@ -197,22 +503,370 @@ func (h *handler) getTransactionsByPeriodEpoch(c echo.Context) error {
 		})
 	}
 
-	response, err := h.apps.Transaction.GetTransactionsByPeriodEpoch(c.Request().Context(), params.StartDate, params.EndDate)
+	accountId := tenant.Account(c)
+	var key string
+	if generation, ok := h.periodGeneration(c.Request().Context(), CachePolicyEpochPeriod, accountId); ok {
+		key = periodCacheKey(accountId, generation, "epoch", strconv.FormatInt(params.StartDate, 10), strconv.FormatInt(params.EndDate, 10), params.Currency, "", params.Field, &params.AttributeParams)
+	}
+
+	var response []*model.TransactionResponse
+	err := h.cachedResponse(c.Request().Context(), CachePolicyEpochPeriod, key, &response, func(ctx context.Context) (interface{}, error) {
+		transactions, err := h.apps.Transaction.GetTransactionsByPeriodEpoch(ctx, accountId, params.StartDate, params.EndDate, params.Field, &params.AttributeParams)
+		if err != nil {
+			return nil, err
+		}
+
+		response = transactions
+		return transactions, h.convertPeriod(transactions, params.Currency)
+	})
+	if errors.Is(err, transaction.ErrInvalidFilter) {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": err.Error(),
+		})
+	}
 	if err != nil {
 		return err
 	}
 
-	for _, r := range response {
-		data, err := h.apps.FiscalData.GetRatesOfExchange(params.Currency, r.PurchaseDate)
-		if err != nil || data == nil {
+	return httpcache.JSON(c, map[string][]*model.TransactionResponse{
+		"ids": response,
+	}, h.policy(CachePolicyEpochPeriod).MaxAge, lastModified(response))
+}
+
+// convertPeriod converts the transactions of a period, failing when a rate is missing
+func (h *handler) convertPeriod(transactions []*model.TransactionResponse, currency string) error {
+	for _, r := range transactions {
+		exchange, err := fiscaldata.Triangulate(h.apps.FiscalData, r.SourceCurrency, currency, r.PurchaseDate)
+		if err != nil {
 			return fmt.Errorf("failed to get rates exchange")
 		}
+		fiscaldata.SetSourceCurrency(r)
 		r.PurchaseAmount = util.RoundFloat(r.PurchaseAmount, 2)
-		r.ExchangeRate = util.RoundFloat(data.ExchangeRate, 2)
-		r.ConvertedPurchaseAmount = util.RoundFloat(r.PurchaseAmount*data.ExchangeRate, 2)
+
+		fiscaldata.SetConversion(r, exchange.Conversion(r.PurchaseAmount))
 	}
 
-	return c.JSON(http.StatusOK, map[string][]*model.TransactionResponse{
-		"ids": response,
+	return nil
+}
+
+// getTransactionsSummary swagger document
+// @Summary Retrive the purchase amount totals of the stored transactions by period
+// @Description The usd totals convert every source currency to USD on its purchase date.
+// @Tags transaction
+// @Accept  json
+// @Produce  json
+// @Param startDate query string true "Period start date, inclusive. E.g. 2023-10-12"
+// @Param endDate query string true "Period end date, inclusive. E.g. 2023-10-14"
+// @Param timezone query string false "IANA timezone of the period dates. Default UTC. E.g. America/Sao_Paulo"
+// @Param field query string false "Time the period filters on. One of purchase or created. Default purchase. E.g. created"
+// @Param currency query string true "Currency ids. E.g. Argentina-Peso"
+// @Param groupBy query string false "Period grouping. One of day, week or month. E.g. month"
+// @Success 200 {array} model.TransactionSummary
+// @Failure 401 {object} string
+// @Failure 403 {object} string
+// @Failure 429 {object} string
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /v1/transaction/summary [get]
+func (h *handler) getTransactionsSummary(c echo.Context) error {
+	params := new(model.GetTransactionSummaryParams)
+
+	if err := c.Bind(params); err != nil {
+		logrus.Error(err)
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid query params",
+		})
+	}
+
+	if err := c.Validate(params); err != nil {
+		logrus.Error(err)
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "missing url params",
+		})
+	}
+
+	aggregates, err := h.apps.Transaction.GetTransactionsSummary(c.Request().Context(), tenant.Account(c), params.StartDate, params.EndDate, params.Timezone, params.Field, params.GroupBy)
+	if errors.Is(err, transaction.ErrInvalidFilter) {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": err.Error(),
+		})
+	}
+	if err != nil {
+		return err
+	}
+
+	response, err := fiscaldata.Summarize(h.apps.FiscalData, aggregates, params.Currency)
+	if err != nil {
+		return fmt.Errorf("failed to get rates exchange")
+	}
+
+	return c.JSON(http.StatusOK, map[string][]*model.TransactionSummary{
+		"summary": response,
 	})
 }
+
+// searchTransactions swagger document
+// @Summary Search stored purchase transactions by description, amount and dates
+// @Tags transaction
+// @Accept  json
+// @Produce  json
+// @Param text query string false "Words searched in the description. E.g. coffee"
+// @Param minAmount query number false "Minimum purchase amount in its source currency. E.g. 10.5"
+// @Param maxAmount query number false "Maximum purchase amount in its source currency. E.g. 100"
+// @Param startDate query string false "Period start date, inclusive. E.g. 2023-10-12"
+// @Param endDate query string false "Period end date, inclusive. E.g. 2023-10-14"
+// @Param timezone query string false "IANA timezone of the period dates. Default UTC. E.g. America/Sao_Paulo"
+// @Param field query string false "Time the period filters on. One of purchase or created. Default purchase. E.g. created"
+// @Param purchaseStartDate query string false "First purchase date, inclusive. E.g. 2023-10-12"
+// @Param purchaseEndDate query string false "Last purchase date, inclusive. E.g. 2023-10-14"
+// @Param currency query string true "Currency ids. If more than one currency is provided, it must be separated by a comma. E.g. Euro Zone-Euro,United Kingdom-Pound"
+// @Param page query int false "Page number, starting at 1. E.g. 1"
+// @Param pageSize query int false "Page size, up to 100. Default 20. E.g. 20"
+// @Param category query string false "Transaction category. E.g. food"
+// @Param tags query string false "Tags the transactions must all have, separated by a comma. E.g. work,travel"
+// @Param merchant query string false "Merchant name. E.g. Coffee Shop"
+// @Param metadata query string false "Metadata key:value pairs the transactions must all have, separated by a comma. E.g. project:alpha"
+// @Success 200 {array} model.TransactionResponse
+// @Failure 401 {object} string
+// @Failure 403 {object} string
+// @Failure 429 {object} string
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /v1/transaction/search [get]
+func (h *handler) searchTransactions(c echo.Context) error {
+	params := new(model.SearchTransactionParams)
+
+	if err := c.Bind(params); err != nil {
+		logrus.Error(err)
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid query params",
+		})
+	}
+
+	if err := c.Validate(params); err != nil {
+		logrus.Error(err)
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "missing url params",
+		})
+	}
+
+	response, err := h.apps.Transaction.SearchTransactions(c.Request().Context(), tenant.Account(c), params)
+	if errors.Is(err, transaction.ErrInvalidFilter) {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": err.Error(),
+		})
+	}
+	if err != nil {
+		return err
+	}
+
+	currencies := splitList(params.Currency)
+	for _, r := range response {
+		fiscaldata.ConvertTransaction(h.apps.FiscalData, r, currencies)
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"ids":       response,
+		"page":      params.Page,
+		"page_size": params.PageSize,
+	})
+}
+
+// exportTransactions swagger document
+// @Summary Export the converted purchase transactions of a period as csv, ndjson or xlsx
+// @Description Rows whose exchange rate is not available carry the error column instead of the conversion.
+// @Tags transaction
+// @Produce  text/csv
+// @Produce  application/x-ndjson
+// @Produce  application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Param startDate query string true "Period start date, inclusive. E.g. 2023-10-01"
+// @Param endDate query string true "Period end date, inclusive. E.g. 2023-10-31"
+// @Param timezone query string false "IANA timezone of the period dates. Default UTC. E.g. America/Sao_Paulo"
+// @Param field query string false "Time the period filters on. One of purchase or created. Default purchase. E.g. created"
+// @Param currency query string true "Currency ids. E.g. Argentina-Peso"
+// @Param format query string true "File format. One of csv, ndjson or xlsx. E.g. csv"
+// @Success 200 {file} file
+// @Failure 401 {object} string
+// @Failure 403 {object} string
+// @Failure 429 {object} string
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /v1/transaction/export [get]
+func (h *handler) exportTransactions(c echo.Context) error {
+	params := new(model.ExportTransactionParams)
+
+	if err := c.Bind(params); err != nil {
+		logrus.Error(err)
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid query params",
+		})
+	}
+
+	if err := c.Validate(params); err != nil {
+		logrus.Error(err)
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "missing url params",
+		})
+	}
+
+	res := c.Response()
+	writer, err := export.NewWriter(params.Format, res)
+	if err != nil {
+		return err
+	}
+
+	res.Header().Set(echo.HeaderContentType, export.ContentType(params.Format))
+	res.Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=transactions_%s_%s.%s", params.StartDate, params.EndDate, params.Format))
+
+	err = h.writeExport(c.Request().Context(), tenant.Account(c), params, writer, func(rows int64) {
+		if rows%exportFlushRows == 0 {
+			res.Flush()
+		}
+	})
+	if err != nil && !res.Committed {
+		res.Header().Del(echo.HeaderContentDisposition)
+		res.Header().Del(echo.HeaderContentType)
+		if errors.Is(err, transaction.ErrInvalidFilter) {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": err.Error(),
+			})
+		}
+		return err
+	}
+	if err != nil {
+		// the status was already sent, the client gets a truncated file
+		logrus.Error(fmt.Errorf("failed to export transactions: %w", err))
+		return nil
+	}
+
+	if err := writer.Close(); err != nil {
+		logrus.Error(fmt.Errorf("failed to close export: %w", err))
+	}
+
+	return nil
+}
+
+// writeExport converts the transactions of the period and writes them as export rows.
+// onRow is called with the number of rows written so far.
+func (h *handler) writeExport(ctx context.Context, accountId string, params *model.ExportTransactionParams, writer export.Writer, onRow func(rows int64)) error {
+	// the rates of a source currency and purchase date are fetched once for the whole export
+	exchanges := make(map[string]*fiscaldata.Exchange)
+	var rows int64
+	return h.apps.Transaction.ExportTransactionsByPeriod(ctx, accountId, params.StartDate, params.EndDate, params.Timezone, params.Field, func(t *model.TransactionResponse) error {
+		fiscaldata.SetSourceCurrency(t)
+		row := &model.ExportRow{
+			Id:             t.Id,
+			Description:    t.Description,
+			PurchaseDate:   t.PurchaseDate,
+			PurchaseAmount: util.RoundFloat(t.PurchaseAmount, 2),
+			SourceCurrency: t.SourceCurrency,
+			Currency:       params.Currency,
+		}
+
+		key := exchangeKey(t.SourceCurrency, t.PurchaseDate)
+		exchange, ok := exchanges[key]
+		if !ok {
+			var err error
+			exchange, err = fiscaldata.Triangulate(h.apps.FiscalData, t.SourceCurrency, params.Currency, t.PurchaseDate)
+			if err != nil {
+				logrus.Error(err)
+			}
+			exchanges[key] = exchange
+		}
+
+		if exchange == nil {
+			row.Error = "failed to get rates exchange"
+		} else {
+			conversion := exchange.Conversion(row.PurchaseAmount)
+			row.ExchangeRate = conversion.ExchangeRate
+			row.RecordDate = conversion.RecordDate
+			row.SourceExchangeRate = conversion.SourceExchangeRate
+			row.SourceRecordDate = conversion.SourceRecordDate
+			row.ConvertedPurchaseAmount = conversion.ConvertedPurchaseAmount
+		}
+
+		if err := writer.Write(row); err != nil {
+			return err
+		}
+
+		rows++
+		onRow(rows)
+		return nil
+	})
+}
+
+func exchangeKey(sourceCurrency, purchaseDate string) string {
+	return fmt.Sprintf("%s:%s", sourceCurrency, purchaseDate)
+}
+
+// splitList splits a comma separated query param, ignoring blanks and duplicates
+func splitList(value string) []string {
+	items := strings.Split(value, ",")
+	list := make([]string, 0, len(items))
+	seen := make(map[string]bool, len(items))
+	for _, item := range items {
+		item = strings.TrimSpace(item)
+		if len(item) == 0 || seen[item] {
+			continue
+		}
+		seen[item] = true
+		list = append(list, item)
+	}
+
+	return list
+}
+
+// periodCacheKey starts with the generation of the periods of the account and ends with the timezone, field
+// and attribute filters as an encoded query, so any filter value fits in the key
+func periodCacheKey(accountId string, generation int64, kind, start, end, currency, timezone, field string, attributes *model.AttributeParams) string {
+	filters := url.Values{}
+	for name, value := range map[string]string{
+		"timezone": timezone,
+		"field":    field,
+		"category": attributes.Category,
+		"tags":     attributes.Tags,
+		"merchant": attributes.Merchant,
+		"metadata": attributes.Metadata,
+	} {
+		if len(value) > 0 {
+			filters.Set(name, value)
+		}
+	}
+
+	return fmt.Sprintf("%s:period:%d:%s:%s:%s:%s:%s", accountId, generation, kind, start, end, currency, filters.Encode())
+}
+
+// periodGenerationKey is the counter of the generation of the cached periods of an account, incremented on every insert
+func periodGenerationKey(accountId string) string {
+	return fmt.Sprintf("%s:period-generation", accountId)
+}
+
+// lastModified is the insertion time of the newest transaction, taken from its id
+func lastModified(transactions []*model.TransactionResponse) time.Time {
+	var last time.Time
+	for _, r := range transactions {
+		objectID, err := primitive.ObjectIDFromHex(r.Id)
+		if err == nil && objectID.Timestamp().After(last) {
+			last = objectID.Timestamp()
+		}
+	}
+
+	return last
+}
+
+// transactionMissingCacheKey marks an id that does not exist, so repeated requests do not reach the database
+func transactionMissingCacheKey(accountId, id string) string {
+	return fmt.Sprintf("%s:transaction:%s:missing", accountId, id)
+}
+
+// transactionCacheKey builds the cache key of a converted transaction, scoped to its account
+func transactionCacheKey(accountId, id, currencies string) string {
+	return fmt.Sprintf("%s:transaction:%s:%s", accountId, id, currencies)
+}
+
+// currencyCacheKey builds a key that does not depend on the currencies order
+func currencyCacheKey(currencies []string) string {
+	sorted := append([]string{}, currencies...)
+	sort.Strings(sorted)
+	return strings.Join(sorted, ",")
+}
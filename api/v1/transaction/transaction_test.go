@@ -1,17 +1,22 @@
 package transaction
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/jcpribeiro/TransactionApp/app"
 	"github.com/jcpribeiro/TransactionApp/app/fiscaldata"
 	"github.com/jcpribeiro/TransactionApp/app/transaction"
 	"github.com/jcpribeiro/TransactionApp/internal/cache"
+	"github.com/jcpribeiro/TransactionApp/internal/httpcache"
+	"github.com/jcpribeiro/TransactionApp/internal/tenant"
 	"github.com/jcpribeiro/TransactionApp/internal/validate"
 	"github.com/jcpribeiro/TransactionApp/model"
 
@@ -20,6 +25,8 @@ import (
 	"github.com/stretchr/testify/assert"
 )
 
+const testAccountId = "652d34910a8fc425116b84aa"
+
 type strucTest struct {
 	echo           *echo.Echo
 	transactionApp *transaction.MockApp
@@ -43,6 +50,12 @@ func setUpTest(t *testing.T) strucTest {
 	}
 }
 
+// loadThrough makes the cache mock miss and run the loader
+func loadThrough(ctx context.Context, key string, value interface{}, expirationTime time.Duration, load cache.Loader) error {
+	_, err := load(ctx)
+	return err
+}
+
 func TestInsertTransactions(t *testing.T) {
 	t.Run("this test simulate a successful transaction insert", func(t *testing.T) {
 		testObj := setUpTest(t)
@@ -59,16 +72,19 @@ func TestInsertTransactions(t *testing.T) {
 		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
 
 		idList := []string{"652d34910a8fc425116b84d9"}
-		testObj.transactionApp.EXPECT().InsertTransactions(gomock.Any(), gomock.Any()).Return(idList, nil)
+		testObj.transactionApp.EXPECT().InsertTransactions(gomock.Any(), testAccountId, gomock.Any()).Return(idList, nil)
+		testObj.cache.EXPECT().Incr(gomock.Any(), testAccountId+":period-generation").Return(int64(4), nil)
 
 		h := handler{
 			apps: &app.Container{
 				FiscalData:  testObj.fiscalDataApp,
 				Transaction: testObj.transactionApp,
 			},
+			cache: testObj.cache,
 		}
 
 		ctx := testObj.echo.NewContext(req, rec)
+		tenant.SetAccount(ctx, testAccountId)
 		err := h.insertTransactions(ctx)
 
 		var resp interface{}
@@ -92,7 +108,7 @@ func TestInsertTransactions(t *testing.T) {
 		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
 
 		idList := []string{}
-		testObj.transactionApp.EXPECT().InsertTransactions(gomock.Any(), gomock.Any()).Return(idList, errors.New("an error has ocurred"))
+		testObj.transactionApp.EXPECT().InsertTransactions(gomock.Any(), testAccountId, gomock.Any()).Return(idList, errors.New("an error has ocurred"))
 
 		h := handler{
 			apps: &app.Container{
@@ -102,6 +118,7 @@ func TestInsertTransactions(t *testing.T) {
 		}
 
 		ctx := testObj.echo.NewContext(req, rec)
+		tenant.SetAccount(ctx, testAccountId)
 		err := h.insertTransactions(ctx)
 
 		var resp interface{}
@@ -109,6 +126,141 @@ func TestInsertTransactions(t *testing.T) {
 		assert.Empty(t, resp)
 		assert.Error(t, err)
 	})
+
+	t.Run("this test simulate a transaction insert with an invalid transaction", func(t *testing.T) {
+		testObj := setUpTest(t)
+		body, _ := json.Marshal([]*model.Transaction{{PurchaseAmount: 23.7}})
+		req := httptest.NewRequest(http.MethodPost, "/v1/transaction", strings.NewReader(string(body)))
+		rec := httptest.NewRecorder()
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+		testObj.transactionApp.EXPECT().InsertTransactions(gomock.Any(), testAccountId, gomock.Any()).Return(nil, fmt.Errorf("%w 0: description is required", transaction.ErrInvalidTransaction))
+
+		h := handler{
+			apps: &app.Container{
+				FiscalData:  testObj.fiscalDataApp,
+				Transaction: testObj.transactionApp,
+			},
+			cache: testObj.cache,
+		}
+
+		ctx := testObj.echo.NewContext(req, rec)
+		tenant.SetAccount(ctx, testAccountId)
+		err := h.insertTransactions(ctx)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("this test simulate a transaction insert breaking a business rule", func(t *testing.T) {
+		testObj := setUpTest(t)
+		body, _ := json.Marshal([]*model.Transaction{{PurchaseAmount: 23.7, Description: "Test", PurchaseDate: "3023-01-01"}})
+		req := httptest.NewRequest(http.MethodPost, "/v1/transaction", strings.NewReader(string(body)))
+		rec := httptest.NewRecorder()
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+		testObj.transactionApp.EXPECT().InsertTransactions(gomock.Any(), testAccountId, gomock.Any()).Return(nil, fmt.Errorf("%s 0: %w", transaction.ErrInvalidTransaction, &transaction.RuleError{
+			Rule:   transaction.RuleDateWindow,
+			Reason: "purchase_date 3023-01-01 is after 2023-10-16",
+		}))
+
+		h := handler{
+			apps: &app.Container{
+				FiscalData:  testObj.fiscalDataApp,
+				Transaction: testObj.transactionApp,
+			},
+			cache: testObj.cache,
+		}
+
+		ctx := testObj.echo.NewContext(req, rec)
+		tenant.SetAccount(ctx, testAccountId)
+		err := h.insertTransactions(ctx)
+
+		var resp map[string]string
+		json.Unmarshal(rec.Body.Bytes(), &resp)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Equal(t, resp["rule"], transaction.RuleDateWindow)
+	})
+}
+
+func TestImportTransactions(t *testing.T) {
+	t.Run("this test simulate a successful csv import", func(t *testing.T) {
+		testObj := setUpTest(t)
+		body := "description,purchase_amount,purchase_date\nTest,23.70,2023-10-15\n"
+		req := httptest.NewRequest(http.MethodPost, "/v1/transaction/import", strings.NewReader(body))
+		rec := httptest.NewRecorder()
+		req.Header.Set(echo.HeaderContentType, "text/csv; charset=utf-8")
+
+		testObj.transactionApp.EXPECT().ImportTransactions(gomock.Any(), testAccountId, transaction.ImportFormatCSV, "", gomock.Any()).Return(&model.ImportReport{
+			Inserted: 1,
+			Errors:   []*model.ImportError{},
+		}, nil)
+		testObj.cache.EXPECT().Incr(gomock.Any(), testAccountId+":period-generation").Return(int64(4), nil)
+
+		h := handler{
+			apps: &app.Container{
+				FiscalData:  testObj.fiscalDataApp,
+				Transaction: testObj.transactionApp,
+			},
+			cache: testObj.cache,
+		}
+
+		ctx := testObj.echo.NewContext(req, rec)
+		tenant.SetAccount(ctx, testAccountId)
+		err := h.importTransactions(ctx)
+
+		var resp model.ImportReport
+		json.Unmarshal(rec.Body.Bytes(), &resp)
+		assert.NoError(t, err)
+		assert.Equal(t, resp.Inserted, 1)
+	})
+
+	t.Run("this test simulate an import with an unsupported content type", func(t *testing.T) {
+		testObj := setUpTest(t)
+		req := httptest.NewRequest(http.MethodPost, "/v1/transaction/import", strings.NewReader("[]"))
+		rec := httptest.NewRecorder()
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+		h := handler{
+			apps: &app.Container{
+				FiscalData:  testObj.fiscalDataApp,
+				Transaction: testObj.transactionApp,
+			},
+		}
+
+		ctx := testObj.echo.NewContext(req, rec)
+		tenant.SetAccount(ctx, testAccountId)
+		err := h.importTransactions(ctx)
+
+		assert.NoError(t, err)
+		assert.Equal(t, rec.Code, http.StatusUnsupportedMediaType)
+	})
+
+	t.Run("this test simulate an import with an invalid file", func(t *testing.T) {
+		testObj := setUpTest(t)
+		req := httptest.NewRequest(http.MethodPost, "/v1/transaction/import", strings.NewReader(""))
+		rec := httptest.NewRecorder()
+		req.Header.Set(echo.HeaderContentType, "application/x-ndjson")
+
+		testObj.transactionApp.EXPECT().ImportTransactions(gomock.Any(), testAccountId, transaction.ImportFormatNDJSON, "", gomock.Any()).Return(nil, errors.New("an error has ocurred"))
+		testObj.cache.EXPECT().Incr(gomock.Any(), testAccountId+":period-generation").Return(int64(4), nil)
+
+		h := handler{
+			apps: &app.Container{
+				FiscalData:  testObj.fiscalDataApp,
+				Transaction: testObj.transactionApp,
+			},
+			cache: testObj.cache,
+		}
+
+		ctx := testObj.echo.NewContext(req, rec)
+		tenant.SetAccount(ctx, testAccountId)
+		err := h.importTransactions(ctx)
+
+		assert.NoError(t, err)
+		assert.Equal(t, rec.Code, http.StatusBadRequest)
+	})
 }
 
 func TestGetTransactions(t *testing.T) {
@@ -130,14 +282,14 @@ func TestGetTransactions(t *testing.T) {
 		rec := httptest.NewRecorder()
 		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
 
-		testObj.transactionApp.EXPECT().GetTransactions(gomock.Any(), gomock.Any()).Return(payload, nil)
-		testObj.cache.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.Any())
+		testObj.transactionApp.EXPECT().GetTransactions(gomock.Any(), testAccountId, gomock.Any()).Return(payload, nil)
+		testObj.cache.EXPECT().MGet(gomock.Any(), gomock.Any(), gomock.Any()).Return([]bool{false, false}, nil)
 		testObj.fiscalDataApp.EXPECT().GetRatesOfExchange(gomock.Any(), gomock.Any()).Return(&fiscaldata.Data{
 			CurrencyDescription: "Canada-Dollar",
 			ExchangeRate:        1.23,
 			RecordDate:          "2023-10-15",
 		}, nil).AnyTimes()
-		testObj.cache.EXPECT().Set(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+		testObj.cache.EXPECT().MSet(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
 
 		h := handler{
 			apps: &app.Container{
@@ -148,6 +300,7 @@ func TestGetTransactions(t *testing.T) {
 		}
 
 		ctx := testObj.echo.NewContext(req, rec)
+		tenant.SetAccount(ctx, testAccountId)
 		ctx.QueryParams().Add("ids", "652d34910a8fc425116b84d9")
 		ctx.QueryParams().Add("currency", "Canada-Dollar")
 		err := h.getTransactions(ctx)
@@ -158,14 +311,31 @@ func TestGetTransactions(t *testing.T) {
 		assert.NoError(t, err)
 	})
 
-	t.Run("This test simulates an error when obtaining transaction information", func(t *testing.T) {
+	t.Run("This test simulates the process for obtaining transaction information in multiple currencies", func(t *testing.T) {
 		testObj := setUpTest(t)
+		payload := []*model.TransactionResponse{
+			0: {
+				Id:             "652d34910a8fc425116b84d9",
+				PurchaseAmount: 10.00,
+				Description:    "Test1",
+				PurchaseDate:   "2023-10-15",
+			},
+		}
 		req := httptest.NewRequest(http.MethodGet, "/v1/transaction", nil)
 		rec := httptest.NewRecorder()
 		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
 
-		testObj.cache.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.Any())
-		testObj.transactionApp.EXPECT().GetTransactions(gomock.Any(), gomock.Any()).Return(nil, errors.New("an error has ocurred"))
+		testObj.cache.EXPECT().MGet(gomock.Any(), []string{
+			testAccountId + ":transaction:652d34910a8fc425116b84d9:Euro Zone-Euro,Japan-Yen",
+			testAccountId + ":transaction:652d34910a8fc425116b84d9:missing",
+		}, gomock.Any()).Return([]bool{false, false}, nil)
+		testObj.transactionApp.EXPECT().GetTransactions(gomock.Any(), testAccountId, []string{"652d34910a8fc425116b84d9"}).Return(payload, nil)
+		testObj.fiscalDataApp.EXPECT().GetRatesOfExchange("Euro Zone-Euro", "2023-10-15").Return(&fiscaldata.Data{
+			CurrencyDescription: "Euro Zone-Euro",
+			ExchangeRate:        0.93,
+			RecordDate:          "2023-09-30",
+		}, nil)
+		testObj.fiscalDataApp.EXPECT().GetRatesOfExchange("Japan-Yen", "2023-10-15").Return(nil, errors.New("an error has ocurred"))
 
 		h := handler{
 			apps: &app.Container{
@@ -176,42 +346,126 @@ func TestGetTransactions(t *testing.T) {
 		}
 
 		ctx := testObj.echo.NewContext(req, rec)
+		tenant.SetAccount(ctx, testAccountId)
 		ctx.QueryParams().Add("ids", "652d34910a8fc425116b84d9")
-		ctx.QueryParams().Add("currency", "Canada-Dollar")
+		ctx.QueryParams().Add("currency", "Japan-Yen, Euro Zone-Euro")
 		err := h.getTransactions(ctx)
 
-		var resp interface{}
+		var resp map[string][]*model.TransactionResponse
 		json.Unmarshal(rec.Body.Bytes(), &resp)
-		assert.Empty(t, resp)
-		assert.Error(t, err)
+		assert.NoError(t, err)
+		assert.Len(t, resp["ids"], 1)
+		assert.Equal(t, &model.Conversion{
+			ExchangeRate:            0.93,
+			RecordDate:              "2023-09-30",
+			ConvertedPurchaseAmount: 9.3,
+		}, resp["ids"][0].Conversions["Euro Zone-Euro"])
+		assert.NotEmpty(t, resp["ids"][0].Conversions["Japan-Yen"].Error)
+		assert.Zero(t, resp["ids"][0].ConvertedPurchaseAmount)
 	})
-}
 
-func TestGetTransactionsByPeriod(t *testing.T) {
-	t.Run("This test simulates the process for obtaining transaction information by date", func(t *testing.T) {
+	t.Run("This test simulates a single currency filling the top level conversion", func(t *testing.T) {
 		testObj := setUpTest(t)
 		payload := []*model.TransactionResponse{
 			0: {
-				PurchaseAmount: 23.70,
+				Id:             "652d34910a8fc425116b84d9",
+				PurchaseAmount: 10.00,
 				Description:    "Test1",
 				PurchaseDate:   "2023-10-15",
 			},
-			1: {
-				PurchaseAmount: 25.00,
-				Description:    "Test2",
-				PurchaseDate:   "2023-10-14",
+		}
+		req := httptest.NewRequest(http.MethodGet, "/v1/transaction", nil)
+		rec := httptest.NewRecorder()
+
+		testObj.cache.EXPECT().MGet(gomock.Any(), gomock.Any(), gomock.Any()).Return([]bool{false, false}, nil)
+		testObj.transactionApp.EXPECT().GetTransactions(gomock.Any(), testAccountId, []string{"652d34910a8fc425116b84d9"}).Return(payload, nil)
+		testObj.fiscalDataApp.EXPECT().GetRatesOfExchange("Canada-Dollar", "2023-10-15").Return(&fiscaldata.Data{
+			CurrencyDescription: "Canada-Dollar",
+			ExchangeRate:        1.37,
+			RecordDate:          "2023-09-30",
+		}, nil)
+		testObj.cache.EXPECT().MSet(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+
+		h := handler{
+			apps: &app.Container{
+				FiscalData:  testObj.fiscalDataApp,
+				Transaction: testObj.transactionApp,
+			},
+			cache: testObj.cache,
+		}
+
+		ctx := testObj.echo.NewContext(req, rec)
+		tenant.SetAccount(ctx, testAccountId)
+		ctx.QueryParams().Add("ids", "652d34910a8fc425116b84d9")
+		ctx.QueryParams().Add("currency", "Canada-Dollar")
+		err := h.getTransactions(ctx)
+
+		var resp map[string][]*model.TransactionResponse
+		json.Unmarshal(rec.Body.Bytes(), &resp)
+		assert.NoError(t, err)
+		assert.Len(t, resp["ids"], 1)
+		assert.Equal(t, resp["ids"][0].ExchangeRate, 1.37)
+		assert.Equal(t, resp["ids"][0].RecordDate, "2023-09-30")
+		assert.Equal(t, resp["ids"][0].ConvertedPurchaseAmount, 13.7)
+		assert.Equal(t, resp["ids"][0].Conversions["Canada-Dollar"].ConvertedPurchaseAmount, 13.7)
+	})
+
+	t.Run("This test simulates transactions found in the cache", func(t *testing.T) {
+		testObj := setUpTest(t)
+		req := httptest.NewRequest(http.MethodGet, "/v1/transaction", nil)
+		rec := httptest.NewRecorder()
+
+		testObj.cache.EXPECT().MGet(gomock.Any(), []string{
+			testAccountId + ":transaction:652d34910a8fc425116b84d9:Canada-Dollar",
+			testAccountId + ":transaction:652d34910a8fc425116b84d9:missing",
+		}, gomock.Any()).DoAndReturn(func(ctx context.Context, keys []string, value func(i int) interface{}) ([]bool, error) {
+			*value(0).(**model.TransactionResponse) = &model.TransactionResponse{Id: "652d34910a8fc425116b84d9"}
+			return []bool{true, false}, nil
+		})
+
+		h := handler{
+			apps: &app.Container{
+				FiscalData:  testObj.fiscalDataApp,
+				Transaction: testObj.transactionApp,
 			},
+			cache: testObj.cache,
 		}
-		req := httptest.NewRequest(http.MethodGet, "/v1/transaction/period", nil)
+
+		ctx := testObj.echo.NewContext(req, rec)
+		tenant.SetAccount(ctx, testAccountId)
+		ctx.QueryParams().Add("ids", "652d34910a8fc425116b84d9")
+		ctx.QueryParams().Add("currency", "Canada-Dollar")
+		err := h.getTransactions(ctx)
+
+		var resp map[string][]*model.TransactionResponse
+		json.Unmarshal(rec.Body.Bytes(), &resp)
+		assert.NoError(t, err)
+		assert.Len(t, resp["ids"], 1)
+		assert.Equal(t, resp["ids"][0].Id, "652d34910a8fc425116b84d9")
+	})
+
+	t.Run("This test simulates a partial cache hit with unknown ids", func(t *testing.T) {
+		testObj := setUpTest(t)
+		req := httptest.NewRequest(http.MethodGet, "/v1/transaction", nil)
 		rec := httptest.NewRecorder()
-		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
 
-		testObj.transactionApp.EXPECT().GetTransactionsByPeriod(gomock.Any(), gomock.Any(), gomock.Any()).Return(payload, nil)
-		testObj.fiscalDataApp.EXPECT().GetRatesOfExchange(gomock.Any(), gomock.Any()).Return(&fiscaldata.Data{
+		testObj.cache.EXPECT().MGet(gomock.Any(), gomock.Len(10), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, keys []string, value func(i int) interface{}) ([]bool, error) {
+				*value(1).(**model.TransactionResponse) = &model.TransactionResponse{Id: "652d34910a8fc425116b84d2"}
+				*value(7).(*bool) = true
+				return []bool{false, true, false, false, false, false, false, true, false, false}, nil
+			})
+		testObj.transactionApp.EXPECT().GetTransactions(gomock.Any(), testAccountId, []string{"652d34910a8fc425116b84d1", "652d34910a8fc425116b84d4"}).
+			Return([]*model.TransactionResponse{{Id: "652d34910a8fc425116b84d1", PurchaseAmount: 10, PurchaseDate: "2023-10-15"}}, nil)
+		testObj.fiscalDataApp.EXPECT().GetRatesOfExchange("Canada-Dollar", "2023-10-15").Return(&fiscaldata.Data{
 			CurrencyDescription: "Canada-Dollar",
 			ExchangeRate:        1.23,
 			RecordDate:          "2023-10-15",
-		}, nil).Times(2)
+		}, nil)
+		testObj.cache.EXPECT().MSet(gomock.Any(), gomock.Len(1), 24*time.Hour).Return(nil)
+		testObj.cache.EXPECT().MSet(gomock.Any(), map[string]interface{}{
+			testAccountId + ":transaction:652d34910a8fc425116b84d4:missing": true,
+		}, missingTransactionTTL).Return(nil)
 
 		h := handler{
 			apps: &app.Container{
@@ -222,25 +476,28 @@ func TestGetTransactionsByPeriod(t *testing.T) {
 		}
 
 		ctx := testObj.echo.NewContext(req, rec)
-		ctx.QueryParams().Add("ids", "652d34910a8fc425116b84d9")
+		tenant.SetAccount(ctx, testAccountId)
+		ctx.QueryParams().Add("ids", "652d34910a8fc425116b84d1,652d34910a8fc425116b84d2,652d34910a8fc425116b84d3,invalid,652d34910a8fc425116b84d4")
 		ctx.QueryParams().Add("currency", "Canada-Dollar")
-		ctx.QueryParams().Add("startDate", "2023-10-12")
-		ctx.QueryParams().Add("endDate", "2023-10-15")
-		err := h.getTransactionsByPeriod(ctx)
+		err := h.getTransactions(ctx)
 
-		var resp interface{}
+		var resp model.GetTransactionsResponse
 		json.Unmarshal(rec.Body.Bytes(), &resp)
-		assert.NotEmpty(t, resp)
 		assert.NoError(t, err)
+		assert.Len(t, resp.Ids, 2)
+		assert.Equal(t, resp.Ids[0].Id, "652d34910a8fc425116b84d1")
+		assert.Equal(t, resp.Ids[1].Id, "652d34910a8fc425116b84d2")
+		assert.Equal(t, resp.NotFound, []string{"652d34910a8fc425116b84d3", "invalid", "652d34910a8fc425116b84d4"})
 	})
 
-	t.Run("This test simulates an error when obtaining transaction information by date", func(t *testing.T) {
+	t.Run("This test simulates an error when obtaining transaction information", func(t *testing.T) {
 		testObj := setUpTest(t)
-		req := httptest.NewRequest(http.MethodGet, "/v1/transaction/period", nil)
+		req := httptest.NewRequest(http.MethodGet, "/v1/transaction", nil)
 		rec := httptest.NewRecorder()
 		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
 
-		testObj.transactionApp.EXPECT().GetTransactionsByPeriod(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, errors.New("an error has ocurred"))
+		testObj.cache.EXPECT().MGet(gomock.Any(), gomock.Any(), gomock.Any()).Return([]bool{false, false}, nil)
+		testObj.transactionApp.EXPECT().GetTransactions(gomock.Any(), testAccountId, gomock.Any()).Return(nil, errors.New("an error has ocurred"))
 
 		h := handler{
 			apps: &app.Container{
@@ -251,11 +508,10 @@ func TestGetTransactionsByPeriod(t *testing.T) {
 		}
 
 		ctx := testObj.echo.NewContext(req, rec)
+		tenant.SetAccount(ctx, testAccountId)
 		ctx.QueryParams().Add("ids", "652d34910a8fc425116b84d9")
 		ctx.QueryParams().Add("currency", "Canada-Dollar")
-		ctx.QueryParams().Add("startDate", "2023-10-12")
-		ctx.QueryParams().Add("endDate", "2023-10-15")
-		err := h.getTransactionsByPeriod(ctx)
+		err := h.getTransactions(ctx)
 
 		var resp interface{}
 		json.Unmarshal(rec.Body.Bytes(), &resp)
@@ -264,8 +520,8 @@ func TestGetTransactionsByPeriod(t *testing.T) {
 	})
 }
 
-func TestGetTransactionsByPeriodEpoch(t *testing.T) {
-	t.Run("This test simulates the process for obtaining transaction information by period", func(t *testing.T) {
+func TestGetTransactionsByPeriod(t *testing.T) {
+	t.Run("This test simulates the process for obtaining transaction information by date", func(t *testing.T) {
 		testObj := setUpTest(t)
 		payload := []*model.TransactionResponse{
 			0: {
@@ -279,11 +535,13 @@ func TestGetTransactionsByPeriodEpoch(t *testing.T) {
 				PurchaseDate:   "2023-10-14",
 			},
 		}
-		req := httptest.NewRequest(http.MethodGet, "/v1/transaction/epoch-period", nil)
+		req := httptest.NewRequest(http.MethodGet, "/v1/transaction/period", nil)
 		rec := httptest.NewRecorder()
 		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
 
-		testObj.transactionApp.EXPECT().GetTransactionsByPeriodEpoch(gomock.Any(), gomock.Any(), gomock.Any()).Return(payload, nil)
+		testObj.cache.EXPECT().Counter(gomock.Any(), testAccountId+":period-generation").Return(int64(3), nil)
+		testObj.cache.EXPECT().GetOrSet(gomock.Any(), testAccountId+":period:3:date:2023-10-12:2023-10-15:Canada-Dollar:", gomock.Any(), 5*time.Minute, gomock.Any()).DoAndReturn(loadThrough)
+		testObj.transactionApp.EXPECT().GetTransactionsByPeriod(gomock.Any(), testAccountId, gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(payload, nil)
 		testObj.fiscalDataApp.EXPECT().GetRatesOfExchange(gomock.Any(), gomock.Any()).Return(&fiscaldata.Data{
 			CurrencyDescription: "Canada-Dollar",
 			ExchangeRate:        1.23,
@@ -299,11 +557,12 @@ func TestGetTransactionsByPeriodEpoch(t *testing.T) {
 		}
 
 		ctx := testObj.echo.NewContext(req, rec)
+		tenant.SetAccount(ctx, testAccountId)
 		ctx.QueryParams().Add("ids", "652d34910a8fc425116b84d9")
 		ctx.QueryParams().Add("currency", "Canada-Dollar")
-		ctx.QueryParams().Add("startDate", "1697150153")
-		ctx.QueryParams().Add("endDate", "1697409353")
-		err := h.getTransactionsByPeriodEpoch(ctx)
+		ctx.QueryParams().Add("startDate", "2023-10-12")
+		ctx.QueryParams().Add("endDate", "2023-10-15")
+		err := h.getTransactionsByPeriod(ctx)
 
 		var resp interface{}
 		json.Unmarshal(rec.Body.Bytes(), &resp)
@@ -313,11 +572,13 @@ func TestGetTransactionsByPeriodEpoch(t *testing.T) {
 
 	t.Run("This test simulates an error when obtaining transaction information by date", func(t *testing.T) {
 		testObj := setUpTest(t)
-		req := httptest.NewRequest(http.MethodGet, "/v1/transaction/epoch-period", nil)
+		req := httptest.NewRequest(http.MethodGet, "/v1/transaction/period", nil)
 		rec := httptest.NewRecorder()
 		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
 
-		testObj.transactionApp.EXPECT().GetTransactionsByPeriodEpoch(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, errors.New("an error has ocurred"))
+		testObj.cache.EXPECT().Counter(gomock.Any(), testAccountId+":period-generation").Return(int64(3), nil)
+		testObj.cache.EXPECT().GetOrSet(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(loadThrough)
+		testObj.transactionApp.EXPECT().GetTransactionsByPeriod(gomock.Any(), testAccountId, gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, errors.New("an error has ocurred"))
 
 		h := handler{
 			apps: &app.Container{
@@ -328,15 +589,783 @@ func TestGetTransactionsByPeriodEpoch(t *testing.T) {
 		}
 
 		ctx := testObj.echo.NewContext(req, rec)
+		tenant.SetAccount(ctx, testAccountId)
 		ctx.QueryParams().Add("ids", "652d34910a8fc425116b84d9")
 		ctx.QueryParams().Add("currency", "Canada-Dollar")
-		ctx.QueryParams().Add("startDate", "1697150153")
-		ctx.QueryParams().Add("endDate", "1697409353")
-		err := h.getTransactionsByPeriodEpoch(ctx)
+		ctx.QueryParams().Add("startDate", "2023-10-12")
+		ctx.QueryParams().Add("endDate", "2023-10-15")
+		err := h.getTransactionsByPeriod(ctx)
 
 		var resp interface{}
 		json.Unmarshal(rec.Body.Bytes(), &resp)
 		assert.Empty(t, resp)
 		assert.Error(t, err)
 	})
+
+	t.Run("This test simulates obtaining transaction information by date filtered by attributes", func(t *testing.T) {
+		testObj := setUpTest(t)
+		req := httptest.NewRequest(http.MethodGet, "/v1/transaction/period", nil)
+		rec := httptest.NewRecorder()
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+		testObj.cache.EXPECT().Counter(gomock.Any(), testAccountId+":period-generation").Return(int64(3), nil)
+		testObj.cache.EXPECT().GetOrSet(gomock.Any(), testAccountId+":period:3:date:2023-10-12:2023-10-15:Canada-Dollar:category=food&metadata=project%3Aalpha", gomock.Any(), 5*time.Minute, gomock.Any()).DoAndReturn(loadThrough)
+		testObj.transactionApp.EXPECT().GetTransactionsByPeriod(gomock.Any(), testAccountId, "2023-10-12", "2023-10-15", "", "", &model.AttributeParams{
+			Category: "food",
+			Metadata: "project:alpha",
+		}).Return([]*model.TransactionResponse{}, nil)
+
+		h := handler{
+			apps: &app.Container{
+				FiscalData:  testObj.fiscalDataApp,
+				Transaction: testObj.transactionApp,
+			},
+			cache: testObj.cache,
+		}
+
+		ctx := testObj.echo.NewContext(req, rec)
+		tenant.SetAccount(ctx, testAccountId)
+		ctx.QueryParams().Add("currency", "Canada-Dollar")
+		ctx.QueryParams().Add("startDate", "2023-10-12")
+		ctx.QueryParams().Add("endDate", "2023-10-15")
+		ctx.QueryParams().Add("category", "food")
+		ctx.QueryParams().Add("metadata", "project:alpha")
+		err := h.getTransactionsByPeriod(ctx)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, rec.Code)
+	})
+
+	t.Run("This test simulates obtaining transaction information by date in a timezone", func(t *testing.T) {
+		testObj := setUpTest(t)
+		req := httptest.NewRequest(http.MethodGet, "/v1/transaction/period", nil)
+		rec := httptest.NewRecorder()
+
+		testObj.cache.EXPECT().Counter(gomock.Any(), testAccountId+":period-generation").Return(int64(3), nil)
+		testObj.cache.EXPECT().GetOrSet(gomock.Any(), testAccountId+":period:3:date:2023-10-12:2023-10-15:Canada-Dollar:timezone=America%2FSao_Paulo", gomock.Any(), 5*time.Minute, gomock.Any()).DoAndReturn(loadThrough)
+		testObj.transactionApp.EXPECT().GetTransactionsByPeriod(gomock.Any(), testAccountId, "2023-10-12", "2023-10-15", "America/Sao_Paulo", "", gomock.Any()).Return([]*model.TransactionResponse{}, nil)
+
+		h := handler{
+			apps: &app.Container{
+				FiscalData:  testObj.fiscalDataApp,
+				Transaction: testObj.transactionApp,
+			},
+			cache: testObj.cache,
+		}
+
+		ctx := testObj.echo.NewContext(req, rec)
+		tenant.SetAccount(ctx, testAccountId)
+		ctx.QueryParams().Add("currency", "Canada-Dollar")
+		ctx.QueryParams().Add("startDate", "2023-10-12")
+		ctx.QueryParams().Add("endDate", "2023-10-15")
+		ctx.QueryParams().Add("timezone", "America/Sao_Paulo")
+		err := h.getTransactionsByPeriod(ctx)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, rec.Code)
+	})
+
+	t.Run("This test simulates obtaining transaction information by the date they were stored", func(t *testing.T) {
+		testObj := setUpTest(t)
+		req := httptest.NewRequest(http.MethodGet, "/v1/transaction/period", nil)
+		rec := httptest.NewRecorder()
+
+		testObj.cache.EXPECT().Counter(gomock.Any(), testAccountId+":period-generation").Return(int64(3), nil)
+		testObj.cache.EXPECT().GetOrSet(gomock.Any(), testAccountId+":period:3:date:2023-10-12:2023-10-15:Canada-Dollar:field=created", gomock.Any(), 5*time.Minute, gomock.Any()).DoAndReturn(loadThrough)
+		testObj.transactionApp.EXPECT().GetTransactionsByPeriod(gomock.Any(), testAccountId, "2023-10-12", "2023-10-15", "", "created", gomock.Any()).Return([]*model.TransactionResponse{}, nil)
+
+		h := handler{
+			apps: &app.Container{
+				FiscalData:  testObj.fiscalDataApp,
+				Transaction: testObj.transactionApp,
+			},
+			cache: testObj.cache,
+		}
+
+		ctx := testObj.echo.NewContext(req, rec)
+		tenant.SetAccount(ctx, testAccountId)
+		ctx.QueryParams().Add("currency", "Canada-Dollar")
+		ctx.QueryParams().Add("startDate", "2023-10-12")
+		ctx.QueryParams().Add("endDate", "2023-10-15")
+		ctx.QueryParams().Add("field", "created")
+		err := h.getTransactionsByPeriod(ctx)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, rec.Code)
+	})
+
+	t.Run("This test simulates an unknown period field", func(t *testing.T) {
+		testObj := setUpTest(t)
+		req := httptest.NewRequest(http.MethodGet, "/v1/transaction/period", nil)
+		rec := httptest.NewRecorder()
+
+		h := handler{
+			apps: &app.Container{
+				FiscalData:  testObj.fiscalDataApp,
+				Transaction: testObj.transactionApp,
+			},
+			cache: testObj.cache,
+		}
+
+		ctx := testObj.echo.NewContext(req, rec)
+		tenant.SetAccount(ctx, testAccountId)
+		ctx.QueryParams().Add("currency", "Canada-Dollar")
+		ctx.QueryParams().Add("startDate", "2023-10-12")
+		ctx.QueryParams().Add("endDate", "2023-10-15")
+		ctx.QueryParams().Add("field", "updated")
+		err := h.getTransactionsByPeriod(ctx)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+}
+
+func TestGetTransactionsByPeriodCache(t *testing.T) {
+	t.Run("This test simulates a period found in the cache", func(t *testing.T) {
+		testObj := setUpTest(t)
+		req := httptest.NewRequest(http.MethodGet, "/v1/transaction/period", nil)
+		rec := httptest.NewRecorder()
+
+		testObj.cache.EXPECT().Counter(gomock.Any(), testAccountId+":period-generation").Return(int64(3), nil)
+		testObj.cache.EXPECT().GetOrSet(gomock.Any(), gomock.Any(), gomock.Any(), time.Hour, gomock.Any()).
+			DoAndReturn(func(ctx context.Context, key string, value interface{}, expirationTime time.Duration, load cache.Loader) error {
+				*value.(*[]*model.TransactionResponse) = []*model.TransactionResponse{{Id: "652d34910a8fc425116b84d9", PurchaseDate: "2023-10-15"}}
+				return nil
+			})
+
+		h := handler{
+			apps: &app.Container{
+				FiscalData:  testObj.fiscalDataApp,
+				Transaction: testObj.transactionApp,
+			},
+			cache: testObj.cache,
+			policies: map[string]cache.Policy{
+				CachePolicyPeriod: {TTL: time.Hour, MaxAge: time.Minute},
+			},
+		}
+
+		ctx := testObj.echo.NewContext(req, rec)
+		tenant.SetAccount(ctx, testAccountId)
+		ctx.QueryParams().Add("currency", "Canada-Dollar")
+		ctx.QueryParams().Add("startDate", "2023-10-12")
+		ctx.QueryParams().Add("endDate", "2023-10-15")
+		err := h.getTransactionsByPeriod(ctx)
+
+		assert.NoError(t, err)
+		assert.Equal(t, rec.Code, http.StatusOK)
+		assert.Equal(t, rec.Header().Get(echo.HeaderCacheControl), "private, max-age=60")
+		assert.Equal(t, rec.Header().Get(echo.HeaderLastModified), "Mon, 16 Oct 2023 13:03:13 GMT")
+		assert.NotEmpty(t, rec.Header().Get(httpcache.HeaderETag))
+	})
+
+	t.Run("This test simulates a period generation that cannot be read", func(t *testing.T) {
+		testObj := setUpTest(t)
+		req := httptest.NewRequest(http.MethodGet, "/v1/transaction/period", nil)
+		rec := httptest.NewRecorder()
+
+		testObj.cache.EXPECT().Counter(gomock.Any(), testAccountId+":period-generation").Return(int64(0), errors.New("an error has ocurred"))
+		testObj.transactionApp.EXPECT().GetTransactionsByPeriod(gomock.Any(), testAccountId, "2023-10-12", "2023-10-15", "", "", gomock.Any()).
+			Return([]*model.TransactionResponse{{Id: "652d34910a8fc425116b84d9", PurchaseAmount: 10, PurchaseDate: "2023-10-15"}}, nil)
+		testObj.fiscalDataApp.EXPECT().GetRatesOfExchange("Canada-Dollar", "2023-10-15").Return(&fiscaldata.Data{
+			ExchangeRate: 1.23,
+		}, nil)
+
+		h := handler{
+			apps: &app.Container{
+				FiscalData:  testObj.fiscalDataApp,
+				Transaction: testObj.transactionApp,
+			},
+			cache: testObj.cache,
+		}
+
+		ctx := testObj.echo.NewContext(req, rec)
+		tenant.SetAccount(ctx, testAccountId)
+		ctx.QueryParams().Add("currency", "Canada-Dollar")
+		ctx.QueryParams().Add("startDate", "2023-10-12")
+		ctx.QueryParams().Add("endDate", "2023-10-15")
+		err := h.getTransactionsByPeriod(ctx)
+
+		// the period is loaded without the cache, which may hold one from before the last insert
+		assert.NoError(t, err)
+		assert.Equal(t, rec.Code, http.StatusOK)
+	})
+
+	t.Run("This test simulates a conditional request for an unchanged period", func(t *testing.T) {
+		testObj := setUpTest(t)
+		payload := []*model.TransactionResponse{{Id: "652d34910a8fc425116b84d9", PurchaseAmount: 10, PurchaseDate: "2023-10-15"}}
+		testObj.fiscalDataApp.EXPECT().GetRatesOfExchange("Canada-Dollar", "2023-10-15").Return(&fiscaldata.Data{
+			ExchangeRate: 1.23,
+		}, nil).Times(2)
+		testObj.transactionApp.EXPECT().GetTransactionsByPeriod(gomock.Any(), testAccountId, "2023-10-12", "2023-10-15", "", "", gomock.Any()).
+			DoAndReturn(func(ctx context.Context, accountId, startDate, endDate, timezone, field string, attributes *model.AttributeParams) ([]*model.TransactionResponse, error) {
+				return []*model.TransactionResponse{{Id: payload[0].Id, PurchaseAmount: 10, PurchaseDate: "2023-10-15"}}, nil
+			}).Times(2)
+
+		h := handler{
+			apps: &app.Container{
+				FiscalData:  testObj.fiscalDataApp,
+				Transaction: testObj.transactionApp,
+			},
+			cache: testObj.cache,
+			policies: map[string]cache.Policy{
+				CachePolicyPeriod: {},
+			},
+		}
+
+		request := func(etag string) *httptest.ResponseRecorder {
+			req := httptest.NewRequest(http.MethodGet, "/v1/transaction/period", nil)
+			req.Header.Set(httpcache.HeaderIfNoneMatch, etag)
+			rec := httptest.NewRecorder()
+			ctx := testObj.echo.NewContext(req, rec)
+			tenant.SetAccount(ctx, testAccountId)
+			ctx.QueryParams().Add("currency", "Canada-Dollar")
+			ctx.QueryParams().Add("startDate", "2023-10-12")
+			ctx.QueryParams().Add("endDate", "2023-10-15")
+			assert.NoError(t, h.getTransactionsByPeriod(ctx))
+			return rec
+		}
+
+		first := request("")
+		assert.Equal(t, first.Code, http.StatusOK)
+		assert.Equal(t, first.Header().Get(echo.HeaderCacheControl), "private, no-cache")
+
+		second := request(first.Header().Get(httpcache.HeaderETag))
+		assert.Equal(t, second.Code, http.StatusNotModified)
+		assert.Empty(t, second.Body.Bytes())
+	})
+}
+
+func TestGetTransactionsByPeriodEpoch(t *testing.T) {
+	t.Run("This test simulates the process for obtaining transaction information by period", func(t *testing.T) {
+		testObj := setUpTest(t)
+		payload := []*model.TransactionResponse{
+			0: {
+				PurchaseAmount: 23.70,
+				Description:    "Test1",
+				PurchaseDate:   "2023-10-15",
+			},
+			1: {
+				PurchaseAmount: 25.00,
+				Description:    "Test2",
+				PurchaseDate:   "2023-10-14",
+			},
+		}
+		req := httptest.NewRequest(http.MethodGet, "/v1/transaction/epoch-period", nil)
+		rec := httptest.NewRecorder()
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+		testObj.cache.EXPECT().Counter(gomock.Any(), testAccountId+":period-generation").Return(int64(3), nil)
+		testObj.cache.EXPECT().GetOrSet(gomock.Any(), testAccountId+":period:3:epoch:1697150153:1697409353:Canada-Dollar:", gomock.Any(), 5*time.Minute, gomock.Any()).DoAndReturn(loadThrough)
+		testObj.transactionApp.EXPECT().GetTransactionsByPeriodEpoch(gomock.Any(), testAccountId, gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(payload, nil)
+		testObj.fiscalDataApp.EXPECT().GetRatesOfExchange(gomock.Any(), gomock.Any()).Return(&fiscaldata.Data{
+			CurrencyDescription: "Canada-Dollar",
+			ExchangeRate:        1.23,
+			RecordDate:          "2023-10-15",
+		}, nil).Times(2)
+
+		h := handler{
+			apps: &app.Container{
+				FiscalData:  testObj.fiscalDataApp,
+				Transaction: testObj.transactionApp,
+			},
+			cache: testObj.cache,
+		}
+
+		ctx := testObj.echo.NewContext(req, rec)
+		tenant.SetAccount(ctx, testAccountId)
+		ctx.QueryParams().Add("ids", "652d34910a8fc425116b84d9")
+		ctx.QueryParams().Add("currency", "Canada-Dollar")
+		ctx.QueryParams().Add("startDate", "1697150153")
+		ctx.QueryParams().Add("endDate", "1697409353")
+		err := h.getTransactionsByPeriodEpoch(ctx)
+
+		var resp interface{}
+		json.Unmarshal(rec.Body.Bytes(), &resp)
+		assert.NotEmpty(t, resp)
+		assert.NoError(t, err)
+	})
+
+	t.Run("This test simulates an error when obtaining transaction information by date", func(t *testing.T) {
+		testObj := setUpTest(t)
+		req := httptest.NewRequest(http.MethodGet, "/v1/transaction/epoch-period", nil)
+		rec := httptest.NewRecorder()
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+		testObj.cache.EXPECT().Counter(gomock.Any(), testAccountId+":period-generation").Return(int64(3), nil)
+		testObj.cache.EXPECT().GetOrSet(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(loadThrough)
+		testObj.transactionApp.EXPECT().GetTransactionsByPeriodEpoch(gomock.Any(), testAccountId, gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, errors.New("an error has ocurred"))
+
+		h := handler{
+			apps: &app.Container{
+				FiscalData:  testObj.fiscalDataApp,
+				Transaction: testObj.transactionApp,
+			},
+			cache: testObj.cache,
+		}
+
+		ctx := testObj.echo.NewContext(req, rec)
+		tenant.SetAccount(ctx, testAccountId)
+		ctx.QueryParams().Add("ids", "652d34910a8fc425116b84d9")
+		ctx.QueryParams().Add("currency", "Canada-Dollar")
+		ctx.QueryParams().Add("startDate", "1697150153")
+		ctx.QueryParams().Add("endDate", "1697409353")
+		err := h.getTransactionsByPeriodEpoch(ctx)
+
+		var resp interface{}
+		json.Unmarshal(rec.Body.Bytes(), &resp)
+		assert.Empty(t, resp)
+		assert.Error(t, err)
+	})
+}
+
+func TestGetTransactionsSummary(t *testing.T) {
+	t.Run("This test simulates the process for obtaining the transactions summary by period", func(t *testing.T) {
+		testObj := setUpTest(t)
+		payload := []*model.TransactionAggregate{
+			0: {
+				Period:       "2023-09",
+				PurchaseDate: "2023-09-30",
+				Count:        2,
+				Sum:          30.00,
+				Min:          10.00,
+				Max:          20.00,
+			},
+			1: {
+				Period:       "2023-10",
+				PurchaseDate: "2023-10-14",
+				Count:        1,
+				Sum:          5.00,
+				Min:          5.00,
+				Max:          5.00,
+			},
+			2: {
+				Period:       "2023-10",
+				PurchaseDate: "2023-10-15",
+				Count:        1,
+				Sum:          25.00,
+				Min:          25.00,
+				Max:          25.00,
+			},
+		}
+		req := httptest.NewRequest(http.MethodGet, "/v1/transaction/summary", nil)
+		rec := httptest.NewRecorder()
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+		testObj.transactionApp.EXPECT().GetTransactionsSummary(gomock.Any(), testAccountId, "2023-09-01", "2023-11-01", "", "", "month").Return(payload, nil)
+		testObj.fiscalDataApp.EXPECT().GetRatesOfExchange("Canada-Dollar", gomock.Any()).Return(&fiscaldata.Data{
+			CurrencyDescription: "Canada-Dollar",
+			ExchangeRate:        2,
+			RecordDate:          "2023-09-30",
+		}, nil).Times(3)
+
+		h := handler{
+			apps: &app.Container{
+				FiscalData:  testObj.fiscalDataApp,
+				Transaction: testObj.transactionApp,
+			},
+			cache: testObj.cache,
+		}
+
+		ctx := testObj.echo.NewContext(req, rec)
+		tenant.SetAccount(ctx, testAccountId)
+		ctx.QueryParams().Add("currency", "Canada-Dollar")
+		ctx.QueryParams().Add("startDate", "2023-09-01")
+		ctx.QueryParams().Add("endDate", "2023-11-01")
+		ctx.QueryParams().Add("groupBy", "month")
+		err := h.getTransactionsSummary(ctx)
+
+		var resp map[string][]*model.TransactionSummary
+		json.Unmarshal(rec.Body.Bytes(), &resp)
+		assert.NoError(t, err)
+		assert.Equal(t, []*model.TransactionSummary{
+			0: {
+				Period:    "2023-09",
+				Currency:  "Canada-Dollar",
+				USD:       model.SummaryValues{Count: 2, Sum: 30, Min: 10, Max: 20, Average: 15},
+				Converted: model.SummaryValues{Count: 2, Sum: 60, Min: 20, Max: 40, Average: 30},
+			},
+			1: {
+				Period:    "2023-10",
+				Currency:  "Canada-Dollar",
+				USD:       model.SummaryValues{Count: 2, Sum: 30, Min: 5, Max: 25, Average: 15},
+				Converted: model.SummaryValues{Count: 2, Sum: 60, Min: 10, Max: 50, Average: 30},
+			},
+		}, resp["summary"])
+	})
+
+	t.Run("This test simulates the transactions summary of purchases in several source currencies", func(t *testing.T) {
+		testObj := setUpTest(t)
+		payload := []*model.TransactionAggregate{
+			0: {
+				PurchaseDate: "2023-10-15",
+				Count:        1,
+				Sum:          10.00,
+				Min:          10.00,
+				Max:          10.00,
+			},
+			1: {
+				PurchaseDate:   "2023-10-15",
+				SourceCurrency: "Euro Zone-Euro",
+				Count:          2,
+				Sum:            40.00,
+				Min:            16.00,
+				Max:            24.00,
+			},
+		}
+		req := httptest.NewRequest(http.MethodGet, "/v1/transaction/summary", nil)
+		rec := httptest.NewRecorder()
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+		testObj.transactionApp.EXPECT().GetTransactionsSummary(gomock.Any(), testAccountId, "2023-10-01", "2023-11-01", "", "", "").Return(payload, nil)
+		testObj.fiscalDataApp.EXPECT().GetRatesOfExchange("Canada-Dollar", "2023-10-15").Return(&fiscaldata.Data{
+			CurrencyDescription: "Canada-Dollar",
+			ExchangeRate:        2,
+			RecordDate:          "2023-09-30",
+		}, nil).Times(2)
+		testObj.fiscalDataApp.EXPECT().GetRatesOfExchange("Euro Zone-Euro", "2023-10-15").Return(&fiscaldata.Data{
+			CurrencyDescription: "Euro Zone-Euro",
+			ExchangeRate:        0.8,
+			RecordDate:          "2023-09-30",
+		}, nil)
+
+		h := handler{
+			apps: &app.Container{
+				FiscalData:  testObj.fiscalDataApp,
+				Transaction: testObj.transactionApp,
+			},
+			cache: testObj.cache,
+		}
+
+		ctx := testObj.echo.NewContext(req, rec)
+		tenant.SetAccount(ctx, testAccountId)
+		ctx.QueryParams().Add("currency", "Canada-Dollar")
+		ctx.QueryParams().Add("startDate", "2023-10-01")
+		ctx.QueryParams().Add("endDate", "2023-11-01")
+		err := h.getTransactionsSummary(ctx)
+
+		var resp map[string][]*model.TransactionSummary
+		json.Unmarshal(rec.Body.Bytes(), &resp)
+		assert.NoError(t, err)
+		assert.Equal(t, []*model.TransactionSummary{
+			0: {
+				Currency:  "Canada-Dollar",
+				USD:       model.SummaryValues{Count: 3, Sum: 60, Min: 10, Max: 30, Average: 20},
+				Converted: model.SummaryValues{Count: 3, Sum: 120, Min: 20, Max: 60, Average: 40},
+			},
+		}, resp["summary"])
+	})
+
+	t.Run("This test simulates an invalid grouping when obtaining the transactions summary", func(t *testing.T) {
+		testObj := setUpTest(t)
+		req := httptest.NewRequest(http.MethodGet, "/v1/transaction/summary", nil)
+		rec := httptest.NewRecorder()
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+		h := handler{
+			apps: &app.Container{
+				FiscalData:  testObj.fiscalDataApp,
+				Transaction: testObj.transactionApp,
+			},
+			cache: testObj.cache,
+		}
+
+		ctx := testObj.echo.NewContext(req, rec)
+		tenant.SetAccount(ctx, testAccountId)
+		ctx.QueryParams().Add("currency", "Canada-Dollar")
+		ctx.QueryParams().Add("startDate", "2023-09-01")
+		ctx.QueryParams().Add("endDate", "2023-11-01")
+		ctx.QueryParams().Add("groupBy", "year")
+		err := h.getTransactionsSummary(ctx)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("This test simulates an unknown timezone when obtaining the transactions summary", func(t *testing.T) {
+		testObj := setUpTest(t)
+		req := httptest.NewRequest(http.MethodGet, "/v1/transaction/summary", nil)
+		rec := httptest.NewRecorder()
+
+		testObj.transactionApp.EXPECT().GetTransactionsSummary(gomock.Any(), testAccountId, "2023-09-01", "2023-11-01", "Mars/Olympus", "", "").
+			Return(nil, fmt.Errorf("%w: unknown timezone Mars/Olympus", transaction.ErrInvalidFilter))
+
+		h := handler{
+			apps: &app.Container{
+				FiscalData:  testObj.fiscalDataApp,
+				Transaction: testObj.transactionApp,
+			},
+			cache: testObj.cache,
+		}
+
+		ctx := testObj.echo.NewContext(req, rec)
+		tenant.SetAccount(ctx, testAccountId)
+		ctx.QueryParams().Add("currency", "Canada-Dollar")
+		ctx.QueryParams().Add("startDate", "2023-09-01")
+		ctx.QueryParams().Add("endDate", "2023-11-01")
+		ctx.QueryParams().Add("timezone", "Mars/Olympus")
+		err := h.getTransactionsSummary(ctx)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("This test simulates an error when obtaining the transactions summary", func(t *testing.T) {
+		testObj := setUpTest(t)
+		req := httptest.NewRequest(http.MethodGet, "/v1/transaction/summary", nil)
+		rec := httptest.NewRecorder()
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+		testObj.transactionApp.EXPECT().GetTransactionsSummary(gomock.Any(), testAccountId, gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, errors.New("an error has ocurred"))
+
+		h := handler{
+			apps: &app.Container{
+				FiscalData:  testObj.fiscalDataApp,
+				Transaction: testObj.transactionApp,
+			},
+			cache: testObj.cache,
+		}
+
+		ctx := testObj.echo.NewContext(req, rec)
+		tenant.SetAccount(ctx, testAccountId)
+		ctx.QueryParams().Add("currency", "Canada-Dollar")
+		ctx.QueryParams().Add("startDate", "2023-09-01")
+		ctx.QueryParams().Add("endDate", "2023-11-01")
+		err := h.getTransactionsSummary(ctx)
+
+		var resp interface{}
+		json.Unmarshal(rec.Body.Bytes(), &resp)
+		assert.Empty(t, resp)
+		assert.Error(t, err)
+	})
+}
+
+func TestSearchTransactions(t *testing.T) {
+	t.Run("This test simulates the process for searching transactions", func(t *testing.T) {
+		testObj := setUpTest(t)
+		payload := []*model.TransactionResponse{
+			0: {
+				PurchaseAmount: 23.70,
+				Description:    "Coffee",
+				PurchaseDate:   "2023-10-15",
+			},
+		}
+		req := httptest.NewRequest(http.MethodGet, "/v1/transaction/search", nil)
+		rec := httptest.NewRecorder()
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+		testObj.transactionApp.EXPECT().SearchTransactions(gomock.Any(), testAccountId, &model.SearchTransactionParams{
+			Text:      "coffee",
+			MinAmount: 10,
+			Currency:  "Canada-Dollar",
+			Page:      2,
+			AttributeParams: model.AttributeParams{
+				Tags: "work,travel",
+			},
+		}).Return(payload, nil)
+		testObj.fiscalDataApp.EXPECT().GetRatesOfExchange("Canada-Dollar", "2023-10-15").Return(&fiscaldata.Data{
+			CurrencyDescription: "Canada-Dollar",
+			ExchangeRate:        1.23,
+			RecordDate:          "2023-09-30",
+		}, nil)
+
+		h := handler{
+			apps: &app.Container{
+				FiscalData:  testObj.fiscalDataApp,
+				Transaction: testObj.transactionApp,
+			},
+			cache: testObj.cache,
+		}
+
+		ctx := testObj.echo.NewContext(req, rec)
+		tenant.SetAccount(ctx, testAccountId)
+		ctx.QueryParams().Add("text", "coffee")
+		ctx.QueryParams().Add("minAmount", "10")
+		ctx.QueryParams().Add("currency", "Canada-Dollar")
+		ctx.QueryParams().Add("page", "2")
+		ctx.QueryParams().Add("tags", "work,travel")
+		err := h.searchTransactions(ctx)
+
+		var resp map[string][]*model.TransactionResponse
+		json.Unmarshal(rec.Body.Bytes(), &resp)
+		assert.NoError(t, err)
+		assert.Equal(t, 29.15, resp["ids"][0].Conversions["Canada-Dollar"].ConvertedPurchaseAmount)
+	})
+
+	t.Run("This test simulates an invalid page size when searching transactions", func(t *testing.T) {
+		testObj := setUpTest(t)
+		req := httptest.NewRequest(http.MethodGet, "/v1/transaction/search", nil)
+		rec := httptest.NewRecorder()
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+		h := handler{
+			apps: &app.Container{
+				FiscalData:  testObj.fiscalDataApp,
+				Transaction: testObj.transactionApp,
+			},
+			cache: testObj.cache,
+		}
+
+		ctx := testObj.echo.NewContext(req, rec)
+		tenant.SetAccount(ctx, testAccountId)
+		ctx.QueryParams().Add("currency", "Canada-Dollar")
+		ctx.QueryParams().Add("pageSize", "1000")
+		err := h.searchTransactions(ctx)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("This test simulates an invalid metadata filter when searching transactions", func(t *testing.T) {
+		testObj := setUpTest(t)
+		req := httptest.NewRequest(http.MethodGet, "/v1/transaction/search", nil)
+		rec := httptest.NewRecorder()
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+		testObj.transactionApp.EXPECT().SearchTransactions(gomock.Any(), testAccountId, gomock.Any()).Return(nil, fmt.Errorf("%w: metadata must be key:value pairs", transaction.ErrInvalidFilter))
+
+		h := handler{
+			apps: &app.Container{
+				FiscalData:  testObj.fiscalDataApp,
+				Transaction: testObj.transactionApp,
+			},
+			cache: testObj.cache,
+		}
+
+		ctx := testObj.echo.NewContext(req, rec)
+		tenant.SetAccount(ctx, testAccountId)
+		ctx.QueryParams().Add("currency", "Canada-Dollar")
+		ctx.QueryParams().Add("metadata", "project")
+		err := h.searchTransactions(ctx)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("This test simulates an error when searching transactions", func(t *testing.T) {
+		testObj := setUpTest(t)
+		req := httptest.NewRequest(http.MethodGet, "/v1/transaction/search", nil)
+		rec := httptest.NewRecorder()
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+		testObj.transactionApp.EXPECT().SearchTransactions(gomock.Any(), testAccountId, gomock.Any()).Return(nil, errors.New("an error has ocurred"))
+
+		h := handler{
+			apps: &app.Container{
+				FiscalData:  testObj.fiscalDataApp,
+				Transaction: testObj.transactionApp,
+			},
+			cache: testObj.cache,
+		}
+
+		ctx := testObj.echo.NewContext(req, rec)
+		tenant.SetAccount(ctx, testAccountId)
+		ctx.QueryParams().Add("currency", "Canada-Dollar")
+		err := h.searchTransactions(ctx)
+
+		var resp interface{}
+		json.Unmarshal(rec.Body.Bytes(), &resp)
+		assert.Empty(t, resp)
+		assert.Error(t, err)
+	})
+}
+
+func TestExportTransactions(t *testing.T) {
+	t.Run("This test simulates the process for exporting transactions as csv", func(t *testing.T) {
+		testObj := setUpTest(t)
+		payload := []*model.TransactionResponse{
+			0: {
+				Id:             "652d34910a8fc425116b84d9",
+				PurchaseAmount: 10.00,
+				Description:    "Test1",
+				PurchaseDate:   "2023-10-15",
+			},
+			1: {
+				Id:             "652d34910a8fc425116b84d8",
+				PurchaseAmount: 20.00,
+				SourceCurrency: "Euro Zone-Euro",
+				Description:    "Test2",
+				PurchaseDate:   "2023-10-15",
+			},
+			2: {
+				Id:             "652d34910a8fc425116b84d7",
+				PurchaseAmount: 30.00,
+				Description:    "Test3",
+				PurchaseDate:   "2023-10-14",
+			},
+		}
+		req := httptest.NewRequest(http.MethodGet, "/v1/transaction/export", nil)
+		rec := httptest.NewRecorder()
+
+		testObj.transactionApp.EXPECT().ExportTransactionsByPeriod(gomock.Any(), testAccountId, "2023-10-01", "2023-11-01", "", "", gomock.Any()).DoAndReturn(
+			func(ctx context.Context, accountId, startDate, endDate, timezone, field string, fn func(*model.TransactionResponse) error) error {
+				for _, p := range payload {
+					if err := fn(p); err != nil {
+						return err
+					}
+				}
+				return nil
+			})
+		testObj.fiscalDataApp.EXPECT().GetRatesOfExchange("Canada-Dollar", "2023-10-15").Return(&fiscaldata.Data{
+			CurrencyDescription: "Canada-Dollar",
+			ExchangeRate:        1.5,
+			RecordDate:          "2023-09-30",
+		}, nil).Times(2)
+		testObj.fiscalDataApp.EXPECT().GetRatesOfExchange("Euro Zone-Euro", "2023-10-15").Return(&fiscaldata.Data{
+			CurrencyDescription: "Euro Zone-Euro",
+			ExchangeRate:        0.8,
+			RecordDate:          "2023-09-29",
+		}, nil)
+		testObj.fiscalDataApp.EXPECT().GetRatesOfExchange("Canada-Dollar", "2023-10-14").Return(nil, errors.New("an error has ocurred"))
+
+		h := handler{
+			apps: &app.Container{
+				FiscalData:  testObj.fiscalDataApp,
+				Transaction: testObj.transactionApp,
+			},
+		}
+
+		ctx := testObj.echo.NewContext(req, rec)
+		tenant.SetAccount(ctx, testAccountId)
+		ctx.QueryParams().Add("currency", "Canada-Dollar")
+		ctx.QueryParams().Add("startDate", "2023-10-01")
+		ctx.QueryParams().Add("endDate", "2023-11-01")
+		ctx.QueryParams().Add("format", "csv")
+		err := h.exportTransactions(ctx)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "text/csv", rec.Header().Get(echo.HeaderContentType))
+		assert.Equal(t, strings.Join([]string{
+			"id,description,purchase_date,purchase_amount,source_currency,currency,exchange_rate,record_date,source_exchange_rate,source_record_date,converted_purchase_amount,error",
+			"652d34910a8fc425116b84d9,Test1,2023-10-15,10,USD,Canada-Dollar,1.5,2023-09-30,,,15,",
+			"652d34910a8fc425116b84d8,Test2,2023-10-15,20,Euro Zone-Euro,Canada-Dollar,1.5,2023-09-30,0.8,2023-09-29,37.5,",
+			"652d34910a8fc425116b84d7,Test3,2023-10-14,30,USD,Canada-Dollar,,,,,,failed to get rates exchange",
+			"",
+		}, "\n"), rec.Body.String())
+	})
+
+	t.Run("This test simulates an error when exporting transactions", func(t *testing.T) {
+		testObj := setUpTest(t)
+		req := httptest.NewRequest(http.MethodGet, "/v1/transaction/export", nil)
+		rec := httptest.NewRecorder()
+
+		testObj.transactionApp.EXPECT().ExportTransactionsByPeriod(gomock.Any(), testAccountId, gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(errors.New("an error has ocurred"))
+
+		h := handler{
+			apps: &app.Container{
+				FiscalData:  testObj.fiscalDataApp,
+				Transaction: testObj.transactionApp,
+			},
+		}
+
+		ctx := testObj.echo.NewContext(req, rec)
+		tenant.SetAccount(ctx, testAccountId)
+		ctx.QueryParams().Add("currency", "Canada-Dollar")
+		ctx.QueryParams().Add("startDate", "2023-10-01")
+		ctx.QueryParams().Add("endDate", "2023-11-01")
+		ctx.QueryParams().Add("format", "ndjson")
+		err := h.exportTransactions(ctx)
+
+		assert.Error(t, err)
+		assert.Empty(t, rec.Body.String())
+	})
 }
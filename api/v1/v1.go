@@ -1,17 +1,60 @@
 package v1
 
 import (
+	"fmt"
+
 	"github.com/jcpribeiro/TransactionApp/app"
+	"github.com/jcpribeiro/TransactionApp/internal/auth"
 	"github.com/jcpribeiro/TransactionApp/internal/cache"
+	"github.com/jcpribeiro/TransactionApp/internal/ratelimit"
+	"github.com/jcpribeiro/TransactionApp/internal/tenant"
 
+	"github.com/jcpribeiro/TransactionApp/api/v1/apikey"
+	"github.com/jcpribeiro/TransactionApp/api/v1/conversion"
+	"github.com/jcpribeiro/TransactionApp/api/v1/job"
+	"github.com/jcpribeiro/TransactionApp/api/v1/rate"
 	"github.com/jcpribeiro/TransactionApp/api/v1/transaction"
 
 	"github.com/labstack/echo/v4"
 )
 
+const (
+	HeaderDeprecation = "Deprecation"
+	HeaderLink        = "Link"
+
+	// deprecatedAt is the release of v2, 2026-10-19 UTC
+	deprecatedAt int64 = 1792368000
+)
+
+// JobTypes are the job types exposed by the api, their runners are registered by the transaction routes
+var JobTypes = map[string]job.Type{
+	transaction.JobTypeExport: {Scope: auth.ScopeTransactionRead, Submit: true},
+	transaction.JobTypeImport: {Scope: auth.ScopeTransactionWrite},
+}
+
 // Registers v1 routes
-func Register(g *echo.Group, apps *app.Container, cache cache.Cache) {
+func Register(g *echo.Group, apps *app.Container, cache cache.Cache, policies map[string]cache.Policy, resolve tenant.Resolver, limits *ratelimit.Policy) {
 	v1 := g.Group("/v1")
+	requireAccount := tenant.Middleware(resolve)
+
+	transaction.Register(v1.Group("/transaction", deprecated("/v2/transactions"), requireAccount), apps, cache, policies, limits)
+	job.Register(v1.Group("/jobs", deprecated("/v2/jobs"), requireAccount), apps, JobTypes, limits)
+	apikey.Register(v1.Group("/keys", deprecated("/v2/keys"), requireAccount), apps, limits)
+	conversion.Register(v1.Group("/conversions", deprecated("/v2/conversions"), requireAccount), apps, limits)
+	rate.Register(v1.Group("/rates", deprecated("/v2/rates"), requireAccount), apps, cache, policies, limits)
+}
+
+// deprecated marks the responses of a group as deprecated (RFC 9745) and links their v2 successor
+func deprecated(successor string) echo.MiddlewareFunc {
+	deprecation := fmt.Sprintf("@%d", deprecatedAt)
+	link := fmt.Sprintf("<%s>; rel=\"successor-version\"", successor)
 
-	transaction.Register(v1.Group("/transaction"), apps, cache)
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			header := c.Response().Header()
+			header.Set(HeaderDeprecation, deprecation)
+			header.Add(HeaderLink, link)
+			return next(c)
+		}
+	}
 }
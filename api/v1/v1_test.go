@@ -0,0 +1,28 @@
+package v1
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeprecated(t *testing.T) {
+	t.Run("this test simulate a response of a deprecated route", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		ctx := echo.New().NewContext(httptest.NewRequest(http.MethodGet, "/v1/transaction", nil), rec)
+
+		err := deprecated("/v2/transactions")(func(c echo.Context) error {
+			return c.JSON(http.StatusUnauthorized, map[string]string{
+				"error": "unauthorized",
+			})
+		})(ctx)
+
+		assert.NoError(t, err)
+		assert.Equal(t, rec.Header().Get(HeaderDeprecation), "@1792368000")
+		assert.Equal(t, rec.Header().Get(HeaderLink), `</v2/transactions>; rel="successor-version"`)
+		assert.JSONEq(t, rec.Body.String(), `{"error": "unauthorized"}`)
+	})
+}
@@ -0,0 +1,127 @@
+package apikey
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/jcpribeiro/TransactionApp/app"
+	"github.com/jcpribeiro/TransactionApp/internal/auth"
+	"github.com/jcpribeiro/TransactionApp/internal/envelope"
+	"github.com/jcpribeiro/TransactionApp/internal/ratelimit"
+	"github.com/jcpribeiro/TransactionApp/internal/tenant"
+	"github.com/jcpribeiro/TransactionApp/model"
+	"github.com/jcpribeiro/TransactionApp/store/apikey"
+
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+)
+
+// Register group api key. Every route requires the admin scope.
+func Register(g *echo.Group, apps *app.Container, limits *ratelimit.Policy) {
+	h := &handler{
+		apps: apps,
+	}
+
+	readLimit := limits.Middleware(ratelimit.ClassRead)
+	writeLimit := limits.Middleware(ratelimit.ClassWrite)
+
+	g.Use(auth.RequireScope(auth.ScopeAdmin))
+	g.POST("", h.createAPIKey, writeLimit)
+	g.GET("", h.listAPIKeys, readLimit)
+	g.DELETE("/:id", h.revokeAPIKey, writeLimit)
+}
+
+type handler struct {
+	apps *app.Container
+}
+
+// createAPIKey swagger document
+// @Summary Create an api key of the caller account
+// @Description The key is returned only in this response, only its hash is stored.
+// @Tags v2 api key
+// @Accept  json
+// @Produce  json
+// @Param key body model.CreateAPIKeyParams true "key name and scopes"
+// @Success 201 {object} model.Envelope{data=model.CreatedAPIKey}
+// @Failure 400 {object} model.Envelope
+// @Failure 401 {object} model.Envelope
+// @Failure 403 {object} model.Envelope
+// @Failure 429 {object} model.Envelope
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /v2/keys [post]
+func (h *handler) createAPIKey(c echo.Context) error {
+	params := new(model.CreateAPIKeyParams)
+
+	if err := c.Bind(params); err != nil {
+		logrus.Error(err)
+		return envelope.Error(c, http.StatusBadRequest, "invalid message")
+	}
+
+	if err := c.Validate(params); err != nil {
+		logrus.Error(err)
+		return envelope.Error(c, http.StatusBadRequest, "missing name or invalid scopes")
+	}
+
+	response, err := h.apps.APIKey.CreateAPIKey(c.Request().Context(), tenant.Account(c), params.Name, params.Scopes)
+	if err != nil {
+		return err
+	}
+
+	return envelope.JSON(c, http.StatusCreated, response, nil)
+}
+
+// listAPIKeys swagger document
+// @Summary Retrive the api keys of the caller account
+// @Tags v2 api key
+// @Accept  json
+// @Produce  json
+// @Success 200 {object} model.Envelope{data=[]model.APIKey}
+// @Failure 401 {object} model.Envelope
+// @Failure 403 {object} model.Envelope
+// @Failure 429 {object} model.Envelope
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /v2/keys [get]
+func (h *handler) listAPIKeys(c echo.Context) error {
+	response, err := h.apps.APIKey.ListAPIKeys(c.Request().Context(), tenant.Account(c))
+	if err != nil {
+		return err
+	}
+	if response == nil {
+		response = []*model.APIKey{}
+	}
+
+	return envelope.JSON(c, http.StatusOK, response, nil)
+}
+
+// revokeAPIKey swagger document
+// @Summary Revoke an api key of the caller account
+// @Tags v2 api key
+// @Param id path string true "Key id"
+// @Success 204
+// @Failure 401 {object} model.Envelope
+// @Failure 403 {object} model.Envelope
+// @Failure 404 {object} model.Envelope
+// @Failure 429 {object} model.Envelope
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /v2/keys/{id} [delete]
+func (h *handler) revokeAPIKey(c echo.Context) error {
+	params := new(model.APIKeyParams)
+
+	if err := c.Bind(params); err != nil {
+		logrus.Error(err)
+		return envelope.Error(c, http.StatusBadRequest, "invalid path params")
+	}
+
+	err := h.apps.APIKey.RevokeAPIKey(c.Request().Context(), tenant.Account(c), params.Id)
+	if errors.Is(err, apikey.ErrAPIKeyNotFound) {
+		return envelope.Error(c, http.StatusNotFound, "api key not found")
+	}
+	if err != nil {
+		return err
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
@@ -0,0 +1,152 @@
+package apikey
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/jcpribeiro/TransactionApp/app"
+	apikeyApp "github.com/jcpribeiro/TransactionApp/app/apikey"
+	"github.com/jcpribeiro/TransactionApp/internal/envelope"
+	"github.com/jcpribeiro/TransactionApp/internal/tenant"
+	"github.com/jcpribeiro/TransactionApp/internal/validate"
+	"github.com/jcpribeiro/TransactionApp/model"
+	"github.com/jcpribeiro/TransactionApp/store/apikey"
+
+	"github.com/golang/mock/gomock"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+const testAccountId = "652d34910a8fc425116b84aa"
+
+type strucTest struct {
+	echo      *echo.Echo
+	apiKeyApp *apikeyApp.MockApp
+	h         handler
+}
+
+func setUpTest(t *testing.T) strucTest {
+	ctrl := gomock.NewController(t)
+	echo := echo.New()
+	echo.Validator = validate.New()
+	apiKeyApp := apikeyApp.NewMockApp(ctrl)
+
+	return strucTest{
+		echo:      echo,
+		apiKeyApp: apiKeyApp,
+		h: handler{
+			apps: &app.Container{
+				APIKey: apiKeyApp,
+			},
+		},
+	}
+}
+
+// serve runs the handler as a route of the v2 group
+func serve(ctx echo.Context, h echo.HandlerFunc) error {
+	tenant.SetAccount(ctx, testAccountId)
+	return envelope.Middleware()(h)(ctx)
+}
+
+func TestCreateAPIKey(t *testing.T) {
+	t.Run("this test simulate a successful api key creation", func(t *testing.T) {
+		testObj := setUpTest(t)
+		body := `{"name": "integration", "scopes": ["transaction:read"]}`
+		req := httptest.NewRequest(http.MethodPost, "/v2/keys", strings.NewReader(body))
+		rec := httptest.NewRecorder()
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+		testObj.apiKeyApp.EXPECT().CreateAPIKey(gomock.Any(), testAccountId, "integration", []string{"transaction:read"}).Return(&model.CreatedAPIKey{
+			APIKey: &model.APIKey{
+				Id:        "652d34910a8fc425116b84d9",
+				AccountId: testAccountId,
+				Hash:      "hash",
+			},
+			Key: "tk_key",
+		}, nil)
+
+		ctx := testObj.echo.NewContext(req, rec)
+		err := serve(ctx, testObj.h.createAPIKey)
+
+		var resp struct {
+			Data map[string]interface{} `json:"data"`
+		}
+		json.Unmarshal(rec.Body.Bytes(), &resp)
+		assert.NoError(t, err)
+		assert.Equal(t, rec.Code, http.StatusCreated)
+		assert.Equal(t, resp.Data["key"], "tk_key")
+		assert.NotContains(t, resp.Data, "hash")
+	})
+
+	t.Run("this test simulate an api key creation with an unknown scope", func(t *testing.T) {
+		testObj := setUpTest(t)
+		body := `{"name": "integration", "scopes": ["transaction:delete"]}`
+		req := httptest.NewRequest(http.MethodPost, "/v2/keys", strings.NewReader(body))
+		rec := httptest.NewRecorder()
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+		ctx := testObj.echo.NewContext(req, rec)
+		err := serve(ctx, testObj.h.createAPIKey)
+
+		var resp model.Envelope
+		json.Unmarshal(rec.Body.Bytes(), &resp)
+		assert.NoError(t, err)
+		assert.Equal(t, rec.Code, http.StatusBadRequest)
+		assert.Equal(t, resp.Errors[0].Code, "bad_request")
+	})
+}
+
+func TestListAPIKeys(t *testing.T) {
+	t.Run("this test simulate an account without api keys", func(t *testing.T) {
+		testObj := setUpTest(t)
+		req := httptest.NewRequest(http.MethodGet, "/v2/keys", nil)
+		rec := httptest.NewRecorder()
+
+		testObj.apiKeyApp.EXPECT().ListAPIKeys(gomock.Any(), testAccountId).Return(nil, nil)
+
+		ctx := testObj.echo.NewContext(req, rec)
+		err := serve(ctx, testObj.h.listAPIKeys)
+
+		assert.NoError(t, err)
+		assert.Equal(t, rec.Code, http.StatusOK)
+		assert.JSONEq(t, rec.Body.String(), `{"data": []}`)
+	})
+}
+
+func TestRevokeAPIKey(t *testing.T) {
+	t.Run("this test simulate a successful api key revocation", func(t *testing.T) {
+		testObj := setUpTest(t)
+		req := httptest.NewRequest(http.MethodDelete, "/v2/keys/652d34910a8fc425116b84d9", nil)
+		rec := httptest.NewRecorder()
+
+		testObj.apiKeyApp.EXPECT().RevokeAPIKey(gomock.Any(), testAccountId, "652d34910a8fc425116b84d9").Return(nil)
+
+		ctx := testObj.echo.NewContext(req, rec)
+		ctx.SetParamNames("id")
+		ctx.SetParamValues("652d34910a8fc425116b84d9")
+		err := serve(ctx, testObj.h.revokeAPIKey)
+
+		assert.NoError(t, err)
+		assert.Equal(t, rec.Code, http.StatusNoContent)
+	})
+
+	t.Run("this test simulate a revocation of an api key that does not exist", func(t *testing.T) {
+		testObj := setUpTest(t)
+		req := httptest.NewRequest(http.MethodDelete, "/v2/keys/652d34910a8fc425116b84d9", nil)
+		rec := httptest.NewRecorder()
+
+		testObj.apiKeyApp.EXPECT().RevokeAPIKey(gomock.Any(), testAccountId, "652d34910a8fc425116b84d9").Return(apikey.ErrAPIKeyNotFound)
+
+		ctx := testObj.echo.NewContext(req, rec)
+		ctx.SetParamNames("id")
+		ctx.SetParamValues("652d34910a8fc425116b84d9")
+		err := serve(ctx, testObj.h.revokeAPIKey)
+
+		assert.NoError(t, err)
+		assert.Equal(t, rec.Code, http.StatusNotFound)
+		assert.JSONEq(t, rec.Body.String(), `{"errors": [{"code": "not_found", "message": "api key not found"}]}`)
+	})
+}
@@ -0,0 +1,61 @@
+package conversion
+
+import (
+	"net/http"
+
+	"github.com/jcpribeiro/TransactionApp/app"
+	"github.com/jcpribeiro/TransactionApp/app/fiscaldata"
+	"github.com/jcpribeiro/TransactionApp/internal/auth"
+	"github.com/jcpribeiro/TransactionApp/internal/envelope"
+	"github.com/jcpribeiro/TransactionApp/internal/ratelimit"
+	"github.com/jcpribeiro/TransactionApp/model"
+
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+)
+
+// Register group conversions. The conversions are not stored and are limited as a conversion.
+func Register(g *echo.Group, apps *app.Container, limits *ratelimit.Policy) {
+	h := &handler{
+		apps: apps,
+	}
+
+	g.POST("", h.convertAmounts, auth.RequireScope(auth.ScopeTransactionRead), limits.Middleware(ratelimit.ClassConversion))
+}
+
+type handler struct {
+	apps *app.Container
+}
+
+// convertAmounts swagger document
+// @Summary Convert amounts on a date without storing them
+// @Description The source_currency of an amount defaults to USD. Other currencies are converted through USD.
+// @Description The rate is the latest one up to 6 months before the date. There is one result per request, in the same order.
+// @Description An amount without a rate reports its error and does not fail the others.
+// @Tags v2 conversion
+// @Accept  json
+// @Produce  json
+// @Param conversions body model.ConvertAmountsParams true "up to 100 amounts to convert"
+// @Success 200 {object} model.Envelope{data=[]model.ConversionResult}
+// @Failure 400 {object} model.Envelope
+// @Failure 401 {object} model.Envelope
+// @Failure 403 {object} model.Envelope
+// @Failure 429 {object} model.Envelope
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /v2/conversions [post]
+func (h *handler) convertAmounts(c echo.Context) error {
+	params := new(model.ConvertAmountsParams)
+
+	if err := c.Bind(params); err != nil {
+		logrus.Error(err)
+		return envelope.Error(c, http.StatusBadRequest, "invalid message")
+	}
+
+	if err := c.Validate(params); err != nil {
+		logrus.Error(err)
+		return envelope.Error(c, http.StatusBadRequest, "invalid amount, date or currency")
+	}
+
+	return envelope.JSON(c, http.StatusOK, fiscaldata.ConvertAmounts(h.apps.FiscalData, params.Conversions), nil)
+}
@@ -0,0 +1,95 @@
+package conversion
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/jcpribeiro/TransactionApp/app"
+	"github.com/jcpribeiro/TransactionApp/app/fiscaldata"
+	"github.com/jcpribeiro/TransactionApp/internal/envelope"
+	"github.com/jcpribeiro/TransactionApp/internal/validate"
+
+	"github.com/golang/mock/gomock"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+type strucTest struct {
+	echo          *echo.Echo
+	fiscalDataApp *fiscaldata.MockApp
+	h             handler
+}
+
+func setUpTest(t *testing.T) strucTest {
+	ctrl := gomock.NewController(t)
+	echo := echo.New()
+	echo.Validator = validate.New()
+	fiscalDataApp := fiscaldata.NewMockApp(ctrl)
+
+	return strucTest{
+		echo:          echo,
+		fiscalDataApp: fiscalDataApp,
+		h: handler{
+			apps: &app.Container{
+				FiscalData: fiscalDataApp,
+			},
+		},
+	}
+}
+
+// serve runs the handler as a route of the v2 group
+func serve(ctx echo.Context, h echo.HandlerFunc) error {
+	return envelope.Middleware()(h)(ctx)
+}
+
+func TestConvertAmounts(t *testing.T) {
+	t.Run("this test simulate a batch conversion with a missing rate", func(t *testing.T) {
+		testObj := setUpTest(t)
+		body := `{"conversions": [
+			{"amount": 123.45, "date": "2023-07-01", "currency": "Brazil-Real"},
+			{"amount": 10, "source_currency": "Brazil-Real", "date": "2023-07-01", "currency": "USD"},
+			{"amount": 10, "date": "2023-07-01", "currency": "Canada-Dollar"}
+		]}`
+		req := httptest.NewRequest(http.MethodPost, "/v2/conversions", strings.NewReader(body))
+		rec := httptest.NewRecorder()
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+		testObj.fiscalDataApp.EXPECT().GetRatesOfExchange("Brazil-Real", "2023-07-01").Return(&fiscaldata.Data{
+			CurrencyDescription: "Brazil-Real",
+			ExchangeRate:        5,
+			RecordDate:          "2023-06-30",
+		}, nil).Times(2)
+		testObj.fiscalDataApp.EXPECT().GetRatesOfExchange("Canada-Dollar", "2023-07-01").Return(nil, nil)
+
+		err := serve(testObj.echo.NewContext(req, rec), testObj.h.convertAmounts)
+
+		var resp struct {
+			Data []map[string]interface{} `json:"data"`
+		}
+		json.Unmarshal(rec.Body.Bytes(), &resp)
+		assert.NoError(t, err)
+		assert.Equal(t, rec.Code, http.StatusOK)
+		assert.Len(t, resp.Data, 3)
+		assert.Equal(t, resp.Data[0]["converted_purchase_amount"], 617.25)
+		assert.Equal(t, resp.Data[1]["converted_purchase_amount"], 2.0)
+		assert.Equal(t, resp.Data[1]["source_record_date"], "2023-06-30")
+		assert.Equal(t, resp.Data[2]["error"], "no exchange rate within 6 months before the date")
+	})
+
+	t.Run("this test simulate a conversion without a currency", func(t *testing.T) {
+		testObj := setUpTest(t)
+		body := `{"conversions": [{"amount": 123.45, "date": "2023-07-01"}]}`
+		req := httptest.NewRequest(http.MethodPost, "/v2/conversions", strings.NewReader(body))
+		rec := httptest.NewRecorder()
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+		err := serve(testObj.echo.NewContext(req, rec), testObj.h.convertAmounts)
+
+		assert.NoError(t, err)
+		assert.Equal(t, rec.Code, http.StatusBadRequest)
+		assert.Contains(t, rec.Body.String(), "invalid amount, date or currency")
+	})
+}
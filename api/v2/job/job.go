@@ -0,0 +1,233 @@
+package job
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+
+	v1job "github.com/jcpribeiro/TransactionApp/api/v1/job"
+	"github.com/jcpribeiro/TransactionApp/app"
+	jobApp "github.com/jcpribeiro/TransactionApp/app/job"
+	"github.com/jcpribeiro/TransactionApp/internal/auth"
+	"github.com/jcpribeiro/TransactionApp/internal/envelope"
+	"github.com/jcpribeiro/TransactionApp/internal/ratelimit"
+	"github.com/jcpribeiro/TransactionApp/internal/tenant"
+	"github.com/jcpribeiro/TransactionApp/model"
+	jobStore "github.com/jcpribeiro/TransactionApp/store/job"
+
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+)
+
+// Register group jobs. Jobs of a type missing from types are only visible to admins.
+func Register(g *echo.Group, apps *app.Container, types map[string]v1job.Type, limits *ratelimit.Policy) {
+	h := &handler{
+		apps:  apps,
+		types: types,
+	}
+
+	readLimit := limits.Middleware(ratelimit.ClassRead)
+	writeLimit := limits.Middleware(ratelimit.ClassWrite)
+
+	g.POST("", h.submitJob, writeLimit)
+	g.GET("/:id", h.getJob, readLimit)
+	g.POST("/:id/cancel", h.cancelJob, writeLimit)
+	g.GET("/:id/result", h.getJobResult, readLimit)
+}
+
+type handler struct {
+	apps  *app.Container
+	types map[string]v1job.Type
+}
+
+// allowed reports whether the caller was granted the scope of the job type
+func (h *handler) allowed(c echo.Context, jobType string) bool {
+	scope := auth.ScopeAdmin
+	if t, ok := h.types[jobType]; ok {
+		scope = t.Scope
+	}
+
+	principal := auth.PrincipalFrom(c)
+	return principal != nil && principal.HasScope(scope)
+}
+
+// getAllowedJob returns the job when the caller may access it, otherwise it writes the error response
+func (h *handler) getAllowedJob(c echo.Context, id string) (*model.Job, error) {
+	job, err := h.apps.Job.GetJob(c.Request().Context(), tenant.Account(c), id)
+	if err != nil {
+		return nil, jobError(c, err)
+	}
+
+	if !h.allowed(c, job.Type) {
+		return nil, envelope.Error(c, http.StatusForbidden, "missing job type scope")
+	}
+
+	return job, nil
+}
+
+func jobError(c echo.Context, err error) error {
+	if errors.Is(err, jobStore.ErrJobNotFound) {
+		return envelope.Error(c, http.StatusNotFound, "job not found")
+	}
+
+	return err
+}
+
+// submitJob swagger document
+// @Summary Submit a long running job
+// @Description Job types: transaction.export, with the startDate, endDate, currency and format params.
+// @Tags v2 job
+// @Accept  json
+// @Produce  json
+// @Param job body model.SubmitJobParams true "job type and params"
+// @Success 202 {object} model.Envelope{data=model.Job} "Location is the submitted job"
+// @Failure 400 {object} model.Envelope
+// @Failure 401 {object} model.Envelope
+// @Failure 403 {object} model.Envelope
+// @Failure 429 {object} model.Envelope
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /v2/jobs [post]
+func (h *handler) submitJob(c echo.Context) error {
+	params := new(model.SubmitJobParams)
+
+	if err := c.Bind(params); err != nil {
+		logrus.Error(err)
+		return envelope.Error(c, http.StatusBadRequest, "invalid message")
+	}
+
+	if err := c.Validate(params); err != nil {
+		logrus.Error(err)
+		return envelope.Error(c, http.StatusBadRequest, "missing job type")
+	}
+
+	if t, ok := h.types[params.Type]; !ok || !t.Submit {
+		return envelope.Error(c, http.StatusBadRequest, fmt.Sprintf("%s: %s", jobApp.ErrUnknownJobType, params.Type))
+	}
+
+	if !h.allowed(c, params.Type) {
+		return envelope.Error(c, http.StatusForbidden, "missing job type scope")
+	}
+
+	response, err := h.apps.Job.SubmitJob(c.Request().Context(), tenant.Account(c), params.Type, params.Params)
+	if errors.Is(err, jobApp.ErrUnknownJobType) {
+		return envelope.Error(c, http.StatusBadRequest, err.Error())
+	}
+	if err != nil {
+		return err
+	}
+
+	c.Response().Header().Set(echo.HeaderLocation, c.Path()+"/"+response.Id)
+	return envelope.JSON(c, http.StatusAccepted, response, nil)
+}
+
+// getJob swagger document
+// @Summary Retrive the status and progress of a job
+// @Tags v2 job
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Job id"
+// @Success 200 {object} model.Envelope{data=model.Job}
+// @Failure 401 {object} model.Envelope
+// @Failure 403 {object} model.Envelope
+// @Failure 404 {object} model.Envelope
+// @Failure 429 {object} model.Envelope
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /v2/jobs/{id} [get]
+func (h *handler) getJob(c echo.Context) error {
+	params := new(model.JobParams)
+
+	if err := c.Bind(params); err != nil {
+		logrus.Error(err)
+		return envelope.Error(c, http.StatusBadRequest, "invalid path params")
+	}
+
+	response, err := h.getAllowedJob(c, params.Id)
+	if response == nil {
+		return err
+	}
+
+	return envelope.JSON(c, http.StatusOK, response, nil)
+}
+
+// cancelJob swagger document
+// @Summary Cancel a job
+// @Description A pending job is canceled at once, a running job is stopped by its worker within a few seconds.
+// @Tags v2 job
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Job id"
+// @Success 200 {object} model.Envelope{data=model.Job}
+// @Failure 401 {object} model.Envelope
+// @Failure 403 {object} model.Envelope
+// @Failure 404 {object} model.Envelope
+// @Failure 429 {object} model.Envelope
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /v2/jobs/{id}/cancel [post]
+func (h *handler) cancelJob(c echo.Context) error {
+	params := new(model.JobParams)
+
+	if err := c.Bind(params); err != nil {
+		logrus.Error(err)
+		return envelope.Error(c, http.StatusBadRequest, "invalid path params")
+	}
+
+	if job, err := h.getAllowedJob(c, params.Id); job == nil {
+		return err
+	}
+
+	response, err := h.apps.Job.CancelJob(c.Request().Context(), tenant.Account(c), params.Id)
+	if err != nil {
+		return jobError(c, err)
+	}
+
+	return envelope.JSON(c, http.StatusOK, response, nil)
+}
+
+// getJobResult swagger document
+// @Summary Download the result file of a succeeded job
+// @Description The file is not wrapped in the envelope, the errors are.
+// @Tags v2 job
+// @Produce  octet-stream
+// @Param id path string true "Job id"
+// @Success 200 {file} file
+// @Failure 401 {object} model.Envelope
+// @Failure 403 {object} model.Envelope
+// @Failure 404 {object} model.Envelope
+// @Failure 409 {object} model.Envelope
+// @Failure 429 {object} model.Envelope
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /v2/jobs/{id}/result [get]
+func (h *handler) getJobResult(c echo.Context) error {
+	params := new(model.JobParams)
+
+	if err := c.Bind(params); err != nil {
+		logrus.Error(err)
+		return envelope.Error(c, http.StatusBadRequest, "invalid path params")
+	}
+
+	job, err := h.getAllowedJob(c, params.Id)
+	if job == nil {
+		return err
+	}
+
+	if job.Status != model.JobStatusSucceeded || len(job.Result) == 0 {
+		return envelope.Error(c, http.StatusConflict, "job has no result")
+	}
+
+	path, err := h.apps.Job.FilePath(job.Result)
+	if err != nil {
+		return err
+	}
+
+	if _, err := os.Stat(path); err != nil {
+		logrus.Error(err)
+		return envelope.Error(c, http.StatusNotFound, "job result not found")
+	}
+
+	return c.Attachment(path, job.Result)
+}
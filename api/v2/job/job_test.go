@@ -0,0 +1,201 @@
+package job
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	v1job "github.com/jcpribeiro/TransactionApp/api/v1/job"
+	"github.com/jcpribeiro/TransactionApp/app"
+	"github.com/jcpribeiro/TransactionApp/app/job"
+	"github.com/jcpribeiro/TransactionApp/internal/auth"
+	"github.com/jcpribeiro/TransactionApp/internal/envelope"
+	"github.com/jcpribeiro/TransactionApp/internal/tenant"
+	"github.com/jcpribeiro/TransactionApp/internal/validate"
+	"github.com/jcpribeiro/TransactionApp/model"
+	jobStore "github.com/jcpribeiro/TransactionApp/store/job"
+
+	"github.com/golang/mock/gomock"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+const testAccountId = "652d34910a8fc425116b84aa"
+
+var readPrincipal = &auth.Principal{
+	AccountId: testAccountId,
+	Scopes:    []string{auth.ScopeTransactionRead},
+}
+
+type strucTest struct {
+	echo   *echo.Echo
+	jobApp *job.MockApp
+	h      handler
+}
+
+func setUpTest(t *testing.T) strucTest {
+	ctrl := gomock.NewController(t)
+	echo := echo.New()
+	echo.Validator = validate.New()
+	jobApp := job.NewMockApp(ctrl)
+
+	return strucTest{
+		echo:   echo,
+		jobApp: jobApp,
+		h: handler{
+			apps: &app.Container{
+				Job: jobApp,
+			},
+			types: map[string]v1job.Type{
+				"transaction.export": {Scope: auth.ScopeTransactionRead, Submit: true},
+				"transaction.import": {Scope: auth.ScopeTransactionWrite},
+			},
+		},
+	}
+}
+
+// serve runs the handler as a route of the v2 group
+func serve(ctx echo.Context, h echo.HandlerFunc) error {
+	tenant.SetAccount(ctx, testAccountId)
+	auth.SetPrincipal(ctx, readPrincipal)
+	return envelope.Middleware()(h)(ctx)
+}
+
+func TestSubmitJob(t *testing.T) {
+	t.Run("this test simulate a successful job submit", func(t *testing.T) {
+		testObj := setUpTest(t)
+		body := `{"type": "transaction.export", "params": {"format": "csv"}}`
+		req := httptest.NewRequest(http.MethodPost, "/v2/jobs", strings.NewReader(body))
+		rec := httptest.NewRecorder()
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+		testObj.jobApp.EXPECT().SubmitJob(gomock.Any(), testAccountId, "transaction.export", map[string]string{"format": "csv"}).Return(&model.Job{
+			Id:     "652d34910a8fc425116b84d9",
+			Type:   "transaction.export",
+			Status: model.JobStatusPending,
+		}, nil)
+
+		ctx := testObj.echo.NewContext(req, rec)
+		ctx.SetPath("/v2/jobs")
+		err := serve(ctx, testObj.h.submitJob)
+
+		var resp struct {
+			Data model.Job `json:"data"`
+		}
+		json.Unmarshal(rec.Body.Bytes(), &resp)
+		assert.NoError(t, err)
+		assert.Equal(t, rec.Code, http.StatusAccepted)
+		assert.Equal(t, rec.Header().Get(echo.HeaderLocation), "/v2/jobs/652d34910a8fc425116b84d9")
+		assert.Equal(t, resp.Data.Id, "652d34910a8fc425116b84d9")
+	})
+
+	t.Run("this test simulate a job submit of a type that is not submittable", func(t *testing.T) {
+		testObj := setUpTest(t)
+		req := httptest.NewRequest(http.MethodPost, "/v2/jobs", strings.NewReader(`{"type": "transaction.import"}`))
+		rec := httptest.NewRecorder()
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+		ctx := testObj.echo.NewContext(req, rec)
+		err := serve(ctx, testObj.h.submitJob)
+
+		var resp model.Envelope
+		json.Unmarshal(rec.Body.Bytes(), &resp)
+		assert.NoError(t, err)
+		assert.Equal(t, rec.Code, http.StatusBadRequest)
+		assert.Equal(t, resp.Errors[0].Code, "bad_request")
+	})
+}
+
+func TestGetJob(t *testing.T) {
+	t.Run("this test simulate obtaining a job without the job type scope", func(t *testing.T) {
+		testObj := setUpTest(t)
+		req := httptest.NewRequest(http.MethodGet, "/v2/jobs/652d34910a8fc425116b84d9", nil)
+		rec := httptest.NewRecorder()
+
+		testObj.jobApp.EXPECT().GetJob(gomock.Any(), testAccountId, "652d34910a8fc425116b84d9").Return(&model.Job{
+			Id:     "652d34910a8fc425116b84d9",
+			Type:   "transaction.import",
+			Status: model.JobStatusRunning,
+		}, nil)
+
+		ctx := testObj.echo.NewContext(req, rec)
+		ctx.SetParamNames("id")
+		ctx.SetParamValues("652d34910a8fc425116b84d9")
+		err := serve(ctx, testObj.h.getJob)
+
+		assert.NoError(t, err)
+		assert.Equal(t, rec.Code, http.StatusForbidden)
+		assert.JSONEq(t, rec.Body.String(), `{"errors": [{"code": "forbidden", "message": "missing job type scope"}]}`)
+	})
+
+	t.Run("this test simulate a job that does not exist", func(t *testing.T) {
+		testObj := setUpTest(t)
+		req := httptest.NewRequest(http.MethodGet, "/v2/jobs/652d34910a8fc425116b84d9", nil)
+		rec := httptest.NewRecorder()
+
+		testObj.jobApp.EXPECT().GetJob(gomock.Any(), testAccountId, "652d34910a8fc425116b84d9").Return(nil, jobStore.ErrJobNotFound)
+
+		ctx := testObj.echo.NewContext(req, rec)
+		ctx.SetParamNames("id")
+		ctx.SetParamValues("652d34910a8fc425116b84d9")
+		err := serve(ctx, testObj.h.getJob)
+
+		assert.NoError(t, err)
+		assert.Equal(t, rec.Code, http.StatusNotFound)
+	})
+}
+
+func TestCancelJob(t *testing.T) {
+	t.Run("this test simulate the cancellation of a job", func(t *testing.T) {
+		testObj := setUpTest(t)
+		req := httptest.NewRequest(http.MethodPost, "/v2/jobs/652d34910a8fc425116b84d9/cancel", nil)
+		rec := httptest.NewRecorder()
+
+		testObj.jobApp.EXPECT().GetJob(gomock.Any(), testAccountId, "652d34910a8fc425116b84d9").Return(&model.Job{
+			Id:     "652d34910a8fc425116b84d9",
+			Type:   "transaction.export",
+			Status: model.JobStatusRunning,
+		}, nil)
+		testObj.jobApp.EXPECT().CancelJob(gomock.Any(), testAccountId, "652d34910a8fc425116b84d9").Return(&model.Job{
+			Id:     "652d34910a8fc425116b84d9",
+			Type:   "transaction.export",
+			Status: model.JobStatusCanceled,
+		}, nil)
+
+		ctx := testObj.echo.NewContext(req, rec)
+		ctx.SetParamNames("id")
+		ctx.SetParamValues("652d34910a8fc425116b84d9")
+		err := serve(ctx, testObj.h.cancelJob)
+
+		var resp struct {
+			Data model.Job `json:"data"`
+		}
+		json.Unmarshal(rec.Body.Bytes(), &resp)
+		assert.NoError(t, err)
+		assert.Equal(t, resp.Data.Status, model.JobStatusCanceled)
+	})
+}
+
+func TestGetJobResult(t *testing.T) {
+	t.Run("this test simulate the download of a running job result", func(t *testing.T) {
+		testObj := setUpTest(t)
+		req := httptest.NewRequest(http.MethodGet, "/v2/jobs/652d34910a8fc425116b84d9/result", nil)
+		rec := httptest.NewRecorder()
+
+		testObj.jobApp.EXPECT().GetJob(gomock.Any(), testAccountId, "652d34910a8fc425116b84d9").Return(&model.Job{
+			Id:     "652d34910a8fc425116b84d9",
+			Type:   "transaction.export",
+			Status: model.JobStatusRunning,
+		}, nil)
+
+		ctx := testObj.echo.NewContext(req, rec)
+		ctx.SetParamNames("id")
+		ctx.SetParamValues("652d34910a8fc425116b84d9")
+		err := serve(ctx, testObj.h.getJobResult)
+
+		assert.NoError(t, err)
+		assert.Equal(t, rec.Code, http.StatusConflict)
+	})
+}
@@ -0,0 +1,88 @@
+package rate
+
+import (
+	"errors"
+	"net/http"
+
+	v1rate "github.com/jcpribeiro/TransactionApp/api/v1/rate"
+	"github.com/jcpribeiro/TransactionApp/app"
+	"github.com/jcpribeiro/TransactionApp/app/fiscaldata"
+	"github.com/jcpribeiro/TransactionApp/internal/auth"
+	"github.com/jcpribeiro/TransactionApp/internal/cache"
+	"github.com/jcpribeiro/TransactionApp/internal/envelope"
+	"github.com/jcpribeiro/TransactionApp/internal/httpcache"
+	"github.com/jcpribeiro/TransactionApp/internal/ratelimit"
+	"github.com/jcpribeiro/TransactionApp/model"
+
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+)
+
+// Register group rates. The rates are read from the Treasury api and are limited as a conversion.
+func Register(g *echo.Group, apps *app.Container, cache cache.Cache, policies map[string]cache.Policy, limits *ratelimit.Policy) {
+	h := &handler{
+		rates: v1rate.NewLookup(apps, cache, policies),
+	}
+
+	g.GET("", h.getRates, auth.RequireScope(auth.ScopeTransactionRead), limits.Middleware(ratelimit.ClassConversion))
+}
+
+type handler struct {
+	rates *v1rate.Lookup
+}
+
+// getRates swagger document
+// @Summary Get the Treasury rate of a currency on a date, or its series between two dates
+// @Description With date, the rate is the one a conversion on the date uses, the latest one up to 6 months before it.
+// @Description With startDate and endDate, the series has every rate recorded between the dates, oldest first.
+// @Description The rates are the amount of currency for one USD.
+// @Tags v2 rate
+// @Accept  json
+// @Produce  json
+// @Param currency query string true "Currency id. E.g. Brazil-Real"
+// @Param date query string false "Date of the conversion. E.g. 2023-07-01"
+// @Param startDate query string false "Series start date, inclusive. E.g. 2023-01-01"
+// @Param endDate query string false "Series end date, inclusive. E.g. 2023-12-31"
+// @Success 200 {object} model.Envelope{data=model.RateResponse} "The rate on date, or a model.RateSeriesResponse with startDate and endDate"
+// @Failure 400 {object} model.Envelope
+// @Failure 401 {object} model.Envelope
+// @Failure 403 {object} model.Envelope
+// @Failure 404 {object} model.Envelope
+// @Failure 429 {object} model.Envelope
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /v2/rates [get]
+func (h *handler) getRates(c echo.Context) error {
+	params := new(model.GetRateParams)
+
+	if err := c.Bind(params); err != nil {
+		logrus.Error(err)
+		return envelope.Error(c, http.StatusBadRequest, "invalid query params")
+	}
+
+	if err := c.Validate(params); err != nil {
+		logrus.Error(err)
+		return envelope.Error(c, http.StatusBadRequest, "invalid currency or date")
+	}
+
+	if err := v1rate.CheckParams(params); err != nil {
+		return envelope.Error(c, http.StatusBadRequest, err.Error())
+	}
+
+	var response interface{}
+	var err error
+	if params.IsSeries() {
+		response, err = h.rates.Series(c.Request().Context(), params.Currency, params.StartDate, params.EndDate)
+	} else {
+		response, err = h.rates.Rate(c.Request().Context(), params.Currency, params.Date)
+	}
+	if errors.Is(err, fiscaldata.ErrRateNotFound) {
+		return envelope.Error(c, http.StatusNotFound, "no exchange rate within 6 months before the date")
+	}
+	if err != nil {
+		return err
+	}
+
+	c.Response().Header().Set(echo.HeaderCacheControl, httpcache.CacheControl(h.rates.Policy.MaxAge))
+	return envelope.JSON(c, http.StatusOK, response, nil)
+}
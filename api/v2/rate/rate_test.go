@@ -0,0 +1,119 @@
+package rate
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	v1rate "github.com/jcpribeiro/TransactionApp/api/v1/rate"
+	"github.com/jcpribeiro/TransactionApp/app"
+	"github.com/jcpribeiro/TransactionApp/app/fiscaldata"
+	"github.com/jcpribeiro/TransactionApp/internal/cache"
+	"github.com/jcpribeiro/TransactionApp/internal/envelope"
+	"github.com/jcpribeiro/TransactionApp/internal/validate"
+
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+type strucTest struct {
+	echo     *echo.Echo
+	requests int
+	h        handler
+}
+
+// setUpTest serves the rates from a Treasury api answering data, through an in-process cache
+func setUpTest(t *testing.T, data []fiscaldata.Data) *strucTest {
+	testObj := &strucTest{
+		echo: echo.New(),
+	}
+	testObj.echo.Validator = validate.New()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		testObj.requests++
+		w.WriteHeader(200)
+		result, _ := json.Marshal(fiscaldata.RateOfExchangeResponse{
+			Data: data,
+		})
+		w.Write(result)
+	}))
+	t.Cleanup(server.Close)
+
+	apps := &app.Container{
+		FiscalData: fiscaldata.NewAppFiscalData(server.URL, *logrus.New()),
+	}
+	testObj.h = handler{
+		rates: v1rate.NewLookup(apps, cache.NewCache(nil, cache.Options{}, *logrus.New()), nil),
+	}
+
+	return testObj
+}
+
+// get runs the handler as a route of the v2 group
+func (s *strucTest) get(url string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, url, nil)
+	rec := httptest.NewRecorder()
+	envelope.Middleware()(s.h.getRates)(s.echo.NewContext(req, rec))
+	return rec
+}
+
+func TestGetRates(t *testing.T) {
+	t.Run("this test simulate the rate of a date read once", func(t *testing.T) {
+		testObj := setUpTest(t, []fiscaldata.Data{
+			{CurrencyDescription: "Brazil-Real", ExchangeRate: 4.8, RecordDate: "2023-06-30"},
+		})
+
+		rec := testObj.get("/v2/rates?currency=Brazil-Real&date=2023-07-01")
+		testObj.get("/v2/rates?currency=Brazil-Real&date=2023-07-01")
+
+		var resp struct {
+			Data map[string]interface{} `json:"data"`
+		}
+		json.Unmarshal(rec.Body.Bytes(), &resp)
+		assert.Equal(t, rec.Code, http.StatusOK)
+		assert.Equal(t, resp.Data["exchange_rate"], 4.8)
+		assert.Equal(t, resp.Data["record_date"], "2023-06-30")
+		assert.Equal(t, rec.Header().Get(echo.HeaderCacheControl), "private, max-age=3600")
+		assert.Equal(t, testObj.requests, 1)
+	})
+
+	t.Run("this test simulate the series between two dates", func(t *testing.T) {
+		testObj := setUpTest(t, []fiscaldata.Data{
+			{CurrencyDescription: "Brazil-Real", ExchangeRate: 5.06, RecordDate: "2023-03-31"},
+			{CurrencyDescription: "Brazil-Real", ExchangeRate: 4.8, RecordDate: "2023-06-30"},
+		})
+
+		rec := testObj.get("/v2/rates?currency=Brazil-Real&startDate=2023-01-01&endDate=2023-07-01")
+
+		var resp struct {
+			Data struct {
+				Rates []map[string]interface{} `json:"rates"`
+			} `json:"data"`
+		}
+		json.Unmarshal(rec.Body.Bytes(), &resp)
+		assert.Equal(t, rec.Code, http.StatusOK)
+		assert.Len(t, resp.Data.Rates, 2)
+		assert.Equal(t, resp.Data.Rates[0]["record_date"], "2023-03-31")
+	})
+
+	t.Run("this test simulate a date without a rate in the last 6 months", func(t *testing.T) {
+		testObj := setUpTest(t, nil)
+
+		rec := testObj.get("/v2/rates?currency=Brazil-Real&date=2023-07-01")
+
+		assert.Equal(t, rec.Code, http.StatusNotFound)
+		assert.Contains(t, rec.Body.String(), "no exchange rate within 6 months before the date")
+	})
+
+	t.Run("this test simulate a range without an end", func(t *testing.T) {
+		testObj := setUpTest(t, nil)
+
+		rec := testObj.get("/v2/rates?currency=Brazil-Real&startDate=2023-01-01")
+
+		assert.Equal(t, rec.Code, http.StatusBadRequest)
+		assert.Contains(t, rec.Body.String(), v1rate.ErrInvalidRange.Error())
+		assert.Equal(t, testObj.requests, 0)
+	})
+}
@@ -0,0 +1,379 @@
+package transaction
+
+import (
+	"errors"
+	"mime"
+	"net/http"
+	"strings"
+
+	v1transaction "github.com/jcpribeiro/TransactionApp/api/v1/transaction"
+	"github.com/jcpribeiro/TransactionApp/app"
+	"github.com/jcpribeiro/TransactionApp/app/fiscaldata"
+	"github.com/jcpribeiro/TransactionApp/app/transaction"
+	"github.com/jcpribeiro/TransactionApp/internal/auth"
+	"github.com/jcpribeiro/TransactionApp/internal/cache"
+	"github.com/jcpribeiro/TransactionApp/internal/envelope"
+	"github.com/jcpribeiro/TransactionApp/internal/ratelimit"
+	"github.com/jcpribeiro/TransactionApp/internal/tenant"
+	"github.com/jcpribeiro/TransactionApp/model"
+
+	"github.com/labstack/echo/v4"
+	emiddleware "github.com/labstack/echo/v4/middleware"
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Register group transactions.
+// Every read converts currencies through the Treasury api and is limited as a conversion.
+func Register(g *echo.Group, apps *app.Container, cache cache.Cache, limits *ratelimit.Policy) {
+	h := &handler{
+		apps:  apps,
+		cache: cache,
+	}
+
+	read := auth.RequireScope(auth.ScopeTransactionRead)
+	write := auth.RequireScope(auth.ScopeTransactionWrite)
+	conversionLimit := limits.Middleware(ratelimit.ClassConversion)
+	writeLimit := limits.Middleware(ratelimit.ClassWrite)
+
+	g.POST("", h.createTransaction, write, writeLimit)
+	g.POST("/batch", h.createTransactions, write, writeLimit)
+	g.GET("", h.listTransactions, read, conversionLimit)
+	g.GET("/summary", h.getTransactionsSummary, read, conversionLimit)
+	g.GET("/:id", h.getTransaction, read, conversionLimit)
+	g.POST("/import", h.importTransactions, write, writeLimit, emiddleware.BodyLimit(importBodyLimit))
+}
+
+const (
+	importBodyLimit = "1G"
+
+	mimeTextCSV = "text/csv"
+	mimeNDJSON  = "application/x-ndjson"
+)
+
+type handler struct {
+	apps  *app.Container
+	cache cache.Cache
+}
+
+// invalidatePeriods makes the cached periods of the account stale after an insert
+func (h *handler) invalidatePeriods(c echo.Context) {
+	v1transaction.InvalidatePeriods(c.Request().Context(), h.cache, tenant.Account(c))
+}
+
+// insertError writes the errors of an insert, a broken business rule carries its rule
+func insertError(c echo.Context, err error) error {
+	var ruleErr *transaction.RuleError
+	if errors.As(err, &ruleErr) {
+		return envelope.Errors(c, http.StatusBadRequest, &model.APIError{
+			Code:    envelope.CodeRuleViolation,
+			Message: err.Error(),
+			Rule:    ruleErr.Rule,
+		})
+	}
+	if errors.Is(err, transaction.ErrInvalidTransaction) {
+		return envelope.Error(c, http.StatusBadRequest, err.Error())
+	}
+
+	return err
+}
+
+// createTransaction swagger document
+// @Summary Store a purchase transaction
+// @Description The source_currency of a purchase defaults to USD. Other currencies are converted through USD.
+// @Description A transaction breaking a business rule is rejected with a rule_violation error naming the rule.
+// @Tags v2 transaction
+// @Accept  json
+// @Produce  json
+// @Param transaction body model.Transaction true "add new transaction"
+// @Success 201 {object} model.Envelope{data=model.CreatedTransaction} "Location is the created transaction"
+// @Failure 400 {object} model.Envelope
+// @Failure 401 {object} model.Envelope
+// @Failure 403 {object} model.Envelope
+// @Failure 429 {object} model.Envelope
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /v2/transactions [post]
+func (h *handler) createTransaction(c echo.Context) error {
+	t := new(model.Transaction)
+	if err := c.Bind(t); err != nil {
+		logrus.Error(err)
+		return envelope.Error(c, http.StatusBadRequest, "invalid message")
+	}
+
+	id, err := h.apps.Transaction.InsertTransaction(c.Request().Context(), tenant.Account(c), t)
+	if err != nil {
+		return insertError(c, err)
+	}
+	h.invalidatePeriods(c)
+
+	return envelope.Created(c, c.Path()+"/"+id, &model.CreatedTransaction{
+		Id: id,
+	})
+}
+
+// createTransactions swagger document
+// @Summary Store a batch of purchase transactions
+// @Description The batch is stored only when every transaction is valid, the error names the position of the first invalid one.
+// @Tags v2 transaction
+// @Accept  json
+// @Produce  json
+// @Param transactions body []model.Transaction true "add new transactions"
+// @Success 201 {object} model.Envelope{data=model.CreatedTransactions}
+// @Failure 400 {object} model.Envelope
+// @Failure 401 {object} model.Envelope
+// @Failure 403 {object} model.Envelope
+// @Failure 429 {object} model.Envelope
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /v2/transactions/batch [post]
+func (h *handler) createTransactions(c echo.Context) error {
+	var transactions []*model.Transaction
+	if err := c.Bind(&transactions); err != nil {
+		logrus.Error(err)
+		return envelope.Error(c, http.StatusBadRequest, "invalid message")
+	}
+	if len(transactions) == 0 {
+		return envelope.Error(c, http.StatusBadRequest, "empty batch")
+	}
+
+	ids, err := h.apps.Transaction.InsertTransactions(c.Request().Context(), tenant.Account(c), transactions)
+	if err != nil {
+		return insertError(c, err)
+	}
+	h.invalidatePeriods(c)
+
+	return envelope.JSON(c, http.StatusCreated, &model.CreatedTransactions{
+		Ids: ids,
+	}, nil)
+}
+
+// getTransaction swagger document
+// @Summary Retrive a stored purchase transaction converted to the currencies
+// @Description A currency without an exchange rate carries the error of its conversion.
+// @Tags v2 transaction
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Transaction id"
+// @Param currency query string true "Currency ids. If more than one currency is provided, it must be separated by a comma. E.g. Euro Zone-Euro,United Kingdom-Pound"
+// @Success 200 {object} model.Envelope{data=model.TransactionResponse}
+// @Failure 400 {object} model.Envelope
+// @Failure 401 {object} model.Envelope
+// @Failure 403 {object} model.Envelope
+// @Failure 404 {object} model.Envelope
+// @Failure 429 {object} model.Envelope
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /v2/transactions/{id} [get]
+func (h *handler) getTransaction(c echo.Context) error {
+	params := new(model.GetTransactionByIdParams)
+
+	if err := c.Bind(params); err != nil {
+		logrus.Error(err)
+		return envelope.Error(c, http.StatusBadRequest, "invalid query params")
+	}
+
+	if err := c.Validate(params); err != nil {
+		logrus.Error(err)
+		return envelope.Error(c, http.StatusBadRequest, "missing currency")
+	}
+
+	if _, err := primitive.ObjectIDFromHex(params.Id); err != nil {
+		return envelope.Error(c, http.StatusNotFound, "transaction not found")
+	}
+
+	transactions, err := h.apps.Transaction.GetTransactions(c.Request().Context(), tenant.Account(c), []string{params.Id})
+	if err != nil {
+		return err
+	}
+	if len(transactions) == 0 {
+		return envelope.Error(c, http.StatusNotFound, "transaction not found")
+	}
+
+	response := transactions[0]
+	fiscaldata.ConvertTransaction(h.apps.FiscalData, response, splitList(params.Currency))
+
+	return envelope.JSON(c, http.StatusOK, response, nil)
+}
+
+// listTransactions swagger document
+// @Summary List the stored purchase transactions, filtered by description, amount, dates and attributes
+// @Description The transactions are sorted by the time of the period field, or by relevance when text is searched.
+// @Description A page with less than page_size transactions is the last one.
+// @Tags v2 transaction
+// @Accept  json
+// @Produce  json
+// @Param text query string false "Words searched in the description. E.g. coffee"
+// @Param minAmount query number false "Minimum purchase amount in its source currency. E.g. 10.5"
+// @Param maxAmount query number false "Maximum purchase amount in its source currency. E.g. 100"
+// @Param startDate query string false "Period start date, inclusive. E.g. 2023-10-12"
+// @Param endDate query string false "Period end date, inclusive. E.g. 2023-10-14"
+// @Param timezone query string false "IANA timezone of the period dates. Default UTC. E.g. America/Sao_Paulo"
+// @Param field query string false "Time the period filters on. One of purchase or created. Default purchase. E.g. created"
+// @Param purchaseStartDate query string false "First purchase date, inclusive. E.g. 2023-10-12"
+// @Param purchaseEndDate query string false "Last purchase date, inclusive. E.g. 2023-10-14"
+// @Param currency query string true "Currency ids. If more than one currency is provided, it must be separated by a comma. E.g. Euro Zone-Euro,United Kingdom-Pound"
+// @Param page query int false "Page number, starting at 1. E.g. 1"
+// @Param pageSize query int false "Page size, up to 100. Default 20. E.g. 20"
+// @Param category query string false "Transaction category. E.g. food"
+// @Param tags query string false "Tags the transactions must all have, separated by a comma. E.g. work,travel"
+// @Param merchant query string false "Merchant name. E.g. Coffee Shop"
+// @Param metadata query string false "Metadata key:value pairs the transactions must all have, separated by a comma. E.g. project:alpha"
+// @Success 200 {object} model.Envelope{data=[]model.TransactionResponse}
+// @Failure 400 {object} model.Envelope
+// @Failure 401 {object} model.Envelope
+// @Failure 403 {object} model.Envelope
+// @Failure 429 {object} model.Envelope
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /v2/transactions [get]
+func (h *handler) listTransactions(c echo.Context) error {
+	params := new(model.SearchTransactionParams)
+
+	if err := c.Bind(params); err != nil {
+		logrus.Error(err)
+		return envelope.Error(c, http.StatusBadRequest, "invalid query params")
+	}
+
+	if err := c.Validate(params); err != nil {
+		logrus.Error(err)
+		return envelope.Error(c, http.StatusBadRequest, "missing currency or invalid page")
+	}
+
+	response, err := h.apps.Transaction.SearchTransactions(c.Request().Context(), tenant.Account(c), params)
+	if errors.Is(err, transaction.ErrInvalidFilter) {
+		return envelope.Error(c, http.StatusBadRequest, err.Error())
+	}
+	if err != nil {
+		return err
+	}
+
+	currencies := splitList(params.Currency)
+	for _, r := range response {
+		fiscaldata.ConvertTransaction(h.apps.FiscalData, r, currencies)
+	}
+
+	return envelope.JSON(c, http.StatusOK, response, &model.Meta{
+		Page: &model.PageMeta{
+			Page:     params.Page,
+			PageSize: params.PageSize,
+			Count:    len(response),
+		},
+	})
+}
+
+// getTransactionsSummary swagger document
+// @Summary Summarize the purchase transactions of a period, converted to a currency
+// @Description The usd totals convert every source currency to USD on its purchase date.
+// @Tags v2 transaction
+// @Accept  json
+// @Produce  json
+// @Param startDate query string true "Period start date, inclusive. E.g. 2023-10-12"
+// @Param endDate query string true "Period end date, inclusive. E.g. 2023-10-14"
+// @Param timezone query string false "IANA timezone of the period dates. Default UTC. E.g. America/Sao_Paulo"
+// @Param field query string false "Time the period filters on. One of purchase or created. Default purchase. E.g. created"
+// @Param currency query string true "Currency ids. E.g. Argentina-Peso"
+// @Param groupBy query string false "Period grouping. One of day, week or month. E.g. month"
+// @Success 200 {object} model.Envelope{data=[]model.TransactionSummary}
+// @Failure 400 {object} model.Envelope
+// @Failure 401 {object} model.Envelope
+// @Failure 403 {object} model.Envelope
+// @Failure 404 {object} model.Envelope
+// @Failure 429 {object} model.Envelope
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /v2/transactions/summary [get]
+func (h *handler) getTransactionsSummary(c echo.Context) error {
+	params := new(model.GetTransactionSummaryParams)
+
+	if err := c.Bind(params); err != nil {
+		logrus.Error(err)
+		return envelope.Error(c, http.StatusBadRequest, "invalid query params")
+	}
+
+	if err := c.Validate(params); err != nil {
+		logrus.Error(err)
+		return envelope.Error(c, http.StatusBadRequest, "missing url params")
+	}
+
+	aggregates, err := h.apps.Transaction.GetTransactionsSummary(c.Request().Context(), tenant.Account(c), params.StartDate, params.EndDate, params.Timezone, params.Field, params.GroupBy)
+	if errors.Is(err, transaction.ErrInvalidFilter) {
+		return envelope.Error(c, http.StatusBadRequest, err.Error())
+	}
+	if err != nil {
+		return err
+	}
+
+	response, err := fiscaldata.Summarize(h.apps.FiscalData, aggregates, params.Currency)
+	if errors.Is(err, fiscaldata.ErrRateNotFound) {
+		return envelope.Error(c, http.StatusNotFound, err.Error())
+	}
+	if err != nil {
+		return err
+	}
+
+	return envelope.JSON(c, http.StatusOK, response, nil)
+}
+
+// importTransactions swagger document
+// @Summary Import purchase transactions from a csv or ndjson file
+// @Description The csv must have a header with the purchase_amount and description columns, in any order.
+// @Description The purchase_date, purchased_at, source_currency, category, merchant and tags columns are optional, tags are separated by semicolons.
+// @Description A row without purchase_date and purchased_at is purchased at the current UTC time.
+// @Description Invalid rows are reported by line and do not fail the whole import.
+// @Tags v2 transaction
+// @Accept  text/csv
+// @Accept  application/x-ndjson
+// @Produce  json
+// @Param file body string true "Transactions file"
+// @Success 200 {object} model.Envelope{data=model.ImportReport}
+// @Failure 400 {object} model.Envelope
+// @Failure 401 {object} model.Envelope
+// @Failure 403 {object} model.Envelope
+// @Failure 413 {object} model.Envelope
+// @Failure 415 {object} model.Envelope
+// @Failure 429 {object} model.Envelope
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /v2/transactions/import [post]
+func (h *handler) importTransactions(c echo.Context) error {
+	var format string
+	mediaType, _, _ := mime.ParseMediaType(c.Request().Header.Get(echo.HeaderContentType))
+	switch mediaType {
+	case mimeTextCSV:
+		format = transaction.ImportFormatCSV
+	case mimeNDJSON:
+		format = transaction.ImportFormatNDJSON
+	default:
+		return envelope.Error(c, http.StatusUnsupportedMediaType, "content type must be text/csv or application/x-ndjson")
+	}
+
+	response, err := h.apps.Transaction.ImportTransactions(c.Request().Context(), tenant.Account(c), format, "", c.Request().Body)
+	h.invalidatePeriods(c)
+	if errors.Is(err, echo.ErrStatusRequestEntityTooLarge) {
+		return err
+	}
+	if err != nil {
+		logrus.Error(err)
+		return envelope.Error(c, http.StatusBadRequest, "invalid file")
+	}
+
+	return envelope.JSON(c, http.StatusOK, response, nil)
+}
+
+// splitList splits a comma separated param, ignoring blanks and duplicates
+func splitList(value string) []string {
+	items := strings.Split(value, ",")
+	list := make([]string, 0, len(items))
+	seen := make(map[string]bool, len(items))
+	for _, item := range items {
+		item = strings.TrimSpace(item)
+		if len(item) == 0 || seen[item] {
+			continue
+		}
+		seen[item] = true
+		list = append(list, item)
+	}
+
+	return list
+}
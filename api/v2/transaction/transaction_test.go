@@ -0,0 +1,320 @@
+package transaction
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/jcpribeiro/TransactionApp/app"
+	"github.com/jcpribeiro/TransactionApp/app/fiscaldata"
+	"github.com/jcpribeiro/TransactionApp/app/transaction"
+	"github.com/jcpribeiro/TransactionApp/internal/cache"
+	"github.com/jcpribeiro/TransactionApp/internal/envelope"
+	"github.com/jcpribeiro/TransactionApp/internal/tenant"
+	"github.com/jcpribeiro/TransactionApp/internal/validate"
+	"github.com/jcpribeiro/TransactionApp/model"
+
+	"github.com/golang/mock/gomock"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+const testAccountId = "652d34910a8fc425116b84aa"
+
+type strucTest struct {
+	echo           *echo.Echo
+	transactionApp *transaction.MockApp
+	fiscalDataApp  *fiscaldata.MockApp
+	cache          *cache.MockCache
+	h              handler
+}
+
+func setUpTest(t *testing.T) strucTest {
+	ctrl := gomock.NewController(t)
+	echo := echo.New()
+	echo.Validator = validate.New()
+	transactionApp := transaction.NewMockApp(ctrl)
+	fiscalDataApp := fiscaldata.NewMockApp(ctrl)
+	cache := cache.NewMockCache(ctrl)
+
+	return strucTest{
+		echo:           echo,
+		transactionApp: transactionApp,
+		fiscalDataApp:  fiscalDataApp,
+		cache:          cache,
+		h: handler{
+			apps: &app.Container{
+				FiscalData:  fiscalDataApp,
+				Transaction: transactionApp,
+			},
+			cache: cache,
+		},
+	}
+}
+
+// serve runs the handler as a route of the v2 group
+func serve(ctx echo.Context, h echo.HandlerFunc) error {
+	tenant.SetAccount(ctx, testAccountId)
+	return envelope.Middleware()(h)(ctx)
+}
+
+func TestCreateTransaction(t *testing.T) {
+	t.Run("this test simulate a successful transaction insert", func(t *testing.T) {
+		testObj := setUpTest(t)
+		body := `{"purchase_amount": 23.7, "description": "Test", "purchase_date": "2023-10-15"}`
+		req := httptest.NewRequest(http.MethodPost, "/v2/transactions", strings.NewReader(body))
+		rec := httptest.NewRecorder()
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+		testObj.transactionApp.EXPECT().InsertTransaction(gomock.Any(), testAccountId, &model.Transaction{
+			PurchaseAmount: 23.7,
+			Description:    "Test",
+			PurchaseDate:   "2023-10-15",
+		}).Return("652d34910a8fc425116b84d9", nil)
+		testObj.cache.EXPECT().Incr(gomock.Any(), testAccountId+":period-generation").Return(int64(4), nil)
+
+		ctx := testObj.echo.NewContext(req, rec)
+		ctx.SetPath("/v2/transactions")
+		err := serve(ctx, testObj.h.createTransaction)
+
+		assert.NoError(t, err)
+		assert.Equal(t, rec.Code, http.StatusCreated)
+		assert.Equal(t, rec.Header().Get(echo.HeaderLocation), "/v2/transactions/652d34910a8fc425116b84d9")
+		assert.JSONEq(t, rec.Body.String(), `{"data": {"id": "652d34910a8fc425116b84d9"}}`)
+	})
+
+	t.Run("this test simulate a transaction insert breaking a business rule", func(t *testing.T) {
+		testObj := setUpTest(t)
+		body := `{"purchase_amount": 23.7, "description": "Test", "purchase_date": "3023-01-01"}`
+		req := httptest.NewRequest(http.MethodPost, "/v2/transactions", strings.NewReader(body))
+		rec := httptest.NewRecorder()
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+		testObj.transactionApp.EXPECT().InsertTransaction(gomock.Any(), testAccountId, gomock.Any()).Return("", fmt.Errorf("%s: %w", transaction.ErrInvalidTransaction, &transaction.RuleError{
+			Rule:   transaction.RuleDateWindow,
+			Reason: "purchase_date 3023-01-01 is after 2023-10-16",
+		}))
+
+		ctx := testObj.echo.NewContext(req, rec)
+		err := serve(ctx, testObj.h.createTransaction)
+
+		var resp model.Envelope
+		json.Unmarshal(rec.Body.Bytes(), &resp)
+		assert.NoError(t, err)
+		assert.Equal(t, rec.Code, http.StatusBadRequest)
+		assert.Nil(t, resp.Data)
+		assert.Equal(t, resp.Errors[0].Code, envelope.CodeRuleViolation)
+		assert.Equal(t, resp.Errors[0].Rule, transaction.RuleDateWindow)
+	})
+
+	t.Run("this test simulate an invalid transaction insert", func(t *testing.T) {
+		testObj := setUpTest(t)
+		req := httptest.NewRequest(http.MethodPost, "/v2/transactions", strings.NewReader(`{"description": "Test"}`))
+		rec := httptest.NewRecorder()
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+		testObj.transactionApp.EXPECT().InsertTransaction(gomock.Any(), testAccountId, gomock.Any()).Return("", fmt.Errorf("%w: purchase_date is required", transaction.ErrInvalidTransaction))
+
+		ctx := testObj.echo.NewContext(req, rec)
+		err := serve(ctx, testObj.h.createTransaction)
+
+		var resp model.Envelope
+		json.Unmarshal(rec.Body.Bytes(), &resp)
+		assert.NoError(t, err)
+		assert.Equal(t, rec.Code, http.StatusBadRequest)
+		assert.Equal(t, resp.Errors[0].Code, "bad_request")
+		assert.Empty(t, resp.Errors[0].Rule)
+	})
+}
+
+func TestCreateTransactions(t *testing.T) {
+	t.Run("this test simulate a successful batch insert", func(t *testing.T) {
+		testObj := setUpTest(t)
+		body := `[{"purchase_amount": 23.7, "description": "Test", "purchase_date": "2023-10-15"}]`
+		req := httptest.NewRequest(http.MethodPost, "/v2/transactions/batch", strings.NewReader(body))
+		rec := httptest.NewRecorder()
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+		testObj.transactionApp.EXPECT().InsertTransactions(gomock.Any(), testAccountId, gomock.Any()).Return([]string{"652d34910a8fc425116b84d9"}, nil)
+		testObj.cache.EXPECT().Incr(gomock.Any(), testAccountId+":period-generation").Return(int64(4), nil)
+
+		ctx := testObj.echo.NewContext(req, rec)
+		err := serve(ctx, testObj.h.createTransactions)
+
+		assert.NoError(t, err)
+		assert.Equal(t, rec.Code, http.StatusCreated)
+		assert.JSONEq(t, rec.Body.String(), `{"data": {"ids": ["652d34910a8fc425116b84d9"]}}`)
+	})
+
+	t.Run("this test simulate an empty batch insert", func(t *testing.T) {
+		testObj := setUpTest(t)
+		req := httptest.NewRequest(http.MethodPost, "/v2/transactions/batch", strings.NewReader(`[]`))
+		rec := httptest.NewRecorder()
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+		ctx := testObj.echo.NewContext(req, rec)
+		err := serve(ctx, testObj.h.createTransactions)
+
+		assert.NoError(t, err)
+		assert.Equal(t, rec.Code, http.StatusBadRequest)
+	})
+}
+
+func TestGetTransaction(t *testing.T) {
+	t.Run("this test simulate a successful transaction read", func(t *testing.T) {
+		testObj := setUpTest(t)
+		req := httptest.NewRequest(http.MethodGet, "/v2/transactions/652d34910a8fc425116b84d9?currency=Canada-Dollar", nil)
+		rec := httptest.NewRecorder()
+
+		testObj.transactionApp.EXPECT().GetTransactions(gomock.Any(), testAccountId, []string{"652d34910a8fc425116b84d9"}).Return([]*model.TransactionResponse{{
+			Id:             "652d34910a8fc425116b84d9",
+			PurchaseAmount: 10.00,
+			SourceCurrency: model.CurrencyUSD,
+			Description:    "Test",
+			PurchaseDate:   "2023-10-15",
+		}}, nil)
+		testObj.fiscalDataApp.EXPECT().GetRatesOfExchange("Canada-Dollar", "2023-10-15").Return(&fiscaldata.Data{
+			CurrencyDescription: "Canada-Dollar",
+			ExchangeRate:        1.23,
+			RecordDate:          "2023-10-15",
+		}, nil)
+
+		ctx := testObj.echo.NewContext(req, rec)
+		ctx.SetParamNames("id")
+		ctx.SetParamValues("652d34910a8fc425116b84d9")
+		err := serve(ctx, testObj.h.getTransaction)
+
+		var resp struct {
+			Data model.TransactionResponse `json:"data"`
+		}
+		json.Unmarshal(rec.Body.Bytes(), &resp)
+		assert.NoError(t, err)
+		assert.Equal(t, rec.Code, http.StatusOK)
+		assert.Equal(t, resp.Data.Id, "652d34910a8fc425116b84d9")
+		assert.Equal(t, resp.Data.Conversions["Canada-Dollar"].ConvertedPurchaseAmount, 12.3)
+	})
+
+	t.Run("this test simulate a read of a transaction that does not exist", func(t *testing.T) {
+		testObj := setUpTest(t)
+		req := httptest.NewRequest(http.MethodGet, "/v2/transactions/652d34910a8fc425116b84d9?currency=Canada-Dollar", nil)
+		rec := httptest.NewRecorder()
+
+		testObj.transactionApp.EXPECT().GetTransactions(gomock.Any(), testAccountId, []string{"652d34910a8fc425116b84d9"}).Return(nil, nil)
+
+		ctx := testObj.echo.NewContext(req, rec)
+		ctx.SetParamNames("id")
+		ctx.SetParamValues("652d34910a8fc425116b84d9")
+		err := serve(ctx, testObj.h.getTransaction)
+
+		assert.NoError(t, err)
+		assert.Equal(t, rec.Code, http.StatusNotFound)
+		assert.JSONEq(t, rec.Body.String(), `{"errors": [{"code": "not_found", "message": "transaction not found"}]}`)
+	})
+
+	t.Run("this test simulate a read of an invalid transaction id", func(t *testing.T) {
+		testObj := setUpTest(t)
+		req := httptest.NewRequest(http.MethodGet, "/v2/transactions/invalid?currency=Canada-Dollar", nil)
+		rec := httptest.NewRecorder()
+
+		ctx := testObj.echo.NewContext(req, rec)
+		ctx.SetParamNames("id")
+		ctx.SetParamValues("invalid")
+		err := serve(ctx, testObj.h.getTransaction)
+
+		assert.NoError(t, err)
+		assert.Equal(t, rec.Code, http.StatusNotFound)
+	})
+
+	t.Run("this test simulate a transaction read without currency", func(t *testing.T) {
+		testObj := setUpTest(t)
+		req := httptest.NewRequest(http.MethodGet, "/v2/transactions/652d34910a8fc425116b84d9", nil)
+		rec := httptest.NewRecorder()
+
+		ctx := testObj.echo.NewContext(req, rec)
+		ctx.SetParamNames("id")
+		ctx.SetParamValues("652d34910a8fc425116b84d9")
+		err := serve(ctx, testObj.h.getTransaction)
+
+		assert.NoError(t, err)
+		assert.Equal(t, rec.Code, http.StatusBadRequest)
+	})
+}
+
+func TestListTransactions(t *testing.T) {
+	t.Run("this test simulate a successful transaction list", func(t *testing.T) {
+		testObj := setUpTest(t)
+		req := httptest.NewRequest(http.MethodGet, "/v2/transactions?currency=Canada-Dollar&pageSize=2", nil)
+		rec := httptest.NewRecorder()
+		rec.Header().Set("RateLimit-Limit", "60")
+		rec.Header().Set("RateLimit-Remaining", "59")
+		rec.Header().Set("RateLimit-Reset", "60")
+
+		testObj.transactionApp.EXPECT().SearchTransactions(gomock.Any(), testAccountId, gomock.Any()).DoAndReturn(func(_ interface{}, _ string, params *model.SearchTransactionParams) ([]*model.TransactionResponse, error) {
+			params.Page = 1
+			return []*model.TransactionResponse{{
+				Id:             "652d34910a8fc425116b84d9",
+				PurchaseAmount: 10.00,
+				SourceCurrency: model.CurrencyUSD,
+				PurchaseDate:   "2023-10-15",
+			}}, nil
+		})
+		testObj.fiscalDataApp.EXPECT().GetRatesOfExchange("Canada-Dollar", "2023-10-15").Return(nil, errors.New("an error has ocurred"))
+
+		ctx := testObj.echo.NewContext(req, rec)
+		err := serve(ctx, testObj.h.listTransactions)
+
+		var resp struct {
+			Data []*model.TransactionResponse `json:"data"`
+			Meta *model.Meta                  `json:"meta"`
+		}
+		json.Unmarshal(rec.Body.Bytes(), &resp)
+		assert.NoError(t, err)
+		assert.Equal(t, rec.Code, http.StatusOK)
+		assert.Len(t, resp.Data, 1)
+		assert.Equal(t, resp.Meta.Page, &model.PageMeta{Page: 1, PageSize: 2, Count: 1})
+		assert.Equal(t, resp.Meta.RateLimit, &model.RateLimitMeta{Limit: 60, Remaining: 59, Reset: 60})
+	})
+
+	t.Run("this test simulate a transaction list with an invalid filter", func(t *testing.T) {
+		testObj := setUpTest(t)
+		req := httptest.NewRequest(http.MethodGet, "/v2/transactions?currency=Canada-Dollar&timezone=Mars", nil)
+		rec := httptest.NewRecorder()
+
+		testObj.transactionApp.EXPECT().SearchTransactions(gomock.Any(), testAccountId, gomock.Any()).Return(nil, fmt.Errorf("%w: unknown timezone Mars", transaction.ErrInvalidFilter))
+
+		ctx := testObj.echo.NewContext(req, rec)
+		err := serve(ctx, testObj.h.listTransactions)
+
+		assert.NoError(t, err)
+		assert.Equal(t, rec.Code, http.StatusBadRequest)
+	})
+}
+
+func TestGetTransactionsSummary(t *testing.T) {
+	t.Run("this test simulate a summary without an exchange rate", func(t *testing.T) {
+		testObj := setUpTest(t)
+		req := httptest.NewRequest(http.MethodGet, "/v2/transactions/summary?startDate=2023-10-01&endDate=2023-10-31&currency=Canada-Dollar", nil)
+		rec := httptest.NewRecorder()
+
+		testObj.transactionApp.EXPECT().GetTransactionsSummary(gomock.Any(), testAccountId, "2023-10-01", "2023-10-31", "", "", "").Return([]*model.TransactionAggregate{{
+			Period:         "2023-10",
+			SourceCurrency: model.CurrencyUSD,
+			PurchaseDate:   "2023-10-15",
+			Count:          1,
+			Sum:            10,
+			Min:            10,
+			Max:            10,
+		}}, nil)
+		testObj.fiscalDataApp.EXPECT().GetRatesOfExchange("Canada-Dollar", "2023-10-15").Return(nil, nil)
+
+		ctx := testObj.echo.NewContext(req, rec)
+		err := serve(ctx, testObj.h.getTransactionsSummary)
+
+		assert.NoError(t, err)
+		assert.Equal(t, rec.Code, http.StatusNotFound)
+	})
+}
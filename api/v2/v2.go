@@ -0,0 +1,33 @@
+package v2
+
+import (
+	v1 "github.com/jcpribeiro/TransactionApp/api/v1"
+	"github.com/jcpribeiro/TransactionApp/app"
+	"github.com/jcpribeiro/TransactionApp/internal/cache"
+	"github.com/jcpribeiro/TransactionApp/internal/envelope"
+	"github.com/jcpribeiro/TransactionApp/internal/ratelimit"
+	"github.com/jcpribeiro/TransactionApp/internal/tenant"
+
+	"github.com/jcpribeiro/TransactionApp/api/v2/apikey"
+	"github.com/jcpribeiro/TransactionApp/api/v2/conversion"
+	"github.com/jcpribeiro/TransactionApp/api/v2/job"
+	"github.com/jcpribeiro/TransactionApp/api/v2/rate"
+	"github.com/jcpribeiro/TransactionApp/api/v2/transaction"
+	"github.com/jcpribeiro/TransactionApp/api/v2/webhook"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Registers v2 routes. Every response, including the errors, is wrapped in the envelope.
+// The job runners are registered by v1, which must be registered first.
+func Register(g *echo.Group, apps *app.Container, cache cache.Cache, policies map[string]cache.Policy, resolve tenant.Resolver, limits *ratelimit.Policy) {
+	v2 := g.Group("/v2", envelope.Middleware())
+	requireAccount := tenant.Middleware(resolve)
+
+	transaction.Register(v2.Group("/transactions", requireAccount), apps, cache, limits)
+	job.Register(v2.Group("/jobs", requireAccount), apps, v1.JobTypes, limits)
+	apikey.Register(v2.Group("/keys", requireAccount), apps, limits)
+	webhook.Register(v2.Group("/webhooks", requireAccount), apps, limits)
+	conversion.Register(v2.Group("/conversions", requireAccount), apps, limits)
+	rate.Register(v2.Group("/rates", requireAccount), apps, cache, policies, limits)
+}
@@ -0,0 +1,37 @@
+package v2
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jcpribeiro/TransactionApp/app"
+	"github.com/jcpribeiro/TransactionApp/internal/envelope"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegister(t *testing.T) {
+	e := echo.New()
+	e.HTTPErrorHandler = envelope.ErrorHandler(e.DefaultHTTPErrorHandler)
+	Register(e.Group(""), &app.Container{}, nil, nil, func(c echo.Context) (string, error) {
+		return "", nil
+	}, nil)
+
+	t.Run("this test simulate a request without credentials", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v2/transactions?currency=Canada-Dollar", nil))
+
+		assert.Equal(t, rec.Code, http.StatusUnauthorized)
+		assert.JSONEq(t, rec.Body.String(), `{"errors": [{"code": "unauthorized", "message": "unauthorized"}]}`)
+	})
+
+	t.Run("this test simulate a request of an unknown route", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v2/unknown", nil))
+
+		assert.Equal(t, rec.Code, http.StatusNotFound)
+		assert.JSONEq(t, rec.Body.String(), `{"errors": [{"code": "not_found", "message": "Not Found"}]}`)
+	})
+}
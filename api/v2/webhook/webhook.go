@@ -0,0 +1,175 @@
+package webhook
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/jcpribeiro/TransactionApp/app"
+	webhookApp "github.com/jcpribeiro/TransactionApp/app/webhook"
+	"github.com/jcpribeiro/TransactionApp/internal/auth"
+	"github.com/jcpribeiro/TransactionApp/internal/envelope"
+	"github.com/jcpribeiro/TransactionApp/internal/ratelimit"
+	"github.com/jcpribeiro/TransactionApp/internal/tenant"
+	"github.com/jcpribeiro/TransactionApp/model"
+	"github.com/jcpribeiro/TransactionApp/store/webhook"
+
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+)
+
+// Register group webhook. Every route requires the admin scope.
+func Register(g *echo.Group, apps *app.Container, limits *ratelimit.Policy) {
+	h := &handler{
+		apps: apps,
+	}
+
+	readLimit := limits.Middleware(ratelimit.ClassRead)
+	writeLimit := limits.Middleware(ratelimit.ClassWrite)
+
+	g.Use(auth.RequireScope(auth.ScopeAdmin))
+	g.POST("", h.createWebhook, writeLimit)
+	g.GET("", h.listWebhooks, readLimit)
+	g.DELETE("/:id", h.deleteWebhook, writeLimit)
+	g.GET("/:id/deliveries", h.listDeliveries, readLimit)
+}
+
+type handler struct {
+	apps *app.Container
+}
+
+func webhookError(c echo.Context, err error) error {
+	if errors.Is(err, webhook.ErrWebhookNotFound) {
+		return envelope.Error(c, http.StatusNotFound, "webhook not found")
+	}
+
+	return err
+}
+
+// createWebhook swagger document
+// @Summary Subscribe a url to transaction events of the caller account
+// @Description Events: transaction.created, emitted by the inserts, batches and imports.
+// @Description Every payload is posted with the X-Webhook-Signature header, sha256= followed by the hex
+// @Description HMAC-SHA256 of the X-Webhook-Timestamp header, a dot and the body, keyed by the secret.
+// @Description A failed delivery is retried with an exponential backoff until it is dead.
+// @Description The url must not target a loopback, private or link-local address, and must be https in production.
+// @Tags v2 webhook
+// @Accept  json
+// @Produce  json
+// @Param webhook body model.CreateWebhookParams true "url, secret and events"
+// @Success 201 {object} model.Envelope{data=model.Webhook}
+// @Failure 400 {object} model.Envelope
+// @Failure 401 {object} model.Envelope
+// @Failure 403 {object} model.Envelope
+// @Failure 429 {object} model.Envelope
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /v2/webhooks [post]
+func (h *handler) createWebhook(c echo.Context) error {
+	params := new(model.CreateWebhookParams)
+
+	if err := c.Bind(params); err != nil {
+		logrus.Error(err)
+		return envelope.Error(c, http.StatusBadRequest, "invalid message")
+	}
+
+	if err := c.Validate(params); err != nil {
+		logrus.Error(err)
+		return envelope.Error(c, http.StatusBadRequest, "invalid url, secret or events")
+	}
+
+	response, err := h.apps.Webhook.CreateWebhook(c.Request().Context(), tenant.Account(c), params)
+	if errors.Is(err, webhookApp.ErrForbiddenURL) {
+		return envelope.Error(c, http.StatusBadRequest, err.Error())
+	}
+	if err != nil {
+		return err
+	}
+
+	return envelope.JSON(c, http.StatusCreated, response, nil)
+}
+
+// listWebhooks swagger document
+// @Summary Retrive the webhooks of the caller account
+// @Tags v2 webhook
+// @Accept  json
+// @Produce  json
+// @Success 200 {object} model.Envelope{data=[]model.Webhook}
+// @Failure 401 {object} model.Envelope
+// @Failure 403 {object} model.Envelope
+// @Failure 429 {object} model.Envelope
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /v2/webhooks [get]
+func (h *handler) listWebhooks(c echo.Context) error {
+	response, err := h.apps.Webhook.ListWebhooks(c.Request().Context(), tenant.Account(c))
+	if err != nil {
+		return err
+	}
+	if response == nil {
+		response = []*model.Webhook{}
+	}
+
+	return envelope.JSON(c, http.StatusOK, response, nil)
+}
+
+// deleteWebhook swagger document
+// @Summary Delete a webhook of the caller account
+// @Description Its pending deliveries are not sent.
+// @Tags v2 webhook
+// @Param id path string true "Webhook id"
+// @Success 204
+// @Failure 401 {object} model.Envelope
+// @Failure 403 {object} model.Envelope
+// @Failure 404 {object} model.Envelope
+// @Failure 429 {object} model.Envelope
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /v2/webhooks/{id} [delete]
+func (h *handler) deleteWebhook(c echo.Context) error {
+	params := new(model.WebhookParams)
+
+	if err := c.Bind(params); err != nil {
+		logrus.Error(err)
+		return envelope.Error(c, http.StatusBadRequest, "invalid path params")
+	}
+
+	if err := h.apps.Webhook.DeleteWebhook(c.Request().Context(), tenant.Account(c), params.Id); err != nil {
+		return webhookError(c, err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// listDeliveries swagger document
+// @Summary Retrive the latest deliveries of a webhook of the caller account
+// @Description The 100 latest deliveries, the newest first. A dead delivery failed every attempt and is not retried.
+// @Tags v2 webhook
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Webhook id"
+// @Success 200 {object} model.Envelope{data=[]model.WebhookDelivery}
+// @Failure 401 {object} model.Envelope
+// @Failure 403 {object} model.Envelope
+// @Failure 404 {object} model.Envelope
+// @Failure 429 {object} model.Envelope
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /v2/webhooks/{id}/deliveries [get]
+func (h *handler) listDeliveries(c echo.Context) error {
+	params := new(model.WebhookParams)
+
+	if err := c.Bind(params); err != nil {
+		logrus.Error(err)
+		return envelope.Error(c, http.StatusBadRequest, "invalid path params")
+	}
+
+	response, err := h.apps.Webhook.ListDeliveries(c.Request().Context(), tenant.Account(c), params.Id)
+	if err != nil {
+		return webhookError(c, err)
+	}
+	if response == nil {
+		response = []*model.WebhookDelivery{}
+	}
+
+	return envelope.JSON(c, http.StatusOK, response, nil)
+}
@@ -0,0 +1,229 @@
+package webhook
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/jcpribeiro/TransactionApp/app"
+	webhookApp "github.com/jcpribeiro/TransactionApp/app/webhook"
+	"github.com/jcpribeiro/TransactionApp/internal/envelope"
+	"github.com/jcpribeiro/TransactionApp/internal/tenant"
+	"github.com/jcpribeiro/TransactionApp/internal/validate"
+	"github.com/jcpribeiro/TransactionApp/model"
+	"github.com/jcpribeiro/TransactionApp/store/webhook"
+
+	"github.com/golang/mock/gomock"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+const testAccountId = "652d34910a8fc425116b84aa"
+
+type strucTest struct {
+	echo       *echo.Echo
+	webhookApp *webhookApp.MockApp
+	h          handler
+}
+
+func setUpTest(t *testing.T) strucTest {
+	ctrl := gomock.NewController(t)
+	echo := echo.New()
+	echo.Validator = validate.New()
+	webhookApp := webhookApp.NewMockApp(ctrl)
+
+	return strucTest{
+		echo:       echo,
+		webhookApp: webhookApp,
+		h: handler{
+			apps: &app.Container{
+				Webhook: webhookApp,
+			},
+		},
+	}
+}
+
+// serve runs the handler as a route of the v2 group
+func serve(ctx echo.Context, h echo.HandlerFunc) error {
+	tenant.SetAccount(ctx, testAccountId)
+	return envelope.Middleware()(h)(ctx)
+}
+
+func TestCreateWebhook(t *testing.T) {
+	t.Run("this test simulate a successful webhook creation", func(t *testing.T) {
+		testObj := setUpTest(t)
+		body := `{"url": "https://example.com/hook", "secret": "0123456789abcdef", "events": ["transaction.created"]}`
+		req := httptest.NewRequest(http.MethodPost, "/v2/webhooks", strings.NewReader(body))
+		rec := httptest.NewRecorder()
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+		testObj.webhookApp.EXPECT().CreateWebhook(gomock.Any(), testAccountId, &model.CreateWebhookParams{
+			URL:    "https://example.com/hook",
+			Secret: "0123456789abcdef",
+			Events: []string{model.EventTransactionCreated},
+		}).Return(&model.Webhook{
+			Id:        "652d34910a8fc425116b84d9",
+			AccountId: testAccountId,
+			URL:       "https://example.com/hook",
+			Secret:    "0123456789abcdef",
+			Events:    []string{model.EventTransactionCreated},
+		}, nil)
+
+		ctx := testObj.echo.NewContext(req, rec)
+		err := serve(ctx, testObj.h.createWebhook)
+
+		var resp struct {
+			Data map[string]interface{} `json:"data"`
+		}
+		json.Unmarshal(rec.Body.Bytes(), &resp)
+		assert.NoError(t, err)
+		assert.Equal(t, rec.Code, http.StatusCreated)
+		assert.Equal(t, resp.Data["id"], "652d34910a8fc425116b84d9")
+		assert.NotContains(t, resp.Data, "secret")
+	})
+
+	t.Run("this test simulate a webhook creation with an unknown event", func(t *testing.T) {
+		testObj := setUpTest(t)
+		body := `{"url": "https://example.com/hook", "secret": "0123456789abcdef", "events": ["transaction.read"]}`
+		req := httptest.NewRequest(http.MethodPost, "/v2/webhooks", strings.NewReader(body))
+		rec := httptest.NewRecorder()
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+		ctx := testObj.echo.NewContext(req, rec)
+		err := serve(ctx, testObj.h.createWebhook)
+
+		assert.NoError(t, err)
+		assert.Equal(t, rec.Code, http.StatusBadRequest)
+		assert.JSONEq(t, rec.Body.String(), `{"errors": [{"code": "bad_request", "message": "invalid url, secret or events"}]}`)
+	})
+
+	t.Run("this test simulate a webhook creation targeting a private address", func(t *testing.T) {
+		testObj := setUpTest(t)
+		body := `{"url": "http://169.254.169.254/latest", "secret": "0123456789abcdef", "events": ["transaction.created"]}`
+		req := httptest.NewRequest(http.MethodPost, "/v2/webhooks", strings.NewReader(body))
+		rec := httptest.NewRecorder()
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+		testObj.webhookApp.EXPECT().CreateWebhook(gomock.Any(), testAccountId, gomock.Any()).
+			Return(nil, fmt.Errorf("%w: 169.254.169.254 is not a public address", webhookApp.ErrForbiddenURL))
+
+		ctx := testObj.echo.NewContext(req, rec)
+		err := serve(ctx, testObj.h.createWebhook)
+
+		assert.NoError(t, err)
+		assert.Equal(t, rec.Code, http.StatusBadRequest)
+		assert.Contains(t, rec.Body.String(), "is not a public address")
+	})
+
+	t.Run("this test simulate a webhook creation with a short secret", func(t *testing.T) {
+		testObj := setUpTest(t)
+		body := `{"url": "https://example.com/hook", "secret": "secret", "events": ["transaction.created"]}`
+		req := httptest.NewRequest(http.MethodPost, "/v2/webhooks", strings.NewReader(body))
+		rec := httptest.NewRecorder()
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+		ctx := testObj.echo.NewContext(req, rec)
+		err := serve(ctx, testObj.h.createWebhook)
+
+		assert.NoError(t, err)
+		assert.Equal(t, rec.Code, http.StatusBadRequest)
+	})
+}
+
+func TestListWebhooks(t *testing.T) {
+	t.Run("this test simulate an account without webhooks", func(t *testing.T) {
+		testObj := setUpTest(t)
+		req := httptest.NewRequest(http.MethodGet, "/v2/webhooks", nil)
+		rec := httptest.NewRecorder()
+
+		testObj.webhookApp.EXPECT().ListWebhooks(gomock.Any(), testAccountId).Return(nil, nil)
+
+		ctx := testObj.echo.NewContext(req, rec)
+		err := serve(ctx, testObj.h.listWebhooks)
+
+		assert.NoError(t, err)
+		assert.Equal(t, rec.Code, http.StatusOK)
+		assert.JSONEq(t, rec.Body.String(), `{"data": []}`)
+	})
+}
+
+func TestDeleteWebhook(t *testing.T) {
+	t.Run("this test simulate a successful webhook deletion", func(t *testing.T) {
+		testObj := setUpTest(t)
+		req := httptest.NewRequest(http.MethodDelete, "/v2/webhooks/652d34910a8fc425116b84d9", nil)
+		rec := httptest.NewRecorder()
+
+		testObj.webhookApp.EXPECT().DeleteWebhook(gomock.Any(), testAccountId, "652d34910a8fc425116b84d9").Return(nil)
+
+		ctx := testObj.echo.NewContext(req, rec)
+		ctx.SetParamNames("id")
+		ctx.SetParamValues("652d34910a8fc425116b84d9")
+		err := serve(ctx, testObj.h.deleteWebhook)
+
+		assert.NoError(t, err)
+		assert.Equal(t, rec.Code, http.StatusNoContent)
+	})
+}
+
+func TestListDeliveries(t *testing.T) {
+	t.Run("this test simulate the delivery log of a webhook", func(t *testing.T) {
+		testObj := setUpTest(t)
+		req := httptest.NewRequest(http.MethodGet, "/v2/webhooks/652d34910a8fc425116b84d9/deliveries", nil)
+		rec := httptest.NewRecorder()
+
+		testObj.webhookApp.EXPECT().ListDeliveries(gomock.Any(), testAccountId, "652d34910a8fc425116b84d9").Return([]*model.WebhookDelivery{{
+			Id:         "652d34910a8fc425116b84e1",
+			WebhookId:  "652d34910a8fc425116b84d9",
+			EventId:    "652d34910a8fc425116b84e2",
+			Event:      model.EventTransactionCreated,
+			Payload:    json.RawMessage(`{"type":"transaction.created"}`),
+			Status:     model.DeliveryStatusDead,
+			Attempts:   8,
+			StatusCode: 500,
+			Error:      "unexpected status 500",
+			CreatedAt:  1697150153,
+			UpdatedAt:  1697153753,
+		}}, nil)
+
+		ctx := testObj.echo.NewContext(req, rec)
+		ctx.SetParamNames("id")
+		ctx.SetParamValues("652d34910a8fc425116b84d9")
+		err := serve(ctx, testObj.h.listDeliveries)
+
+		assert.NoError(t, err)
+		assert.Equal(t, rec.Code, http.StatusOK)
+		assert.JSONEq(t, rec.Body.String(), `{"data": [{
+			"id": "652d34910a8fc425116b84e1",
+			"webhook_id": "652d34910a8fc425116b84d9",
+			"event_id": "652d34910a8fc425116b84e2",
+			"event": "transaction.created",
+			"payload": {"type": "transaction.created"},
+			"status": "dead",
+			"attempts": 8,
+			"status_code": 500,
+			"error": "unexpected status 500",
+			"created_at": 1697150153,
+			"updated_at": 1697153753
+		}]}`)
+	})
+
+	t.Run("this test simulate the delivery log of a webhook that does not exist", func(t *testing.T) {
+		testObj := setUpTest(t)
+		req := httptest.NewRequest(http.MethodGet, "/v2/webhooks/652d34910a8fc425116b84d9/deliveries", nil)
+		rec := httptest.NewRecorder()
+
+		testObj.webhookApp.EXPECT().ListDeliveries(gomock.Any(), testAccountId, "652d34910a8fc425116b84d9").Return(nil, webhook.ErrWebhookNotFound)
+
+		ctx := testObj.echo.NewContext(req, rec)
+		ctx.SetParamNames("id")
+		ctx.SetParamValues("652d34910a8fc425116b84d9")
+		err := serve(ctx, testObj.h.listDeliveries)
+
+		assert.NoError(t, err)
+		assert.Equal(t, rec.Code, http.StatusNotFound)
+		assert.JSONEq(t, rec.Body.String(), `{"errors": [{"code": "not_found", "message": "webhook not found"}]}`)
+	})
+}
@@ -0,0 +1,141 @@
+package apikey
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jcpribeiro/TransactionApp/internal/auth"
+	"github.com/jcpribeiro/TransactionApp/model"
+	"github.com/jcpribeiro/TransactionApp/store"
+	"github.com/jcpribeiro/TransactionApp/store/apikey"
+
+	"github.com/sirupsen/logrus"
+)
+
+//go:generate mockgen -source=$GOFILE -destination=apikey_mock.go -package=$GOPACKAGE
+
+const (
+	keyPrefix    = "tk_"
+	keyBytes     = 32
+	prefixLength = 11
+	// bootstrapName names the admin key provided at startup
+	bootstrapName = "bootstrap"
+)
+
+// ErrWeakBootstrapKey is returned when the bootstrap key is not a key this app could have generated
+var ErrWeakBootstrapKey = fmt.Errorf("the bootstrap key must start with %s and have at least %d random bytes", keyPrefix, keyBytes)
+
+type App interface {
+	CreateAPIKey(ctx context.Context, accountId, name string, scopes []string) (*model.CreatedAPIKey, error)
+	ListAPIKeys(ctx context.Context, accountId string) ([]*model.APIKey, error)
+	RevokeAPIKey(ctx context.Context, accountId, id string) error
+	VerifyAPIKey(ctx context.Context, key string) (*auth.Principal, error)
+	BootstrapAPIKey(ctx context.Context, accountId, key string) error
+}
+
+type appImpl struct {
+	stores *store.Container
+	log    logrus.Logger
+}
+
+func NewAppAPIKey(stores *store.Container, log logrus.Logger) App {
+	return &appImpl{
+		stores: stores,
+		log:    log,
+	}
+}
+
+// hashKey hashes a key for storage. The keys are random, so a fast hash is enough to make the stored value useless.
+func hashKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// CreateAPIKey generates a key of the account. Only its hash is stored, the key is returned once.
+func (a appImpl) CreateAPIKey(ctx context.Context, accountId, name string, scopes []string) (*model.CreatedAPIKey, error) {
+	secret := make([]byte, keyBytes)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, fmt.Errorf("failed to generate api key: %w", err)
+	}
+	key := keyPrefix + base64.RawURLEncoding.EncodeToString(secret)
+
+	apiKey := &model.APIKey{
+		AccountId: accountId,
+		Name:      name,
+		Prefix:    key[:prefixLength],
+		Hash:      hashKey(key),
+		Scopes:    scopes,
+		CreatedAt: time.Now().Unix(),
+	}
+
+	id, err := a.stores.APIKey.InsertAPIKey(ctx, apiKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to insert api key: %w", err)
+	}
+	apiKey.Id = id
+
+	return &model.CreatedAPIKey{
+		APIKey: apiKey,
+		Key:    key,
+	}, nil
+}
+
+func (a appImpl) ListAPIKeys(ctx context.Context, accountId string) ([]*model.APIKey, error) {
+	return a.stores.APIKey.ListAPIKeys(ctx, accountId)
+}
+
+func (a appImpl) RevokeAPIKey(ctx context.Context, accountId, id string) error {
+	return a.stores.APIKey.RevokeAPIKey(ctx, accountId, id, time.Now().Unix())
+}
+
+// BootstrapAPIKey stores key as an admin key of the account, so that the first keys can be created
+// without a token. It does nothing when the key is already stored, a revoked bootstrap key stays revoked.
+func (a appImpl) BootstrapAPIKey(ctx context.Context, accountId, key string) error {
+	secret, err := base64.RawURLEncoding.DecodeString(strings.TrimPrefix(key, keyPrefix))
+	if !strings.HasPrefix(key, keyPrefix) || err != nil || len(secret) < keyBytes {
+		return ErrWeakBootstrapKey
+	}
+
+	_, err = a.stores.APIKey.InsertAPIKey(ctx, &model.APIKey{
+		AccountId: accountId,
+		Name:      bootstrapName,
+		Prefix:    key[:prefixLength],
+		Hash:      hashKey(key),
+		Scopes:    []string{auth.ScopeAdmin},
+		CreatedAt: time.Now().Unix(),
+	})
+	if errors.Is(err, apikey.ErrAPIKeyExists) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to insert bootstrap api key: %w", err)
+	}
+
+	a.log.Infof("bootstrap api key %s created for account %s", key[:prefixLength], accountId)
+	return nil
+}
+
+// VerifyAPIKey returns the principal of an active key, or nil when the key is unknown or revoked.
+// It satisfies auth.KeyVerifier.
+func (a appImpl) VerifyAPIKey(ctx context.Context, key string) (*auth.Principal, error) {
+	apiKey, err := a.stores.APIKey.GetAPIKeyByHash(ctx, hashKey(key))
+	if errors.Is(err, apikey.ErrAPIKeyNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get api key: %w", err)
+	}
+
+	return &auth.Principal{
+		AccountId: apiKey.AccountId,
+		Subject:   apiKey.Id,
+		Scopes:    apiKey.Scopes,
+	}, nil
+}
@@ -0,0 +1,110 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: apikey.go
+
+// Package apikey is a generated GoMock package.
+package apikey
+
+import (
+	context "context"
+	reflect "reflect"
+	auth "github.com/jcpribeiro/TransactionApp/internal/auth"
+	model "github.com/jcpribeiro/TransactionApp/model"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockApp is a mock of App interface.
+type MockApp struct {
+	ctrl     *gomock.Controller
+	recorder *MockAppMockRecorder
+}
+
+// MockAppMockRecorder is the mock recorder for MockApp.
+type MockAppMockRecorder struct {
+	mock *MockApp
+}
+
+// NewMockApp creates a new mock instance.
+func NewMockApp(ctrl *gomock.Controller) *MockApp {
+	mock := &MockApp{ctrl: ctrl}
+	mock.recorder = &MockAppMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockApp) EXPECT() *MockAppMockRecorder {
+	return m.recorder
+}
+
+// BootstrapAPIKey mocks base method.
+func (m *MockApp) BootstrapAPIKey(ctx context.Context, accountId, key string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BootstrapAPIKey", ctx, accountId, key)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// BootstrapAPIKey indicates an expected call of BootstrapAPIKey.
+func (mr *MockAppMockRecorder) BootstrapAPIKey(ctx, accountId, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BootstrapAPIKey", reflect.TypeOf((*MockApp)(nil).BootstrapAPIKey), ctx, accountId, key)
+}
+
+// CreateAPIKey mocks base method.
+func (m *MockApp) CreateAPIKey(ctx context.Context, accountId, name string, scopes []string) (*model.CreatedAPIKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAPIKey", ctx, accountId, name, scopes)
+	ret0, _ := ret[0].(*model.CreatedAPIKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateAPIKey indicates an expected call of CreateAPIKey.
+func (mr *MockAppMockRecorder) CreateAPIKey(ctx, accountId, name, scopes interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAPIKey", reflect.TypeOf((*MockApp)(nil).CreateAPIKey), ctx, accountId, name, scopes)
+}
+
+// ListAPIKeys mocks base method.
+func (m *MockApp) ListAPIKeys(ctx context.Context, accountId string) ([]*model.APIKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAPIKeys", ctx, accountId)
+	ret0, _ := ret[0].([]*model.APIKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAPIKeys indicates an expected call of ListAPIKeys.
+func (mr *MockAppMockRecorder) ListAPIKeys(ctx, accountId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAPIKeys", reflect.TypeOf((*MockApp)(nil).ListAPIKeys), ctx, accountId)
+}
+
+// RevokeAPIKey mocks base method.
+func (m *MockApp) RevokeAPIKey(ctx context.Context, accountId, id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeAPIKey", ctx, accountId, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeAPIKey indicates an expected call of RevokeAPIKey.
+func (mr *MockAppMockRecorder) RevokeAPIKey(ctx, accountId, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeAPIKey", reflect.TypeOf((*MockApp)(nil).RevokeAPIKey), ctx, accountId, id)
+}
+
+// VerifyAPIKey mocks base method.
+func (m *MockApp) VerifyAPIKey(ctx context.Context, key string) (*auth.Principal, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyAPIKey", ctx, key)
+	ret0, _ := ret[0].(*auth.Principal)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// VerifyAPIKey indicates an expected call of VerifyAPIKey.
+func (mr *MockAppMockRecorder) VerifyAPIKey(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyAPIKey", reflect.TypeOf((*MockApp)(nil).VerifyAPIKey), ctx, key)
+}
@@ -0,0 +1,163 @@
+package apikey
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/jcpribeiro/TransactionApp/internal/auth"
+	"github.com/jcpribeiro/TransactionApp/model"
+	"github.com/jcpribeiro/TransactionApp/store"
+	"github.com/jcpribeiro/TransactionApp/store/apikey"
+
+	"github.com/golang/mock/gomock"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+const testAccountId = "652d34910a8fc425116b84aa"
+
+type structTest struct {
+	storesMock *apikey.MockStore
+	appTest    App
+}
+
+func setUptest(t *testing.T) structTest {
+	ctrl := gomock.NewController(t)
+	storesMock := apikey.NewMockStore(ctrl)
+
+	return structTest{
+		storesMock: storesMock,
+		appTest: NewAppAPIKey(&store.Container{
+			APIKey: storesMock,
+		}, *logrus.New()),
+	}
+}
+
+func TestCreateAPIKey(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("this test simulate a successful api key creation", func(t *testing.T) {
+		testObj := setUptest(t)
+		var stored *model.APIKey
+		testObj.storesMock.EXPECT().InsertAPIKey(ctx, gomock.Any()).DoAndReturn(func(ctx context.Context, key *model.APIKey) (string, error) {
+			stored = key
+			return "652d34910a8fc425116b84d9", nil
+		})
+
+		created, err := testObj.appTest.CreateAPIKey(ctx, testAccountId, "integration", []string{auth.ScopeTransactionRead})
+
+		assert.NoError(t, err)
+		assert.Equal(t, created.Id, "652d34910a8fc425116b84d9")
+		assert.True(t, strings.HasPrefix(created.Key, "tk_"))
+		assert.True(t, strings.HasPrefix(created.Key, stored.Prefix))
+		assert.Equal(t, stored.AccountId, testAccountId)
+		assert.Equal(t, stored.Hash, hashKey(created.Key))
+		assert.NotContains(t, stored.Hash, created.Key)
+	})
+
+	t.Run("this test simulate an error during the api key creation", func(t *testing.T) {
+		testObj := setUptest(t)
+		testObj.storesMock.EXPECT().InsertAPIKey(ctx, gomock.Any()).Return("", errors.New("an error has ocurred"))
+
+		created, err := testObj.appTest.CreateAPIKey(ctx, testAccountId, "integration", []string{auth.ScopeTransactionRead})
+
+		assert.Error(t, err)
+		assert.Nil(t, created)
+	})
+}
+
+func TestVerifyAPIKey(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("this test simulate a valid api key", func(t *testing.T) {
+		testObj := setUptest(t)
+		testObj.storesMock.EXPECT().GetAPIKeyByHash(ctx, hashKey("tk_key")).Return(&model.APIKey{
+			Id:        "652d34910a8fc425116b84d9",
+			AccountId: testAccountId,
+			Scopes:    []string{auth.ScopeAdmin},
+		}, nil)
+
+		principal, err := testObj.appTest.VerifyAPIKey(ctx, "tk_key")
+
+		assert.NoError(t, err)
+		assert.Equal(t, principal, &auth.Principal{
+			AccountId: testAccountId,
+			Subject:   "652d34910a8fc425116b84d9",
+			Scopes:    []string{auth.ScopeAdmin},
+		})
+	})
+
+	t.Run("this test simulate an unknown or revoked api key", func(t *testing.T) {
+		testObj := setUptest(t)
+		testObj.storesMock.EXPECT().GetAPIKeyByHash(ctx, gomock.Any()).Return(nil, apikey.ErrAPIKeyNotFound)
+
+		principal, err := testObj.appTest.VerifyAPIKey(ctx, "tk_key")
+
+		assert.NoError(t, err)
+		assert.Nil(t, principal)
+	})
+
+	t.Run("this test simulate an error verifying an api key", func(t *testing.T) {
+		testObj := setUptest(t)
+		testObj.storesMock.EXPECT().GetAPIKeyByHash(ctx, gomock.Any()).Return(nil, errors.New("an error has ocurred"))
+
+		principal, err := testObj.appTest.VerifyAPIKey(ctx, "tk_key")
+
+		assert.Error(t, err)
+		assert.Nil(t, principal)
+	})
+}
+
+func TestBootstrapAPIKey(t *testing.T) {
+	ctx := context.Background()
+	key := "tk_" + strings.Repeat("a", 43)
+
+	t.Run("this test simulate storing the bootstrap api key", func(t *testing.T) {
+		testObj := setUptest(t)
+		testObj.storesMock.EXPECT().InsertAPIKey(ctx, gomock.Any()).DoAndReturn(func(ctx context.Context, stored *model.APIKey) (string, error) {
+			assert.Equal(t, stored.AccountId, testAccountId)
+			assert.Equal(t, stored.Hash, hashKey(key))
+			assert.Equal(t, stored.Prefix, key[:prefixLength])
+			assert.Equal(t, stored.Scopes, []string{auth.ScopeAdmin})
+			return "652d34910a8fc425116b84d9", nil
+		})
+
+		err := testObj.appTest.BootstrapAPIKey(ctx, testAccountId, key)
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("this test simulate a bootstrap api key already stored", func(t *testing.T) {
+		testObj := setUptest(t)
+		testObj.storesMock.EXPECT().InsertAPIKey(ctx, gomock.Any()).Return("", apikey.ErrAPIKeyExists)
+
+		err := testObj.appTest.BootstrapAPIKey(ctx, testAccountId, key)
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("this test simulate a weak bootstrap api key", func(t *testing.T) {
+		testObj := setUptest(t)
+
+		for _, weak := range []string{"tk_short", strings.Repeat("a", 46), "tk_" + strings.Repeat("*", 43)} {
+			err := testObj.appTest.BootstrapAPIKey(ctx, testAccountId, weak)
+
+			assert.ErrorIs(t, err, ErrWeakBootstrapKey, weak)
+		}
+	})
+}
+
+func TestRevokeAPIKey(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("this test simulate revoking an api key", func(t *testing.T) {
+		testObj := setUptest(t)
+		testObj.storesMock.EXPECT().RevokeAPIKey(ctx, testAccountId, "652d34910a8fc425116b84d9", gomock.Any()).Return(nil)
+
+		err := testObj.appTest.RevokeAPIKey(ctx, testAccountId, "652d34910a8fc425116b84d9")
+
+		assert.NoError(t, err)
+	})
+}
@@ -1,10 +1,17 @@
 package app
 
 import (
+	"github.com/jcpribeiro/TransactionApp/app/apikey"
 	"github.com/jcpribeiro/TransactionApp/app/fiscaldata"
+	"github.com/jcpribeiro/TransactionApp/app/ingest"
+	"github.com/jcpribeiro/TransactionApp/app/job"
+	"github.com/jcpribeiro/TransactionApp/app/migration"
+	"github.com/jcpribeiro/TransactionApp/app/outbox"
 	"github.com/jcpribeiro/TransactionApp/app/transaction"
+	"github.com/jcpribeiro/TransactionApp/app/webhook"
 	"github.com/jcpribeiro/TransactionApp/store"
 
+	"github.com/go-redis/redis/v8"
 	"github.com/sirupsen/logrus"
 )
 
@@ -12,20 +19,41 @@ import (
 type Container struct {
 	FiscalData  fiscaldata.App
 	Transaction transaction.App
+	Job         job.App
+	APIKey      apikey.App
+	Migration   migration.App
+	Webhook     webhook.App
+	Outbox      outbox.App
+	Ingest      ingest.App
 }
 
 type Options struct {
-	Log    logrus.Logger
-	URL    string
-	Stores *store.Container
+	Log       logrus.Logger
+	URL       string
+	Stores    *store.Container
+	Redis     *redis.Client
+	Jobs      job.Options
+	Migration migration.Options
+	Rules     transaction.Rules
+	Webhooks  webhook.Options
+	Outbox    outbox.Options
+	Ingest    ingest.Options
 }
 
 // New creates a new instance of the services
 func NewApp(opts Options) *Container {
 	opts.Log.Info("Registered APP")
 
+	transactions := transaction.NewAppTransaction(opts.Stores, opts.Rules, opts.Log)
+
 	return &Container{
 		FiscalData:  fiscaldata.NewAppFiscalData(opts.URL, opts.Log),
-		Transaction: transaction.NewAppTransaction(opts.Stores, opts.Log),
+		Transaction: transactions,
+		Job:         job.NewAppJob(opts.Stores, opts.Jobs, opts.Log),
+		APIKey:      apikey.NewAppAPIKey(opts.Stores, opts.Log),
+		Migration:   migration.NewAppMigration(opts.Stores, opts.Migration, opts.Log),
+		Webhook:     webhook.NewAppWebhook(opts.Stores, opts.Webhooks, opts.Log),
+		Outbox:      outbox.NewAppOutbox(opts.Stores, opts.Outbox, opts.Log),
+		Ingest:      ingest.NewAppIngest(opts.Redis, transactions, opts.Ingest, opts.Log),
 	}
 }
@@ -0,0 +1,183 @@
+package fiscaldata
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/jcpribeiro/TransactionApp/internal/util"
+	"github.com/jcpribeiro/TransactionApp/model"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Conversion converts an amount with both legs of the exchange, the source leg is left out for USD amounts
+func (e *Exchange) Conversion(amount float64) *model.Conversion {
+	conversion := &model.Conversion{
+		ExchangeRate:            util.RoundFloat(e.TargetRate(), 2),
+		RecordDate:              e.TargetRecordDate(),
+		ConvertedPurchaseAmount: util.RoundFloat(e.Convert(amount), 2),
+	}
+	if e.Source != nil {
+		conversion.SourceExchangeRate = util.RoundFloat(e.SourceRate(), 2)
+		conversion.SourceRecordDate = e.SourceRecordDate()
+	}
+
+	return conversion
+}
+
+// SetSourceCurrency defaults the transactions stored before the source currency existed to USD
+func SetSourceCurrency(r *model.TransactionResponse) {
+	if len(r.SourceCurrency) == 0 {
+		r.SourceCurrency = model.CurrencyUSD
+	}
+}
+
+// SetConversion fills the top level conversion of a transaction converted to a single currency
+func SetConversion(r *model.TransactionResponse, conversion *model.Conversion) {
+	r.ExchangeRate = conversion.ExchangeRate
+	r.RecordDate = conversion.RecordDate
+	r.SourceExchangeRate = conversion.SourceExchangeRate
+	r.SourceRecordDate = conversion.SourceRecordDate
+	r.ConvertedPurchaseAmount = conversion.ConvertedPurchaseAmount
+}
+
+// ConvertTransaction fills the conversions of a transaction, one per currency.
+// A currency without an exchange rate is reported in its own conversion instead
+// of failing the others. It returns false if any conversion has failed.
+// A single currency also fills the top level conversion, as the clients of a single currency read it.
+func ConvertTransaction(app App, r *model.TransactionResponse, currencies []string) bool {
+	converted := true
+	SetSourceCurrency(r)
+	r.PurchaseAmount = util.RoundFloat(r.PurchaseAmount, 2)
+	r.Conversions = make(map[string]*model.Conversion, len(currencies))
+	for _, currency := range currencies {
+		exchange, err := Triangulate(app, r.SourceCurrency, currency, r.PurchaseDate)
+		if err != nil {
+			logrus.Error(err)
+			r.Conversions[currency] = &model.Conversion{
+				Error: "failed to get rates exchange",
+			}
+			converted = false
+			continue
+		}
+
+		r.Conversions[currency] = exchange.Conversion(r.PurchaseAmount)
+		if len(currencies) == 1 {
+			SetConversion(r, r.Conversions[currency])
+		}
+	}
+
+	return converted
+}
+
+// ConvertAmounts converts ad-hoc amounts without storing them, one result per request in the same
+// order. The rates of a currency pair and date are fetched once. A request without an exchange rate
+// is reported in its own result instead of failing the others.
+func ConvertAmounts(app App, requests []*model.ConversionRequest) []*model.ConversionResult {
+	exchanges := make(map[string]*Exchange)
+	failures := make(map[string]error)
+	results := make([]*model.ConversionResult, 0, len(requests))
+	for _, r := range requests {
+		result := &model.ConversionResult{
+			Amount:         util.RoundFloat(r.Amount, 2),
+			SourceCurrency: r.SourceCurrency,
+			Date:           r.Date,
+			Currency:       r.Currency,
+		}
+		if IsUSD(result.SourceCurrency) {
+			result.SourceCurrency = model.CurrencyUSD
+		}
+		results = append(results, result)
+
+		pair := fmt.Sprintf("%s:%s:%s", result.SourceCurrency, result.Currency, result.Date)
+		exchange, ok := exchanges[pair]
+		err := failures[pair]
+		if !ok && err == nil {
+			exchange, err = Triangulate(app, result.SourceCurrency, result.Currency, result.Date)
+			if err != nil {
+				logrus.Error(err)
+				failures[pair] = err
+			}
+			exchanges[pair] = exchange
+		}
+		if err != nil {
+			result.Conversion = &model.Conversion{Error: conversionError(err)}
+			continue
+		}
+
+		result.Conversion = exchange.Conversion(result.Amount)
+	}
+
+	return results
+}
+
+// conversionError is the error reported to the client for a failed conversion
+func conversionError(err error) string {
+	if errors.Is(err, ErrRateNotFound) {
+		return "no exchange rate within 6 months before the date"
+	}
+
+	return "failed to get rates exchange"
+}
+
+// Summarize converts the purchase amount totals of a period to USD and to currency, merging the
+// totals of each period. The rates of a source currency and purchase date are fetched once.
+func Summarize(app App, aggregates []*model.TransactionAggregate, currency string) ([]*model.TransactionSummary, error) {
+	exchanges := make(map[string]*Exchange)
+	periods := make(map[string]*model.TransactionSummary)
+	summaries := make([]*model.TransactionSummary, 0)
+	for _, a := range aggregates {
+		key := fmt.Sprintf("%s:%s", a.SourceCurrency, a.PurchaseDate)
+		exchange, ok := exchanges[key]
+		if !ok {
+			var err error
+			exchange, err = Triangulate(app, a.SourceCurrency, currency, a.PurchaseDate)
+			if err != nil {
+				return nil, err
+			}
+			exchanges[key] = exchange
+		}
+
+		summary, ok := periods[a.Period]
+		if !ok {
+			summary = &model.TransactionSummary{
+				Period:   a.Period,
+				Currency: currency,
+			}
+			periods[a.Period] = summary
+			summaries = append(summaries, summary)
+		}
+
+		addSummaryValues(&summary.USD, a.Count, exchange.USD(a.Sum), exchange.USD(a.Min), exchange.USD(a.Max))
+		addSummaryValues(&summary.Converted, a.Count, exchange.Convert(a.Sum), exchange.Convert(a.Min), exchange.Convert(a.Max))
+	}
+
+	for _, summary := range summaries {
+		roundSummaryValues(&summary.USD)
+		roundSummaryValues(&summary.Converted)
+	}
+
+	return summaries, nil
+}
+
+// addSummaryValues merges partial totals into the period totals
+func addSummaryValues(v *model.SummaryValues, count int64, sum, min, max float64) {
+	if v.Count == 0 || min < v.Min {
+		v.Min = min
+	}
+	if v.Count == 0 || max > v.Max {
+		v.Max = max
+	}
+	v.Count += count
+	v.Sum += sum
+}
+
+// roundSummaryValues calculates the average and rounds the totals
+func roundSummaryValues(v *model.SummaryValues) {
+	if v.Count > 0 {
+		v.Average = util.RoundFloat(v.Sum/float64(v.Count), 2)
+	}
+	v.Sum = util.RoundFloat(v.Sum, 2)
+	v.Min = util.RoundFloat(v.Min, 2)
+	v.Max = util.RoundFloat(v.Max, 2)
+}
@@ -0,0 +1,63 @@
+package fiscaldata
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/jcpribeiro/TransactionApp/model"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConvertAmounts(t *testing.T) {
+	t.Run("this test simulate a batch with duplicated requests", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		appMock := NewMockApp(ctrl)
+		appMock.EXPECT().GetRatesOfExchange("Brazil-Real", "2023-07-01").Return(&Data{
+			CurrencyDescription: "Brazil-Real",
+			ExchangeRate:        4.8,
+			RecordDate:          "2023-06-30",
+		}, nil).Times(1)
+
+		results := ConvertAmounts(appMock, []*model.ConversionRequest{
+			{Amount: 123.45, Date: "2023-07-01", Currency: "Brazil-Real"},
+			{Amount: 123.45, SourceCurrency: model.CurrencyUSD, Date: "2023-07-01", Currency: "Brazil-Real"},
+			{Amount: 10, Date: "2023-07-01", Currency: "Brazil-Real"},
+		})
+
+		assert.Len(t, results, 3)
+		assert.Equal(t, results[0].SourceCurrency, model.CurrencyUSD)
+		assert.Equal(t, results[0].ExchangeRate, 4.8)
+		assert.Equal(t, results[0].RecordDate, "2023-06-30")
+		assert.Equal(t, results[0].ConvertedPurchaseAmount, 592.56)
+		assert.Equal(t, results[1], results[0])
+		assert.Equal(t, results[2].Amount, 10.0)
+		assert.Equal(t, results[2].ConvertedPurchaseAmount, 48.0)
+	})
+
+	t.Run("this test simulate a request without a rate in the last 6 months", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		appMock := NewMockApp(ctrl)
+		appMock.EXPECT().GetRatesOfExchange("Brazil-Real", "2023-07-01").Return(nil, nil).Times(1)
+		appMock.EXPECT().GetRatesOfExchange("Canada-Dollar", "2023-07-01").Return(nil, errors.New("timeout"))
+		appMock.EXPECT().GetRatesOfExchange("Euro Zone-Euro", "2023-07-01").Return(&Data{
+			CurrencyDescription: "Euro Zone-Euro",
+			ExchangeRate:        0.9,
+			RecordDate:          "2023-06-30",
+		}, nil)
+
+		results := ConvertAmounts(appMock, []*model.ConversionRequest{
+			{Amount: 123.45, Date: "2023-07-01", Currency: "Brazil-Real"},
+			{Amount: 10, Date: "2023-07-01", Currency: "Brazil-Real"},
+			{Amount: 10, Date: "2023-07-01", Currency: "Canada-Dollar"},
+			{Amount: 10, Date: "2023-07-01", Currency: "Euro Zone-Euro"},
+		})
+
+		assert.Len(t, results, 4)
+		assert.Equal(t, results[0].Error, "no exchange rate within 6 months before the date")
+		assert.Equal(t, results[1].Error, "no exchange rate within 6 months before the date")
+		assert.Equal(t, results[2].Error, "failed to get rates exchange")
+		assert.Equal(t, results[3].ConvertedPurchaseAmount, 9.0)
+	})
+}
@@ -0,0 +1,108 @@
+package fiscaldata
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/jcpribeiro/TransactionApp/model"
+)
+
+// ErrRateNotFound is returned when the Treasury has no rate for the currency up to six months before the date
+var ErrRateNotFound = errors.New("exchange rate not found")
+
+// Exchange is a conversion triangulated through USD. Source is the rate of the source
+// currency and is nil when the amount is in USD, Target is nil when converting to USD.
+type Exchange struct {
+	Source *Data
+	Target *Data
+}
+
+// Triangulate gets the rates of both legs of a conversion on the purchase date
+func Triangulate(app App, source, target, date string) (*Exchange, error) {
+	exchange := &Exchange{}
+
+	var err error
+	if !IsUSD(source) {
+		exchange.Source, err = rateOf(app, source, date)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if !IsUSD(target) {
+		exchange.Target, err = rateOf(app, target, date)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return exchange, nil
+}
+
+func rateOf(app App, currency, date string) (*Data, error) {
+	data, err := app.GetRatesOfExchange(currency, date)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get rates exchange for %s: %w", currency, err)
+	}
+	if data == nil || data.ExchangeRate == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrRateNotFound, currency)
+	}
+
+	return data, nil
+}
+
+// IsUSD reports whether the currency is USD, an empty currency is USD
+func IsUSD(currency string) bool {
+	return len(currency) == 0 || currency == model.CurrencyUSD
+}
+
+// Rate is the amount of target currency for one unit of the source currency
+func (e *Exchange) Rate() float64 {
+	return e.TargetRate() / e.SourceRate()
+}
+
+// SourceRate is the amount of source currency for one USD
+func (e *Exchange) SourceRate() float64 {
+	if e.Source == nil {
+		return 1
+	}
+
+	return e.Source.ExchangeRate
+}
+
+// TargetRate is the amount of target currency for one USD
+func (e *Exchange) TargetRate() float64 {
+	if e.Target == nil {
+		return 1
+	}
+
+	return e.Target.ExchangeRate
+}
+
+// SourceRecordDate is the record date of the source leg, empty when the source is USD
+func (e *Exchange) SourceRecordDate() string {
+	if e.Source == nil {
+		return ""
+	}
+
+	return e.Source.RecordDate
+}
+
+// TargetRecordDate is the record date of the target leg, empty when the target is USD
+func (e *Exchange) TargetRecordDate() string {
+	if e.Target == nil {
+		return ""
+	}
+
+	return e.Target.RecordDate
+}
+
+// USD converts an amount of the source currency to USD
+func (e *Exchange) USD(amount float64) float64 {
+	return amount / e.SourceRate()
+}
+
+// Convert converts an amount of the source currency to the target currency
+func (e *Exchange) Convert(amount float64) float64 {
+	return e.USD(amount) * e.TargetRate()
+}
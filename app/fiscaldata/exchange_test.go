@@ -0,0 +1,89 @@
+package fiscaldata
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTriangulate(t *testing.T) {
+	t.Run("this test simulate a conversion from a non USD source currency", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		appMock := NewMockApp(ctrl)
+		appMock.EXPECT().GetRatesOfExchange("Euro Zone-Euro", "2023-10-15").Return(&Data{
+			CurrencyDescription: "Euro Zone-Euro",
+			ExchangeRate:        0.8,
+			RecordDate:          "2023-09-30",
+		}, nil)
+		appMock.EXPECT().GetRatesOfExchange("Canada-Dollar", "2023-10-15").Return(&Data{
+			CurrencyDescription: "Canada-Dollar",
+			ExchangeRate:        1.5,
+			RecordDate:          "2023-09-29",
+		}, nil)
+
+		exchange, err := Triangulate(appMock, "Euro Zone-Euro", "Canada-Dollar", "2023-10-15")
+
+		assert.NoError(t, err)
+		assert.Equal(t, exchange.USD(20), 25.0)
+		assert.Equal(t, exchange.Convert(20), 37.5)
+		assert.Equal(t, exchange.Rate(), 1.875)
+		assert.Equal(t, exchange.SourceRecordDate(), "2023-09-30")
+		assert.Equal(t, exchange.TargetRecordDate(), "2023-09-29")
+	})
+
+	t.Run("this test simulate a conversion from USD", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		appMock := NewMockApp(ctrl)
+		appMock.EXPECT().GetRatesOfExchange("Canada-Dollar", "2023-10-15").Return(&Data{
+			CurrencyDescription: "Canada-Dollar",
+			ExchangeRate:        1.5,
+			RecordDate:          "2023-09-29",
+		}, nil)
+
+		exchange, err := Triangulate(appMock, "", "Canada-Dollar", "2023-10-15")
+
+		assert.NoError(t, err)
+		assert.Nil(t, exchange.Source)
+		assert.Equal(t, exchange.Convert(20), 30.0)
+	})
+
+	t.Run("this test simulate a conversion to USD", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		appMock := NewMockApp(ctrl)
+		appMock.EXPECT().GetRatesOfExchange("Euro Zone-Euro", "2023-10-15").Return(&Data{
+			CurrencyDescription: "Euro Zone-Euro",
+			ExchangeRate:        0.8,
+			RecordDate:          "2023-09-30",
+		}, nil)
+
+		exchange, err := Triangulate(appMock, "Euro Zone-Euro", "USD", "2023-10-15")
+
+		assert.NoError(t, err)
+		assert.Nil(t, exchange.Target)
+		assert.Equal(t, exchange.Convert(20), 25.0)
+	})
+
+	t.Run("this test simulate a missing source rate", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		appMock := NewMockApp(ctrl)
+		appMock.EXPECT().GetRatesOfExchange("Euro Zone-Euro", "2023-10-15").Return(nil, nil)
+
+		exchange, err := Triangulate(appMock, "Euro Zone-Euro", "Canada-Dollar", "2023-10-15")
+
+		assert.Nil(t, exchange)
+		assert.ErrorIs(t, err, ErrRateNotFound)
+	})
+
+	t.Run("this test simulate an error getting the target rate", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		appMock := NewMockApp(ctrl)
+		appMock.EXPECT().GetRatesOfExchange("Canada-Dollar", "2023-10-15").Return(nil, errors.New("an error has ocurred"))
+
+		exchange, err := Triangulate(appMock, "USD", "Canada-Dollar", "2023-10-15")
+
+		assert.Nil(t, exchange)
+		assert.Error(t, err)
+	})
+}
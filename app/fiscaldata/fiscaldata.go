@@ -14,6 +14,8 @@ import (
 
 type App interface {
 	GetRatesOfExchange(currencyDescription, transactionDate string) (*Data, error)
+	// GetRatesSeries gets every rate of the currency recorded between the dates, inclusive, oldest first
+	GetRatesSeries(currencyDescription, startDate, endDate string) ([]Data, error)
 }
 
 type appImpl struct {
@@ -46,6 +48,9 @@ func newHttpClient() *http.Client {
 	}
 }
 
+// seriesPageSize is the max page size of the Treasury api, a series is read in a single page
+const seriesPageSize = 10000
+
 func formatUrl(baseUrl, currencyDescription, startDate, endDate string) string {
 	endpoint := "v1/accounting/od/rates_of_exchange"
 	fields := "fields=country_currency_desc,exchange_rate,record_date"
@@ -55,6 +60,15 @@ func formatUrl(baseUrl, currencyDescription, startDate, endDate string) string {
 	return formatedUrl
 }
 
+func formatSeriesUrl(baseUrl, currencyDescription, startDate, endDate string) string {
+	endpoint := "v1/accounting/od/rates_of_exchange"
+	fields := "fields=country_currency_desc,exchange_rate,record_date"
+	filter := fmt.Sprintf("filter=country_currency_desc:eq:%s,record_date:lte:%s,record_date:gte:%s", currencyDescription, endDate, startDate)
+	sort := "sort=record_date"
+	page := fmt.Sprintf("page[size]=%d", seriesPageSize)
+	return fmt.Sprintf("%s/%s?%s&%s&%s&%s", baseUrl, endpoint, fields, filter, sort, page)
+}
+
 func (e *RateOfExchangeResponse) getExchangeValue() *Data {
 	if len(e.Data) > 0 {
 		return &e.Data[0]
@@ -88,7 +102,25 @@ func (a appImpl) GetRatesOfExchange(currencyDescription, transactionDate string)
 		return nil, err
 	}
 
-	resp, err := a.client.Get(formatUrl(a.url, currencyDescription, convertedDate, transactionDate))
+	responseData, err := a.get(formatUrl(a.url, currencyDescription, convertedDate, transactionDate))
+	if err != nil {
+		return nil, err
+	}
+
+	return responseData.getExchangeValue(), nil
+}
+
+func (a appImpl) GetRatesSeries(currencyDescription, startDate, endDate string) ([]Data, error) {
+	responseData, err := a.get(formatSeriesUrl(a.url, currencyDescription, startDate, endDate))
+	if err != nil {
+		return nil, err
+	}
+
+	return responseData.Data, nil
+}
+
+func (a appImpl) get(url string) (*RateOfExchangeResponse, error) {
+	resp, err := a.client.Get(url)
 	if err != nil || resp.StatusCode != http.StatusOK {
 		return nil, fmt.Errorf("failed to execute request: %w", err)
 	}
@@ -105,5 +137,5 @@ func (a appImpl) GetRatesOfExchange(currencyDescription, transactionDate string)
 		return nil, fmt.Errorf("failed to unmarshal body response: %w", err)
 	}
 
-	return responseData.getExchangeValue(), nil
+	return responseData, nil
 }
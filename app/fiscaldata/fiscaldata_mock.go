@@ -47,3 +47,18 @@ func (mr *MockAppMockRecorder) GetRatesOfExchange(currencyDescription, transacti
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRatesOfExchange", reflect.TypeOf((*MockApp)(nil).GetRatesOfExchange), currencyDescription, transactionDate)
 }
+
+// GetRatesSeries mocks base method.
+func (m *MockApp) GetRatesSeries(currencyDescription, startDate, endDate string) ([]Data, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRatesSeries", currencyDescription, startDate, endDate)
+	ret0, _ := ret[0].([]Data)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRatesSeries indicates an expected call of GetRatesSeries.
+func (mr *MockAppMockRecorder) GetRatesSeries(currencyDescription, startDate, endDate interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRatesSeries", reflect.TypeOf((*MockApp)(nil).GetRatesSeries), currencyDescription, startDate, endDate)
+}
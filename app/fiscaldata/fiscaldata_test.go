@@ -58,3 +58,50 @@ func TestGetRatesOfExchange(t *testing.T) {
 		assert.Error(t, err)
 	})
 }
+
+func TestGetRatesSeries(t *testing.T) {
+	t.Run("This test simulates the exchange rate series search", func(t *testing.T) {
+		expected := []Data{
+			{CurrencyDescription: "Canada-Dollar", ExchangeRate: 1.36, RecordDate: "2023-03-31"},
+			{CurrencyDescription: "Canada-Dollar", ExchangeRate: 1.32, RecordDate: "2023-06-30"},
+		}
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, r.URL.Query().Get("filter"), "country_currency_desc:eq:Canada-Dollar,record_date:lte:2023-07-01,record_date:gte:2023-01-01")
+			assert.Equal(t, r.URL.Query().Get("sort"), "record_date")
+			w.WriteHeader(200)
+			result, _ := json.Marshal(RateOfExchangeResponse{
+				Data: expected,
+			})
+			w.Write(result)
+		}))
+		defer server.Close()
+
+		testFiscalData := appImpl{
+			url:    server.URL,
+			client: server.Client(),
+		}
+
+		data, err := testFiscalData.GetRatesSeries("Canada-Dollar", "2023-01-01", "2023-07-01")
+
+		assert.Equal(t, data, expected)
+		assert.NoError(t, err)
+	})
+
+	t.Run("This test simulates an error when searching for the exchange rate series", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(500)
+		}))
+
+		defer server.Close()
+
+		testFiscalData := appImpl{
+			url:    server.URL,
+			client: server.Client(),
+		}
+
+		data, err := testFiscalData.GetRatesSeries("Canada-Dollar", "2023-01-01", "2023-07-01")
+
+		assert.Nil(t, data)
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,395 @@
+package ingest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"expvar"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jcpribeiro/TransactionApp/app/transaction"
+	"github.com/jcpribeiro/TransactionApp/internal/tenant"
+	"github.com/jcpribeiro/TransactionApp/model"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/sirupsen/logrus"
+)
+
+//go:generate mockgen -source=$GOFILE -destination=ingest_mock.go -package=$GOPACKAGE
+
+const (
+	FieldAccountId   = "account_id"
+	FieldTransaction = "transaction"
+	// FieldError and FieldStreamId are added to the dead-lettered messages, with why and where they failed
+	FieldError    = "error"
+	FieldStreamId = "stream_id"
+
+	metricConsumed     = "consumed"
+	metricDeadLettered = "dead_lettered"
+	metricErrors       = "errors"
+	metricPending      = "pending"
+	metricLag          = "lag_ms"
+)
+
+// ErrInvalidMessage is returned when a message has no account or its transaction is not valid JSON
+var ErrInvalidMessage = errors.New("invalid message")
+
+var (
+	metrics = expvar.NewMap("ingest")
+	// pending is the number of messages read by the group and not acknowledged yet
+	pending = new(expvar.Int)
+	// lag is the age of the last message read, how far behind the producers the consumers are
+	lag = new(expvar.Int)
+)
+
+func init() {
+	metrics.Set(metricPending, pending)
+	metrics.Set(metricLag, lag)
+}
+
+type App interface {
+	Start()
+	Stop()
+}
+
+// Options of the consumers. A message is read by one consumer of Group, which inserts its transaction and
+// acknowledges it. A message whose insert failed is read again after ClaimIdle, and moved to DeadLetterStream
+// after MaxDeliveries. A message that can never be inserted is moved there right away.
+type Options struct {
+	Stream           string
+	Group            string
+	DeadLetterStream string
+	Workers          int
+	Batch            int64
+	Block            time.Duration
+	ClaimIdle        time.Duration
+	MaxDeliveries    int64
+	StopTimeout      time.Duration
+	// Inserted is called after the transaction of a message is stored
+	Inserted func(ctx context.Context, accountId string)
+}
+
+type appImpl struct {
+	redis        *redis.Client
+	transactions transaction.App
+	log          logrus.Logger
+	opts         Options
+	consumer     string
+	mu           sync.Mutex
+	stop         chan struct{}
+	cancel       context.CancelFunc
+	wg           sync.WaitGroup
+	started      bool
+}
+
+// NewAppIngest creates the consumers of the stream. Without redis or a stream they are not started.
+func NewAppIngest(redis *redis.Client, transactions transaction.App, opts Options, log logrus.Logger) App {
+	if len(opts.Group) == 0 {
+		opts.Group = "transactionapp"
+	}
+	if len(opts.DeadLetterStream) == 0 {
+		opts.DeadLetterStream = opts.Stream + ".dead"
+	}
+	if opts.Workers <= 0 {
+		opts.Workers = 1
+	}
+	if opts.Batch <= 0 {
+		opts.Batch = 10
+	}
+	if opts.Block <= 0 {
+		opts.Block = 2 * time.Second
+	}
+	if opts.ClaimIdle <= 0 {
+		opts.ClaimIdle = time.Minute
+	}
+	if opts.MaxDeliveries <= 0 {
+		opts.MaxDeliveries = 5
+	}
+	if opts.StopTimeout <= 0 {
+		opts.StopTimeout = 10 * time.Second
+	}
+	if opts.Inserted == nil {
+		opts.Inserted = func(context.Context, string) {}
+	}
+
+	hostname, _ := os.Hostname()
+
+	return &appImpl{
+		redis:        redis,
+		transactions: transactions,
+		log:          log,
+		opts:         opts,
+		consumer:     fmt.Sprintf("%s-%d", hostname, os.Getpid()),
+	}
+}
+
+// Start creates the consumer group, reading the stream from its start, and runs the consumers
+func (a *appImpl) Start() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.started {
+		return
+	}
+	if a.redis == nil || len(a.opts.Stream) == 0 {
+		a.log.Info("transaction stream is disabled")
+		return
+	}
+
+	err := a.redis.XGroupCreateMkStream(context.Background(), a.opts.Stream, a.opts.Group, "0").Err()
+	if err != nil && !strings.HasPrefix(err.Error(), "BUSYGROUP") {
+		a.log.Error(fmt.Errorf("cannot create the consumer group of %s: %w", a.opts.Stream, err))
+		return
+	}
+
+	var ctx context.Context
+	ctx, a.cancel = context.WithCancel(context.Background())
+	a.stop = make(chan struct{})
+	a.started = true
+
+	for i := 0; i < a.opts.Workers; i++ {
+		a.wg.Add(1)
+		go a.work(ctx, fmt.Sprintf("%s-%d", a.consumer, i))
+	}
+
+	a.log.Infof("Started %d consumers of %s", a.opts.Workers, a.opts.Stream)
+}
+
+// Stop waits for the messages being inserted. After StopTimeout they are interrupted,
+// left unacknowledged and read again by another consumer after ClaimIdle.
+func (a *appImpl) Stop() {
+	a.mu.Lock()
+	if !a.started {
+		a.mu.Unlock()
+		return
+	}
+	a.started = false
+	close(a.stop)
+	a.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		a.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(a.opts.StopTimeout):
+		a.log.Warn("stream consumers did not stop in time, interrupting running messages")
+		a.cancel()
+		<-done
+	}
+	a.cancel()
+}
+
+func (a *appImpl) stopping() bool {
+	select {
+	case <-a.stop:
+		return true
+	default:
+		return false
+	}
+}
+
+func (a *appImpl) work(ctx context.Context, consumer string) {
+	defer a.wg.Done()
+
+	var reclaimed time.Time
+	for !a.stopping() {
+		if time.Since(reclaimed) >= a.opts.ClaimIdle/2 {
+			a.reclaim(ctx, consumer)
+			reclaimed = time.Now()
+		}
+
+		// the read blocks for at most Block, so a stop is noticed in time
+		streams, err := a.redis.XReadGroup(ctx, &redis.XReadGroupArgs{
+			Group:    a.opts.Group,
+			Consumer: consumer,
+			Streams:  []string{a.opts.Stream, ">"},
+			Count:    a.opts.Batch,
+			Block:    a.opts.Block,
+		}).Result()
+		if errors.Is(err, redis.Nil) {
+			continue
+		}
+		if err != nil {
+			if ctx.Err() == nil {
+				metrics.Add(metricErrors, 1)
+				a.log.Error("cannot read transaction stream ", err.Error())
+			}
+			select {
+			case <-a.stop:
+			case <-time.After(a.opts.Block):
+			}
+			continue
+		}
+
+		for _, stream := range streams {
+			for _, message := range stream.Messages {
+				if a.stopping() {
+					// left pending, another consumer claims it
+					return
+				}
+				a.handle(ctx, message, 1)
+			}
+		}
+	}
+}
+
+// reclaim takes over the messages left unacknowledged for ClaimIdle, by a failed insert or a stopped consumer
+func (a *appImpl) reclaim(ctx context.Context, consumer string) {
+	summary, err := a.redis.XPending(ctx, a.opts.Stream, a.opts.Group).Result()
+	if err != nil {
+		a.log.Error("cannot read pending messages ", err.Error())
+		return
+	}
+	pending.Set(summary.Count)
+	if summary.Count == 0 {
+		return
+	}
+
+	idle, err := a.redis.XPendingExt(ctx, &redis.XPendingExtArgs{
+		Stream: a.opts.Stream,
+		Group:  a.opts.Group,
+		Idle:   a.opts.ClaimIdle,
+		Start:  "-",
+		End:    "+",
+		Count:  a.opts.Batch,
+	}).Result()
+	if err != nil || len(idle) == 0 {
+		return
+	}
+
+	ids := make([]string, 0, len(idle))
+	deliveries := make(map[string]int64, len(idle))
+	for _, p := range idle {
+		ids = append(ids, p.ID)
+		deliveries[p.ID] = p.RetryCount + 1
+	}
+
+	messages, err := a.redis.XClaim(ctx, &redis.XClaimArgs{
+		Stream:   a.opts.Stream,
+		Group:    a.opts.Group,
+		Consumer: consumer,
+		MinIdle:  a.opts.ClaimIdle,
+		Messages: ids,
+	}).Result()
+	if err != nil {
+		a.log.Error("cannot claim pending messages ", err.Error())
+		return
+	}
+
+	for _, message := range messages {
+		if a.stopping() {
+			return
+		}
+		a.handle(ctx, message, deliveries[message.ID])
+	}
+}
+
+// decode returns the account and the transaction of a message
+func decode(message redis.XMessage) (string, *model.Transaction, error) {
+	accountId, _ := message.Values[FieldAccountId].(string)
+	if len(accountId) == 0 {
+		return "", nil, fmt.Errorf("%w: missing %s", ErrInvalidMessage, FieldAccountId)
+	}
+
+	body, _ := message.Values[FieldTransaction].(string)
+	var t *model.Transaction
+	if err := json.Unmarshal([]byte(body), &t); err != nil || t == nil {
+		return "", nil, fmt.Errorf("%w: the %s field is not a transaction", ErrInvalidMessage, FieldTransaction)
+	}
+
+	return accountId, t, nil
+}
+
+// messageSource keys the transaction of a message, so a message delivered again after it was
+// inserted, as when the consumer stops before acknowledging it, is not inserted twice
+func messageSource(stream, id string) string {
+	return fmt.Sprintf("stream:%s:%s", stream, id)
+}
+
+// messageAge returns how long ago a message was added, from the time part of its id
+func messageAge(id string) time.Duration {
+	ms, _, _ := strings.Cut(id, "-")
+	added, err := strconv.ParseInt(ms, 10, 64)
+	if err != nil {
+		return 0
+	}
+
+	return time.Since(time.UnixMilli(added))
+}
+
+// handle inserts the transaction of a message, its deliveries counting this one. The message is
+// acknowledged on success, dead-lettered when it can not be inserted, and left pending otherwise.
+func (a *appImpl) handle(ctx context.Context, message redis.XMessage, deliveries int64) {
+	lag.Set(messageAge(message.ID).Milliseconds())
+
+	accountId, t, err := decode(message)
+	if err != nil {
+		a.deadLetter(ctx, message, err)
+		return
+	}
+	t.SourceId = messageSource(a.opts.Stream, message.ID)
+
+	_, err = a.transactions.InsertTransaction(ctx, accountId, t)
+	switch {
+	case errors.Is(err, transaction.ErrInvalidTransaction), errors.Is(err, tenant.ErrMissingAccount):
+		a.deadLetter(ctx, message, err)
+	case err != nil && ctx.Err() != nil:
+		// interrupted by Stop, it is read again after ClaimIdle
+	case err != nil && deliveries >= a.opts.MaxDeliveries:
+		a.deadLetter(ctx, message, fmt.Errorf("failed after %d deliveries: %w", deliveries, err))
+	case err != nil:
+		metrics.Add(metricErrors, 1)
+		a.log.Error(fmt.Errorf("cannot insert the transaction of message %s: %w", message.ID, err))
+	default:
+		a.opts.Inserted(ctx, accountId)
+		a.ack(ctx, message)
+		metrics.Add(metricConsumed, 1)
+	}
+}
+
+func (a *appImpl) ack(ctx context.Context, message redis.XMessage) {
+	if err := a.redis.XAck(ctx, a.opts.Stream, a.opts.Group, message.ID).Err(); err != nil {
+		metrics.Add(metricErrors, 1)
+		a.log.Error(fmt.Errorf("cannot acknowledge message %s: %w", message.ID, err))
+	}
+}
+
+// deadLetter moves a message to the dead-letter stream with the error and its id, it stays pending when the move fails
+func (a *appImpl) deadLetter(ctx context.Context, message redis.XMessage, cause error) {
+	keys := make([]string, 0, len(message.Values))
+	for key := range message.Values {
+		if key != FieldError && key != FieldStreamId {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	values := make([]interface{}, 0, 2*len(keys)+4)
+	for _, key := range keys {
+		values = append(values, key, message.Values[key])
+	}
+	values = append(values, FieldError, cause.Error(), FieldStreamId, message.ID)
+
+	err := a.redis.XAdd(ctx, &redis.XAddArgs{
+		Stream: a.opts.DeadLetterStream,
+		Values: values,
+	}).Err()
+	if err != nil {
+		metrics.Add(metricErrors, 1)
+		a.log.Error(fmt.Errorf("cannot dead-letter message %s: %w", message.ID, err))
+		return
+	}
+
+	a.log.Warnf("message %s moved to %s: %s", message.ID, a.opts.DeadLetterStream, cause.Error())
+	a.ack(ctx, message)
+	metrics.Add(metricDeadLettered, 1)
+}
@@ -0,0 +1,242 @@
+package ingest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/jcpribeiro/TransactionApp/app/transaction"
+	"github.com/jcpribeiro/TransactionApp/model"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/go-redis/redismock/v8"
+	"github.com/golang/mock/gomock"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+const testAccountId = "652d34910a8fc425116b84aa"
+
+type structTest struct {
+	mock             redismock.ClientMock
+	transactionsMock *transaction.MockApp
+	inserted         []string
+	appTest          *appImpl
+}
+
+func setUpTest(t *testing.T) *structTest {
+	db, mock := redismock.NewClientMock()
+	testObj := &structTest{
+		mock:             mock,
+		transactionsMock: transaction.NewMockApp(gomock.NewController(t)),
+	}
+
+	testObj.appTest = NewAppIngest(db, testObj.transactionsMock, Options{
+		Stream:        "transactions",
+		MaxDeliveries: 3,
+		Inserted: func(ctx context.Context, accountId string) {
+			testObj.inserted = append(testObj.inserted, accountId)
+		},
+	}, *logrus.New()).(*appImpl)
+	testObj.appTest.stop = make(chan struct{})
+
+	return testObj
+}
+
+func testMessage(values map[string]interface{}) redis.XMessage {
+	return redis.XMessage{ID: "1697371200000-0", Values: values}
+}
+
+func TestHandle(t *testing.T) {
+	ctx := context.Background()
+	valid := map[string]interface{}{
+		FieldAccountId:   testAccountId,
+		FieldTransaction: `{"purchase_amount":23.7,"description":"Test","purchase_date":"2023-10-15"}`,
+	}
+
+	t.Run("this test simulate a message inserted and acknowledged", func(t *testing.T) {
+		testObj := setUpTest(t)
+		testObj.transactionsMock.EXPECT().InsertTransaction(ctx, testAccountId, &model.Transaction{
+			PurchaseAmount: 23.7,
+			Description:    "Test",
+			PurchaseDate:   "2023-10-15",
+			SourceId:       "stream:transactions:1697371200000-0",
+		}).Return("652d34910a8fc425116b84d9", nil)
+		testObj.mock.ExpectXAck("transactions", "transactionapp", "1697371200000-0").SetVal(1)
+
+		testObj.appTest.handle(ctx, testMessage(valid), 1)
+
+		assert.NoError(t, testObj.mock.ExpectationsWereMet())
+		assert.Equal(t, testObj.inserted, []string{testAccountId})
+	})
+
+	t.Run("this test simulate a message without an account", func(t *testing.T) {
+		testObj := setUpTest(t)
+		testObj.mock.ExpectXAdd(&redis.XAddArgs{
+			Stream: "transactions.dead",
+			Values: []interface{}{
+				FieldTransaction, "{}",
+				FieldError, "invalid message: missing account_id",
+				FieldStreamId, "1697371200000-0",
+			},
+		}).SetVal("1697371200001-0")
+		testObj.mock.ExpectXAck("transactions", "transactionapp", "1697371200000-0").SetVal(1)
+
+		testObj.appTest.handle(ctx, testMessage(map[string]interface{}{FieldTransaction: "{}"}), 1)
+
+		assert.NoError(t, testObj.mock.ExpectationsWereMet())
+		assert.Empty(t, testObj.inserted)
+	})
+
+	t.Run("this test simulate a message whose transaction is not json", func(t *testing.T) {
+		testObj := setUpTest(t)
+		testObj.mock.ExpectXAdd(&redis.XAddArgs{
+			Stream: "transactions.dead",
+			Values: []interface{}{
+				FieldAccountId, testAccountId,
+				FieldTransaction, "purchase",
+				FieldError, "invalid message: the transaction field is not a transaction",
+				FieldStreamId, "1697371200000-0",
+			},
+		}).SetVal("1697371200001-0")
+		testObj.mock.ExpectXAck("transactions", "transactionapp", "1697371200000-0").SetVal(1)
+
+		testObj.appTest.handle(ctx, testMessage(map[string]interface{}{
+			FieldAccountId:   testAccountId,
+			FieldTransaction: "purchase",
+		}), 1)
+
+		assert.NoError(t, testObj.mock.ExpectationsWereMet())
+	})
+
+	t.Run("this test simulate a transaction breaking a business rule", func(t *testing.T) {
+		testObj := setUpTest(t)
+		ruleErr := fmt.Errorf("%w: purchase_amount is required", transaction.ErrInvalidTransaction)
+		testObj.transactionsMock.EXPECT().InsertTransaction(ctx, testAccountId, gomock.Any()).Return("", ruleErr)
+		testObj.mock.ExpectXAdd(&redis.XAddArgs{
+			Stream: "transactions.dead",
+			Values: []interface{}{
+				FieldAccountId, testAccountId,
+				FieldTransaction, valid[FieldTransaction],
+				FieldError, ruleErr.Error(),
+				FieldStreamId, "1697371200000-0",
+			},
+		}).SetVal("1697371200001-0")
+		testObj.mock.ExpectXAck("transactions", "transactionapp", "1697371200000-0").SetVal(1)
+
+		testObj.appTest.handle(ctx, testMessage(valid), 1)
+
+		assert.NoError(t, testObj.mock.ExpectationsWereMet())
+	})
+
+	t.Run("this test simulate a failed insert left pending", func(t *testing.T) {
+		testObj := setUpTest(t)
+		testObj.transactionsMock.EXPECT().InsertTransaction(ctx, testAccountId, gomock.Any()).Return("", errors.New("mongo unavailable"))
+
+		testObj.appTest.handle(ctx, testMessage(valid), 2)
+
+		assert.NoError(t, testObj.mock.ExpectationsWereMet())
+		assert.Empty(t, testObj.inserted)
+	})
+
+	t.Run("this test simulate a failed insert after the last delivery", func(t *testing.T) {
+		testObj := setUpTest(t)
+		testObj.transactionsMock.EXPECT().InsertTransaction(ctx, testAccountId, gomock.Any()).Return("", errors.New("mongo unavailable"))
+		testObj.mock.ExpectXAdd(&redis.XAddArgs{
+			Stream: "transactions.dead",
+			Values: []interface{}{
+				FieldAccountId, testAccountId,
+				FieldTransaction, valid[FieldTransaction],
+				FieldError, "failed after 3 deliveries: mongo unavailable",
+				FieldStreamId, "1697371200000-0",
+			},
+		}).SetVal("1697371200001-0")
+		testObj.mock.ExpectXAck("transactions", "transactionapp", "1697371200000-0").SetVal(1)
+
+		testObj.appTest.handle(ctx, testMessage(valid), 3)
+
+		assert.NoError(t, testObj.mock.ExpectationsWereMet())
+	})
+
+	t.Run("this test simulate a dead letter that failed", func(t *testing.T) {
+		testObj := setUpTest(t)
+		testObj.mock.ExpectXAdd(&redis.XAddArgs{
+			Stream: "transactions.dead",
+			Values: []interface{}{
+				FieldTransaction, "{}",
+				FieldError, "invalid message: missing account_id",
+				FieldStreamId, "1697371200000-0",
+			},
+		}).SetErr(errors.New("connection refused"))
+
+		testObj.appTest.handle(ctx, testMessage(map[string]interface{}{FieldTransaction: "{}"}), 1)
+
+		// not acknowledged, it is dead-lettered again once claimed
+		assert.NoError(t, testObj.mock.ExpectationsWereMet())
+	})
+}
+
+func TestReclaim(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("this test simulate a message claimed from a stopped consumer", func(t *testing.T) {
+		testObj := setUpTest(t)
+		testObj.mock.ExpectXPending("transactions", "transactionapp").SetVal(&redis.XPending{Count: 1})
+		testObj.mock.ExpectXPendingExt(&redis.XPendingExtArgs{
+			Stream: "transactions",
+			Group:  "transactionapp",
+			Idle:   time.Minute,
+			Start:  "-",
+			End:    "+",
+			Count:  10,
+		}).SetVal([]redis.XPendingExt{{ID: "1697371200000-0", Consumer: "stopped-0", RetryCount: 1}})
+		testObj.mock.ExpectXClaim(&redis.XClaimArgs{
+			Stream:   "transactions",
+			Group:    "transactionapp",
+			Consumer: "consumer-0",
+			MinIdle:  time.Minute,
+			Messages: []string{"1697371200000-0"},
+		}).SetVal([]redis.XMessage{testMessage(map[string]interface{}{
+			FieldAccountId:   testAccountId,
+			FieldTransaction: `{"purchase_amount":23.7,"description":"Test"}`,
+		})})
+		testObj.transactionsMock.EXPECT().InsertTransaction(ctx, testAccountId, gomock.Any()).Return("652d34910a8fc425116b84d9", nil)
+		testObj.mock.ExpectXAck("transactions", "transactionapp", "1697371200000-0").SetVal(1)
+
+		testObj.appTest.reclaim(ctx, "consumer-0")
+
+		assert.NoError(t, testObj.mock.ExpectationsWereMet())
+		assert.Equal(t, pending.Value(), int64(1))
+	})
+
+	t.Run("this test simulate a group without pending messages", func(t *testing.T) {
+		testObj := setUpTest(t)
+		testObj.mock.ExpectXPending("transactions", "transactionapp").SetVal(&redis.XPending{Count: 0})
+
+		testObj.appTest.reclaim(ctx, "consumer-0")
+
+		assert.NoError(t, testObj.mock.ExpectationsWereMet())
+		assert.Equal(t, pending.Value(), int64(0))
+	})
+}
+
+func TestStart(t *testing.T) {
+	t.Run("this test simulate the consumers without a stream", func(t *testing.T) {
+		db, mock := redismock.NewClientMock()
+		appTest := NewAppIngest(db, nil, Options{}, *logrus.New())
+
+		appTest.Start()
+		appTest.Stop()
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestMessageAge(t *testing.T) {
+	id := fmt.Sprintf("%d-0", time.Now().Add(-time.Minute).UnixMilli())
+
+	assert.InDelta(t, messageAge(id).Seconds(), 60, 1)
+	assert.Equal(t, messageAge("invalid"), time.Duration(0))
+}
@@ -0,0 +1,339 @@
+package job
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/jcpribeiro/TransactionApp/internal/tenant"
+	"github.com/jcpribeiro/TransactionApp/model"
+	"github.com/jcpribeiro/TransactionApp/store"
+	jobStore "github.com/jcpribeiro/TransactionApp/store/job"
+
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+//go:generate mockgen -source=$GOFILE -destination=job_mock.go -package=$GOPACKAGE
+
+// ErrUnknownJobType is returned when no runner is registered for the submitted type
+var ErrUnknownJobType = errors.New("unknown job type")
+
+// Progress reports how much of a job is done. The unit depends on the job type
+// and total is zero when it is not known in advance.
+type Progress func(done, total int64)
+
+// Runner executes a job and returns its result location. It must stop when ctx is done.
+type Runner func(ctx context.Context, job *model.Job, progress Progress) (string, error)
+
+type App interface {
+	SubmitJob(ctx context.Context, accountId, jobType string, params map[string]string) (*model.Job, error)
+	GetJob(ctx context.Context, accountId, id string) (*model.Job, error)
+	CancelJob(ctx context.Context, accountId, id string) (*model.Job, error)
+	RegisterRunner(jobType string, runner Runner)
+	FilePath(name string) (string, error)
+	Start()
+	Stop()
+}
+
+type Options struct {
+	Workers int
+	// Dir holds the job inputs and results. It must be a persistent volume shared by every
+	// instance, as a job runs again after a restart and may be taken over by another instance.
+	Dir           string
+	PollInterval  time.Duration
+	LeaseDuration time.Duration
+	StopTimeout   time.Duration
+}
+
+type appImpl struct {
+	stores  *store.Container
+	log     logrus.Logger
+	opts    Options
+	owner   string
+	mu      sync.RWMutex
+	runners map[string]Runner
+	stop    chan struct{}
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+	started bool
+}
+
+func NewAppJob(stores *store.Container, opts Options, log logrus.Logger) App {
+	if opts.Workers <= 0 {
+		opts.Workers = 1
+	}
+	if opts.PollInterval <= 0 {
+		opts.PollInterval = time.Second
+	}
+	if opts.LeaseDuration <= 0 {
+		opts.LeaseDuration = 30 * time.Second
+	}
+	if opts.StopTimeout <= 0 {
+		opts.StopTimeout = 30 * time.Second
+	}
+
+	hostname, _ := os.Hostname()
+
+	return &appImpl{
+		stores:  stores,
+		log:     log,
+		opts:    opts,
+		owner:   fmt.Sprintf("%s-%d-%s", hostname, os.Getpid(), primitive.NewObjectID().Hex()),
+		runners: make(map[string]Runner),
+	}
+}
+
+func (a *appImpl) RegisterRunner(jobType string, runner Runner) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.runners[jobType] = runner
+}
+
+func (a *appImpl) runner(jobType string) (Runner, bool) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	runner, ok := a.runners[jobType]
+	return runner, ok
+}
+
+// FilePath resolves a job input or result file name inside the jobs directory
+func (a *appImpl) FilePath(name string) (string, error) {
+	if len(name) == 0 || name != filepath.Base(name) || strings.HasPrefix(name, ".") {
+		return "", fmt.Errorf("invalid job file name: %s", name)
+	}
+
+	return filepath.Join(a.opts.Dir, name), nil
+}
+
+// SubmitJob queues a job of the account, its runner gets the account in job.AccountId
+func (a *appImpl) SubmitJob(ctx context.Context, accountId, jobType string, params map[string]string) (*model.Job, error) {
+	if len(accountId) == 0 {
+		return nil, tenant.ErrMissingAccount
+	}
+
+	if _, ok := a.runner(jobType); !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownJobType, jobType)
+	}
+
+	now := time.Now().Unix()
+	job := &model.Job{
+		AccountId: accountId,
+		Type:      jobType,
+		Status:    model.JobStatusPending,
+		Params:    params,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	id, err := a.stores.Job.InsertJob(ctx, job)
+	if err != nil {
+		return nil, fmt.Errorf("failed to insert job: %w", err)
+	}
+	job.Id = id
+
+	return job, nil
+}
+
+func (a *appImpl) GetJob(ctx context.Context, accountId, id string) (*model.Job, error) {
+	return a.stores.Job.GetJobById(ctx, accountId, id)
+}
+
+func (a *appImpl) CancelJob(ctx context.Context, accountId, id string) (*model.Job, error) {
+	return a.stores.Job.CancelJob(ctx, accountId, id, time.Now().Unix())
+}
+
+// Start runs the workers. Jobs left running by a stopped process are taken over once their lease expires.
+func (a *appImpl) Start() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.started {
+		return
+	}
+
+	if err := os.MkdirAll(a.opts.Dir, 0o755); err != nil {
+		a.log.Error("cannot create jobs dir ", err.Error())
+	}
+
+	var ctx context.Context
+	ctx, a.cancel = context.WithCancel(context.Background())
+	a.stop = make(chan struct{})
+	a.started = true
+
+	for i := 0; i < a.opts.Workers; i++ {
+		a.wg.Add(1)
+		go a.work(ctx)
+	}
+
+	a.log.Infof("Started %d job workers", a.opts.Workers)
+}
+
+// Stop waits for the running jobs to finish. After StopTimeout they are
+// interrupted and put back in the queue to run again on the next start.
+func (a *appImpl) Stop() {
+	a.mu.Lock()
+	if !a.started {
+		a.mu.Unlock()
+		return
+	}
+	a.started = false
+	close(a.stop)
+	a.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		a.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(a.opts.StopTimeout):
+		a.log.Warn("job workers did not stop in time, interrupting running jobs")
+		a.cancel()
+		<-done
+	}
+	a.cancel()
+}
+
+func (a *appImpl) stopping() bool {
+	select {
+	case <-a.stop:
+		return true
+	default:
+		return false
+	}
+}
+
+func (a *appImpl) work(ctx context.Context) {
+	defer a.wg.Done()
+
+	for !a.stopping() {
+		now := time.Now()
+		job, err := a.stores.Job.ClaimJob(ctx, a.owner, now.Unix(), now.Add(a.opts.LeaseDuration).Unix())
+		if err != nil {
+			a.log.Error("cannot claim job ", err.Error())
+		}
+
+		if job == nil {
+			select {
+			case <-a.stop:
+			case <-time.After(a.opts.PollInterval):
+			}
+			continue
+		}
+
+		a.run(ctx, job)
+	}
+}
+
+// run executes a claimed job, renewing its lease and watching for cancellation while it runs
+func (a *appImpl) run(ctx context.Context, job *model.Job) {
+	runner, ok := a.runner(job.Type)
+	if !ok {
+		job.Status = model.JobStatusFailed
+		job.Error = ErrUnknownJobType.Error()
+		a.finish(job)
+		return
+	}
+
+	jobCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var done, total int64
+	progress := func(d, t int64) {
+		atomic.StoreInt64(&done, d)
+		atomic.StoreInt64(&total, t)
+	}
+
+	var canceled, lost int32
+	heartbeat := make(chan struct{})
+	go func() {
+		defer close(heartbeat)
+		ticker := time.NewTicker(a.opts.LeaseDuration / 3)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-jobCtx.Done():
+				return
+			case <-ticker.C:
+				now := time.Now()
+				current, err := a.stores.Job.UpdateJobProgress(context.Background(), job.Id, a.owner,
+					atomic.LoadInt64(&done), atomic.LoadInt64(&total), now.Add(a.opts.LeaseDuration).Unix(), now.Unix())
+				if errors.Is(err, jobStore.ErrJobNotFound) {
+					// the lease expired and another worker took the job over, it is not ours to finish
+					atomic.StoreInt32(&lost, 1)
+					cancel()
+					return
+				}
+				if err != nil {
+					a.log.Error(fmt.Errorf("cannot update job %s: %w", job.Id, err))
+					continue
+				}
+				if current.CancelRequested {
+					atomic.StoreInt32(&canceled, 1)
+					cancel()
+					return
+				}
+			}
+		}
+	}()
+
+	result, err := a.safeRun(jobCtx, runner, job, progress)
+	cancel()
+	<-heartbeat
+
+	if atomic.LoadInt32(&lost) == 1 {
+		a.log.Warnf("job %s %s stopped, its lease was lost", job.Type, job.Id)
+		return
+	}
+
+	job.Progress = atomic.LoadInt64(&done)
+	job.Total = atomic.LoadInt64(&total)
+	job.Result = result
+	switch {
+	case atomic.LoadInt32(&canceled) == 1:
+		job.Status = model.JobStatusCanceled
+	case err != nil && ctx.Err() != nil:
+		// interrupted by Stop, the job runs again after the restart
+		if err := a.stores.Job.ReleaseJob(context.Background(), job.Id, a.owner, time.Now().Unix()); err != nil {
+			a.log.Error(fmt.Errorf("cannot release job %s: %w", job.Id, err))
+		}
+		return
+	case err != nil:
+		job.Status = model.JobStatusFailed
+		job.Error = err.Error()
+	default:
+		job.Status = model.JobStatusSucceeded
+	}
+
+	a.finish(job)
+}
+
+func (a *appImpl) safeRun(ctx context.Context, runner Runner, job *model.Job, progress Progress) (result string, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("job panicked: %v", r)
+		}
+	}()
+
+	return runner(ctx, job, progress)
+}
+
+func (a *appImpl) finish(job *model.Job) {
+	if err := a.stores.Job.FinishJob(context.Background(), job.Id, a.owner, job, time.Now().Unix()); err != nil {
+		a.log.Error(fmt.Errorf("cannot finish job %s: %w", job.Id, err))
+		return
+	}
+
+	a.log.Infof("job %s %s finished with status %s", job.Type, job.Id, job.Status)
+}
@@ -0,0 +1,132 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: job.go
+
+// Package job is a generated GoMock package.
+package job
+
+import (
+	context "context"
+	reflect "reflect"
+	model "github.com/jcpribeiro/TransactionApp/model"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockApp is a mock of App interface.
+type MockApp struct {
+	ctrl     *gomock.Controller
+	recorder *MockAppMockRecorder
+}
+
+// MockAppMockRecorder is the mock recorder for MockApp.
+type MockAppMockRecorder struct {
+	mock *MockApp
+}
+
+// NewMockApp creates a new mock instance.
+func NewMockApp(ctrl *gomock.Controller) *MockApp {
+	mock := &MockApp{ctrl: ctrl}
+	mock.recorder = &MockAppMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockApp) EXPECT() *MockAppMockRecorder {
+	return m.recorder
+}
+
+// CancelJob mocks base method.
+func (m *MockApp) CancelJob(ctx context.Context, accountId, id string) (*model.Job, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CancelJob", ctx, accountId, id)
+	ret0, _ := ret[0].(*model.Job)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CancelJob indicates an expected call of CancelJob.
+func (mr *MockAppMockRecorder) CancelJob(ctx, accountId, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelJob", reflect.TypeOf((*MockApp)(nil).CancelJob), ctx, accountId, id)
+}
+
+// FilePath mocks base method.
+func (m *MockApp) FilePath(name string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FilePath", name)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FilePath indicates an expected call of FilePath.
+func (mr *MockAppMockRecorder) FilePath(name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FilePath", reflect.TypeOf((*MockApp)(nil).FilePath), name)
+}
+
+// GetJob mocks base method.
+func (m *MockApp) GetJob(ctx context.Context, accountId, id string) (*model.Job, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetJob", ctx, accountId, id)
+	ret0, _ := ret[0].(*model.Job)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetJob indicates an expected call of GetJob.
+func (mr *MockAppMockRecorder) GetJob(ctx, accountId, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetJob", reflect.TypeOf((*MockApp)(nil).GetJob), ctx, accountId, id)
+}
+
+// RegisterRunner mocks base method.
+func (m *MockApp) RegisterRunner(jobType string, runner Runner) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "RegisterRunner", jobType, runner)
+}
+
+// RegisterRunner indicates an expected call of RegisterRunner.
+func (mr *MockAppMockRecorder) RegisterRunner(jobType, runner interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterRunner", reflect.TypeOf((*MockApp)(nil).RegisterRunner), jobType, runner)
+}
+
+// Start mocks base method.
+func (m *MockApp) Start() {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Start")
+}
+
+// Start indicates an expected call of Start.
+func (mr *MockAppMockRecorder) Start() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Start", reflect.TypeOf((*MockApp)(nil).Start))
+}
+
+// Stop mocks base method.
+func (m *MockApp) Stop() {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Stop")
+}
+
+// Stop indicates an expected call of Stop.
+func (mr *MockAppMockRecorder) Stop() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Stop", reflect.TypeOf((*MockApp)(nil).Stop))
+}
+
+// SubmitJob mocks base method.
+func (m *MockApp) SubmitJob(ctx context.Context, accountId, jobType string, params map[string]string) (*model.Job, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SubmitJob", ctx, accountId, jobType, params)
+	ret0, _ := ret[0].(*model.Job)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SubmitJob indicates an expected call of SubmitJob.
+func (mr *MockAppMockRecorder) SubmitJob(ctx, accountId, jobType, params interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SubmitJob", reflect.TypeOf((*MockApp)(nil).SubmitJob), ctx, accountId, jobType, params)
+}
@@ -0,0 +1,288 @@
+package job
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jcpribeiro/TransactionApp/internal/tenant"
+	"github.com/jcpribeiro/TransactionApp/model"
+	"github.com/jcpribeiro/TransactionApp/store"
+	"github.com/jcpribeiro/TransactionApp/store/job"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+const testAccountId = "652d34910a8fc425116b84aa"
+
+type structTest struct {
+	store   job.Store
+	appTest App
+}
+
+func setUptest(t *testing.T) structTest {
+	jobStore := job.NewMemoryStoreJob()
+
+	return structTest{
+		store: jobStore,
+		appTest: NewAppJob(&store.Container{
+			Job: jobStore,
+		}, Options{
+			Workers:       2,
+			Dir:           t.TempDir(),
+			PollInterval:  10 * time.Millisecond,
+			LeaseDuration: 300 * time.Millisecond,
+			StopTimeout:   100 * time.Millisecond,
+		}, *logrus.New()),
+	}
+}
+
+func waitStatus(t *testing.T, app App, id string, status string) *model.Job {
+	var job *model.Job
+	assert.Eventually(t, func() bool {
+		job, _ = app.GetJob(context.Background(), testAccountId, id)
+		return job != nil && job.Status == status
+	}, 2*time.Second, 10*time.Millisecond)
+
+	return job
+}
+
+func TestSubmitJob(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("this test simulate a successful job run", func(t *testing.T) {
+		testObj := setUptest(t)
+		testObj.appTest.RegisterRunner("test", func(ctx context.Context, job *model.Job, progress Progress) (string, error) {
+			progress(10, 10)
+			return job.Params["name"] + ".json", nil
+		})
+		testObj.appTest.Start()
+		defer testObj.appTest.Stop()
+
+		submitted, err := testObj.appTest.SubmitJob(ctx, testAccountId, "test", map[string]string{"name": "result"})
+		assert.NoError(t, err)
+		assert.Equal(t, submitted.Status, model.JobStatusPending)
+
+		finished := waitStatus(t, testObj.appTest, submitted.Id, model.JobStatusSucceeded)
+		assert.Equal(t, finished.Result, "result.json")
+		assert.Equal(t, finished.Progress, int64(10))
+	})
+
+	t.Run("this test simulate a failed job run", func(t *testing.T) {
+		testObj := setUptest(t)
+		testObj.appTest.RegisterRunner("test", func(ctx context.Context, job *model.Job, progress Progress) (string, error) {
+			return "", errors.New("an error has ocurred")
+		})
+		testObj.appTest.Start()
+		defer testObj.appTest.Stop()
+
+		submitted, err := testObj.appTest.SubmitJob(ctx, testAccountId, "test", nil)
+		assert.NoError(t, err)
+
+		finished := waitStatus(t, testObj.appTest, submitted.Id, model.JobStatusFailed)
+		assert.Equal(t, finished.Error, "an error has ocurred")
+	})
+
+	t.Run("this test simulate a job that panics", func(t *testing.T) {
+		testObj := setUptest(t)
+		testObj.appTest.RegisterRunner("test", func(ctx context.Context, job *model.Job, progress Progress) (string, error) {
+			panic("unexpected")
+		})
+		testObj.appTest.Start()
+		defer testObj.appTest.Stop()
+
+		submitted, err := testObj.appTest.SubmitJob(ctx, testAccountId, "test", nil)
+		assert.NoError(t, err)
+
+		waitStatus(t, testObj.appTest, submitted.Id, model.JobStatusFailed)
+	})
+
+	t.Run("this test simulate a job with an unknown type", func(t *testing.T) {
+		testObj := setUptest(t)
+
+		submitted, err := testObj.appTest.SubmitJob(ctx, testAccountId, "unknown", nil)
+
+		assert.Nil(t, submitted)
+		assert.ErrorIs(t, err, ErrUnknownJobType)
+	})
+}
+
+func TestCancelJob(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("this test simulate the cancellation of a pending job", func(t *testing.T) {
+		testObj := setUptest(t)
+		testObj.appTest.RegisterRunner("test", func(ctx context.Context, job *model.Job, progress Progress) (string, error) {
+			return "", nil
+		})
+
+		submitted, _ := testObj.appTest.SubmitJob(ctx, testAccountId, "test", nil)
+		canceled, err := testObj.appTest.CancelJob(ctx, testAccountId, submitted.Id)
+
+		assert.NoError(t, err)
+		assert.Equal(t, canceled.Status, model.JobStatusCanceled)
+	})
+
+	t.Run("this test simulate the cancellation of a running job", func(t *testing.T) {
+		testObj := setUptest(t)
+		testObj.appTest.RegisterRunner("test", func(ctx context.Context, job *model.Job, progress Progress) (string, error) {
+			<-ctx.Done()
+			return "", ctx.Err()
+		})
+		testObj.appTest.Start()
+		defer testObj.appTest.Stop()
+
+		submitted, _ := testObj.appTest.SubmitJob(ctx, testAccountId, "test", nil)
+		waitStatus(t, testObj.appTest, submitted.Id, model.JobStatusRunning)
+
+		running, err := testObj.appTest.CancelJob(ctx, testAccountId, submitted.Id)
+		assert.NoError(t, err)
+		assert.True(t, running.CancelRequested)
+
+		waitStatus(t, testObj.appTest, submitted.Id, model.JobStatusCanceled)
+	})
+
+	t.Run("this test simulate the cancellation of an unknown job", func(t *testing.T) {
+		testObj := setUptest(t)
+
+		canceled, err := testObj.appTest.CancelJob(ctx, testAccountId, "652d34910a8fc425116b84d9")
+
+		assert.Nil(t, canceled)
+		assert.ErrorIs(t, err, job.ErrJobNotFound)
+	})
+}
+
+func TestJobAccount(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("this test simulate another account reading and canceling a job", func(t *testing.T) {
+		testObj := setUptest(t)
+		testObj.appTest.RegisterRunner("test", func(ctx context.Context, job *model.Job, progress Progress) (string, error) {
+			return "", nil
+		})
+
+		submitted, _ := testObj.appTest.SubmitJob(ctx, testAccountId, "test", nil)
+
+		found, err := testObj.appTest.GetJob(ctx, "652d34910a8fc425116b84bb", submitted.Id)
+		assert.Nil(t, found)
+		assert.ErrorIs(t, err, job.ErrJobNotFound)
+
+		canceled, err := testObj.appTest.CancelJob(ctx, "652d34910a8fc425116b84bb", submitted.Id)
+		assert.Nil(t, canceled)
+		assert.ErrorIs(t, err, job.ErrJobNotFound)
+	})
+
+	t.Run("this test simulate a job submitted without an account", func(t *testing.T) {
+		testObj := setUptest(t)
+		testObj.appTest.RegisterRunner("test", func(ctx context.Context, job *model.Job, progress Progress) (string, error) {
+			return "", nil
+		})
+
+		submitted, err := testObj.appTest.SubmitJob(ctx, "", "test", nil)
+
+		assert.Nil(t, submitted)
+		assert.ErrorIs(t, err, tenant.ErrMissingAccount)
+	})
+
+	t.Run("this test simulate a runner receiving the job account", func(t *testing.T) {
+		testObj := setUptest(t)
+		testObj.appTest.RegisterRunner("test", func(ctx context.Context, job *model.Job, progress Progress) (string, error) {
+			return job.AccountId, nil
+		})
+		testObj.appTest.Start()
+		defer testObj.appTest.Stop()
+
+		submitted, _ := testObj.appTest.SubmitJob(ctx, testAccountId, "test", nil)
+
+		finished := waitStatus(t, testObj.appTest, submitted.Id, model.JobStatusSucceeded)
+		assert.Equal(t, finished.Result, testAccountId)
+	})
+}
+
+func TestRecoverJob(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("this test simulate a job left running by a stopped process", func(t *testing.T) {
+		testObj := setUptest(t)
+		id, _ := testObj.store.InsertJob(ctx, &model.Job{AccountId: testAccountId, Type: "test", Status: model.JobStatusPending})
+		claimed, _ := testObj.store.ClaimJob(ctx, "stopped-process", time.Now().Unix(), time.Now().Add(-time.Second).Unix())
+		assert.Equal(t, claimed.Id, id)
+
+		testObj.appTest.RegisterRunner("test", func(ctx context.Context, job *model.Job, progress Progress) (string, error) {
+			return "recovered", nil
+		})
+		testObj.appTest.Start()
+		defer testObj.appTest.Stop()
+
+		finished := waitStatus(t, testObj.appTest, id, model.JobStatusSucceeded)
+		assert.Equal(t, finished.Result, "recovered")
+	})
+
+	t.Run("this test simulate a running job interrupted by stop", func(t *testing.T) {
+		testObj := setUptest(t)
+		testObj.appTest.RegisterRunner("test", func(ctx context.Context, job *model.Job, progress Progress) (string, error) {
+			<-ctx.Done()
+			return "", ctx.Err()
+		})
+		testObj.appTest.Start()
+
+		submitted, _ := testObj.appTest.SubmitJob(ctx, testAccountId, "test", nil)
+		waitStatus(t, testObj.appTest, submitted.Id, model.JobStatusRunning)
+		testObj.appTest.Stop()
+
+		released, err := testObj.appTest.GetJob(ctx, testAccountId, submitted.Id)
+		assert.NoError(t, err)
+		assert.Equal(t, released.Status, model.JobStatusPending)
+	})
+
+	t.Run("this test simulate a running job whose lease was taken over by another process", func(t *testing.T) {
+		testObj := setUptest(t)
+		stopped := make(chan struct{})
+		testObj.appTest.RegisterRunner("test", func(ctx context.Context, job *model.Job, progress Progress) (string, error) {
+			<-ctx.Done()
+			close(stopped)
+			return "", ctx.Err()
+		})
+		testObj.appTest.Start()
+
+		submitted, _ := testObj.appTest.SubmitJob(ctx, testAccountId, "test", nil)
+		waitStatus(t, testObj.appTest, submitted.Id, model.JobStatusRunning)
+		later := time.Now().Add(time.Hour).Unix()
+		claimed, _ := testObj.store.ClaimJob(ctx, "other-process", later, later)
+		assert.Equal(t, claimed.Id, submitted.Id)
+
+		select {
+		case <-stopped:
+		case <-time.After(2 * time.Second):
+			t.Fatal("the job was not stopped")
+		}
+		testObj.appTest.Stop()
+
+		current, err := testObj.appTest.GetJob(ctx, testAccountId, submitted.Id)
+		assert.NoError(t, err)
+		assert.Equal(t, current.Status, model.JobStatusRunning)
+		assert.Equal(t, current.Owner, "other-process")
+	})
+}
+
+func TestFilePath(t *testing.T) {
+	t.Run("this test simulate a valid job file name", func(t *testing.T) {
+		testObj := setUptest(t)
+
+		path, err := testObj.appTest.FilePath("652d34910a8fc425116b84d9.csv")
+
+		assert.NoError(t, err)
+		assert.Contains(t, path, "652d34910a8fc425116b84d9.csv")
+	})
+
+	t.Run("this test simulate a job file name outside the jobs dir", func(t *testing.T) {
+		testObj := setUptest(t)
+
+		for _, name := range []string{"", "../config.json", "/etc/passwd", ".."} {
+			_, err := testObj.appTest.FilePath(name)
+			assert.Error(t, err, name)
+		}
+	})
+}
@@ -0,0 +1,136 @@
+package migration
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jcpribeiro/TransactionApp/store"
+
+	"github.com/sirupsen/logrus"
+)
+
+//go:generate mockgen -source=$GOFILE -destination=migration_mock.go -package=$GOPACKAGE
+
+// Migration changes the stored documents once. Run returns the number of updated documents
+// and must be safe to run again, as an interrupted migration starts over when its lock expires.
+type Migration struct {
+	Id  string
+	Run func(ctx context.Context, stores *store.Container, opts Options) (int64, error)
+}
+
+// Options configures the migrations. DefaultAccount is the account of the documents stored
+// before the accounts, it is required while such documents exist.
+type Options struct {
+	DefaultAccount string
+}
+
+type App interface {
+	Run(ctx context.Context) error
+}
+
+// lockDuration bounds how long an instance that died while migrating blocks the others
+const lockDuration = 30 * time.Minute
+
+// migrations run in order. A released id must never change, it is how a migration is known to be finished.
+var migrations = []Migration{
+	{
+		Id: "0001_normalize_purchase_dates",
+		Run: func(ctx context.Context, stores *store.Container, opts Options) (int64, error) {
+			return stores.Transaction.NormalizePurchaseDates(ctx)
+		},
+	},
+	{
+		Id: "0002_convert_date_fields",
+		Run: func(ctx context.Context, stores *store.Container, opts Options) (int64, error) {
+			return stores.Transaction.ConvertDateFields(ctx)
+		},
+	},
+	{
+		Id:  "0003_assign_default_account",
+		Run: assignDefaultAccount,
+	},
+}
+
+// assignDefaultAccount scopes the transactions and jobs stored before the accounts to the default
+// account, without it they are never read again. It fails while such documents exist and no default
+// account is configured, so that it runs again on the next start.
+func assignDefaultAccount(ctx context.Context, stores *store.Container, opts Options) (int64, error) {
+	if len(opts.DefaultAccount) == 0 {
+		legacy, err := stores.Transaction.CountWithoutAccount(ctx)
+		if err != nil {
+			return 0, err
+		}
+		if legacy > 0 {
+			return 0, fmt.Errorf("%d transactions have no account, set migrations.default_account", legacy)
+		}
+		return 0, nil
+	}
+
+	transactions, err := stores.Transaction.AssignAccount(ctx, opts.DefaultAccount)
+	if err != nil {
+		return 0, err
+	}
+
+	jobs, err := stores.Job.AssignAccount(ctx, opts.DefaultAccount)
+	if err != nil {
+		return transactions, err
+	}
+
+	return transactions + jobs, nil
+}
+
+type appImpl struct {
+	stores     *store.Container
+	migrations []Migration
+	opts       Options
+	now        func() time.Time
+	log        logrus.Logger
+}
+
+func NewAppMigration(stores *store.Container, opts Options, log logrus.Logger) App {
+	return &appImpl{
+		stores:     stores,
+		migrations: migrations,
+		opts:       opts,
+		now:        time.Now,
+		log:        log,
+	}
+}
+
+// Run applies the pending migrations in order. It stops without error at a migration
+// another instance is running, the migrations after it may depend on it.
+func (a appImpl) Run(ctx context.Context) error {
+	finished, err := a.stores.Migration.GetFinishedMigrations(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get finished migrations: %w", err)
+	}
+
+	for _, m := range a.migrations {
+		if finished[m.Id] {
+			continue
+		}
+
+		now := a.now()
+		claimed, err := a.stores.Migration.ClaimMigration(ctx, m.Id, now.Unix(), now.Add(lockDuration).Unix())
+		if err != nil {
+			return fmt.Errorf("failed to claim migration %s: %w", m.Id, err)
+		}
+		if !claimed {
+			a.log.Infof("migration %s is running on another instance", m.Id)
+			return nil
+		}
+
+		updated, err := m.Run(ctx, a.stores, a.opts)
+		if err != nil {
+			return fmt.Errorf("migration %s failed: %w", m.Id, err)
+		}
+
+		if err := a.stores.Migration.FinishMigration(ctx, m.Id, updated, a.now().Unix()); err != nil {
+			return fmt.Errorf("failed to finish migration %s: %w", m.Id, err)
+		}
+		a.log.Infof("migration %s finished: %d documents updated", m.Id, updated)
+	}
+
+	return nil
+}
@@ -0,0 +1,145 @@
+package migration
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jcpribeiro/TransactionApp/store"
+	"github.com/jcpribeiro/TransactionApp/store/job"
+	"github.com/jcpribeiro/TransactionApp/store/migration"
+	"github.com/jcpribeiro/TransactionApp/store/transaction"
+
+	"github.com/golang/mock/gomock"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+type structTest struct {
+	migrationMock   *migration.MockStore
+	transactionMock *transaction.MockStore
+	jobMock         *job.MockStore
+	appTest         App
+}
+
+func setUptest(t *testing.T, opts Options) structTest {
+	ctrl := gomock.NewController(t)
+	migrationMock := migration.NewMockStore(ctrl)
+	transactionMock := transaction.NewMockStore(ctrl)
+	jobMock := job.NewMockStore(ctrl)
+
+	return structTest{
+		migrationMock:   migrationMock,
+		transactionMock: transactionMock,
+		jobMock:         jobMock,
+		appTest: &appImpl{
+			stores: &store.Container{
+				Migration:   migrationMock,
+				Transaction: transactionMock,
+				Job:         jobMock,
+			},
+			migrations: migrations,
+			opts:       opts,
+			now: func() time.Time {
+				return time.Unix(1697409353, 0)
+			},
+			log: *logrus.New(),
+		},
+	}
+}
+
+func TestRun(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("this test simulate running the pending migrations", func(t *testing.T) {
+		testObj := setUptest(t, Options{})
+		testObj.migrationMock.EXPECT().GetFinishedMigrations(ctx).Return(map[string]bool{}, nil)
+		gomock.InOrder(
+			testObj.migrationMock.EXPECT().ClaimMigration(ctx, "0001_normalize_purchase_dates", int64(1697409353), int64(1697411153)).Return(true, nil),
+			testObj.transactionMock.EXPECT().NormalizePurchaseDates(ctx).Return(int64(3), nil),
+			testObj.migrationMock.EXPECT().FinishMigration(ctx, "0001_normalize_purchase_dates", int64(3), int64(1697409353)).Return(nil),
+			testObj.migrationMock.EXPECT().ClaimMigration(ctx, "0002_convert_date_fields", int64(1697409353), int64(1697411153)).Return(true, nil),
+			testObj.transactionMock.EXPECT().ConvertDateFields(ctx).Return(int64(5), nil),
+			testObj.migrationMock.EXPECT().FinishMigration(ctx, "0002_convert_date_fields", int64(5), int64(1697409353)).Return(nil),
+			testObj.migrationMock.EXPECT().ClaimMigration(ctx, "0003_assign_default_account", int64(1697409353), int64(1697411153)).Return(true, nil),
+			testObj.transactionMock.EXPECT().CountWithoutAccount(ctx).Return(int64(0), nil),
+			testObj.migrationMock.EXPECT().FinishMigration(ctx, "0003_assign_default_account", int64(0), int64(1697409353)).Return(nil),
+		)
+
+		err := testObj.appTest.Run(ctx)
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("this test simulate a finished migration", func(t *testing.T) {
+		testObj := setUptest(t, Options{})
+		testObj.migrationMock.EXPECT().GetFinishedMigrations(ctx).Return(map[string]bool{"0001_normalize_purchase_dates": true, "0003_assign_default_account": true}, nil)
+		testObj.migrationMock.EXPECT().ClaimMigration(ctx, "0002_convert_date_fields", gomock.Any(), gomock.Any()).Return(true, nil)
+		testObj.transactionMock.EXPECT().ConvertDateFields(ctx).Return(int64(0), nil)
+		testObj.migrationMock.EXPECT().FinishMigration(ctx, "0002_convert_date_fields", int64(0), gomock.Any()).Return(nil)
+
+		err := testObj.appTest.Run(ctx)
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("this test simulate a migration running on another instance", func(t *testing.T) {
+		testObj := setUptest(t, Options{})
+		testObj.migrationMock.EXPECT().GetFinishedMigrations(ctx).Return(map[string]bool{}, nil)
+		testObj.migrationMock.EXPECT().ClaimMigration(ctx, "0001_normalize_purchase_dates", gomock.Any(), gomock.Any()).Return(false, nil)
+
+		err := testObj.appTest.Run(ctx)
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("this test simulate a failed migration", func(t *testing.T) {
+		testObj := setUptest(t, Options{})
+		testObj.migrationMock.EXPECT().GetFinishedMigrations(ctx).Return(map[string]bool{}, nil)
+		testObj.migrationMock.EXPECT().ClaimMigration(ctx, "0001_normalize_purchase_dates", gomock.Any(), gomock.Any()).Return(true, nil)
+		testObj.transactionMock.EXPECT().NormalizePurchaseDates(ctx).Return(int64(0), errors.New("an error has ocurred"))
+
+		err := testObj.appTest.Run(ctx)
+
+		assert.Error(t, err)
+	})
+
+	t.Run("this test simulate an error obtaining the finished migrations", func(t *testing.T) {
+		testObj := setUptest(t, Options{})
+		testObj.migrationMock.EXPECT().GetFinishedMigrations(ctx).Return(nil, errors.New("an error has ocurred"))
+
+		err := testObj.appTest.Run(ctx)
+
+		assert.Error(t, err)
+	})
+}
+
+func TestAssignDefaultAccount(t *testing.T) {
+	ctx := context.Background()
+	finished := map[string]bool{"0001_normalize_purchase_dates": true, "0002_convert_date_fields": true}
+
+	t.Run("this test simulate assigning the default account to the legacy documents", func(t *testing.T) {
+		testObj := setUptest(t, Options{DefaultAccount: "652d34910a8fc425116b84aa"})
+		testObj.migrationMock.EXPECT().GetFinishedMigrations(ctx).Return(finished, nil)
+		testObj.migrationMock.EXPECT().ClaimMigration(ctx, "0003_assign_default_account", gomock.Any(), gomock.Any()).Return(true, nil)
+		testObj.transactionMock.EXPECT().AssignAccount(ctx, "652d34910a8fc425116b84aa").Return(int64(3), nil)
+		testObj.jobMock.EXPECT().AssignAccount(ctx, "652d34910a8fc425116b84aa").Return(int64(1), nil)
+		testObj.migrationMock.EXPECT().FinishMigration(ctx, "0003_assign_default_account", int64(4), gomock.Any()).Return(nil)
+
+		err := testObj.appTest.Run(ctx)
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("this test simulate legacy documents without a default account", func(t *testing.T) {
+		testObj := setUptest(t, Options{})
+		testObj.migrationMock.EXPECT().GetFinishedMigrations(ctx).Return(finished, nil)
+		testObj.migrationMock.EXPECT().ClaimMigration(ctx, "0003_assign_default_account", gomock.Any(), gomock.Any()).Return(true, nil)
+		testObj.transactionMock.EXPECT().CountWithoutAccount(ctx).Return(int64(2), nil)
+
+		err := testObj.appTest.Run(ctx)
+
+		assert.ErrorContains(t, err, "set migrations.default_account")
+	})
+}
@@ -0,0 +1,235 @@
+package outbox
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jcpribeiro/TransactionApp/model"
+	"github.com/jcpribeiro/TransactionApp/store"
+
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+//go:generate mockgen -source=$GOFILE -destination=outbox_mock.go -package=$GOPACKAGE
+
+// Sink publishes the outbox events to a consumer. An event can be published more than once,
+// so a sink passes its id along for the consumers to drop the duplicates.
+type Sink interface {
+	Name() string
+	Publish(ctx context.Context, event *model.OutboxEvent) error
+}
+
+type App interface {
+	RegisterSink(sink Sink)
+	Start()
+	Stop()
+}
+
+// Options of the relay. An event that fails to publish is retried after MinBackoff,
+// doubled on every attempt up to MaxBackoff, until every sink accepts it.
+type Options struct {
+	Workers       int
+	PollInterval  time.Duration
+	LeaseDuration time.Duration
+	StopTimeout   time.Duration
+	Timeout       time.Duration
+	MinBackoff    time.Duration
+	MaxBackoff    time.Duration
+}
+
+type appImpl struct {
+	stores  *store.Container
+	log     logrus.Logger
+	opts    Options
+	owner   string
+	sinks   []Sink
+	mu      sync.Mutex
+	stop    chan struct{}
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+	started bool
+}
+
+func NewAppOutbox(stores *store.Container, opts Options, log logrus.Logger) App {
+	if opts.Workers <= 0 {
+		opts.Workers = 1
+	}
+	if opts.PollInterval <= 0 {
+		opts.PollInterval = time.Second
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = 10 * time.Second
+	}
+	if opts.LeaseDuration <= opts.Timeout {
+		opts.LeaseDuration = opts.Timeout + 30*time.Second
+	}
+	if opts.StopTimeout <= 0 {
+		opts.StopTimeout = opts.Timeout
+	}
+	if opts.MinBackoff <= 0 {
+		opts.MinBackoff = time.Second
+	}
+	if opts.MaxBackoff < opts.MinBackoff {
+		opts.MaxBackoff = 5 * time.Minute
+	}
+
+	hostname, _ := os.Hostname()
+
+	return &appImpl{
+		stores: stores,
+		log:    log,
+		opts:   opts,
+		owner:  fmt.Sprintf("%s-%d-%s", hostname, os.Getpid(), primitive.NewObjectID().Hex()),
+	}
+}
+
+// RegisterSink adds a sink the events are published to, it must be called before Start
+func (a *appImpl) RegisterSink(sink Sink) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.sinks = append(a.sinks, sink)
+}
+
+// Start runs the relay workers. Without sinks the events are kept in the outbox until a relay with sinks runs.
+func (a *appImpl) Start() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.started {
+		return
+	}
+	if len(a.sinks) == 0 {
+		a.log.Warn("outbox has no sinks, the events are not published")
+		return
+	}
+
+	var ctx context.Context
+	ctx, a.cancel = context.WithCancel(context.Background())
+	a.stop = make(chan struct{})
+	a.started = true
+
+	for i := 0; i < a.opts.Workers; i++ {
+		a.wg.Add(1)
+		go a.work(ctx)
+	}
+
+	a.log.Infof("Started %d outbox workers", a.opts.Workers)
+}
+
+// Stop waits for the events being published. After StopTimeout they are interrupted
+// and published again once their lease expires.
+func (a *appImpl) Stop() {
+	a.mu.Lock()
+	if !a.started {
+		a.mu.Unlock()
+		return
+	}
+	a.started = false
+	close(a.stop)
+	a.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		a.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(a.opts.StopTimeout):
+		a.log.Warn("outbox workers did not stop in time, interrupting running events")
+		a.cancel()
+		<-done
+	}
+	a.cancel()
+}
+
+func (a *appImpl) stopping() bool {
+	select {
+	case <-a.stop:
+		return true
+	default:
+		return false
+	}
+}
+
+func (a *appImpl) work(ctx context.Context) {
+	defer a.wg.Done()
+
+	for !a.stopping() {
+		now := time.Now()
+		event, err := a.stores.Outbox.ClaimEvent(ctx, a.owner, now.Unix(), now.Add(a.opts.LeaseDuration).Unix())
+		if err != nil {
+			a.log.Error("cannot claim outbox event ", err.Error())
+		}
+
+		if event == nil {
+			select {
+			case <-a.stop:
+			case <-time.After(a.opts.PollInterval):
+			}
+			continue
+		}
+
+		a.relay(ctx, event)
+	}
+}
+
+// backoff returns how long to wait after a failed attempt before the next one
+func (a *appImpl) backoff(attempts int) time.Duration {
+	wait := a.opts.MinBackoff
+	for i := 1; i < attempts; i++ {
+		wait *= 2
+		if wait >= a.opts.MaxBackoff {
+			return a.opts.MaxBackoff
+		}
+	}
+
+	return wait
+}
+
+// publish sends the event to every sink and returns the errors of the sinks that failed
+func (a *appImpl) publish(ctx context.Context, event *model.OutboxEvent) []string {
+	ctx, cancel := context.WithTimeout(ctx, a.opts.Timeout)
+	defer cancel()
+
+	failures := make([]string, 0)
+	for _, sink := range a.sinks {
+		if err := sink.Publish(ctx, event); err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %s", sink.Name(), err.Error()))
+		}
+	}
+
+	return failures
+}
+
+// relay publishes a claimed event and removes it from the outbox, or schedules the next attempt when a sink fails.
+// Every sink gets the event again on a retry, the ones that already had it drop it by its id.
+func (a *appImpl) relay(ctx context.Context, event *model.OutboxEvent) {
+	failures := a.publish(ctx, event)
+	if ctx.Err() != nil {
+		// interrupted by Stop, the event is published again once the lease expires
+		return
+	}
+
+	if len(failures) == 0 {
+		if err := a.stores.Outbox.DeleteEvent(context.Background(), event.Id, a.owner); err != nil {
+			a.log.Error(fmt.Errorf("cannot delete outbox event %s: %w", event.Id, err))
+		}
+		return
+	}
+
+	event.Attempts++
+	event.Error = strings.Join(failures, "; ")
+	event.NextAttemptAt = time.Now().Add(a.backoff(event.Attempts)).Unix()
+
+	a.log.Warnf("outbox event %s of %s failed attempt %d: %s", event.Id, event.Type, event.Attempts, event.Error)
+	if err := a.stores.Outbox.RetryEvent(context.Background(), event.Id, a.owner, event, time.Now().Unix()); err != nil {
+		a.log.Error(fmt.Errorf("cannot retry outbox event %s: %w", event.Id, err))
+	}
+}
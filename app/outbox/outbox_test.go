@@ -0,0 +1,143 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jcpribeiro/TransactionApp/model"
+	"github.com/jcpribeiro/TransactionApp/store"
+	"github.com/jcpribeiro/TransactionApp/store/outbox"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+const testAccountId = "652d34910a8fc425116b84aa"
+
+type structTest struct {
+	store   outbox.Store
+	appTest App
+}
+
+func setUptest(t *testing.T) structTest {
+	outboxStore := outbox.NewMemoryStoreOutbox()
+
+	return structTest{
+		store: outboxStore,
+		appTest: NewAppOutbox(&store.Container{
+			Outbox: outboxStore,
+		}, Options{
+			Workers:      2,
+			PollInterval: 10 * time.Millisecond,
+			Timeout:      time.Second,
+			StopTimeout:  100 * time.Millisecond,
+			MinBackoff:   time.Millisecond,
+			MaxBackoff:   time.Millisecond,
+		}, *logrus.New()),
+	}
+}
+
+func insertEvent(t *testing.T, s outbox.Store, aggregateId string) {
+	event, err := outbox.NewEvent(testAccountId, model.EventTransactionCreated, aggregateId, map[string]string{"id": aggregateId}, time.Now())
+	assert.NoError(t, err)
+	assert.NoError(t, s.InsertEvents(context.Background(), []*model.OutboxEvent{event}))
+}
+
+// flakySink fails the first publishes of every event
+type flakySink struct {
+	failures int32
+	calls    int32
+}
+
+func (f *flakySink) Name() string {
+	return "flaky"
+}
+
+func (f *flakySink) Publish(ctx context.Context, event *model.OutboxEvent) error {
+	if atomic.AddInt32(&f.calls, 1) <= f.failures {
+		return errors.New("unavailable")
+	}
+	return nil
+}
+
+func TestRelay(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("this test simulate the events published to every sink", func(t *testing.T) {
+		testObj := setUptest(t)
+		first, second := NewMemorySink(), NewMemorySink()
+		testObj.appTest.RegisterSink(first)
+		testObj.appTest.RegisterSink(second)
+		insertEvent(t, testObj.store, "652d34910a8fc425116b84d9")
+		insertEvent(t, testObj.store, "652d34910a8fc425116b84da")
+
+		testObj.appTest.Start()
+		defer testObj.appTest.Stop()
+
+		assert.Eventually(t, func() bool {
+			return len(first.Events()) == 2 && len(second.Events()) == 2
+		}, 2*time.Second, 10*time.Millisecond)
+		assert.Eventually(t, func() bool {
+			event, _ := testObj.store.ClaimEvent(ctx, "test", time.Now().Add(time.Hour).Unix(), time.Now().Add(time.Hour).Unix())
+			return event == nil
+		}, 2*time.Second, 10*time.Millisecond)
+
+		event := first.Events()[0]
+		assert.NotEmpty(t, event.Id)
+		assert.Equal(t, event.AccountId, testAccountId)
+		assert.Equal(t, event.Type, model.EventTransactionCreated)
+	})
+
+	t.Run("this test simulate an event retried after a sink failed", func(t *testing.T) {
+		testObj := setUptest(t)
+		memory, flaky := NewMemorySink(), &flakySink{failures: 2}
+		testObj.appTest.RegisterSink(memory)
+		testObj.appTest.RegisterSink(flaky)
+		insertEvent(t, testObj.store, "652d34910a8fc425116b84d9")
+
+		testObj.appTest.Start()
+		defer testObj.appTest.Stop()
+
+		assert.Eventually(t, func() bool {
+			return atomic.LoadInt32(&flaky.calls) == 3
+		}, 2*time.Second, 10*time.Millisecond)
+
+		// the memory sink got the event on every attempt and kept it once
+		assert.Len(t, memory.Events(), 1)
+	})
+
+	t.Run("this test simulate a relay without sinks", func(t *testing.T) {
+		testObj := setUptest(t)
+		insertEvent(t, testObj.store, "652d34910a8fc425116b84d9")
+
+		testObj.appTest.Start()
+		testObj.appTest.Stop()
+
+		event, err := testObj.store.ClaimEvent(ctx, "test", time.Now().Unix(), time.Now().Add(time.Minute).Unix())
+		assert.NoError(t, err)
+		assert.NotNil(t, event)
+	})
+}
+
+func TestMemorySink(t *testing.T) {
+	ctx := context.Background()
+	sink := NewMemorySink()
+	event := &model.OutboxEvent{Id: "652d34910a8fc425116b84e1", Type: model.EventTransactionCreated}
+
+	assert.NoError(t, sink.Publish(ctx, event))
+	assert.NoError(t, sink.Publish(ctx, event))
+
+	assert.Len(t, sink.Events(), 1)
+}
+
+func TestBackoff(t *testing.T) {
+	a := &appImpl{opts: Options{MinBackoff: time.Second, MaxBackoff: 5 * time.Second}}
+
+	assert.Equal(t, a.backoff(1), time.Second)
+	assert.Equal(t, a.backoff(2), 2*time.Second)
+	assert.Equal(t, a.backoff(3), 4*time.Second)
+	assert.Equal(t, a.backoff(4), 5*time.Second)
+}
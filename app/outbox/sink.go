@@ -0,0 +1,114 @@
+package outbox
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/jcpribeiro/TransactionApp/app/webhook"
+	"github.com/jcpribeiro/TransactionApp/model"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// MemorySink keeps the published events in process, dropping the ones it already has. It is meant for tests and local development.
+type MemorySink struct {
+	mu     sync.Mutex
+	seen   map[string]bool
+	events []*model.OutboxEvent
+}
+
+func NewMemorySink() *MemorySink {
+	return &MemorySink{
+		seen: make(map[string]bool),
+	}
+}
+
+func (m *MemorySink) Name() string {
+	return "memory"
+}
+
+func (m *MemorySink) Publish(ctx context.Context, event *model.OutboxEvent) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.seen[event.Id] {
+		return nil
+	}
+	m.seen[event.Id] = true
+
+	c := *event
+	c.Payload = append([]byte(nil), event.Payload...)
+	m.events = append(m.events, &c)
+
+	return nil
+}
+
+// Events returns the published events, in the order they were published
+func (m *MemorySink) Events() []*model.OutboxEvent {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return append([]*model.OutboxEvent(nil), m.events...)
+}
+
+type redisSink struct {
+	client *redis.Client
+	stream string
+	maxLen int64
+}
+
+// NewRedisSink publishes the events to a Redis stream, trimmed to about maxLen entries when it is positive.
+// The id field of an entry is the outbox event id, the consumers use it to drop the duplicates.
+func NewRedisSink(client *redis.Client, stream string, maxLen int64) Sink {
+	return &redisSink{
+		client: client,
+		stream: stream,
+		maxLen: maxLen,
+	}
+}
+
+func (r *redisSink) Name() string {
+	return "redis"
+}
+
+func (r *redisSink) Publish(ctx context.Context, event *model.OutboxEvent) error {
+	return r.client.XAdd(ctx, &redis.XAddArgs{
+		Stream: r.stream,
+		MaxLen: r.maxLen,
+		Approx: r.maxLen > 0,
+		Values: []interface{}{
+			"id", event.Id,
+			"account_id", event.AccountId,
+			"type", event.Type,
+			"aggregate_id", event.AggregateId,
+			"payload", string(event.Payload),
+			"created_at", event.CreatedAt,
+		},
+	}).Err()
+}
+
+type webhookSink struct {
+	webhooks webhook.App
+}
+
+// NewWebhookSink queues the deliveries of the events to the subscribed webhooks. The outbox event id is the
+// webhook event id, so an event published again is not delivered twice.
+func NewWebhookSink(webhooks webhook.App) Sink {
+	return &webhookSink{
+		webhooks: webhooks,
+	}
+}
+
+func (w *webhookSink) Name() string {
+	return "webhook"
+}
+
+func (w *webhookSink) Publish(ctx context.Context, event *model.OutboxEvent) error {
+	return w.webhooks.Emit(ctx, event.AccountId, &model.WebhookEvent{
+		Id:        event.Id,
+		Type:      event.Type,
+		CreatedAt: time.Unix(event.CreatedAt, 0).UTC().Format(time.RFC3339),
+		Data:      event.Payload,
+	})
+}
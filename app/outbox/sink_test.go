@@ -0,0 +1,79 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/jcpribeiro/TransactionApp/app/webhook"
+	"github.com/jcpribeiro/TransactionApp/model"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/go-redis/redismock/v8"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func testEvent() *model.OutboxEvent {
+	return &model.OutboxEvent{
+		Id:          "652d34910a8fc425116b84e1",
+		AccountId:   testAccountId,
+		Type:        model.EventTransactionCreated,
+		AggregateId: "652d34910a8fc425116b84d9",
+		Payload:     json.RawMessage(`{"id":"652d34910a8fc425116b84d9"}`),
+		CreatedAt:   1697371200,
+	}
+}
+
+func TestRedisSink(t *testing.T) {
+	ctx := context.Background()
+	args := &redis.XAddArgs{
+		Stream: "events",
+		MaxLen: 1000,
+		Approx: true,
+		Values: []interface{}{
+			"id", "652d34910a8fc425116b84e1",
+			"account_id", testAccountId,
+			"type", model.EventTransactionCreated,
+			"aggregate_id", "652d34910a8fc425116b84d9",
+			"payload", `{"id":"652d34910a8fc425116b84d9"}`,
+			"created_at", int64(1697371200),
+		},
+	}
+
+	t.Run("this test simulate an event added to the stream", func(t *testing.T) {
+		db, mock := redismock.NewClientMock()
+		mock.ExpectXAdd(args).SetVal("1697371200000-0")
+
+		err := NewRedisSink(db, "events", 1000).Publish(ctx, testEvent())
+
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("this test simulate redis unavailable", func(t *testing.T) {
+		db, mock := redismock.NewClientMock()
+		mock.ExpectXAdd(args).SetErr(errors.New("connection refused"))
+
+		err := NewRedisSink(db, "events", 1000).Publish(ctx, testEvent())
+
+		assert.Error(t, err)
+	})
+}
+
+func TestWebhookSink(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("this test simulate an event emitted to the webhooks with the outbox id", func(t *testing.T) {
+		webhooksMock := webhook.NewMockApp(gomock.NewController(t))
+		webhooksMock.EXPECT().Emit(ctx, testAccountId, &model.WebhookEvent{
+			Id:        "652d34910a8fc425116b84e1",
+			Type:      model.EventTransactionCreated,
+			CreatedAt: "2023-10-15T12:00:00Z",
+			Data:      json.RawMessage(`{"id":"652d34910a8fc425116b84d9"}`),
+		}).Return(nil)
+
+		assert.NoError(t, NewWebhookSink(webhooksMock).Publish(ctx, testEvent()))
+	})
+}
@@ -0,0 +1,246 @@
+package transaction
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jcpribeiro/TransactionApp/model"
+)
+
+// errImportInsert is reported for the rows of a batch the store failed to insert
+var errImportInsert = errors.New("failed to insert transaction")
+
+const (
+	importBatchSize = 500
+
+	ImportFormatCSV    = "csv"
+	ImportFormatNDJSON = "ndjson"
+)
+
+// importRow is a parsed transaction and the line it was read from
+type importRow struct {
+	line        int
+	transaction *model.Transaction
+}
+
+// rowReader reads one transaction at a time, returning io.EOF at the end of the file.
+// A row error is returned as *importRowError so the import can go on with the next row.
+type rowReader func() (*importRow, error)
+
+type importRowError struct {
+	line int
+	err  error
+}
+
+func (e *importRowError) Error() string {
+	return fmt.Sprintf("line %d: %s", e.line, e.err)
+}
+
+// ImportTransactions validates and inserts the transactions read from body in batches.
+// Invalid rows are reported in the result and do not fail the whole import. The source id
+// of a row is its line in source, so a rerun import does not insert a row twice.
+func (a appImpl) ImportTransactions(ctx context.Context, accountId, format, source string, body io.Reader) (*model.ImportReport, error) {
+	var next rowReader
+	var err error
+	switch format {
+	case ImportFormatCSV:
+		next, err = newCSVReader(body)
+	case ImportFormatNDJSON:
+		next = newNDJSONReader(body)
+	default:
+		err = fmt.Errorf("unsupported import format: %s", format)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	report := &model.ImportReport{
+		Errors: []*model.ImportError{},
+	}
+
+	now := time.Now()
+	batch := make([]*importRow, 0, importBatchSize)
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		row, err := next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		var rowErr *importRowError
+		if errors.As(err, &rowErr) {
+			addImportError(report, rowErr.line, rowErr.err)
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read import: %w", err)
+		}
+
+		if err := a.check(row.transaction, now); err != nil {
+			addImportError(report, row.line, err)
+			continue
+		}
+		if len(source) > 0 {
+			row.transaction.SourceId = fmt.Sprintf("%s:%d", source, row.line)
+		}
+
+		batch = append(batch, row)
+		if len(batch) == importBatchSize {
+			a.insertImportBatch(ctx, accountId, batch, report)
+			batch = batch[:0]
+		}
+	}
+
+	if len(batch) > 0 {
+		a.insertImportBatch(ctx, accountId, batch, report)
+	}
+
+	a.log.Infof("import of account %s finished: %d inserted, %d failed", accountId, report.Inserted, report.Failed)
+	return report, nil
+}
+
+// addImportError reports a failed row, with the rule it broke if any
+func addImportError(report *model.ImportReport, line int, err error) {
+	importErr := &model.ImportError{
+		Line:  line,
+		Error: err.Error(),
+	}
+
+	var ruleErr *RuleError
+	if errors.As(err, &ruleErr) {
+		importErr.Rule = ruleErr.Rule
+	}
+
+	report.Failed++
+	report.Errors = append(report.Errors, importErr)
+}
+
+func (a appImpl) insertImportBatch(ctx context.Context, accountId string, batch []*importRow, report *model.ImportReport) {
+	transactions := make([]*model.Transaction, 0, len(batch))
+	for _, row := range batch {
+		transactions = append(transactions, row.transaction)
+	}
+
+	ids, err := a.InsertTransactions(ctx, accountId, transactions)
+	if err != nil {
+		a.log.Error(fmt.Errorf("import of account %s: failed to insert batch: %w", accountId, err))
+		for _, row := range batch {
+			addImportError(report, row.line, errImportInsert)
+		}
+		return
+	}
+
+	report.Inserted += len(ids)
+}
+
+// newCSVReader reads a csv file whose header names the purchase_amount and description columns,
+// in any order. The purchase_date, purchased_at, source_currency, category, merchant and tags
+// columns are optional, the tags of a row are separated by semicolons.
+func newCSVReader(body io.Reader) (rowReader, error) {
+	r := csv.NewReader(body)
+	r.FieldsPerRecord = -1
+	r.TrimLeadingSpace = true
+
+	header, err := r.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read csv header: %w", err)
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, name := range []string{"purchase_amount", "description"} {
+		if _, ok := columns[name]; !ok {
+			return nil, fmt.Errorf("missing csv column: %s", name)
+		}
+	}
+
+	field := func(record []string, name string) string {
+		i, ok := columns[name]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	return func() (*importRow, error) {
+		record, err := r.Read()
+		if err != nil {
+			var parseErr *csv.ParseError
+			if errors.As(err, &parseErr) {
+				return nil, &importRowError{line: parseErr.Line, err: parseErr.Err}
+			}
+			return nil, err
+		}
+
+		line, _ := r.FieldPos(0)
+		row := &importRow{
+			line: line,
+			transaction: &model.Transaction{
+				Description:    field(record, "description"),
+				PurchaseDate:   field(record, "purchase_date"),
+				PurchasedAt:    field(record, "purchased_at"),
+				SourceCurrency: field(record, "source_currency"),
+				Category:       field(record, "category"),
+				Merchant:       field(record, "merchant"),
+			},
+		}
+
+		for _, tag := range strings.Split(field(record, "tags"), ";") {
+			if tag = strings.TrimSpace(tag); len(tag) > 0 {
+				row.transaction.Tags = append(row.transaction.Tags, tag)
+			}
+		}
+
+		if amount := field(record, "purchase_amount"); len(amount) > 0 {
+			row.transaction.PurchaseAmount, err = strconv.ParseFloat(amount, 64)
+			if err != nil {
+				return nil, &importRowError{line: line, err: fmt.Errorf("invalid purchase_amount: %s", amount)}
+			}
+		}
+
+		return row, nil
+	}, nil
+}
+
+// newNDJSONReader reads one json transaction per line, skipping blank lines
+func newNDJSONReader(body io.Reader) rowReader {
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	line := 0
+
+	return func() (*importRow, error) {
+		for scanner.Scan() {
+			line++
+			text := strings.TrimSpace(scanner.Text())
+			if len(text) == 0 {
+				continue
+			}
+
+			transaction := &model.Transaction{}
+			if err := json.Unmarshal([]byte(text), transaction); err != nil {
+				return nil, &importRowError{line: line, err: fmt.Errorf("invalid json: %w", err)}
+			}
+
+			return &importRow{line: line, transaction: transaction}, nil
+		}
+
+		if err := scanner.Err(); err != nil {
+			return nil, err
+		}
+
+		return nil, io.EOF
+	}
+}
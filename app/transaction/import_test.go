@@ -0,0 +1,135 @@
+package transaction
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/jcpribeiro/TransactionApp/model"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestImportTransactions(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("this test simulate a csv import with invalid rows", func(t *testing.T) {
+		testObj := setUptest(t)
+		body := strings.Join([]string{
+			"description,purchase_amount,purchase_date",
+			"Test1,23.70,2023-10-15",
+			"Test2,abc,2023-10-15",
+			",25.00,2023-10-14",
+			"Test3,25.00,2023-10-14",
+		}, "\n")
+		testObj.storesMock.EXPECT().InsertTransactions(ctx, testAccountId, gomock.Len(2)).DoAndReturn(func(ctx context.Context, accountId string, transactions []*model.Transaction) ([]string, error) {
+			assert.Equal(t, transactions[0].Description, "Test1")
+			assert.Equal(t, transactions[0].PurchaseAmount, 23.70)
+			assert.Equal(t, transactions[1].Description, "Test3")
+			return []string{"652d34910a8fc425116b84d9", "652d34910a8fc425116b84d8"}, nil
+		})
+
+		report, err := testObj.appTest.ImportTransactions(ctx, testAccountId, ImportFormatCSV, "", strings.NewReader(body))
+
+		assert.NoError(t, err)
+		assert.Empty(t, report.JobId)
+		assert.Equal(t, report.Inserted, 2)
+		assert.Equal(t, report.Failed, 2)
+		assert.Equal(t, report.Errors[0].Line, 3)
+		assert.Equal(t, report.Errors[1].Line, 4)
+	})
+
+	t.Run("this test simulate a csv import with the optional attribute columns", func(t *testing.T) {
+		testObj := setUptest(t)
+		body := strings.Join([]string{
+			"description,purchase_amount,purchase_date,category,merchant,tags,source_currency",
+			"Test1,23.70,2023-10-15,food,Coffee Shop,work; travel,Euro Zone-Euro",
+			strings.Repeat("a", 51) + ",25.00,2023-10-14,,,,",
+		}, "\n")
+		testObj.storesMock.EXPECT().InsertTransactions(ctx, testAccountId, gomock.Len(1)).DoAndReturn(func(ctx context.Context, accountId string, transactions []*model.Transaction) ([]string, error) {
+			assert.Equal(t, transactions[0].Category, "food")
+			assert.Equal(t, transactions[0].Merchant, "Coffee Shop")
+			assert.Equal(t, transactions[0].Tags, []string{"work", "travel"})
+			assert.Equal(t, transactions[0].SourceCurrency, "Euro Zone-Euro")
+			return []string{"652d34910a8fc425116b84d9"}, nil
+		})
+
+		report, err := testObj.appTest.ImportTransactions(ctx, testAccountId, ImportFormatCSV, "", strings.NewReader(body))
+
+		assert.NoError(t, err)
+		assert.Equal(t, report.Inserted, 1)
+		assert.Equal(t, report.Failed, 1)
+		assert.Equal(t, report.Errors[0].Line, 3)
+	})
+
+	t.Run("this test simulate a ndjson import with an insert error", func(t *testing.T) {
+		testObj := setUptest(t)
+		body := strings.Join([]string{
+			`{"purchase_amount": 23.70, "description": "Test1", "purchase_date": "2023-10-15"}`,
+			``,
+			`{"purchase_amount": 25.00, "description": "Test2"`,
+		}, "\n")
+		testObj.storesMock.EXPECT().InsertTransactions(ctx, testAccountId, gomock.Len(1)).Return([]string{}, errors.New("an error has ocurred"))
+
+		report, err := testObj.appTest.ImportTransactions(ctx, testAccountId, ImportFormatNDJSON, "", strings.NewReader(body))
+
+		assert.NoError(t, err)
+		assert.Equal(t, report.Inserted, 0)
+		assert.Equal(t, report.Failed, 2)
+		assert.Equal(t, report.Errors[0].Line, 3)
+		assert.Equal(t, report.Errors[1].Line, 1)
+	})
+
+	t.Run("this test simulate a csv import with a row breaking a business rule", func(t *testing.T) {
+		testObj := setUptest(t)
+		body := strings.Join([]string{
+			"description,purchase_amount,purchase_date",
+			"Test1,23.70,2023-10-15",
+			"Test2,25.00,3023-01-01",
+		}, "\n")
+		testObj.storesMock.EXPECT().InsertTransactions(ctx, testAccountId, gomock.Len(1)).Return([]string{"652d34910a8fc425116b84d9"}, nil)
+
+		report, err := testObj.appTest.ImportTransactions(ctx, testAccountId, ImportFormatCSV, "", strings.NewReader(body))
+
+		assert.NoError(t, err)
+		assert.Equal(t, report.Inserted, 1)
+		assert.Equal(t, report.Failed, 1)
+		assert.Equal(t, report.Errors[0].Line, 3)
+		assert.Equal(t, report.Errors[0].Rule, RuleDateWindow)
+	})
+
+	t.Run("this test simulate an import keyed by its source", func(t *testing.T) {
+		testObj := setUptest(t)
+		body := "description,purchase_amount,purchase_date\nTest1,23.70,2023-10-15\nTest2,25.00,2023-10-14\n"
+		testObj.storesMock.EXPECT().InsertTransactions(ctx, testAccountId, gomock.Len(2)).DoAndReturn(func(ctx context.Context, accountId string, transactions []*model.Transaction) ([]string, error) {
+			assert.Equal(t, transactions[0].SourceId, "job:652d34910a8fc425116b84c1:2")
+			assert.Equal(t, transactions[1].SourceId, "job:652d34910a8fc425116b84c1:3")
+			return []string{"652d34910a8fc425116b84d9", "652d34910a8fc425116b84d8"}, nil
+		})
+
+		report, err := testObj.appTest.ImportTransactions(ctx, testAccountId, ImportFormatCSV, "job:652d34910a8fc425116b84c1", strings.NewReader(body))
+
+		assert.NoError(t, err)
+		assert.Equal(t, report.Inserted, 2)
+	})
+
+	t.Run("this test simulate a csv import without the required columns", func(t *testing.T) {
+		testObj := setUptest(t)
+
+		report, err := testObj.appTest.ImportTransactions(ctx, testAccountId, ImportFormatCSV, "", strings.NewReader("description\nTest1"))
+
+		assert.Nil(t, report)
+		assert.Error(t, err)
+	})
+
+	t.Run("this test simulate an import with an unsupported format", func(t *testing.T) {
+		testObj := setUptest(t)
+
+		report, err := testObj.appTest.ImportTransactions(ctx, testAccountId, "xml", "", strings.NewReader(""))
+
+		assert.Nil(t, report)
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,121 @@
+package transaction
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/jcpribeiro/TransactionApp/model"
+)
+
+// loadTimezone loads an IANA timezone, an empty name is UTC
+func loadTimezone(name string) (*time.Location, error) {
+	if len(name) == 0 {
+		return time.UTC, nil
+	}
+
+	// Local is the server timezone, which callers can not know
+	location, err := time.LoadLocation(name)
+	if err != nil || name == "Local" {
+		return nil, fmt.Errorf("%w: unknown timezone %q", ErrInvalidFilter, name)
+	}
+
+	return location, nil
+}
+
+// parseDate parses a date param as the midnight starting that day in location
+func parseDate(name, date string, location *time.Location) (time.Time, error) {
+	t, err := time.ParseInLocation(model.DateLayout, date, location)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: %s must be formatted as YYYY-MM-DD, got %q", ErrInvalidFilter, name, date)
+	}
+
+	return t, nil
+}
+
+// periodField checks the field a period filters on, empty is the purchase time
+func periodField(field string) (string, error) {
+	switch field {
+	case "", model.PeriodFieldPurchase:
+		return model.PeriodFieldPurchase, nil
+	case model.PeriodFieldCreated:
+		return model.PeriodFieldCreated, nil
+	default:
+		return "", fmt.Errorf("%w: field must be %s or %s, got %q", ErrInvalidFilter, model.PeriodFieldPurchase, model.PeriodFieldCreated, field)
+	}
+}
+
+// periodOf builds the period of field from the start of startDate to the end of endDate in timezone,
+// both days included. Either date may be empty to leave that side open.
+func periodOf(field, startDate, endDate, timezone string) (*model.Period, error) {
+	field, err := periodField(field)
+	if err != nil {
+		return nil, err
+	}
+
+	location, err := loadTimezone(timezone)
+	if err != nil {
+		return nil, err
+	}
+
+	period := &model.Period{
+		Field:    field,
+		Timezone: location.String(),
+	}
+
+	if len(startDate) > 0 {
+		period.Start, err = parseDate("startDate", startDate, location)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if len(endDate) > 0 {
+		end, err := parseDate("endDate", endDate, location)
+		if err != nil {
+			return nil, err
+		}
+		period.End = end.AddDate(0, 0, 1)
+	}
+
+	// a purchase date has no timezone, it is matched as the calendar date
+	if field == model.PeriodFieldPurchase {
+		if len(startDate) > 0 {
+			period.FirstDate = time.Date(period.Start.Year(), period.Start.Month(), period.Start.Day(), 0, 0, 0, 0, time.UTC)
+		}
+		if len(endDate) > 0 {
+			period.LastDate = time.Date(period.End.Year(), period.End.Month(), period.End.Day()-1, 0, 0, 0, 0, time.UTC)
+		}
+	}
+
+	return period, nil
+}
+
+// setPurchaseTime derives the purchase date of a transaction. A purchased_at keeps its offset
+// and gives the purchase date of that offset, a transaction without either is purchased now.
+func setPurchaseTime(transaction *model.Transaction, now time.Time) error {
+	switch {
+	case len(transaction.PurchasedAt) > 0:
+		purchasedAt, err := time.Parse(time.RFC3339, transaction.PurchasedAt)
+		if err != nil {
+			return fmt.Errorf("purchased_at must be a RFC 3339 timestamp with offset, got %q", transaction.PurchasedAt)
+		}
+
+		date := purchasedAt.Format(model.DateLayout)
+		if len(transaction.PurchaseDate) > 0 && transaction.PurchaseDate != date {
+			return fmt.Errorf("purchase_date %s is not the date of purchased_at %s", transaction.PurchaseDate, transaction.PurchasedAt)
+		}
+
+		transaction.PurchasedAt = purchasedAt.Format(time.RFC3339)
+		transaction.PurchaseDate = date
+	case len(transaction.PurchaseDate) > 0:
+		if _, err := time.ParseInLocation(model.DateLayout, transaction.PurchaseDate, time.UTC); err != nil {
+			return fmt.Errorf("purchase_date must be formatted as YYYY-MM-DD, got %q", transaction.PurchaseDate)
+		}
+	default:
+		now = now.UTC()
+		transaction.PurchasedAt = now.Format(time.RFC3339)
+		transaction.PurchaseDate = now.Format(model.DateLayout)
+	}
+
+	return nil
+}
@@ -0,0 +1,136 @@
+package transaction
+
+import (
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/jcpribeiro/TransactionApp/model"
+)
+
+// Names of the business rules, reported by RuleError
+const (
+	RuleDateWindow  = "purchase_date_window"
+	RuleMinAmount   = "min_amount"
+	RuleMaxAmount   = "max_amount"
+	RuleDescription = "description_charset"
+)
+
+// Timezones furthest ahead and behind UTC, a purchase made today anywhere is dated between their dates
+var (
+	aheadZone  = time.FixedZone("UTC+14", 14*60*60)
+	behindZone = time.FixedZone("UTC-12", -12*60*60)
+)
+
+// Rules configures the business rules every inserted transaction must pass after the model validation.
+// A zero MaxPastDays, MinAmount or MaxAmount and a nil DescriptionPattern disable their rule,
+// a negative MaxFutureDays allows any future purchase date.
+type Rules struct {
+	MaxPastDays        int
+	MaxFutureDays      int
+	MinAmount          float64
+	MaxAmount          float64
+	DescriptionPattern *regexp.Regexp
+}
+
+// RuleError is returned when a transaction breaks a business rule, it is an ErrInvalidTransaction
+type RuleError struct {
+	Rule   string
+	Reason string
+}
+
+func (e *RuleError) Error() string {
+	return fmt.Sprintf("rule %s: %s", e.Rule, e.Reason)
+}
+
+// Is makes a RuleError match ErrInvalidTransaction
+func (e *RuleError) Is(target error) bool {
+	return target == ErrInvalidTransaction
+}
+
+// rule checks a transaction whose purchase date is set, returning the reason it is broken or an empty string
+type rule struct {
+	name  string
+	check func(transaction *model.Transaction, now time.Time) string
+}
+
+// newRules builds the enabled rules, in the order they are checked
+func newRules(opts Rules) []rule {
+	var rules []rule
+
+	if opts.MaxPastDays > 0 || opts.MaxFutureDays >= 0 {
+		rules = append(rules, rule{name: RuleDateWindow, check: dateWindow(opts.MaxPastDays, opts.MaxFutureDays)})
+	}
+
+	if opts.MinAmount != 0 {
+		rules = append(rules, rule{name: RuleMinAmount, check: func(transaction *model.Transaction, _ time.Time) string {
+			if transaction.PurchaseAmount < opts.MinAmount {
+				return fmt.Sprintf("purchase_amount %v is below %v", transaction.PurchaseAmount, opts.MinAmount)
+			}
+			return ""
+		}})
+	}
+
+	if opts.MaxAmount != 0 {
+		rules = append(rules, rule{name: RuleMaxAmount, check: func(transaction *model.Transaction, _ time.Time) string {
+			if transaction.PurchaseAmount > opts.MaxAmount {
+				return fmt.Sprintf("purchase_amount %v is above %v", transaction.PurchaseAmount, opts.MaxAmount)
+			}
+			return ""
+		}})
+	}
+
+	if opts.DescriptionPattern != nil {
+		rules = append(rules, rule{name: RuleDescription, check: func(transaction *model.Transaction, _ time.Time) string {
+			if !opts.DescriptionPattern.MatchString(transaction.Description) {
+				return fmt.Sprintf("description %q does not match %s", transaction.Description, opts.DescriptionPattern)
+			}
+			return ""
+		}})
+	}
+
+	return rules
+}
+
+// dateWindow accepts the purchase dates from maxPastDays before today to maxFutureDays after it.
+// Today is taken in the timezones furthest from UTC, as a purchase date has no timezone.
+func dateWindow(maxPastDays, maxFutureDays int) func(*model.Transaction, time.Time) string {
+	return func(transaction *model.Transaction, now time.Time) string {
+		date, err := time.ParseInLocation(model.DateLayout, transaction.PurchaseDate, time.UTC)
+		if err != nil {
+			return fmt.Sprintf("purchase_date must be formatted as YYYY-MM-DD, got %q", transaction.PurchaseDate)
+		}
+
+		if maxFutureDays >= 0 {
+			latest := calendarDate(now.In(aheadZone)).AddDate(0, 0, maxFutureDays)
+			if date.After(latest) {
+				return fmt.Sprintf("purchase_date %s is after %s", transaction.PurchaseDate, latest.Format(model.DateLayout))
+			}
+		}
+
+		if maxPastDays > 0 {
+			earliest := calendarDate(now.In(behindZone)).AddDate(0, 0, -maxPastDays)
+			if date.Before(earliest) {
+				return fmt.Sprintf("purchase_date %s is before %s", transaction.PurchaseDate, earliest.Format(model.DateLayout))
+			}
+		}
+
+		return ""
+	}
+}
+
+// calendarDate is the UTC midnight of the date of t in its location
+func calendarDate(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// checkRules returns a *RuleError for the first rule the transaction breaks
+func checkRules(rules []rule, transaction *model.Transaction, now time.Time) error {
+	for _, r := range rules {
+		if reason := r.check(transaction, now); len(reason) > 0 {
+			return &RuleError{Rule: r.name, Reason: reason}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,87 @@
+package transaction
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/jcpribeiro/TransactionApp/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckRules(t *testing.T) {
+	now := time.Date(2023, 10, 15, 12, 0, 0, 0, time.UTC)
+	rules := newRules(Rules{
+		MaxPastDays:        365,
+		MinAmount:          0.01,
+		MaxAmount:          1000,
+		DescriptionPattern: regexp.MustCompile(`^[^\p{Cc}]*$`),
+	})
+
+	tests := []struct {
+		name        string
+		transaction *model.Transaction
+		rule        string
+	}{
+		{
+			name:        "this test simulate a transaction passing every rule",
+			transaction: &model.Transaction{PurchaseAmount: 23.70, Description: "Café 2", PurchaseDate: "2023-10-15"},
+		},
+		{
+			name:        "this test simulate a purchase dated tomorrow in the timezones ahead of UTC",
+			transaction: &model.Transaction{PurchaseAmount: 23.70, Description: "Test", PurchaseDate: "2023-10-16"},
+		},
+		{
+			name:        "this test simulate a purchase date typo in the future",
+			transaction: &model.Transaction{PurchaseAmount: 23.70, Description: "Test", PurchaseDate: "3023-01-01"},
+			rule:        RuleDateWindow,
+		},
+		{
+			name:        "this test simulate a purchase date older than the window",
+			transaction: &model.Transaction{PurchaseAmount: 23.70, Description: "Test", PurchaseDate: "2022-10-13"},
+			rule:        RuleDateWindow,
+		},
+		{
+			name:        "this test simulate an amount below the minimum",
+			transaction: &model.Transaction{PurchaseAmount: -5, Description: "Test", PurchaseDate: "2023-10-15"},
+			rule:        RuleMinAmount,
+		},
+		{
+			name:        "this test simulate an amount above the maximum",
+			transaction: &model.Transaction{PurchaseAmount: 1000.01, Description: "Test", PurchaseDate: "2023-10-15"},
+			rule:        RuleMaxAmount,
+		},
+		{
+			name:        "this test simulate a description with common punctuation",
+			transaction: &model.Transaction{PurchaseAmount: 23.70, Description: `AMZN*Mktp 50% off foo_bar "gift" @store #1 (a/b) & c-d!?`, PurchaseDate: "2023-10-15"},
+		},
+		{
+			name:        "this test simulate a description with a control character",
+			transaction: &model.Transaction{PurchaseAmount: 23.70, Description: "Test\x00", PurchaseDate: "2023-10-15"},
+			rule:        RuleDescription,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := checkRules(rules, test.transaction, now)
+
+			if len(test.rule) == 0 {
+				assert.NoError(t, err)
+				return
+			}
+
+			var ruleErr *RuleError
+			assert.ErrorAs(t, err, &ruleErr)
+			assert.Equal(t, ruleErr.Rule, test.rule)
+			assert.ErrorIs(t, err, ErrInvalidTransaction)
+		})
+	}
+
+	t.Run("this test simulate the date window disabled", func(t *testing.T) {
+		err := checkRules(newRules(Rules{MaxFutureDays: -1}), &model.Transaction{PurchaseDate: "3023-01-01"}, now)
+
+		assert.NoError(t, err)
+	})
+}
@@ -2,8 +2,13 @@ package transaction
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
+	"strings"
 	"time"
+
+	"github.com/jcpribeiro/TransactionApp/internal/validate"
 	"github.com/jcpribeiro/TransactionApp/model"
 	"github.com/jcpribeiro/TransactionApp/store"
 
@@ -13,104 +18,234 @@ import (
 //go:generate mockgen -source=$GOFILE -destination=transaction_mock.go -package=$GOPACKAGE
 
 type App interface {
-	InsertTransaction(ctx context.Context, transaction *model.Transaction) (string, error)
-	InsertTransactions(ctx context.Context, transaction []*model.Transaction) ([]string, error)
-	GetTransactions(ctx context.Context, transactionIds []string) ([]*model.TransactionResponse, error)
-	GetTransactionsByPeriod(ctx context.Context, startDate, endDate string) ([]*model.TransactionResponse, error)
-	GetTransactionsByPeriodEpoch(ctx context.Context, startDate, endDate int64) ([]*model.TransactionResponse, error)
+	InsertTransaction(ctx context.Context, accountId string, transaction *model.Transaction) (string, error)
+	InsertTransactions(ctx context.Context, accountId string, transaction []*model.Transaction) ([]string, error)
+	GetTransactions(ctx context.Context, accountId string, transactionIds []string) ([]*model.TransactionResponse, error)
+	GetTransactionsByPeriod(ctx context.Context, accountId, startDate, endDate, timezone, field string, attributes *model.AttributeParams) ([]*model.TransactionResponse, error)
+	GetTransactionsByPeriodEpoch(ctx context.Context, accountId string, startDate, endDate int64, field string, attributes *model.AttributeParams) ([]*model.TransactionResponse, error)
+	GetTransactionsSummary(ctx context.Context, accountId, startDate, endDate, timezone, field, groupBy string) ([]*model.TransactionAggregate, error)
+	SearchTransactions(ctx context.Context, accountId string, params *model.SearchTransactionParams) ([]*model.TransactionResponse, error)
+	// ImportTransactions imports the rows of body. A non-empty source makes the import idempotent,
+	// a row already inserted by an import of the same source is skipped when it runs again.
+	ImportTransactions(ctx context.Context, accountId, format, source string, body io.Reader) (*model.ImportReport, error)
+	ExportTransactionsByPeriod(ctx context.Context, accountId, startDate, endDate, timezone, field string, fn func(*model.TransactionResponse) error) error
 }
 
+const (
+	defaultPageSize = 20
+)
+
+var (
+	// ErrInvalidTransaction is returned when a transaction fails the model validation or a business rule
+	ErrInvalidTransaction = errors.New("invalid transaction")
+	// ErrInvalidFilter is returned when an attribute filter, a date or a timezone can not be parsed
+	ErrInvalidFilter = errors.New("invalid filter")
+)
+
 type appImpl struct {
-	stores *store.Container
-	log    logrus.Logger
+	stores    *store.Container
+	validator validate.Validator
+	rules     []rule
+	log       logrus.Logger
 }
 
-func NewAppTransaction(stores *store.Container, log logrus.Logger) App {
+func NewAppTransaction(stores *store.Container, rules Rules, log logrus.Logger) App {
 	return &appImpl{
-		stores: stores,
-		log:    log,
+		stores:    stores,
+		validator: validate.New(),
+		rules:     newRules(rules),
+		log:       log,
+	}
+}
+
+// check validates a transaction, sets its purchase time and runs the business rules.
+// A broken rule is returned as *RuleError.
+func (a appImpl) check(transaction *model.Transaction, now time.Time) error {
+	if err := a.validator.Validate(transaction); err != nil {
+		return err
 	}
+	if err := setPurchaseTime(transaction, now); err != nil {
+		return err
+	}
+
+	return checkRules(a.rules, transaction, now)
 }
 
-func (a appImpl) InsertTransaction(ctx context.Context, transaction *model.Transaction) (string, error) {
-	return a.stores.Transaction.InsertTransaction(ctx, transaction)
+// invalidTransaction wraps an error of check as ErrInvalidTransaction after position. A *RuleError
+// is kept in the chain instead, it is also an ErrInvalidTransaction and tells which rule failed.
+func invalidTransaction(position string, err error) error {
+	var ruleErr *RuleError
+	if errors.As(err, &ruleErr) {
+		return fmt.Errorf("%s%s: %w", ErrInvalidTransaction, position, err)
+	}
+
+	return fmt.Errorf("%w%s: %s", ErrInvalidTransaction, position, err)
 }
 
-func convertDateToString(t time.Time) string {
-	switch t.Month() {
-	case time.January:
-		return fmt.Sprintf("%d-01-%d", t.Year(), t.Day())
-	case time.February:
-		return fmt.Sprintf("%d-02-%d", t.Year(), t.Day())
-	case time.March:
-		return fmt.Sprintf("%d-03-%d", t.Year(), t.Day())
-	case time.April:
-		return fmt.Sprintf("%d-04-%d", t.Year(), t.Day())
-	case time.May:
-		return fmt.Sprintf("%d-05-%d", t.Year(), t.Day())
-	case time.June:
-		return fmt.Sprintf("%d-06-%d", t.Year(), t.Day())
-	case time.July:
-		return fmt.Sprintf("%d-07-%d", t.Year(), t.Day())
-	case time.August:
-		return fmt.Sprintf("%d-08-%d", t.Year(), t.Day())
-	case time.September:
-		return fmt.Sprintf("%d-09-%d", t.Year(), t.Day())
-	case time.October:
-		return fmt.Sprintf("%d-10-%d", t.Year(), t.Day())
-	case time.November:
-		return fmt.Sprintf("%d-11-%d", t.Year(), t.Day())
-	default:
-		return fmt.Sprintf("%d-12-%d", t.Year(), t.Day())
+func (a appImpl) InsertTransaction(ctx context.Context, accountId string, transaction *model.Transaction) (string, error) {
+	if err := a.check(transaction, time.Now()); err != nil {
+		return "", invalidTransaction("", err)
 	}
+	if len(transaction.SourceCurrency) == 0 {
+		transaction.SourceCurrency = model.CurrencyUSD
+	}
+
+	return a.stores.Transaction.InsertTransaction(ctx, accountId, transaction)
 }
 
-func (a appImpl) InsertTransactions(ctx context.Context, transaction []*model.Transaction) ([]string, error) {
+func (a appImpl) InsertTransactions(ctx context.Context, accountId string, transaction []*model.Transaction) ([]string, error) {
+	now := time.Now()
+	for i, t := range transaction {
+		if err := a.check(t, now); err != nil {
+			return nil, invalidTransaction(fmt.Sprintf(" %d", i), err)
+		}
+	}
+
 	for _, t := range transaction {
-		if len(t.PurchaseDate) == 0 {
-			currentTime := time.Now()
-			t.CreatedAt = currentTime.Unix()
-			t.PurchaseDate = convertDateToString(currentTime)
-		} else {
-			date, _ := formatDate(t.PurchaseDate)
-			t.CreatedAt = date
+		if len(t.SourceCurrency) == 0 {
+			t.SourceCurrency = model.CurrencyUSD
 		}
 	}
-	return a.stores.Transaction.InsertTransactions(ctx, transaction)
+	return a.stores.Transaction.InsertTransactions(ctx, accountId, transaction)
 }
 
-func (a appImpl) GetTransactions(ctx context.Context, transactionIds []string) ([]*model.TransactionResponse, error) {
+func (a appImpl) GetTransactions(ctx context.Context, accountId string, transactionIds []string) ([]*model.TransactionResponse, error) {
 	if len(transactionIds) == 0 {
 		return nil, fmt.Errorf("failed to get transactions: empty id list")
 	}
 
-	return a.stores.Transaction.GetTransactionByIds(ctx, transactionIds)
+	return a.stores.Transaction.GetTransactionByIds(ctx, accountId, transactionIds)
+}
+
+// attributeFilter parses the attribute params, a nil params is an empty filter
+func attributeFilter(params *model.AttributeParams) (*model.AttributeFilter, error) {
+	filter := &model.AttributeFilter{}
+	if params == nil {
+		return filter, nil
+	}
+
+	filter.Category = strings.TrimSpace(params.Category)
+	filter.Merchant = strings.TrimSpace(params.Merchant)
+	for _, tag := range strings.Split(params.Tags, ",") {
+		if tag = strings.TrimSpace(tag); len(tag) > 0 {
+			filter.Tags = append(filter.Tags, tag)
+		}
+	}
+
+	for _, pair := range strings.Split(params.Metadata, ",") {
+		if len(strings.TrimSpace(pair)) == 0 {
+			continue
+		}
+
+		key, value, ok := strings.Cut(pair, ":")
+		key = strings.TrimSpace(key)
+		if !ok || len(key) == 0 || strings.ContainsAny(key, ".$") {
+			return nil, fmt.Errorf("%w: metadata must be key:value pairs, got %q", ErrInvalidFilter, pair)
+		}
+
+		if filter.Metadata == nil {
+			filter.Metadata = map[string]string{}
+		}
+		filter.Metadata[key] = strings.TrimSpace(value)
+	}
+
+	return filter, nil
 }
 
-func formatDate(date string) (int64, error) {
-	d := fmt.Sprintf("%sT00:00:00", date)
-	ts, err := time.ParseInLocation("2006-01-02T15:04:05", d, time.UTC)
+// GetTransactionsByPeriod returns the transactions purchased, or stored when field is created,
+// from the start of startDate to the end of endDate in timezone, both days included
+func (a appImpl) GetTransactionsByPeriod(ctx context.Context, accountId, startDate, endDate, timezone, field string, attributes *model.AttributeParams) ([]*model.TransactionResponse, error) {
+	filter, err := attributeFilter(attributes)
 	if err != nil {
-		return 0, err
+		return nil, err
 	}
 
-	return ts.Unix(), nil
+	period, err := periodOf(field, startDate, endDate, timezone)
+	if err != nil {
+		return nil, err
+	}
+
+	return a.stores.Transaction.GetTransactionByDate(ctx, accountId, period, filter)
+}
+
+// GetTransactionsByPeriodEpoch returns the transactions purchased, or stored when field is created,
+// from startDate to endDate, both seconds included
+func (a appImpl) GetTransactionsByPeriodEpoch(ctx context.Context, accountId string, startDate, endDate int64, field string, attributes *model.AttributeParams) ([]*model.TransactionResponse, error) {
+	filter, err := attributeFilter(attributes)
+	if err != nil {
+		return nil, err
+	}
+
+	field, err = periodField(field)
+	if err != nil {
+		return nil, err
+	}
+
+	return a.stores.Transaction.GetTransactionByDate(ctx, accountId, &model.Period{
+		Field: field,
+		Start: time.Unix(startDate, 0).UTC(),
+		End:   time.Unix(endDate+1, 0).UTC(),
+	}, filter)
+}
+
+func (a appImpl) GetTransactionsSummary(ctx context.Context, accountId, startDate, endDate, timezone, field, groupBy string) ([]*model.TransactionAggregate, error) {
+	period, err := periodOf(field, startDate, endDate, timezone)
+	if err != nil {
+		return nil, err
+	}
+
+	return a.stores.Transaction.GetTransactionSummary(ctx, accountId, period, groupBy)
 }
 
-func (a appImpl) GetTransactionsByPeriod(ctx context.Context, startDate, endDate string) ([]*model.TransactionResponse, error) {
-	sDate, err := formatDate(startDate)
+// SearchTransactions applies the default pagination to params and returns the requested page
+func (a appImpl) SearchTransactions(ctx context.Context, accountId string, params *model.SearchTransactionParams) ([]*model.TransactionResponse, error) {
+	if params.Page == 0 {
+		params.Page = 1
+	}
+	if params.PageSize == 0 {
+		params.PageSize = defaultPageSize
+	}
+
+	attributes, err := attributeFilter(&params.AttributeParams)
 	if err != nil {
-		return nil, fmt.Errorf("failed to format startDate: %w", err)
+		return nil, err
 	}
 
-	eDate, err := formatDate(endDate)
+	period, err := periodOf(params.Field, params.StartDate, params.EndDate, params.Timezone)
 	if err != nil {
-		return nil, fmt.Errorf("failed to format endDate: %w", err)
+		return nil, err
 	}
 
-	return a.stores.Transaction.GetTransactionByDate(ctx, sDate, eDate)
+	filter := &model.TransactionFilter{
+		Text:            params.Text,
+		MinAmount:       params.MinAmount,
+		MaxAmount:       params.MaxAmount,
+		Period:          *period,
+		Skip:            (params.Page - 1) * params.PageSize,
+		Limit:           params.PageSize,
+		AttributeFilter: *attributes,
+	}
+
+	if len(params.PurchaseStartDate) > 0 {
+		if filter.PurchaseStartDate, err = parseDate("purchaseStartDate", params.PurchaseStartDate, time.UTC); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(params.PurchaseEndDate) > 0 {
+		if filter.PurchaseEndDate, err = parseDate("purchaseEndDate", params.PurchaseEndDate, time.UTC); err != nil {
+			return nil, err
+		}
+	}
+
+	return a.stores.Transaction.SearchTransactions(ctx, accountId, filter)
 }
 
-func (a appImpl) GetTransactionsByPeriodEpoch(ctx context.Context, startDate, endDate int64) ([]*model.TransactionResponse, error) {
-	return a.stores.Transaction.GetTransactionByDate(ctx, startDate, endDate)
+func (a appImpl) ExportTransactionsByPeriod(ctx context.Context, accountId, startDate, endDate, timezone, field string, fn func(*model.TransactionResponse) error) error {
+	period, err := periodOf(field, startDate, endDate, timezone)
+	if err != nil {
+		return err
+	}
+
+	return a.stores.Transaction.StreamTransactionByDate(ctx, accountId, period, fn)
 }
@@ -6,6 +6,7 @@ package transaction
 
 import (
 	context "context"
+	io "io"
 	reflect "reflect"
 	model "github.com/jcpribeiro/TransactionApp/model"
 
@@ -35,77 +36,136 @@ func (m *MockApp) EXPECT() *MockAppMockRecorder {
 	return m.recorder
 }
 
+// ExportTransactionsByPeriod mocks base method.
+func (m *MockApp) ExportTransactionsByPeriod(ctx context.Context, accountId, startDate, endDate, timezone, field string, fn func(*model.TransactionResponse) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExportTransactionsByPeriod", ctx, accountId, startDate, endDate, timezone, field, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ExportTransactionsByPeriod indicates an expected call of ExportTransactionsByPeriod.
+func (mr *MockAppMockRecorder) ExportTransactionsByPeriod(ctx, accountId, startDate, endDate, timezone, field, fn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportTransactionsByPeriod", reflect.TypeOf((*MockApp)(nil).ExportTransactionsByPeriod), ctx, accountId, startDate, endDate, timezone, field, fn)
+}
+
 // GetTransactions mocks base method.
-func (m *MockApp) GetTransactions(ctx context.Context, transactionIds []string) ([]*model.TransactionResponse, error) {
+func (m *MockApp) GetTransactions(ctx context.Context, accountId string, transactionIds []string) ([]*model.TransactionResponse, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetTransactions", ctx, transactionIds)
+	ret := m.ctrl.Call(m, "GetTransactions", ctx, accountId, transactionIds)
 	ret0, _ := ret[0].([]*model.TransactionResponse)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetTransactions indicates an expected call of GetTransactions.
-func (mr *MockAppMockRecorder) GetTransactions(ctx, transactionIds interface{}) *gomock.Call {
+func (mr *MockAppMockRecorder) GetTransactions(ctx, accountId, transactionIds interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransactions", reflect.TypeOf((*MockApp)(nil).GetTransactions), ctx, transactionIds)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransactions", reflect.TypeOf((*MockApp)(nil).GetTransactions), ctx, accountId, transactionIds)
 }
 
 // GetTransactionsByPeriod mocks base method.
-func (m *MockApp) GetTransactionsByPeriod(ctx context.Context, startDate, endDate string) ([]*model.TransactionResponse, error) {
+func (m *MockApp) GetTransactionsByPeriod(ctx context.Context, accountId, startDate, endDate, timezone, field string, attributes *model.AttributeParams) ([]*model.TransactionResponse, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetTransactionsByPeriod", ctx, startDate, endDate)
+	ret := m.ctrl.Call(m, "GetTransactionsByPeriod", ctx, accountId, startDate, endDate, timezone, field, attributes)
 	ret0, _ := ret[0].([]*model.TransactionResponse)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetTransactionsByPeriod indicates an expected call of GetTransactionsByPeriod.
-func (mr *MockAppMockRecorder) GetTransactionsByPeriod(ctx, startDate, endDate interface{}) *gomock.Call {
+func (mr *MockAppMockRecorder) GetTransactionsByPeriod(ctx, accountId, startDate, endDate, timezone, field, attributes interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransactionsByPeriod", reflect.TypeOf((*MockApp)(nil).GetTransactionsByPeriod), ctx, startDate, endDate)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransactionsByPeriod", reflect.TypeOf((*MockApp)(nil).GetTransactionsByPeriod), ctx, accountId, startDate, endDate, timezone, field, attributes)
 }
 
 // GetTransactionsByPeriodEpoch mocks base method.
-func (m *MockApp) GetTransactionsByPeriodEpoch(ctx context.Context, startDate, endDate int64) ([]*model.TransactionResponse, error) {
+func (m *MockApp) GetTransactionsByPeriodEpoch(ctx context.Context, accountId string, startDate, endDate int64, field string, attributes *model.AttributeParams) ([]*model.TransactionResponse, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetTransactionsByPeriodEpoch", ctx, startDate, endDate)
+	ret := m.ctrl.Call(m, "GetTransactionsByPeriodEpoch", ctx, accountId, startDate, endDate, field, attributes)
 	ret0, _ := ret[0].([]*model.TransactionResponse)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetTransactionsByPeriodEpoch indicates an expected call of GetTransactionsByPeriodEpoch.
-func (mr *MockAppMockRecorder) GetTransactionsByPeriodEpoch(ctx, startDate, endDate interface{}) *gomock.Call {
+func (mr *MockAppMockRecorder) GetTransactionsByPeriodEpoch(ctx, accountId, startDate, endDate, field, attributes interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransactionsByPeriodEpoch", reflect.TypeOf((*MockApp)(nil).GetTransactionsByPeriodEpoch), ctx, accountId, startDate, endDate, field, attributes)
+}
+
+// GetTransactionsSummary mocks base method.
+func (m *MockApp) GetTransactionsSummary(ctx context.Context, accountId, startDate, endDate, timezone, field, groupBy string) ([]*model.TransactionAggregate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTransactionsSummary", ctx, accountId, startDate, endDate, timezone, field, groupBy)
+	ret0, _ := ret[0].([]*model.TransactionAggregate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTransactionsSummary indicates an expected call of GetTransactionsSummary.
+func (mr *MockAppMockRecorder) GetTransactionsSummary(ctx, accountId, startDate, endDate, timezone, field, groupBy interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransactionsByPeriodEpoch", reflect.TypeOf((*MockApp)(nil).GetTransactionsByPeriodEpoch), ctx, startDate, endDate)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransactionsSummary", reflect.TypeOf((*MockApp)(nil).GetTransactionsSummary), ctx, accountId, startDate, endDate, timezone, field, groupBy)
+}
+
+// ImportTransactions mocks base method.
+func (m *MockApp) ImportTransactions(ctx context.Context, accountId, format, source string, body io.Reader) (*model.ImportReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ImportTransactions", ctx, accountId, format, source, body)
+	ret0, _ := ret[0].(*model.ImportReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ImportTransactions indicates an expected call of ImportTransactions.
+func (mr *MockAppMockRecorder) ImportTransactions(ctx, accountId, format, source, body interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImportTransactions", reflect.TypeOf((*MockApp)(nil).ImportTransactions), ctx, accountId, format, source, body)
 }
 
 // InsertTransaction mocks base method.
-func (m *MockApp) InsertTransaction(ctx context.Context, transaction *model.Transaction) (string, error) {
+func (m *MockApp) InsertTransaction(ctx context.Context, accountId string, transaction *model.Transaction) (string, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "InsertTransaction", ctx, transaction)
+	ret := m.ctrl.Call(m, "InsertTransaction", ctx, accountId, transaction)
 	ret0, _ := ret[0].(string)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // InsertTransaction indicates an expected call of InsertTransaction.
-func (mr *MockAppMockRecorder) InsertTransaction(ctx, transaction interface{}) *gomock.Call {
+func (mr *MockAppMockRecorder) InsertTransaction(ctx, accountId, transaction interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertTransaction", reflect.TypeOf((*MockApp)(nil).InsertTransaction), ctx, transaction)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertTransaction", reflect.TypeOf((*MockApp)(nil).InsertTransaction), ctx, accountId, transaction)
 }
 
 // InsertTransactions mocks base method.
-func (m *MockApp) InsertTransactions(ctx context.Context, transaction []*model.Transaction) ([]string, error) {
+func (m *MockApp) InsertTransactions(ctx context.Context, accountId string, transaction []*model.Transaction) ([]string, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "InsertTransactions", ctx, transaction)
+	ret := m.ctrl.Call(m, "InsertTransactions", ctx, accountId, transaction)
 	ret0, _ := ret[0].([]string)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // InsertTransactions indicates an expected call of InsertTransactions.
-func (mr *MockAppMockRecorder) InsertTransactions(ctx, transaction interface{}) *gomock.Call {
+func (mr *MockAppMockRecorder) InsertTransactions(ctx, accountId, transaction interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertTransactions", reflect.TypeOf((*MockApp)(nil).InsertTransactions), ctx, accountId, transaction)
+}
+
+// SearchTransactions mocks base method.
+func (m *MockApp) SearchTransactions(ctx context.Context, accountId string, params *model.SearchTransactionParams) ([]*model.TransactionResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchTransactions", ctx, accountId, params)
+	ret0, _ := ret[0].([]*model.TransactionResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchTransactions indicates an expected call of SearchTransactions.
+func (mr *MockAppMockRecorder) SearchTransactions(ctx, accountId, params interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertTransactions", reflect.TypeOf((*MockApp)(nil).InsertTransactions), ctx, transaction)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchTransactions", reflect.TypeOf((*MockApp)(nil).SearchTransactions), ctx, accountId, params)
 }
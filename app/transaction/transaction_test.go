@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 	"github.com/jcpribeiro/TransactionApp/model"
 	"github.com/jcpribeiro/TransactionApp/store"
 	"github.com/jcpribeiro/TransactionApp/store/transaction"
@@ -13,6 +14,8 @@ import (
 	"github.com/stretchr/testify/assert"
 )
 
+const testAccountId = "652d34910a8fc425116b84aa"
+
 type structTest struct {
 	storesMock *transaction.MockStore
 	appTest    App
@@ -26,7 +29,7 @@ func setUptest(t *testing.T) structTest {
 		storesMock: storesMock,
 		appTest: NewAppTransaction(&store.Container{
 			Transaction: storesMock,
-		}, *logrus.New()),
+		}, Rules{}, *logrus.New()),
 	}
 }
 
@@ -36,13 +39,14 @@ func TestInsertTransaction(t *testing.T) {
 	t.Run("this test simulate a successful transaction insert", func(t *testing.T) {
 		testObj := setUptest(t)
 		expectedId := "652d34910a8fc425116b84d9"
-		testObj.storesMock.EXPECT().InsertTransaction(ctx, &model.Transaction{
+		testObj.storesMock.EXPECT().InsertTransaction(ctx, testAccountId, &model.Transaction{
 			PurchaseAmount: 23.70,
+			SourceCurrency: model.CurrencyUSD,
 			Description:    "Test",
 			PurchaseDate:   "2023-10-15",
 		}).Return(expectedId, nil)
 
-		id, err := testObj.appTest.InsertTransaction(ctx, &model.Transaction{
+		id, err := testObj.appTest.InsertTransaction(ctx, testAccountId, &model.Transaction{
 			PurchaseAmount: 23.70,
 			Description:    "Test",
 			PurchaseDate:   "2023-10-15",
@@ -54,13 +58,14 @@ func TestInsertTransaction(t *testing.T) {
 
 	t.Run("this test simulate an error during a transaction insert", func(t *testing.T) {
 		testObj := setUptest(t)
-		testObj.storesMock.EXPECT().InsertTransaction(ctx, &model.Transaction{
+		testObj.storesMock.EXPECT().InsertTransaction(ctx, testAccountId, &model.Transaction{
 			PurchaseAmount: 23.70,
+			SourceCurrency: model.CurrencyUSD,
 			Description:    "Test",
 			PurchaseDate:   "2023-10-15",
 		}).Return("", errors.New("an error has ocurred"))
 
-		id, err := testObj.appTest.InsertTransaction(ctx, &model.Transaction{
+		id, err := testObj.appTest.InsertTransaction(ctx, testAccountId, &model.Transaction{
 			PurchaseAmount: 23.70,
 			Description:    "Test",
 			PurchaseDate:   "2023-10-15",
@@ -69,6 +74,53 @@ func TestInsertTransaction(t *testing.T) {
 		assert.Equal(t, id, "")
 		assert.Error(t, err)
 	})
+
+	t.Run("this test simulate a transaction insert with a purchase time", func(t *testing.T) {
+		testObj := setUptest(t)
+		testObj.storesMock.EXPECT().InsertTransaction(ctx, testAccountId, &model.Transaction{
+			PurchaseAmount: 23.70,
+			SourceCurrency: model.CurrencyUSD,
+			Description:    "Test",
+			PurchaseDate:   "2023-10-14",
+			PurchasedAt:    "2023-10-14T23:30:00-03:00",
+		}).Return("652d34910a8fc425116b84d9", nil)
+
+		id, err := testObj.appTest.InsertTransaction(ctx, testAccountId, &model.Transaction{
+			PurchaseAmount: 23.70,
+			Description:    "Test",
+			PurchasedAt:    "2023-10-14T23:30:00-03:00",
+		})
+
+		assert.Equal(t, id, "652d34910a8fc425116b84d9")
+		assert.NoError(t, err)
+	})
+
+	t.Run("this test simulate a transaction insert with a purchase date of another day than its purchase time", func(t *testing.T) {
+		testObj := setUptest(t)
+
+		id, err := testObj.appTest.InsertTransaction(ctx, testAccountId, &model.Transaction{
+			PurchaseAmount: 23.70,
+			Description:    "Test",
+			PurchaseDate:   "2023-10-15",
+			PurchasedAt:    "2023-10-14T23:30:00-03:00",
+		})
+
+		assert.Equal(t, id, "")
+		assert.ErrorIs(t, err, ErrInvalidTransaction)
+	})
+
+	t.Run("this test simulate a transaction insert with a description over the limit", func(t *testing.T) {
+		testObj := setUptest(t)
+
+		id, err := testObj.appTest.InsertTransaction(ctx, testAccountId, &model.Transaction{
+			PurchaseAmount: 23.70,
+			Description:    "Test - 12345678910111213141516171819202122324252627282930313233",
+			PurchaseDate:   "2023-10-15",
+		})
+
+		assert.Equal(t, id, "")
+		assert.ErrorIs(t, err, ErrInvalidTransaction)
+	})
 }
 
 func TestInsertTransactions(t *testing.T) {
@@ -89,9 +141,9 @@ func TestInsertTransactions(t *testing.T) {
 				PurchaseDate:   "2023-10-14",
 			},
 		}
-		testObj.storesMock.EXPECT().InsertTransactions(ctx, payload).Return(expectedIds, nil)
+		testObj.storesMock.EXPECT().InsertTransactions(ctx, testAccountId, payload).Return(expectedIds, nil)
 
-		ids, err := testObj.appTest.InsertTransactions(ctx, payload)
+		ids, err := testObj.appTest.InsertTransactions(ctx, testAccountId, payload)
 
 		assert.Equal(t, ids, expectedIds)
 		assert.NoError(t, err)
@@ -110,9 +162,9 @@ func TestInsertTransactions(t *testing.T) {
 				Description:    "Test2",
 			},
 		}
-		testObj.storesMock.EXPECT().InsertTransactions(ctx, payload).Return(expectedIds, nil)
+		testObj.storesMock.EXPECT().InsertTransactions(ctx, testAccountId, payload).Return(expectedIds, nil)
 
-		ids, err := testObj.appTest.InsertTransactions(ctx, payload)
+		ids, err := testObj.appTest.InsertTransactions(ctx, testAccountId, payload)
 
 		assert.Equal(t, ids, expectedIds)
 		assert.NoError(t, err)
@@ -132,13 +184,82 @@ func TestInsertTransactions(t *testing.T) {
 				PurchaseDate:   "2023-10-14",
 			},
 		}
-		testObj.storesMock.EXPECT().InsertTransactions(ctx, payload).Return([]string{}, errors.New("an error has ocurred"))
+		testObj.storesMock.EXPECT().InsertTransactions(ctx, testAccountId, payload).Return([]string{}, errors.New("an error has ocurred"))
 
-		id, err := testObj.appTest.InsertTransactions(ctx, payload)
+		id, err := testObj.appTest.InsertTransactions(ctx, testAccountId, payload)
 
 		assert.Equal(t, id, []string{})
 		assert.Error(t, err)
 	})
+
+	t.Run("this test simulate a transactions insert with invalid attributes", func(t *testing.T) {
+		testObj := setUptest(t)
+		payload := []*model.Transaction{
+			0: {
+				PurchaseAmount: 23.70,
+				Description:    "Test1",
+				Category:       "food",
+				Tags:           []string{"work"},
+				Metadata:       map[string]string{"project": "alpha"},
+			},
+			1: {
+				PurchaseAmount: 25.00,
+				Description:    "Test2",
+				Metadata:       map[string]string{"project.name": "alpha"},
+			},
+		}
+
+		ids, err := testObj.appTest.InsertTransactions(ctx, testAccountId, payload)
+
+		assert.Nil(t, ids)
+		assert.ErrorIs(t, err, ErrInvalidTransaction)
+	})
+
+	t.Run("this test simulate a transactions insert breaking a business rule", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		appTest := NewAppTransaction(&store.Container{
+			Transaction: transaction.NewMockStore(ctrl),
+		}, Rules{MaxAmount: 1000}, *logrus.New())
+		payload := []*model.Transaction{
+			0: {
+				PurchaseAmount: 23.70,
+				Description:    "Test1",
+				PurchaseDate:   "2023-10-15",
+			},
+			1: {
+				PurchaseAmount: 2370,
+				Description:    "Test2",
+				PurchaseDate:   "2023-10-14",
+			},
+		}
+
+		ids, err := appTest.InsertTransactions(ctx, testAccountId, payload)
+
+		var ruleErr *RuleError
+		assert.Nil(t, ids)
+		assert.ErrorIs(t, err, ErrInvalidTransaction)
+		assert.ErrorAs(t, err, &ruleErr)
+		assert.Equal(t, ruleErr.Rule, RuleMaxAmount)
+		assert.Equal(t, err.Error(), "invalid transaction 1: rule max_amount: purchase_amount 2370 is above 1000")
+	})
+
+	t.Run("this test simulate a transactions insert with a future purchase date", func(t *testing.T) {
+		testObj := setUptest(t)
+		payload := []*model.Transaction{
+			0: {
+				PurchaseAmount: 23.70,
+				Description:    "Test1",
+				PurchaseDate:   "3023-01-01",
+			},
+		}
+
+		ids, err := testObj.appTest.InsertTransactions(ctx, testAccountId, payload)
+
+		var ruleErr *RuleError
+		assert.Nil(t, ids)
+		assert.ErrorAs(t, err, &ruleErr)
+		assert.Equal(t, ruleErr.Rule, RuleDateWindow)
+	})
 }
 
 func TestGetTransactions(t *testing.T) {
@@ -159,9 +280,9 @@ func TestGetTransactions(t *testing.T) {
 				PurchaseDate:   "2023-10-14",
 			},
 		}
-		testObj.storesMock.EXPECT().GetTransactionByIds(ctx, idList).Return(expectedResponse, nil)
+		testObj.storesMock.EXPECT().GetTransactionByIds(ctx, testAccountId, idList).Return(expectedResponse, nil)
 
-		resp, err := testObj.appTest.GetTransactions(ctx, idList)
+		resp, err := testObj.appTest.GetTransactions(ctx, testAccountId, idList)
 
 		assert.Equal(t, resp, expectedResponse)
 		assert.NoError(t, err)
@@ -170,9 +291,9 @@ func TestGetTransactions(t *testing.T) {
 	t.Run("This test simulates an error when obtaining transaction information", func(t *testing.T) {
 		testObj := setUptest(t)
 		idList := []string{"652d34910a8fc425116b84d9", "652d34910a8fc425116b84d8"}
-		testObj.storesMock.EXPECT().GetTransactionByIds(ctx, idList).Return(nil, errors.New("an error has ocurred"))
+		testObj.storesMock.EXPECT().GetTransactionByIds(ctx, testAccountId, idList).Return(nil, errors.New("an error has ocurred"))
 
-		resp, err := testObj.appTest.GetTransactions(ctx, idList)
+		resp, err := testObj.appTest.GetTransactions(ctx, testAccountId, idList)
 
 		assert.Nil(t, resp)
 		assert.Error(t, err)
@@ -182,7 +303,7 @@ func TestGetTransactions(t *testing.T) {
 		testObj := setUptest(t)
 		idList := []string{}
 
-		resp, err := testObj.appTest.GetTransactions(ctx, idList)
+		resp, err := testObj.appTest.GetTransactions(ctx, testAccountId, idList)
 
 		assert.Nil(t, resp)
 		assert.Error(t, err)
@@ -196,8 +317,6 @@ func TestGetTransactionsByPeriod(t *testing.T) {
 		testObj := setUptest(t)
 		startDate := "2023-10-12"
 		endDate := "2023-10-15"
-		sDate, _ := formatDate(startDate)
-		eDate, _ := formatDate(endDate)
 		expectedResponse := []*model.TransactionResponse{
 			0: {
 				PurchaseAmount: 23.70,
@@ -210,9 +329,16 @@ func TestGetTransactionsByPeriod(t *testing.T) {
 				PurchaseDate:   "2023-10-14",
 			},
 		}
-		testObj.storesMock.EXPECT().GetTransactionByDate(ctx, sDate, eDate).Return(expectedResponse, nil)
+		testObj.storesMock.EXPECT().GetTransactionByDate(ctx, testAccountId, &model.Period{
+			Field:     model.PeriodFieldPurchase,
+			Start:     time.Date(2023, 10, 12, 0, 0, 0, 0, time.UTC),
+			End:       time.Date(2023, 10, 16, 0, 0, 0, 0, time.UTC),
+			FirstDate: time.Date(2023, 10, 12, 0, 0, 0, 0, time.UTC),
+			LastDate:  time.Date(2023, 10, 15, 0, 0, 0, 0, time.UTC),
+			Timezone:  "UTC",
+		}, &model.AttributeFilter{}).Return(expectedResponse, nil)
 
-		resp, err := testObj.appTest.GetTransactionsByPeriod(ctx, startDate, endDate)
+		resp, err := testObj.appTest.GetTransactionsByPeriod(ctx, testAccountId, startDate, endDate, "", "", nil)
 
 		assert.Equal(t, resp, expectedResponse)
 		assert.NoError(t, err)
@@ -222,13 +348,93 @@ func TestGetTransactionsByPeriod(t *testing.T) {
 		testObj := setUptest(t)
 		startDate := "2023-10-12"
 		endDate := "2023-10-15"
-		testObj.storesMock.EXPECT().GetTransactionByDate(ctx, gomock.Any(), gomock.Any()).Return(nil, errors.New("an error has ocurred"))
+		testObj.storesMock.EXPECT().GetTransactionByDate(ctx, testAccountId, gomock.Any(), gomock.Any()).Return(nil, errors.New("an error has ocurred"))
 
-		resp, err := testObj.appTest.GetTransactionsByPeriod(ctx, startDate, endDate)
+		resp, err := testObj.appTest.GetTransactionsByPeriod(ctx, testAccountId, startDate, endDate, "", "", nil)
 
 		assert.Nil(t, resp)
 		assert.Error(t, err)
 	})
+
+	t.Run("This test simulates obtaining transaction information by date filtered by attributes", func(t *testing.T) {
+		testObj := setUptest(t)
+		testObj.storesMock.EXPECT().GetTransactionByDate(ctx, testAccountId, gomock.Any(), &model.AttributeFilter{
+			Category: "food",
+			Tags:     []string{"work", "travel"},
+			Metadata: map[string]string{"project": "alpha", "cost_center": "12"},
+		}).Return([]*model.TransactionResponse{}, nil)
+
+		resp, err := testObj.appTest.GetTransactionsByPeriod(ctx, testAccountId, "2023-10-12", "2023-10-15", "", "", &model.AttributeParams{
+			Category: "food",
+			Tags:     "work, travel,",
+			Metadata: "project:alpha,cost_center:12",
+		})
+
+		assert.Empty(t, resp)
+		assert.NoError(t, err)
+	})
+
+	t.Run("This test simulates an invalid metadata filter when obtaining transaction information by date", func(t *testing.T) {
+		testObj := setUptest(t)
+
+		resp, err := testObj.appTest.GetTransactionsByPeriod(ctx, testAccountId, "2023-10-12", "2023-10-15", "", "", &model.AttributeParams{
+			Metadata: "project",
+		})
+
+		assert.Nil(t, resp)
+		assert.ErrorIs(t, err, ErrInvalidFilter)
+	})
+
+	t.Run("This test simulates obtaining transaction information by date in a timezone", func(t *testing.T) {
+		testObj := setUptest(t)
+		location, _ := time.LoadLocation("America/Sao_Paulo")
+		testObj.storesMock.EXPECT().GetTransactionByDate(ctx, testAccountId, &model.Period{
+			Field:     model.PeriodFieldPurchase,
+			Start:     time.Date(2023, 10, 12, 0, 0, 0, 0, location),
+			End:       time.Date(2023, 10, 16, 0, 0, 0, 0, location),
+			FirstDate: time.Date(2023, 10, 12, 0, 0, 0, 0, time.UTC),
+			LastDate:  time.Date(2023, 10, 15, 0, 0, 0, 0, time.UTC),
+			Timezone:  "America/Sao_Paulo",
+		}, &model.AttributeFilter{}).Return([]*model.TransactionResponse{}, nil)
+
+		resp, err := testObj.appTest.GetTransactionsByPeriod(ctx, testAccountId, "2023-10-12", "2023-10-15", "America/Sao_Paulo", "", nil)
+
+		assert.Empty(t, resp)
+		assert.NoError(t, err)
+	})
+
+	t.Run("This test simulates an unknown timezone when obtaining transaction information by date", func(t *testing.T) {
+		testObj := setUptest(t)
+
+		resp, err := testObj.appTest.GetTransactionsByPeriod(ctx, testAccountId, "2023-10-12", "2023-10-15", "Mars/Olympus", "", nil)
+
+		assert.Nil(t, resp)
+		assert.ErrorIs(t, err, ErrInvalidFilter)
+	})
+
+	t.Run("This test simulates obtaining transaction information by the date they were stored", func(t *testing.T) {
+		testObj := setUptest(t)
+		testObj.storesMock.EXPECT().GetTransactionByDate(ctx, testAccountId, &model.Period{
+			Field:    model.PeriodFieldCreated,
+			Start:    time.Date(2023, 10, 12, 0, 0, 0, 0, time.UTC),
+			End:      time.Date(2023, 10, 16, 0, 0, 0, 0, time.UTC),
+			Timezone: "UTC",
+		}, &model.AttributeFilter{}).Return([]*model.TransactionResponse{}, nil)
+
+		resp, err := testObj.appTest.GetTransactionsByPeriod(ctx, testAccountId, "2023-10-12", "2023-10-15", "", "created", nil)
+
+		assert.Empty(t, resp)
+		assert.NoError(t, err)
+	})
+
+	t.Run("This test simulates an unknown period field when obtaining transaction information by date", func(t *testing.T) {
+		testObj := setUptest(t)
+
+		resp, err := testObj.appTest.GetTransactionsByPeriod(ctx, testAccountId, "2023-10-12", "2023-10-15", "", "updated", nil)
+
+		assert.Nil(t, resp)
+		assert.ErrorIs(t, err, ErrInvalidFilter)
+	})
 }
 
 func TestGetTransactionsByPeriodEpoch(t *testing.T) {
@@ -250,9 +456,13 @@ func TestGetTransactionsByPeriodEpoch(t *testing.T) {
 				PurchaseDate:   "2023-10-14",
 			},
 		}
-		testObj.storesMock.EXPECT().GetTransactionByDate(ctx, sDate, eDate).Return(expectedResponse, nil)
+		testObj.storesMock.EXPECT().GetTransactionByDate(ctx, testAccountId, &model.Period{
+			Field: model.PeriodFieldPurchase,
+			Start: time.Unix(sDate, 0).UTC(),
+			End:   time.Unix(eDate+1, 0).UTC(),
+		}, &model.AttributeFilter{}).Return(expectedResponse, nil)
 
-		resp, err := testObj.appTest.GetTransactionsByPeriodEpoch(ctx, sDate, eDate)
+		resp, err := testObj.appTest.GetTransactionsByPeriodEpoch(ctx, testAccountId, sDate, eDate, "", nil)
 
 		assert.Equal(t, resp, expectedResponse)
 		assert.NoError(t, err)
@@ -262,11 +472,151 @@ func TestGetTransactionsByPeriodEpoch(t *testing.T) {
 		testObj := setUptest(t)
 		var sDate int64 = 1697150153
 		var eDate int64 = 1697409353
-		testObj.storesMock.EXPECT().GetTransactionByDate(ctx, sDate, eDate).Return(nil, errors.New("an error has ocurred"))
+		testObj.storesMock.EXPECT().GetTransactionByDate(ctx, testAccountId, gomock.Any(), &model.AttributeFilter{}).Return(nil, errors.New("an error has ocurred"))
+
+		resp, err := testObj.appTest.GetTransactionsByPeriodEpoch(ctx, testAccountId, sDate, eDate, "", nil)
+
+		assert.Nil(t, resp)
+		assert.Error(t, err)
+	})
+}
+
+func TestGetTransactionsSummary(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("This test simulates the process for obtaining the transactions summary", func(t *testing.T) {
+		testObj := setUptest(t)
+		startDate := "2023-10-01"
+		endDate := "2023-10-31"
+		expectedResponse := []*model.TransactionAggregate{
+			0: {
+				Period:       "2023-10",
+				PurchaseDate: "2023-10-15",
+				Count:        2,
+				Sum:          48.70,
+				Min:          23.70,
+				Max:          25.00,
+			},
+		}
+		testObj.storesMock.EXPECT().GetTransactionSummary(ctx, testAccountId, &model.Period{
+			Field:     model.PeriodFieldPurchase,
+			Start:     time.Date(2023, 10, 1, 0, 0, 0, 0, time.UTC),
+			End:       time.Date(2023, 11, 1, 0, 0, 0, 0, time.UTC),
+			FirstDate: time.Date(2023, 10, 1, 0, 0, 0, 0, time.UTC),
+			LastDate:  time.Date(2023, 10, 31, 0, 0, 0, 0, time.UTC),
+			Timezone:  "UTC",
+		}, "month").Return(expectedResponse, nil)
+
+		resp, err := testObj.appTest.GetTransactionsSummary(ctx, testAccountId, startDate, endDate, "", "", "month")
+
+		assert.Equal(t, resp, expectedResponse)
+		assert.NoError(t, err)
+	})
+
+	t.Run("This test simulates an error when obtaining the transactions summary - invalid date", func(t *testing.T) {
+		testObj := setUptest(t)
+
+		resp, err := testObj.appTest.GetTransactionsSummary(ctx, testAccountId, "2023/10/01", "2023-11-01", "", "", "month")
+
+		assert.Nil(t, resp)
+		assert.Error(t, err)
+	})
+}
 
-		resp, err := testObj.appTest.GetTransactionsByPeriodEpoch(ctx, sDate, eDate)
+func TestSearchTransactions(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("This test simulates the process for searching transactions", func(t *testing.T) {
+		testObj := setUptest(t)
+		expectedResponse := []*model.TransactionResponse{
+			0: {
+				PurchaseAmount: 23.70,
+				Description:    "Coffee",
+				PurchaseDate:   "2023-10-15",
+			},
+		}
+		testObj.storesMock.EXPECT().SearchTransactions(ctx, testAccountId, &model.TransactionFilter{
+			Text:      "coffee",
+			MaxAmount: 50,
+			Period: model.Period{
+				Field:     model.PeriodFieldPurchase,
+				Start:     time.Date(2023, 10, 1, 0, 0, 0, 0, time.UTC),
+				FirstDate: time.Date(2023, 10, 1, 0, 0, 0, 0, time.UTC),
+				Timezone:  "UTC",
+			},
+			PurchaseStartDate: time.Date(2023, 10, 10, 0, 0, 0, 0, time.UTC),
+			Skip:              20,
+			Limit:             20,
+			AttributeFilter: model.AttributeFilter{
+				Merchant: "Coffee Shop",
+			},
+		}).Return(expectedResponse, nil)
+
+		params := &model.SearchTransactionParams{
+			Text:              "coffee",
+			MaxAmount:         50,
+			StartDate:         "2023-10-01",
+			PurchaseStartDate: "2023-10-10",
+			Page:              2,
+			AttributeParams: model.AttributeParams{
+				Merchant: "Coffee Shop",
+			},
+		}
+		resp, err := testObj.appTest.SearchTransactions(ctx, testAccountId, params)
+
+		assert.Equal(t, resp, expectedResponse)
+		assert.Equal(t, params.PageSize, int64(20))
+		assert.NoError(t, err)
+	})
+
+	t.Run("This test simulates an error when searching transactions - invalid purchase date", func(t *testing.T) {
+		testObj := setUptest(t)
+
+		resp, err := testObj.appTest.SearchTransactions(ctx, testAccountId, &model.SearchTransactionParams{
+			PurchaseEndDate: "15/10/2023",
+		})
+
+		assert.Nil(t, resp)
+		assert.Error(t, err)
+	})
+
+	t.Run("This test simulates an error when searching transactions", func(t *testing.T) {
+		testObj := setUptest(t)
+		testObj.storesMock.EXPECT().SearchTransactions(ctx, testAccountId, gomock.Any()).Return(nil, errors.New("an error has ocurred"))
+
+		resp, err := testObj.appTest.SearchTransactions(ctx, testAccountId, &model.SearchTransactionParams{})
 
 		assert.Nil(t, resp)
 		assert.Error(t, err)
 	})
 }
+
+func TestExportTransactionsByPeriod(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("This test simulates the process for exporting transactions by date", func(t *testing.T) {
+		testObj := setUptest(t)
+		testObj.storesMock.EXPECT().StreamTransactionByDate(ctx, testAccountId, &model.Period{
+			Field:    model.PeriodFieldCreated,
+			Start:    time.Date(2023, 10, 1, 0, 0, 0, 0, time.UTC),
+			End:      time.Date(2023, 11, 2, 0, 0, 0, 0, time.UTC),
+			Timezone: "UTC",
+		}, gomock.Any()).Return(nil)
+
+		err := testObj.appTest.ExportTransactionsByPeriod(ctx, testAccountId, "2023-10-01", "2023-11-01", "", "created", func(*model.TransactionResponse) error {
+			return nil
+		})
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("This test simulates an error when exporting transactions by date - invalid date", func(t *testing.T) {
+		testObj := setUptest(t)
+
+		err := testObj.appTest.ExportTransactionsByPeriod(ctx, testAccountId, "2023-10-01", "11/01/2023", "", "", func(*model.TransactionResponse) error {
+			return nil
+		})
+
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,85 @@
+package webhook
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// ErrForbiddenURL is returned when a webhook url is not https while it is required, or targets
+// a loopback, private or link-local address
+var ErrForbiddenURL = errors.New("forbidden webhook url")
+
+// forbiddenIP reports whether the service must not connect to ip, as it reaches the service itself
+// or the internal network instead of a receiver on the internet
+func forbiddenIP(ip net.IP) bool {
+	return ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() || ip.IsUnspecified()
+}
+
+// publicOnly is the dialer Control rejecting the forbidden addresses. It runs on the resolved address
+// the connection is made to, so a host resolving to an internal address is rejected, even when its
+// resolution changes after the webhook was created.
+func publicOnly(network, address string, c syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+
+	if ip := net.ParseIP(host); ip == nil || forbiddenIP(ip) {
+		return fmt.Errorf("%w: %s is not a public address", ErrForbiddenURL, host)
+	}
+
+	return nil
+}
+
+// newClient is the delivery client. It does not use the environment proxy, which would connect to the
+// targets on its behalf, and follows a redirect only to an url a webhook could be created with.
+func newClient(opts Options) *http.Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	if !opts.AllowPrivate {
+		dialer := &net.Dialer{
+			Timeout:   30 * time.Second,
+			KeepAlive: 30 * time.Second,
+			Control:   publicOnly,
+		}
+		transport.DialContext = dialer.DialContext
+	}
+
+	return &http.Client{
+		Transport: transport,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= 10 {
+				return errors.New("stopped after 10 redirects")
+			}
+			return checkURL(req.URL, opts)
+		},
+	}
+}
+
+// checkURL rejects the urls that are not https while it is required, and the hosts that are known to
+// be forbidden without resolving them. The resolved addresses are checked when delivering.
+func checkURL(u *url.URL, opts Options) error {
+	if opts.RequireHTTPS && u.Scheme != "https" {
+		return fmt.Errorf("%w: the url must be https", ErrForbiddenURL)
+	}
+	if opts.AllowPrivate {
+		return nil
+	}
+
+	host := strings.TrimSuffix(strings.ToLower(u.Hostname()), ".")
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return fmt.Errorf("%w: %s is not a public address", ErrForbiddenURL, host)
+	}
+	if ip := net.ParseIP(host); ip != nil && forbiddenIP(ip) {
+		return fmt.Errorf("%w: %s is not a public address", ErrForbiddenURL, host)
+	}
+
+	return nil
+}
@@ -0,0 +1,107 @@
+package webhook
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/jcpribeiro/TransactionApp/model"
+	"github.com/jcpribeiro/TransactionApp/store"
+	"github.com/jcpribeiro/TransactionApp/store/webhook"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckURL(t *testing.T) {
+	t.Run("this test simulate the urls of the internal network", func(t *testing.T) {
+		for _, raw := range []string{
+			"http://localhost:8080",
+			"http://api.localhost",
+			"http://127.0.0.1",
+			"http://10.0.0.8",
+			"http://192.168.0.1",
+			"http://169.254.169.254/latest/meta-data",
+			"http://[::1]",
+			"http://[fe80::1]",
+			"http://0.0.0.0",
+		} {
+			u, _ := url.Parse(raw)
+
+			assert.ErrorIs(t, checkURL(u, Options{}), ErrForbiddenURL, raw)
+		}
+	})
+
+	t.Run("this test simulate a public url", func(t *testing.T) {
+		u, _ := url.Parse("https://hooks.example.com/transactions")
+
+		assert.NoError(t, checkURL(u, Options{RequireHTTPS: true}))
+	})
+
+	t.Run("this test simulate an http url while https is required", func(t *testing.T) {
+		u, _ := url.Parse("http://hooks.example.com/transactions")
+
+		assert.ErrorIs(t, checkURL(u, Options{RequireHTTPS: true}), ErrForbiddenURL)
+	})
+}
+
+func TestPublicOnly(t *testing.T) {
+	t.Run("this test simulate the resolved addresses of a webhook", func(t *testing.T) {
+		assert.NoError(t, publicOnly("tcp4", "93.184.216.34:443", nil))
+		assert.ErrorIs(t, publicOnly("tcp4", "127.0.0.1:443", nil), ErrForbiddenURL)
+		assert.ErrorIs(t, publicOnly("tcp4", "172.16.0.1:443", nil), ErrForbiddenURL)
+		assert.ErrorIs(t, publicOnly("tcp6", "[::ffff:10.0.0.1]:443", nil), ErrForbiddenURL)
+	})
+
+	t.Run("this test simulate the delivery client connecting to a private address", func(t *testing.T) {
+		var calls int
+		receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls++
+		}))
+		defer receiver.Close()
+
+		_, err := newClient(Options{}).Get(receiver.URL)
+
+		assert.ErrorIs(t, err, ErrForbiddenURL)
+		assert.Equal(t, calls, 0)
+	})
+
+	t.Run("this test simulate a delivery to a private address", func(t *testing.T) {
+		ctx := context.Background()
+		var calls int
+		receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls++
+		}))
+		defer receiver.Close()
+
+		// the webhook is stored as a host resolving to a private address would be, past the creation checks
+		webhookStore := webhook.NewMemoryStoreWebhook()
+		id, err := webhookStore.InsertWebhook(ctx, &model.Webhook{
+			AccountId: testAccountId,
+			URL:       receiver.URL,
+			Secret:    testSecret,
+			Events:    []string{model.EventTransactionCreated},
+		})
+		assert.NoError(t, err)
+
+		appTest := NewAppWebhook(&store.Container{
+			Webhook: webhookStore,
+		}, Options{
+			PollInterval: 10 * time.Millisecond,
+			Timeout:      time.Second,
+			StopTimeout:  100 * time.Millisecond,
+			MaxAttempts:  1,
+		}, *logrus.New())
+		appTest.Start()
+		defer appTest.Stop()
+
+		assert.NoError(t, appTest.Emit(ctx, testAccountId, newEvent("652d34910a8fc425116b84e1", model.EventTransactionCreated, nil)))
+
+		delivery := waitDelivery(t, appTest, id, model.DeliveryStatusDead)
+		assert.Contains(t, delivery.Error, "is not a public address")
+		assert.Equal(t, calls, 0)
+	})
+}
@@ -0,0 +1,388 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/jcpribeiro/TransactionApp/model"
+	"github.com/jcpribeiro/TransactionApp/store"
+	webhookStore "github.com/jcpribeiro/TransactionApp/store/webhook"
+
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+//go:generate mockgen -source=$GOFILE -destination=webhook_mock.go -package=$GOPACKAGE
+
+const (
+	HeaderEventId   = "X-Webhook-Id"
+	HeaderEvent     = "X-Webhook-Event"
+	HeaderTimestamp = "X-Webhook-Timestamp"
+	HeaderSignature = "X-Webhook-Signature"
+
+	signaturePrefix = "sha256="
+	deliveriesLimit = 100
+)
+
+type App interface {
+	CreateWebhook(ctx context.Context, accountId string, params *model.CreateWebhookParams) (*model.Webhook, error)
+	ListWebhooks(ctx context.Context, accountId string) ([]*model.Webhook, error)
+	DeleteWebhook(ctx context.Context, accountId, id string) error
+	ListDeliveries(ctx context.Context, accountId, id string) ([]*model.WebhookDelivery, error)
+	Emit(ctx context.Context, accountId string, event *model.WebhookEvent) error
+	Start()
+	Stop()
+}
+
+// Options of the delivery workers. A failed delivery is retried after MinBackoff, doubled on every
+// attempt up to MaxBackoff, and becomes dead after MaxAttempts. The webhooks can only target public
+// addresses unless AllowPrivate, and only https urls when RequireHTTPS.
+type Options struct {
+	Workers       int
+	PollInterval  time.Duration
+	LeaseDuration time.Duration
+	StopTimeout   time.Duration
+	Timeout       time.Duration
+	MaxAttempts   int
+	MinBackoff    time.Duration
+	MaxBackoff    time.Duration
+	AllowPrivate  bool
+	RequireHTTPS  bool
+	Client        *http.Client
+}
+
+type appImpl struct {
+	stores  *store.Container
+	log     logrus.Logger
+	opts    Options
+	owner   string
+	mu      sync.Mutex
+	stop    chan struct{}
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+	started bool
+}
+
+func NewAppWebhook(stores *store.Container, opts Options, log logrus.Logger) App {
+	if opts.Workers <= 0 {
+		opts.Workers = 1
+	}
+	if opts.PollInterval <= 0 {
+		opts.PollInterval = time.Second
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = 10 * time.Second
+	}
+	if opts.LeaseDuration <= opts.Timeout {
+		opts.LeaseDuration = opts.Timeout + 30*time.Second
+	}
+	if opts.StopTimeout <= 0 {
+		opts.StopTimeout = opts.Timeout
+	}
+	if opts.MaxAttempts <= 0 {
+		opts.MaxAttempts = 8
+	}
+	if opts.MinBackoff <= 0 {
+		opts.MinBackoff = 10 * time.Second
+	}
+	if opts.MaxBackoff < opts.MinBackoff {
+		opts.MaxBackoff = time.Hour
+	}
+	if opts.Client == nil {
+		opts.Client = newClient(opts)
+	}
+
+	hostname, _ := os.Hostname()
+
+	return &appImpl{
+		stores: stores,
+		log:    log,
+		opts:   opts,
+		owner:  fmt.Sprintf("%s-%d-%s", hostname, os.Getpid(), primitive.NewObjectID().Hex()),
+	}
+}
+
+// Sign returns the X-Webhook-Signature of a payload, the hex HMAC-SHA256 of "timestamp.body" keyed by the secret
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+
+	return signaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+func (a *appImpl) CreateWebhook(ctx context.Context, accountId string, params *model.CreateWebhookParams) (*model.Webhook, error) {
+	target, err := url.Parse(params.URL)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrForbiddenURL, err.Error())
+	}
+	if err := checkURL(target, a.opts); err != nil {
+		return nil, err
+	}
+
+	webhook := &model.Webhook{
+		AccountId: accountId,
+		URL:       params.URL,
+		Secret:    params.Secret,
+		Events:    params.Events,
+		CreatedAt: time.Now().Unix(),
+	}
+
+	id, err := a.stores.Webhook.InsertWebhook(ctx, webhook)
+	if err != nil {
+		return nil, fmt.Errorf("failed to insert webhook: %w", err)
+	}
+	webhook.Id = id
+
+	return webhook, nil
+}
+
+func (a *appImpl) ListWebhooks(ctx context.Context, accountId string) ([]*model.Webhook, error) {
+	return a.stores.Webhook.ListWebhooks(ctx, accountId)
+}
+
+func (a *appImpl) DeleteWebhook(ctx context.Context, accountId, id string) error {
+	return a.stores.Webhook.DeleteWebhook(ctx, accountId, id)
+}
+
+// ListDeliveries returns the latest deliveries of a webhook of the account, the newest first
+func (a *appImpl) ListDeliveries(ctx context.Context, accountId, id string) ([]*model.WebhookDelivery, error) {
+	if _, err := a.stores.Webhook.GetWebhookById(ctx, accountId, id); err != nil {
+		return nil, err
+	}
+
+	return a.stores.Webhook.ListDeliveries(ctx, accountId, id, deliveriesLimit)
+}
+
+// Emit queues a delivery of the event to every webhook of the account subscribed to it.
+// An event emitted again with the same id is not delivered twice.
+func (a *appImpl) Emit(ctx context.Context, accountId string, event *model.WebhookEvent) error {
+	webhooks, err := a.stores.Webhook.ListWebhooksByEvent(ctx, accountId, event.Type)
+	if err != nil {
+		return fmt.Errorf("failed to list webhooks of %s: %w", event.Type, err)
+	}
+	if len(webhooks) == 0 {
+		return nil
+	}
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s: %w", event.Type, err)
+	}
+
+	now := time.Now().Unix()
+	deliveries := make([]*model.WebhookDelivery, 0, len(webhooks))
+	for _, webhook := range webhooks {
+		deliveries = append(deliveries, &model.WebhookDelivery{
+			AccountId:     accountId,
+			WebhookId:     webhook.Id,
+			EventId:       event.Id,
+			Event:         event.Type,
+			Payload:       payload,
+			Status:        model.DeliveryStatusPending,
+			NextAttemptAt: now,
+			CreatedAt:     now,
+			UpdatedAt:     now,
+		})
+	}
+
+	if err := a.stores.Webhook.InsertDeliveries(ctx, deliveries); err != nil {
+		return fmt.Errorf("failed to insert deliveries of %s: %w", event.Type, err)
+	}
+
+	return nil
+}
+
+// Start runs the delivery workers. Deliveries left running by a stopped process are retried once their lease expires.
+func (a *appImpl) Start() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.started {
+		return
+	}
+
+	var ctx context.Context
+	ctx, a.cancel = context.WithCancel(context.Background())
+	a.stop = make(chan struct{})
+	a.started = true
+
+	for i := 0; i < a.opts.Workers; i++ {
+		a.wg.Add(1)
+		go a.work(ctx)
+	}
+
+	a.log.Infof("Started %d webhook workers", a.opts.Workers)
+}
+
+// Stop waits for the running deliveries to finish. After StopTimeout they are interrupted
+// and sent again once their lease expires.
+func (a *appImpl) Stop() {
+	a.mu.Lock()
+	if !a.started {
+		a.mu.Unlock()
+		return
+	}
+	a.started = false
+	close(a.stop)
+	a.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		a.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(a.opts.StopTimeout):
+		a.log.Warn("webhook workers did not stop in time, interrupting running deliveries")
+		a.cancel()
+		<-done
+	}
+	a.cancel()
+}
+
+func (a *appImpl) stopping() bool {
+	select {
+	case <-a.stop:
+		return true
+	default:
+		return false
+	}
+}
+
+func (a *appImpl) work(ctx context.Context) {
+	defer a.wg.Done()
+
+	for !a.stopping() {
+		now := time.Now()
+		delivery, err := a.stores.Webhook.ClaimDelivery(ctx, a.owner, now.Unix(), now.Add(a.opts.LeaseDuration).Unix())
+		if err != nil {
+			a.log.Error("cannot claim webhook delivery ", err.Error())
+		}
+
+		if delivery == nil {
+			select {
+			case <-a.stop:
+			case <-time.After(a.opts.PollInterval):
+			}
+			continue
+		}
+
+		a.deliver(ctx, delivery)
+	}
+}
+
+// backoff returns how long to wait after a failed attempt before the next one
+func (a *appImpl) backoff(attempts int) time.Duration {
+	wait := a.opts.MinBackoff
+	for i := 1; i < attempts; i++ {
+		wait *= 2
+		if wait >= a.opts.MaxBackoff {
+			return a.opts.MaxBackoff
+		}
+	}
+
+	return wait
+}
+
+// deliver makes an attempt of a claimed delivery and schedules the next one when it fails
+func (a *appImpl) deliver(ctx context.Context, delivery *model.WebhookDelivery) {
+	delivery.Attempts++
+	delivery.StatusCode = 0
+	delivery.Error = ""
+
+	webhook, err := a.stores.Webhook.GetWebhookById(ctx, delivery.AccountId, delivery.WebhookId)
+	switch {
+	case errors.Is(err, webhookStore.ErrWebhookNotFound):
+		delivery.Status = model.DeliveryStatusDead
+		delivery.Error = "webhook deleted"
+		a.finish(delivery)
+		return
+	case err != nil:
+		delivery.Error = err.Error()
+	default:
+		delivery.StatusCode, err = a.post(ctx, webhook, delivery)
+		if err != nil {
+			delivery.Error = err.Error()
+		}
+	}
+
+	if ctx.Err() != nil {
+		// interrupted by Stop, the attempt is made again once the lease expires
+		return
+	}
+
+	switch {
+	case err == nil:
+		delivery.Status = model.DeliveryStatusSucceeded
+		delivery.NextAttemptAt = 0
+	case delivery.Attempts >= a.opts.MaxAttempts:
+		delivery.Status = model.DeliveryStatusDead
+		delivery.NextAttemptAt = 0
+	default:
+		delivery.Status = model.DeliveryStatusPending
+		delivery.NextAttemptAt = time.Now().Add(a.backoff(delivery.Attempts)).Unix()
+	}
+
+	a.finish(delivery)
+}
+
+// post sends the signed payload to the webhook, any status other than 2xx is an error
+func (a *appImpl) post(ctx context.Context, webhook *model.Webhook, delivery *model.WebhookDelivery) (int, error) {
+	ctx, cancel := context.WithTimeout(ctx, a.opts.Timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, err
+	}
+	if err := checkURL(req.URL, a.opts); err != nil {
+		return 0, err
+	}
+
+	timestamp := time.Now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderEventId, delivery.EventId)
+	req.Header.Set(HeaderEvent, delivery.Event)
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(HeaderSignature, Sign(webhook.Secret, timestamp, delivery.Payload))
+
+	resp, err := a.opts.Client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+
+	return resp.StatusCode, nil
+}
+
+func (a *appImpl) finish(delivery *model.WebhookDelivery) {
+	if err := a.stores.Webhook.FinishDelivery(context.Background(), delivery.Id, a.owner, delivery, time.Now().Unix()); err != nil {
+		a.log.Error(fmt.Errorf("cannot finish webhook delivery %s: %w", delivery.Id, err))
+		return
+	}
+
+	if delivery.Status == model.DeliveryStatusDead {
+		a.log.Warnf("webhook delivery %s of %s is dead after %d attempts: %s", delivery.Id, delivery.Event, delivery.Attempts, delivery.Error)
+	}
+}
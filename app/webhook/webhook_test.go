@@ -0,0 +1,237 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jcpribeiro/TransactionApp/internal/tenant"
+	"github.com/jcpribeiro/TransactionApp/model"
+	"github.com/jcpribeiro/TransactionApp/store"
+	"github.com/jcpribeiro/TransactionApp/store/webhook"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testAccountId = "652d34910a8fc425116b84aa"
+	testSecret    = "0123456789abcdef"
+)
+
+type structTest struct {
+	store   webhook.Store
+	appTest App
+}
+
+func setUptest(t *testing.T, maxAttempts int) structTest {
+	webhookStore := webhook.NewMemoryStoreWebhook()
+
+	return structTest{
+		store: webhookStore,
+		appTest: NewAppWebhook(&store.Container{
+			Webhook: webhookStore,
+		}, Options{
+			Workers:      2,
+			PollInterval: 10 * time.Millisecond,
+			Timeout:      time.Second,
+			StopTimeout:  100 * time.Millisecond,
+			MaxAttempts:  maxAttempts,
+			MinBackoff:   time.Millisecond,
+			MaxBackoff:   time.Millisecond,
+			AllowPrivate: true,
+		}, *logrus.New()),
+	}
+}
+
+func createWebhook(t *testing.T, app App, url string) *model.Webhook {
+	webhook, err := app.CreateWebhook(context.Background(), testAccountId, &model.CreateWebhookParams{
+		URL:    url,
+		Secret: testSecret,
+		Events: []string{model.EventTransactionCreated},
+	})
+	assert.NoError(t, err)
+
+	return webhook
+}
+
+func newEvent(id, eventType string, data interface{}) *model.WebhookEvent {
+	return &model.WebhookEvent{
+		Id:        id,
+		Type:      eventType,
+		CreatedAt: "2023-10-15T12:00:00Z",
+		Data:      data,
+	}
+}
+
+func waitDelivery(t *testing.T, app App, id string, status string) *model.WebhookDelivery {
+	var delivery *model.WebhookDelivery
+	assert.Eventually(t, func() bool {
+		deliveries, _ := app.ListDeliveries(context.Background(), testAccountId, id)
+		if len(deliveries) == 0 {
+			return false
+		}
+		delivery = deliveries[0]
+		return delivery.Status == status
+	}, 3*time.Second, 10*time.Millisecond)
+
+	return delivery
+}
+
+func TestEmit(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("this test simulate a successful signed delivery", func(t *testing.T) {
+		testObj := setUptest(t, 3)
+		received := make(chan *http.Request, 1)
+		var body []byte
+		receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ = io.ReadAll(r.Body)
+			received <- r
+		}))
+		defer receiver.Close()
+
+		created := createWebhook(t, testObj.appTest, receiver.URL)
+		testObj.appTest.Start()
+		defer testObj.appTest.Stop()
+
+		err := testObj.appTest.Emit(ctx, testAccountId, newEvent("652d34910a8fc425116b84e1", model.EventTransactionCreated, map[string]string{"id": "652d34910a8fc425116b84d9"}))
+		assert.NoError(t, err)
+
+		r := <-received
+		timestamp, _ := strconv.ParseInt(r.Header.Get(HeaderTimestamp), 10, 64)
+		assert.Equal(t, r.Header.Get(HeaderEvent), model.EventTransactionCreated)
+		assert.Equal(t, r.Header.Get(HeaderSignature), Sign(testSecret, timestamp, body))
+
+		var event model.WebhookEvent
+		assert.NoError(t, json.Unmarshal(body, &event))
+		assert.Equal(t, event.Type, model.EventTransactionCreated)
+		assert.Equal(t, event.Id, "652d34910a8fc425116b84e1")
+		assert.Equal(t, r.Header.Get(HeaderEventId), "652d34910a8fc425116b84e1")
+		assert.Equal(t, event.Data, map[string]interface{}{"id": "652d34910a8fc425116b84d9"})
+
+		delivery := waitDelivery(t, testObj.appTest, created.Id, model.DeliveryStatusSucceeded)
+		assert.Equal(t, delivery.Attempts, 1)
+		assert.Equal(t, delivery.StatusCode, http.StatusOK)
+	})
+
+	t.Run("this test simulate a delivery retried after failures", func(t *testing.T) {
+		testObj := setUptest(t, 5)
+		var calls int32
+		receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if atomic.AddInt32(&calls, 1) < 3 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+			}
+		}))
+		defer receiver.Close()
+
+		created := createWebhook(t, testObj.appTest, receiver.URL)
+		testObj.appTest.Start()
+		defer testObj.appTest.Stop()
+
+		err := testObj.appTest.Emit(ctx, testAccountId, newEvent("652d34910a8fc425116b84e1", model.EventTransactionCreated, nil))
+		assert.NoError(t, err)
+
+		delivery := waitDelivery(t, testObj.appTest, created.Id, model.DeliveryStatusSucceeded)
+		assert.Equal(t, delivery.Attempts, 3)
+		assert.Empty(t, delivery.Error)
+	})
+
+	t.Run("this test simulate a dead delivery", func(t *testing.T) {
+		testObj := setUptest(t, 2)
+		receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+		defer receiver.Close()
+
+		created := createWebhook(t, testObj.appTest, receiver.URL)
+		testObj.appTest.Start()
+		defer testObj.appTest.Stop()
+
+		err := testObj.appTest.Emit(ctx, testAccountId, newEvent("652d34910a8fc425116b84e1", model.EventTransactionCreated, nil))
+		assert.NoError(t, err)
+
+		delivery := waitDelivery(t, testObj.appTest, created.Id, model.DeliveryStatusDead)
+		assert.Equal(t, delivery.Attempts, 2)
+		assert.Equal(t, delivery.StatusCode, http.StatusInternalServerError)
+		assert.Equal(t, delivery.Error, "unexpected status 500")
+	})
+
+	t.Run("this test simulate an event emitted twice", func(t *testing.T) {
+		testObj := setUptest(t, 3)
+		created := createWebhook(t, testObj.appTest, "http://localhost")
+		event := newEvent("652d34910a8fc425116b84e1", model.EventTransactionCreated, nil)
+
+		assert.NoError(t, testObj.appTest.Emit(ctx, testAccountId, event))
+		assert.NoError(t, testObj.appTest.Emit(ctx, testAccountId, event))
+
+		deliveries, err := testObj.appTest.ListDeliveries(ctx, testAccountId, created.Id)
+		assert.NoError(t, err)
+		assert.Len(t, deliveries, 1)
+	})
+
+	t.Run("this test simulate an event without subscribers", func(t *testing.T) {
+		testObj := setUptest(t, 3)
+		created := createWebhook(t, testObj.appTest, "http://localhost")
+
+		err := testObj.appTest.Emit(ctx, testAccountId, newEvent("652d34910a8fc425116b84e1", "account.updated", nil))
+		assert.NoError(t, err)
+
+		deliveries, err := testObj.appTest.ListDeliveries(ctx, testAccountId, created.Id)
+		assert.NoError(t, err)
+		assert.Empty(t, deliveries)
+	})
+
+	t.Run("this test simulate an event without account", func(t *testing.T) {
+		testObj := setUptest(t, 3)
+
+		err := testObj.appTest.Emit(ctx, "", newEvent("652d34910a8fc425116b84e1", model.EventTransactionCreated, nil))
+		assert.ErrorIs(t, err, tenant.ErrMissingAccount)
+	})
+}
+
+func TestDeleteWebhook(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("this test simulate the pending deliveries of a deleted webhook", func(t *testing.T) {
+		testObj := setUptest(t, 3)
+		created := createWebhook(t, testObj.appTest, "http://localhost")
+		assert.NoError(t, testObj.appTest.Emit(ctx, testAccountId, newEvent("652d34910a8fc425116b84e1", model.EventTransactionCreated, nil)))
+
+		assert.NoError(t, testObj.appTest.DeleteWebhook(ctx, testAccountId, created.Id))
+		testObj.appTest.Start()
+		defer testObj.appTest.Stop()
+
+		assert.Eventually(t, func() bool {
+			deliveries, _ := testObj.store.ListDeliveries(ctx, testAccountId, created.Id, 10)
+			return len(deliveries) == 1 && deliveries[0].Status == model.DeliveryStatusDead
+		}, 2*time.Second, 10*time.Millisecond)
+
+		_, err := testObj.appTest.ListDeliveries(ctx, testAccountId, created.Id)
+		assert.ErrorIs(t, err, webhook.ErrWebhookNotFound)
+	})
+
+	t.Run("this test simulate deleting a webhook of another account", func(t *testing.T) {
+		testObj := setUptest(t, 3)
+		created := createWebhook(t, testObj.appTest, "http://localhost")
+
+		err := testObj.appTest.DeleteWebhook(ctx, "652d34910a8fc425116b84bb", created.Id)
+		assert.ErrorIs(t, err, webhook.ErrWebhookNotFound)
+	})
+}
+
+func TestBackoff(t *testing.T) {
+	a := &appImpl{opts: Options{MinBackoff: 10 * time.Second, MaxBackoff: time.Minute}}
+
+	assert.Equal(t, a.backoff(1), 10*time.Second)
+	assert.Equal(t, a.backoff(2), 20*time.Second)
+	assert.Equal(t, a.backoff(3), 40*time.Second)
+	assert.Equal(t, a.backoff(4), time.Minute)
+	assert.Equal(t, a.backoff(30), time.Minute)
+}
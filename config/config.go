@@ -1,14 +1,21 @@
 package config
 
-// Server is a struct to use in config
+import "time"
+
+// Server is a struct to use in config. An empty grpc_port disables the gRPC api.
+// Server configures the listeners. metrics_port serves /debug/vars apart from the api, it must
+// not be reachable from outside, an empty metrics_port disables it.
 type Server struct {
-	Port string `mapstructure:"port"`
+	Port        string `mapstructure:"port"`
+	GRPCPort    string `mapstructure:"grpc_port"`
+	MetricsPort string `mapstructure:"metrics_port"`
 }
 
 type FiscalData struct {
 	URL string `mapstructure:"url"`
 }
 
+// Redis is disabled when url is empty, the cache is then kept in process and the requests are not rate limited
 type Redis struct {
 	Password string `mapstructure:"password"`
 	URL      string `mapstructure:"url"`
@@ -20,13 +27,123 @@ type MongoDb struct {
 	Scheme string `mapstructure:"scheme"`
 }
 
+// CachePolicy is how long the responses of an endpoint are cached, by the service for ttl
+// and by the clients for max_age. A zero ttl disables the service cache.
+type CachePolicy struct {
+	TTL    time.Duration `mapstructure:"ttl"`
+	MaxAge time.Duration `mapstructure:"max_age"`
+}
+
+// Cache configures the in-process tier in front of Redis and the policy of each endpoint
+type Cache struct {
+	LocalSize     int                    `mapstructure:"local_size"`
+	LocalMaxBytes int64                  `mapstructure:"local_max_bytes"`
+	LocalTTL      time.Duration          `mapstructure:"local_ttl"`
+	Endpoints     map[string]CachePolicy `mapstructure:"endpoints"`
+}
+
+type Jobs struct {
+	Workers int    `mapstructure:"workers"`
+	Dir     string `mapstructure:"dir"`
+}
+
+// Webhooks configures the delivery workers. A failed delivery is retried after min_backoff,
+// doubled on every attempt up to max_backoff, and is dead after max_attempts. allow_private
+// lets the webhooks target the local network, require_https rejects the http urls.
+type Webhooks struct {
+	Workers      int           `mapstructure:"workers"`
+	Timeout      time.Duration `mapstructure:"timeout"`
+	MaxAttempts  int           `mapstructure:"max_attempts"`
+	MinBackoff   time.Duration `mapstructure:"min_backoff"`
+	MaxBackoff   time.Duration `mapstructure:"max_backoff"`
+	AllowPrivate bool          `mapstructure:"allow_private"`
+	RequireHTTPS bool          `mapstructure:"require_https"`
+}
+
+// Outbox configures the relay publishing the stored events to the sinks: "webhook" and "redis".
+// The redis sink appends to redis_stream, trimmed to about redis_max_len entries.
+type Outbox struct {
+	Workers     int      `mapstructure:"workers"`
+	Sinks       []string `mapstructure:"sinks"`
+	RedisStream string   `mapstructure:"redis_stream"`
+	RedisMaxLen int64    `mapstructure:"redis_max_len"`
+}
+
+// Ingest configures the consumers inserting the transactions of a Redis stream, disabled when stream is empty.
+// A message that fails max_deliveries times, or can never be inserted, is moved to dead_letter_stream.
+type Ingest struct {
+	Stream           string        `mapstructure:"stream"`
+	Group            string        `mapstructure:"group"`
+	DeadLetterStream string        `mapstructure:"dead_letter_stream"`
+	Workers          int           `mapstructure:"workers"`
+	Batch            int64         `mapstructure:"batch"`
+	ClaimIdle        time.Duration `mapstructure:"claim_idle"`
+	MaxDeliveries    int64         `mapstructure:"max_deliveries"`
+}
+
+// JWT configures the bearer tokens accepted, secret enables HS256 and
+// public_key_file or jwks_file enable RS256
+type JWT struct {
+	Secret        string `mapstructure:"secret"`
+	PublicKeyFile string `mapstructure:"public_key_file"`
+	JWKSFile      string `mapstructure:"jwks_file"`
+	Issuer        string `mapstructure:"issuer"`
+	Audience      string `mapstructure:"audience"`
+	AccountClaim  string `mapstructure:"account_claim"`
+}
+
+// Limit allows requests per client in any window, such as "1m"
+type Limit struct {
+	Requests int64         `mapstructure:"requests"`
+	Window   time.Duration `mapstructure:"window"`
+}
+
+// RateLimit configures the limit of each route class. Conversions are the reads
+// that call the Treasury api.
+type RateLimit struct {
+	Enabled    bool  `mapstructure:"enabled"`
+	Read       Limit `mapstructure:"read"`
+	Write      Limit `mapstructure:"write"`
+	Conversion Limit `mapstructure:"conversion"`
+}
+
+// Migrations configures the startup migrations. DefaultAccount is the account the documents stored
+// before the accounts are assigned to, it is required while such documents exist.
+type Migrations struct {
+	DefaultAccount string `mapstructure:"default_account"`
+}
+
+// Rules are the business rules of every inserted transaction. A zero max_past_days, min_amount or
+// max_amount and an empty description_pattern disable their rule, a negative max_future_days allows
+// any future purchase date.
+type Rules struct {
+	MaxPastDays        int     `mapstructure:"max_past_days"`
+	MaxFutureDays      int     `mapstructure:"max_future_days"`
+	MinAmount          float64 `mapstructure:"min_amount"`
+	MaxAmount          float64 `mapstructure:"max_amount"`
+	DescriptionPattern string  `mapstructure:"description_pattern"`
+}
+
+type Auth struct {
+	JWT JWT `mapstructure:"jwt"`
+}
+
 type Config struct {
 	ENV           string     `mapstructure:"env"`
 	Server        Server     `mapstructure:"server"`
 	FiscalData    FiscalData `mapstructure:"fiscaldata"`
 	Redis         Redis      `mapstructure:"redis"`
+	Cache         Cache      `mapstructure:"cache"`
 	MongoDbReader MongoDb    `mapstructure:"mongodb_reader"`
 	MongoDbWriter MongoDb    `mapstructure:"mongodb_writer"`
+	Jobs          Jobs       `mapstructure:"jobs"`
+	Webhooks      Webhooks   `mapstructure:"webhooks"`
+	Outbox        Outbox     `mapstructure:"outbox"`
+	Ingest        Ingest     `mapstructure:"ingest"`
+	Auth          Auth       `mapstructure:"auth"`
+	RateLimit     RateLimit  `mapstructure:"rate_limit"`
+	Rules         Rules      `mapstructure:"rules"`
+	Migrations    Migrations `mapstructure:"migrations"`
 }
 
 // GlobalConfig is you use in all app
@@ -0,0 +1,123 @@
+package auth
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/jcpribeiro/TransactionApp/internal/envelope"
+
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	ScopeTransactionRead  = "transaction:read"
+	ScopeTransactionWrite = "transaction:write"
+	ScopeAdmin            = "admin"
+
+	HeaderAPIKey = "X-API-Key"
+
+	principalKey = "principal"
+	bearerPrefix = "bearer "
+)
+
+// Principal is the authenticated caller of a request
+type Principal struct {
+	AccountId string
+	Subject   string
+	Scopes    []string
+}
+
+// HasScope reports whether the principal was granted the scope. The admin scope grants every scope.
+func (p *Principal) HasScope(scope string) bool {
+	for _, s := range p.Scopes {
+		if s == scope || s == ScopeAdmin {
+			return true
+		}
+	}
+
+	return false
+}
+
+// KeyVerifier returns the principal of an API key, or nil when the key is unknown or revoked
+type KeyVerifier func(ctx context.Context, key string) (*Principal, error)
+
+// Authenticator authenticates the requests with an API key in the X-API-Key header
+// or a JWT in the Authorization bearer header
+type Authenticator struct {
+	keys KeyVerifier
+	jwt  *JWTVerifier
+	log  logrus.Logger
+}
+
+// NewAuthenticator creates an authenticator. jwt may be nil to accept API keys only.
+func NewAuthenticator(keys KeyVerifier, jwt *JWTVerifier, log logrus.Logger) *Authenticator {
+	return &Authenticator{
+		keys: keys,
+		jwt:  jwt,
+		log:  log,
+	}
+}
+
+// Resolve authenticates the request and returns the account of its principal,
+// or an empty string when the credentials are missing or invalid.
+// It satisfies tenant.Resolver.
+func (a *Authenticator) Resolve(c echo.Context) (string, error) {
+	principal, err := a.authenticate(c)
+	if err != nil || principal == nil {
+		return "", err
+	}
+
+	SetPrincipal(c, principal)
+	return principal.AccountId, nil
+}
+
+func (a *Authenticator) authenticate(c echo.Context) (*Principal, error) {
+	return a.Authenticate(c.Request().Context(), c.Request().Header.Get(HeaderAPIKey), c.Request().Header.Get(echo.HeaderAuthorization))
+}
+
+// Authenticate returns the principal of an API key, or of the bearer token in an authorization header
+// when the key is empty. It returns nil when the credentials are missing or invalid.
+func (a *Authenticator) Authenticate(ctx context.Context, key, header string) (*Principal, error) {
+	if len(key) > 0 {
+		return a.keys(ctx, key)
+	}
+
+	if a.jwt == nil || len(header) <= len(bearerPrefix) || !strings.EqualFold(header[:len(bearerPrefix)], bearerPrefix) {
+		return nil, nil
+	}
+
+	principal, err := a.jwt.Verify(header[len(bearerPrefix):])
+	if err != nil {
+		a.log.Debug("invalid bearer token ", err.Error())
+		return nil, nil
+	}
+
+	return principal, nil
+}
+
+// RequireScope rejects the requests whose principal was not granted the scope
+func RequireScope(scope string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			principal := PrincipalFrom(c)
+			if principal == nil || !principal.HasScope(scope) {
+				return envelope.Error(c, http.StatusForbidden, "missing scope "+scope)
+			}
+
+			return next(c)
+		}
+	}
+}
+
+// SetPrincipal stores the principal of the request
+func SetPrincipal(c echo.Context, principal *Principal) {
+	c.Set(principalKey, principal)
+}
+
+// PrincipalFrom returns the principal of the request, or nil when it was not authenticated
+func PrincipalFrom(c echo.Context) *Principal {
+	principal, _ := c.Get(principalKey).(*Principal)
+	return principal
+}
@@ -0,0 +1,116 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolve(t *testing.T) {
+	keys := func(ctx context.Context, key string) (*Principal, error) {
+		switch key {
+		case "key-1":
+			return &Principal{AccountId: "account-1", Scopes: []string{ScopeTransactionRead}}, nil
+		case "broken":
+			return nil, errors.New("an error has ocurred")
+		}
+		return nil, nil
+	}
+	verifier, _ := NewJWTVerifier(JWTOptions{Secret: "secret"})
+	authenticator := NewAuthenticator(keys, verifier, *logrus.New())
+
+	t.Run("this test simulate a request with an api key", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/v1/transaction", nil)
+		req.Header.Set(HeaderAPIKey, "key-1")
+		ctx := echo.New().NewContext(req, httptest.NewRecorder())
+
+		accountId, err := authenticator.Resolve(ctx)
+
+		assert.NoError(t, err)
+		assert.Equal(t, accountId, "account-1")
+		assert.Equal(t, PrincipalFrom(ctx).Scopes, []string{ScopeTransactionRead})
+	})
+
+	t.Run("this test simulate a request with a bearer token", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/v1/transaction", nil)
+		req.Header.Set(echo.HeaderAuthorization, "Bearer "+signHS256(t, "secret", validClaims()))
+		ctx := echo.New().NewContext(req, httptest.NewRecorder())
+
+		accountId, err := authenticator.Resolve(ctx)
+
+		assert.NoError(t, err)
+		assert.Equal(t, accountId, "account-1")
+		assert.Equal(t, PrincipalFrom(ctx).Subject, "user-1")
+	})
+
+	t.Run("this test simulate requests with invalid credentials", func(t *testing.T) {
+		headers := []map[string]string{
+			{},
+			{HeaderAPIKey: "key-2"},
+			{echo.HeaderAuthorization: "Bearer token"},
+			{echo.HeaderAuthorization: "Basic dXNlcjpwYXNz"},
+			{echo.HeaderAuthorization: "Bearer " + signHS256(t, "other", validClaims())},
+		}
+		for _, header := range headers {
+			req := httptest.NewRequest(http.MethodGet, "/v1/transaction", nil)
+			for k, v := range header {
+				req.Header.Set(k, v)
+			}
+			ctx := echo.New().NewContext(req, httptest.NewRecorder())
+
+			accountId, err := authenticator.Resolve(ctx)
+
+			assert.NoError(t, err)
+			assert.Empty(t, accountId)
+			assert.Nil(t, PrincipalFrom(ctx))
+		}
+	})
+
+	t.Run("this test simulate an error verifying an api key", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/v1/transaction", nil)
+		req.Header.Set(HeaderAPIKey, "broken")
+		ctx := echo.New().NewContext(req, httptest.NewRecorder())
+
+		accountId, err := authenticator.Resolve(ctx)
+
+		assert.Error(t, err)
+		assert.Empty(t, accountId)
+	})
+}
+
+func TestRequireScope(t *testing.T) {
+	next := func(c echo.Context) error {
+		return c.NoContent(http.StatusOK)
+	}
+
+	tests := []struct {
+		name      string
+		principal *Principal
+		code      int
+	}{
+		{"this test simulate a principal with the scope", &Principal{Scopes: []string{ScopeTransactionWrite}}, http.StatusOK},
+		{"this test simulate an admin principal", &Principal{Scopes: []string{ScopeAdmin}}, http.StatusOK},
+		{"this test simulate a principal without the scope", &Principal{Scopes: []string{ScopeTransactionRead}}, http.StatusForbidden},
+		{"this test simulate a request without principal", nil, http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			ctx := echo.New().NewContext(httptest.NewRequest(http.MethodPost, "/v1/transaction", nil), rec)
+			if tt.principal != nil {
+				SetPrincipal(ctx, tt.principal)
+			}
+
+			err := RequireScope(ScopeTransactionWrite)(next)(ctx)
+
+			assert.NoError(t, err)
+			assert.Equal(t, rec.Code, tt.code)
+		})
+	}
+}
@@ -0,0 +1,194 @@
+package auth
+
+import (
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+)
+
+const (
+	defaultAccountClaim = "account_id"
+)
+
+// JWTOptions configures the bearer tokens accepted. Secret enables HS256,
+// PublicKeyFile (a PEM file) or JWKSFile enable RS256.
+type JWTOptions struct {
+	Secret        string
+	PublicKeyFile string
+	JWKSFile      string
+	Issuer        string
+	Audience      string
+	// AccountClaim is the claim holding the account id, account_id by default
+	AccountClaim string
+}
+
+// JWTVerifier validates the bearer tokens and maps their claims to a principal.
+// The scopes are read from the space separated scope claim or the scopes array claim.
+type JWTVerifier struct {
+	secret       []byte
+	publicKey    *rsa.PublicKey
+	jwks         map[string]*rsa.PublicKey
+	issuer       string
+	audience     string
+	accountClaim string
+	methods      []string
+}
+
+type jwks struct {
+	Keys []struct {
+		Kid string `json:"kid"`
+		Kty string `json:"kty"`
+		Use string `json:"use"`
+		N   string `json:"n"`
+		E   string `json:"e"`
+	} `json:"keys"`
+}
+
+// NewJWTVerifier loads the configured keys. It returns nil when no key is configured.
+func NewJWTVerifier(opts JWTOptions) (*JWTVerifier, error) {
+	v := &JWTVerifier{
+		issuer:       opts.Issuer,
+		audience:     opts.Audience,
+		accountClaim: opts.AccountClaim,
+	}
+	if len(v.accountClaim) == 0 {
+		v.accountClaim = defaultAccountClaim
+	}
+
+	if len(opts.Secret) > 0 {
+		v.secret = []byte(opts.Secret)
+		v.methods = append(v.methods, jwt.SigningMethodHS256.Alg())
+	}
+
+	if len(opts.PublicKeyFile) > 0 {
+		data, err := os.ReadFile(opts.PublicKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read jwt public key: %w", err)
+		}
+
+		v.publicKey, err = jwt.ParseRSAPublicKeyFromPEM(data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse jwt public key: %w", err)
+		}
+	}
+
+	if len(opts.JWKSFile) > 0 {
+		data, err := os.ReadFile(opts.JWKSFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read jwks: %w", err)
+		}
+
+		v.jwks, err = parseJWKS(data)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if v.publicKey != nil || len(v.jwks) > 0 {
+		v.methods = append(v.methods, jwt.SigningMethodRS256.Alg())
+	}
+
+	if len(v.methods) == 0 {
+		return nil, nil
+	}
+
+	return v, nil
+}
+
+func parseJWKS(data []byte) (map[string]*rsa.PublicKey, error) {
+	var set jwks
+	if err := json.Unmarshal(data, &set); err != nil {
+		return nil, fmt.Errorf("failed to parse jwks: %w", err)
+	}
+
+	keys := make(map[string]*rsa.PublicKey, len(set.Keys))
+	for _, k := range set.Keys {
+		if k.Kty != "RSA" || (len(k.Use) > 0 && k.Use != "sig") {
+			continue
+		}
+
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse jwks key %s: %w", k.Kid, err)
+		}
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse jwks key %s: %w", k.Kid, err)
+		}
+
+		keys[k.Kid] = &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}
+	}
+
+	return keys, nil
+}
+
+func (v *JWTVerifier) key(token *jwt.Token) (interface{}, error) {
+	switch token.Method.Alg() {
+	case jwt.SigningMethodHS256.Alg():
+		return v.secret, nil
+	case jwt.SigningMethodRS256.Alg():
+		kid, _ := token.Header["kid"].(string)
+		if key, ok := v.jwks[kid]; ok {
+			return key, nil
+		}
+		if v.publicKey != nil {
+			return v.publicKey, nil
+		}
+		return nil, fmt.Errorf("unknown key id %q", kid)
+	}
+
+	return nil, fmt.Errorf("unexpected signing method %s", token.Method.Alg())
+}
+
+// Verify validates the signature, expiration, issuer and audience of a token
+func (v *JWTVerifier) Verify(tokenString string) (*Principal, error) {
+	parser := &jwt.Parser{ValidMethods: v.methods}
+	claims := jwt.MapClaims{}
+	if _, err := parser.ParseWithClaims(tokenString, claims, v.key); err != nil {
+		return nil, err
+	}
+
+	if !claims.VerifyExpiresAt(time.Now().Unix(), true) {
+		return nil, errors.New("token has no valid expiration")
+	}
+	if len(v.issuer) > 0 && !claims.VerifyIssuer(v.issuer, true) {
+		return nil, errors.New("invalid token issuer")
+	}
+	if len(v.audience) > 0 && !claims.VerifyAudience(v.audience, true) {
+		return nil, errors.New("invalid token audience")
+	}
+
+	accountId, _ := claims[v.accountClaim].(string)
+	if len(accountId) == 0 {
+		return nil, fmt.Errorf("missing %s claim", v.accountClaim)
+	}
+
+	principal := &Principal{
+		AccountId: accountId,
+	}
+	principal.Subject, _ = claims["sub"].(string)
+
+	if scope, ok := claims["scope"].(string); ok {
+		principal.Scopes = strings.Fields(scope)
+	}
+	if scopes, ok := claims["scopes"].([]interface{}); ok {
+		for _, s := range scopes {
+			if scope, ok := s.(string); ok {
+				principal.Scopes = append(principal.Scopes, scope)
+			}
+		}
+	}
+
+	return principal, nil
+}
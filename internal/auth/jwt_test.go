@@ -0,0 +1,175 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+	"github.com/stretchr/testify/assert"
+)
+
+func signHS256(t *testing.T, secret string, claims jwt.MapClaims) string {
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(secret))
+	assert.NoError(t, err)
+	return token
+}
+
+func signRS256(t *testing.T, key *rsa.PrivateKey, kid string, claims jwt.MapClaims) string {
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	if len(kid) > 0 {
+		token.Header["kid"] = kid
+	}
+	signed, err := token.SignedString(key)
+	assert.NoError(t, err)
+	return signed
+}
+
+func validClaims() jwt.MapClaims {
+	return jwt.MapClaims{
+		"sub":        "user-1",
+		"account_id": "account-1",
+		"scope":      "transaction:read transaction:write",
+		"exp":        time.Now().Add(time.Hour).Unix(),
+	}
+}
+
+func TestVerifyHS256(t *testing.T) {
+	verifier, err := NewJWTVerifier(JWTOptions{Secret: "secret", Issuer: "issuer", Audience: "transactionapp"})
+	assert.NoError(t, err)
+
+	t.Run("this test simulate a valid token", func(t *testing.T) {
+		claims := validClaims()
+		claims["iss"] = "issuer"
+		claims["aud"] = []string{"transactionapp"}
+
+		principal, err := verifier.Verify(signHS256(t, "secret", claims))
+
+		assert.NoError(t, err)
+		assert.Equal(t, principal, &Principal{
+			AccountId: "account-1",
+			Subject:   "user-1",
+			Scopes:    []string{ScopeTransactionRead, ScopeTransactionWrite},
+		})
+	})
+
+	t.Run("this test simulate invalid tokens", func(t *testing.T) {
+		expired := validClaims()
+		expired["iss"] = "issuer"
+		expired["aud"] = "transactionapp"
+		expired["exp"] = time.Now().Add(-time.Minute).Unix()
+
+		noExpiration := validClaims()
+		noExpiration["iss"] = "issuer"
+		noExpiration["aud"] = "transactionapp"
+		delete(noExpiration, "exp")
+
+		wrongIssuer := validClaims()
+		wrongIssuer["iss"] = "other"
+		wrongIssuer["aud"] = "transactionapp"
+
+		wrongAudience := validClaims()
+		wrongAudience["iss"] = "issuer"
+		wrongAudience["aud"] = "other"
+
+		noAccount := validClaims()
+		noAccount["iss"] = "issuer"
+		noAccount["aud"] = "transactionapp"
+		delete(noAccount, "account_id")
+
+		valid := validClaims()
+		valid["iss"] = "issuer"
+		valid["aud"] = "transactionapp"
+
+		tokens := map[string]string{
+			"expired":         signHS256(t, "secret", expired),
+			"no expiration":   signHS256(t, "secret", noExpiration),
+			"wrong issuer":    signHS256(t, "secret", wrongIssuer),
+			"wrong audience":  signHS256(t, "secret", wrongAudience),
+			"no account":      signHS256(t, "secret", noAccount),
+			"wrong secret":    signHS256(t, "other", valid),
+			"none algorithm":  "eyJhbGciOiJub25lIiwidHlwIjoiSldUIn0.eyJhY2NvdW50X2lkIjoiYWNjb3VudC0xIn0.",
+			"malformed token": "token",
+		}
+		for name, token := range tokens {
+			principal, err := verifier.Verify(token)
+			assert.Error(t, err, name)
+			assert.Nil(t, principal, name)
+		}
+	})
+}
+
+func TestVerifyRS256(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	dir := t.TempDir()
+
+	t.Run("this test simulate a token verified with a public key file", func(t *testing.T) {
+		der, _ := x509.MarshalPKIXPublicKey(&key.PublicKey)
+		path := filepath.Join(dir, "public.pem")
+		os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), 0o644)
+
+		verifier, err := NewJWTVerifier(JWTOptions{PublicKeyFile: path})
+		assert.NoError(t, err)
+
+		claims := validClaims()
+		delete(claims, "scope")
+		claims["scopes"] = []string{ScopeAdmin}
+		principal, err := verifier.Verify(signRS256(t, key, "", claims))
+
+		assert.NoError(t, err)
+		assert.Equal(t, principal.Scopes, []string{ScopeAdmin})
+
+		// a HS256 token signed with the public key must not be accepted
+		_, err = verifier.Verify(signHS256(t, string(der), validClaims()))
+		assert.Error(t, err)
+	})
+
+	t.Run("this test simulate a token verified with a jwks file", func(t *testing.T) {
+		data, _ := json.Marshal(map[string]interface{}{
+			"keys": []map[string]string{{
+				"kid": "key-1",
+				"kty": "RSA",
+				"use": "sig",
+				"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+			}},
+		})
+		path := filepath.Join(dir, "jwks.json")
+		os.WriteFile(path, data, 0o644)
+
+		verifier, err := NewJWTVerifier(JWTOptions{JWKSFile: path, AccountClaim: "tenant"})
+		assert.NoError(t, err)
+
+		claims := validClaims()
+		claims["tenant"] = "account-2"
+		principal, err := verifier.Verify(signRS256(t, key, "key-1", claims))
+		assert.NoError(t, err)
+		assert.Equal(t, principal.AccountId, "account-2")
+
+		_, err = verifier.Verify(signRS256(t, key, "key-2", claims))
+		assert.Error(t, err)
+	})
+
+	t.Run("this test simulate a verifier without keys", func(t *testing.T) {
+		verifier, err := NewJWTVerifier(JWTOptions{})
+
+		assert.NoError(t, err)
+		assert.Nil(t, verifier)
+	})
+
+	t.Run("this test simulate a missing key file", func(t *testing.T) {
+		verifier, err := NewJWTVerifier(JWTOptions{JWKSFile: filepath.Join(dir, "missing.json")})
+
+		assert.Error(t, err)
+		assert.Nil(t, verifier)
+	})
+}
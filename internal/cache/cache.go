@@ -4,41 +4,331 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"expvar"
 	"fmt"
+	"os"
+	"sync"
 	"time"
 
 	"github.com/go-redis/redis/v8"
 	"github.com/sirupsen/logrus"
+	"golang.org/x/sync/singleflight"
 )
 
 //go:generate mockgen -source=$GOFILE -destination=cache_mock.go -package=$GOPACKAGE
 
+const (
+	scanCount = 100
+
+	// invalidationChannel is where the instances publish the keys they change, so the others drop their local copy
+	invalidationChannel = "cache:invalidate"
+
+	defaultLocalSize = 10000
+	defaultLocalTTL  = 10 * time.Second
+)
+
+// Metrics published in /debug/vars under "cache"
+const (
+	metricLocalHits     = "local_hits"
+	metricLocalMisses   = "local_misses"
+	metricRedisHits     = "redis_hits"
+	metricRedisMisses   = "redis_misses"
+	metricErrors        = "errors"
+	metricEvictions     = "evictions"
+	metricInvalidations = "invalidations"
+	metricLocalEntries  = "local_entries"
+)
+
+var metrics = expvar.NewMap("cache")
+
+// Policy is how long the responses of an endpoint are cached, by the service for TTL and by the clients for MaxAge
+type Policy struct {
+	TTL    time.Duration
+	MaxAge time.Duration
+}
+
+// Loader loads the value of a missing key
+type Loader func(ctx context.Context) (interface{}, error)
+
 type Cache interface {
-	Get(ctx context.Context, key string, value interface{})
+	// Get decodes the value of key into value and reports whether the key was found
+	Get(ctx context.Context, key string, value interface{}) (bool, error)
+	// MGet decodes the value of each found key into value(i) in one round trip. On a decode error the key
+	// is reported as not found and the error is returned along the results of the other keys.
+	MGet(ctx context.Context, keys []string, value func(i int) interface{}) ([]bool, error)
 	Set(ctx context.Context, key string, value interface{}, expirationTime time.Duration) error
+	// MSet sets every key of values in one round trip
+	MSet(ctx context.Context, values map[string]interface{}, expirationTime time.Duration) error
+	Delete(ctx context.Context, keys ...string) error
+	// DeleteByPattern deletes the keys matching a glob pattern and returns how many were deleted
+	DeleteByPattern(ctx context.Context, pattern string) (int64, error)
+	// Incr increments the counter of key and returns its new value. The counters never expire.
+	Incr(ctx context.Context, key string) (int64, error)
+	// Counter returns the counter of key, zero when it was never incremented. It is read from Redis,
+	// never from the in-process tier, so an increment is seen at once by every instance.
+	Counter(ctx context.Context, key string) (int64, error)
+	// GetOrSet decodes the value of key into value, loading and setting it when missing.
+	// Concurrent calls for the same key share a single load.
+	GetOrSet(ctx context.Context, key string, value interface{}, expirationTime time.Duration, load Loader) error
+	// Close stops listening for invalidations
+	Close() error
+}
+
+type Options struct {
+	// LocalSize is the max number of entries of the in-process tier, zero disables it.
+	// Without Redis the in-process tier is always enabled.
+	LocalSize int
+	// LocalMaxBytes bounds the size of the values in the in-process tier, zero does not bound it
+	LocalMaxBytes int64
+	// LocalTTL bounds how long an entry is served by the in-process tier, so an instance that
+	// missed an invalidation while disconnected from Redis does not serve a stale value for long
+	LocalTTL time.Duration
+}
+
+// invalidation is published when an instance changes keys
+type invalidation struct {
+	Origin  string   `json:"origin"`
+	Keys    []string `json:"keys,omitempty"`
+	Pattern string   `json:"pattern,omitempty"`
 }
 
 type cacheImpl struct {
-	redis *redis.Client
-	log   logrus.Logger
+	redis  *redis.Client
+	local  *local
+	opts   Options
+	origin string
+	pubsub *redis.PubSub
+	log    logrus.Logger
+	group  singleflight.Group
+
+	// counters are kept in process without Redis, apart from the in-process tier which may evict them
+	mu       sync.Mutex
+	counters map[string]int64
+}
+
+// NewCache creates a cache backed by Redis, with an in-process tier in front of it when LocalSize is set.
+// A nil redis keeps the values only in the in-process tier, for local development.
+func NewCache(redis *redis.Client, opts Options, log logrus.Logger) Cache {
+	if redis == nil && opts.LocalSize <= 0 {
+		opts.LocalSize = defaultLocalSize
+	}
+	if opts.LocalTTL <= 0 {
+		opts.LocalTTL = defaultLocalTTL
+	}
+
+	hostname, _ := os.Hostname()
+	c := &cacheImpl{
+		redis:    redis,
+		opts:     opts,
+		origin:   fmt.Sprintf("%s-%d-%d", hostname, os.Getpid(), time.Now().UnixNano()),
+		log:      log,
+		counters: make(map[string]int64),
+	}
+
+	if opts.LocalSize > 0 {
+		c.local = newLocal(opts.LocalSize, opts.LocalMaxBytes)
+		metrics.Set(metricLocalEntries, expvar.Func(func() interface{} {
+			return c.local.len()
+		}))
+
+		if redis != nil {
+			c.pubsub = redis.Subscribe(context.Background(), invalidationChannel)
+			go c.listen(c.pubsub.Channel())
+		}
+	}
+
+	return c
+}
+
+func (c *cacheImpl) Close() error {
+	if c.pubsub == nil {
+		return nil
+	}
+
+	return c.pubsub.Close()
+}
+
+func (c *cacheImpl) listen(messages <-chan *redis.Message) {
+	for message := range messages {
+		c.invalidate(message.Payload)
+	}
+}
+
+// invalidate drops the local copy of the keys changed by another instance
+func (c *cacheImpl) invalidate(payload string) {
+	var message invalidation
+	if err := json.Unmarshal([]byte(payload), &message); err != nil {
+		c.log.Error(fmt.Errorf("failed to unmarshal cache invalidation: %w", err))
+		return
+	}
+	if message.Origin == c.origin {
+		return
+	}
+
+	metrics.Add(metricInvalidations, 1)
+	c.local.delete(message.Keys...)
+	if len(message.Pattern) > 0 {
+		c.local.deletePattern(message.Pattern)
+	}
+}
+
+// publish tells the other instances to drop their local copy, the instances without
+// an in-process tier do not publish as they share its configuration
+func (c *cacheImpl) publish(ctx context.Context, message invalidation) {
+	if c.local == nil || c.redis == nil {
+		return
+	}
+
+	message.Origin = c.origin
+	b, err := json.Marshal(message)
+	if err != nil {
+		c.log.Error(fmt.Errorf("failed to marshal cache invalidation: %w", err))
+		return
+	}
+
+	if err := c.redis.Publish(ctx, invalidationChannel, b).Err(); err != nil {
+		metrics.Add(metricErrors, 1)
+		c.log.Error(fmt.Errorf("failed to publish cache invalidation: %w", err))
+	}
 }
 
-func NewCache(redis *redis.Client, log logrus.Logger) Cache {
-	return &cacheImpl{
-		redis: redis,
+// localTTL is the lifetime of a value in the in-process tier
+func (c *cacheImpl) localTTL(expirationTime time.Duration) time.Duration {
+	if c.redis == nil || (expirationTime > 0 && expirationTime < c.opts.LocalTTL) {
+		return expirationTime
 	}
+
+	return c.opts.LocalTTL
 }
 
-func (c *cacheImpl) Get(ctx context.Context, key string, value interface{}) {
-	cmd := c.redis.Get(ctx, key)
-	if cmd.Err() != nil && !errors.Is(cmd.Err(), redis.Nil) {
-		c.log.Error(cmd.Err())
+func (c *cacheImpl) getLocal(key string) ([]byte, bool) {
+	if c.local == nil {
+		return nil, false
+	}
+
+	data, ok := c.local.get(key)
+	if ok {
+		metrics.Add(metricLocalHits, 1)
+	} else {
+		metrics.Add(metricLocalMisses, 1)
 	}
 
-	err := json.Unmarshal([]byte(cmd.Val()), &value)
+	return data, ok
+}
+
+func (c *cacheImpl) setLocal(key string, data []byte, expirationTime time.Duration) {
+	if c.local != nil {
+		c.local.set(key, data, c.localTTL(expirationTime))
+	}
+}
+
+func (c *cacheImpl) get(ctx context.Context, key string) ([]byte, bool, error) {
+	if data, ok := c.getLocal(key); ok {
+		return data, true, nil
+	}
+	if c.redis == nil {
+		return nil, false, nil
+	}
+
+	data, err := c.redis.Get(ctx, key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		metrics.Add(metricRedisMisses, 1)
+		return nil, false, nil
+	}
 	if err != nil {
-		c.log.Error(fmt.Errorf("failed to unmarshal value: %w", err))
+		metrics.Add(metricErrors, 1)
+		return nil, false, fmt.Errorf("failed to get cache: %w", err)
 	}
+
+	metrics.Add(metricRedisHits, 1)
+	c.setLocal(key, data, c.opts.LocalTTL)
+	return data, true, nil
+}
+
+func (c *cacheImpl) Get(ctx context.Context, key string, value interface{}) (bool, error) {
+	data, found, err := c.get(ctx, key)
+	if !found || err != nil {
+		return false, err
+	}
+
+	if err := json.Unmarshal(data, value); err != nil {
+		return false, fmt.Errorf("failed to unmarshal value: %w", err)
+	}
+
+	return true, nil
+}
+
+// MGet reads from Redis only the keys missing in the in-process tier
+func (c *cacheImpl) MGet(ctx context.Context, keys []string, value func(i int) interface{}) ([]bool, error) {
+	if len(keys) == 0 {
+		return nil, nil
+	}
+
+	data := make([][]byte, len(keys))
+	var missing []int
+	for i, key := range keys {
+		var ok bool
+		if data[i], ok = c.getLocal(key); !ok {
+			missing = append(missing, i)
+		}
+	}
+
+	if len(missing) > 0 && c.redis != nil {
+		cmds := make([]*redis.StringCmd, len(missing))
+		_, err := c.redis.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+			for j, i := range missing {
+				cmds[j] = pipe.Get(ctx, keys[i])
+			}
+			return nil
+		})
+		if err != nil && !errors.Is(err, redis.Nil) {
+			metrics.Add(metricErrors, 1)
+			return nil, fmt.Errorf("failed to get cache: %w", err)
+		}
+
+		for j, i := range missing {
+			b, err := cmds[j].Bytes()
+			if err != nil {
+				metrics.Add(metricRedisMisses, 1)
+				continue
+			}
+
+			metrics.Add(metricRedisHits, 1)
+			data[i] = b
+			c.setLocal(keys[i], b, c.opts.LocalTTL)
+		}
+	}
+
+	var decodeErr error
+	found := make([]bool, len(keys))
+	for i, b := range data {
+		if b == nil {
+			continue
+		}
+
+		if err := json.Unmarshal(b, value(i)); err != nil {
+			decodeErr = fmt.Errorf("failed to unmarshal value of %s: %w", keys[i], err)
+			continue
+		}
+		found[i] = true
+	}
+
+	return found, decodeErr
+}
+
+func (c *cacheImpl) set(ctx context.Context, key string, data []byte, expirationTime time.Duration) error {
+	if c.redis != nil {
+		cmd := c.redis.Set(ctx, key, data, expirationTime)
+		if cmd.Err() != nil {
+			metrics.Add(metricErrors, 1)
+			return fmt.Errorf("failed to set cache: %w", cmd.Err())
+		}
+	}
+
+	c.setLocal(key, data, expirationTime)
+	c.publish(ctx, invalidation{Keys: []string{key}})
+
+	return nil
 }
 
 func (c *cacheImpl) Set(ctx context.Context, key string, value interface{}, expirationTime time.Duration) error {
@@ -47,9 +337,171 @@ func (c *cacheImpl) Set(ctx context.Context, key string, value interface{}, expi
 		return fmt.Errorf("failed to marshal data: %w", err)
 	}
 
-	cmd := c.redis.Set(ctx, key, b, expirationTime)
-	if cmd.Err() != nil {
-		return fmt.Errorf("failed to set cache: %w", cmd.Err())
+	return c.set(ctx, key, b, expirationTime)
+}
+
+func (c *cacheImpl) MSet(ctx context.Context, values map[string]interface{}, expirationTime time.Duration) error {
+	if len(values) == 0 {
+		return nil
+	}
+
+	data := make(map[string][]byte, len(values))
+	keys := make([]string, 0, len(values))
+	for key, value := range values {
+		b, err := marshalBinary(value)
+		if err != nil {
+			return fmt.Errorf("failed to marshal data of %s: %w", key, err)
+		}
+		data[key] = b
+		keys = append(keys, key)
+	}
+
+	if c.redis != nil {
+		_, err := c.redis.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+			for key, b := range data {
+				pipe.Set(ctx, key, b, expirationTime)
+			}
+			return nil
+		})
+		if err != nil {
+			metrics.Add(metricErrors, 1)
+			return fmt.Errorf("failed to set cache: %w", err)
+		}
+	}
+
+	for key, b := range data {
+		c.setLocal(key, b, expirationTime)
+	}
+	c.publish(ctx, invalidation{Keys: keys})
+
+	return nil
+}
+
+func (c *cacheImpl) Delete(ctx context.Context, keys ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+
+	if c.redis != nil {
+		if err := c.redis.Del(ctx, keys...).Err(); err != nil {
+			metrics.Add(metricErrors, 1)
+			return fmt.Errorf("failed to delete cache: %w", err)
+		}
+	}
+
+	if c.local != nil {
+		c.local.delete(keys...)
+	}
+	c.publish(ctx, invalidation{Keys: keys})
+
+	return nil
+}
+
+// DeleteByPattern scans the keys instead of using KEYS, so it does not block Redis on large databases
+func (c *cacheImpl) DeleteByPattern(ctx context.Context, pattern string) (int64, error) {
+	var deleted int64
+	if c.local != nil {
+		deleted = c.local.deletePattern(pattern)
+	}
+	if c.redis == nil {
+		return deleted, nil
+	}
+	defer c.publish(ctx, invalidation{Pattern: pattern})
+
+	deleted = 0
+	var cursor uint64
+	for {
+		keys, next, err := c.redis.Scan(ctx, cursor, pattern, scanCount).Result()
+		if err != nil {
+			metrics.Add(metricErrors, 1)
+			return deleted, fmt.Errorf("failed to scan cache: %w", err)
+		}
+
+		if len(keys) > 0 {
+			n, err := c.redis.Del(ctx, keys...).Result()
+			if err != nil {
+				metrics.Add(metricErrors, 1)
+				return deleted, fmt.Errorf("failed to delete cache: %w", err)
+			}
+			deleted += n
+		}
+
+		cursor = next
+		if cursor == 0 {
+			return deleted, nil
+		}
+	}
+}
+
+func (c *cacheImpl) Incr(ctx context.Context, key string) (int64, error) {
+	if c.redis == nil {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		c.counters[key]++
+		return c.counters[key], nil
+	}
+
+	value, err := c.redis.Incr(ctx, key).Result()
+	if err != nil {
+		metrics.Add(metricErrors, 1)
+		return 0, fmt.Errorf("failed to increment cache counter: %w", err)
+	}
+
+	return value, nil
+}
+
+func (c *cacheImpl) Counter(ctx context.Context, key string) (int64, error) {
+	if c.redis == nil {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		return c.counters[key], nil
+	}
+
+	value, err := c.redis.Get(ctx, key).Int64()
+	if errors.Is(err, redis.Nil) {
+		return 0, nil
+	}
+	if err != nil {
+		metrics.Add(metricErrors, 1)
+		return 0, fmt.Errorf("failed to get cache counter: %w", err)
+	}
+
+	return value, nil
+}
+
+// GetOrSet falls back to load when the cache is unavailable. The shared load runs with the ctx of the first caller.
+func (c *cacheImpl) GetOrSet(ctx context.Context, key string, value interface{}, expirationTime time.Duration, load Loader) error {
+	found, err := c.Get(ctx, key, value)
+	if err != nil {
+		c.log.Error(err)
+	}
+	if found {
+		return nil
+	}
+
+	data, err, _ := c.group.Do(key, func() (interface{}, error) {
+		loaded, err := load(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		b, err := marshalBinary(loaded)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal data: %w", err)
+		}
+
+		if err := c.set(ctx, key, b, expirationTime); err != nil {
+			c.log.Error(err)
+		}
+
+		return b, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := json.Unmarshal(data.([]byte), value); err != nil {
+		return fmt.Errorf("failed to unmarshal value: %w", err)
 	}
 
 	return nil
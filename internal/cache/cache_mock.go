@@ -35,10 +35,76 @@ func (m *MockCache) EXPECT() *MockCacheMockRecorder {
 	return m.recorder
 }
 
+// Close mocks base method.
+func (m *MockCache) Close() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Close")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Close indicates an expected call of Close.
+func (mr *MockCacheMockRecorder) Close() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockCache)(nil).Close))
+}
+
+// Counter mocks base method.
+func (m *MockCache) Counter(ctx context.Context, key string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Counter", ctx, key)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Counter indicates an expected call of Counter.
+func (mr *MockCacheMockRecorder) Counter(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Counter", reflect.TypeOf((*MockCache)(nil).Counter), ctx, key)
+}
+
+// Delete mocks base method.
+func (m *MockCache) Delete(ctx context.Context, keys ...string) error {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx}
+	for _, a := range keys {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Delete", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockCacheMockRecorder) Delete(ctx interface{}, keys ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx}, keys...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockCache)(nil).Delete), varargs...)
+}
+
+// DeleteByPattern mocks base method.
+func (m *MockCache) DeleteByPattern(ctx context.Context, pattern string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteByPattern", ctx, pattern)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteByPattern indicates an expected call of DeleteByPattern.
+func (mr *MockCacheMockRecorder) DeleteByPattern(ctx, pattern interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteByPattern", reflect.TypeOf((*MockCache)(nil).DeleteByPattern), ctx, pattern)
+}
+
 // Get mocks base method.
-func (m *MockCache) Get(ctx context.Context, key string, value interface{}) {
+func (m *MockCache) Get(ctx context.Context, key string, value interface{}) (bool, error) {
 	m.ctrl.T.Helper()
-	m.ctrl.Call(m, "Get", ctx, key, value)
+	ret := m.ctrl.Call(m, "Get", ctx, key, value)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 // Get indicates an expected call of Get.
@@ -47,6 +113,64 @@ func (mr *MockCacheMockRecorder) Get(ctx, key, value interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockCache)(nil).Get), ctx, key, value)
 }
 
+// GetOrSet mocks base method.
+func (m *MockCache) GetOrSet(ctx context.Context, key string, value interface{}, expirationTime time.Duration, load Loader) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOrSet", ctx, key, value, expirationTime, load)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// GetOrSet indicates an expected call of GetOrSet.
+func (mr *MockCacheMockRecorder) GetOrSet(ctx, key, value, expirationTime, load interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrSet", reflect.TypeOf((*MockCache)(nil).GetOrSet), ctx, key, value, expirationTime, load)
+}
+
+// Incr mocks base method.
+func (m *MockCache) Incr(ctx context.Context, key string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Incr", ctx, key)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Incr indicates an expected call of Incr.
+func (mr *MockCacheMockRecorder) Incr(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Incr", reflect.TypeOf((*MockCache)(nil).Incr), ctx, key)
+}
+
+// MGet mocks base method.
+func (m *MockCache) MGet(ctx context.Context, keys []string, value func(int) interface{}) ([]bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MGet", ctx, keys, value)
+	ret0, _ := ret[0].([]bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MGet indicates an expected call of MGet.
+func (mr *MockCacheMockRecorder) MGet(ctx, keys, value interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MGet", reflect.TypeOf((*MockCache)(nil).MGet), ctx, keys, value)
+}
+
+// MSet mocks base method.
+func (m *MockCache) MSet(ctx context.Context, values map[string]interface{}, expirationTime time.Duration) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MSet", ctx, values, expirationTime)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MSet indicates an expected call of MSet.
+func (mr *MockCacheMockRecorder) MSet(ctx, values, expirationTime interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MSet", reflect.TypeOf((*MockCache)(nil).MSet), ctx, values, expirationTime)
+}
+
 // Set mocks base method.
 func (m *MockCache) Set(ctx context.Context, key string, value interface{}, expirationTime time.Duration) error {
 	m.ctrl.T.Helper()
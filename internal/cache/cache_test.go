@@ -3,7 +3,10 @@ package cache
 import (
 	"context"
 	"errors"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 	"github.com/jcpribeiro/TransactionApp/model"
 
 	"github.com/go-redis/redis/v8"
@@ -20,7 +23,7 @@ type structTest struct {
 
 func setUpTest() structTest {
 	db, mock := redismock.NewClientMock()
-	appTest := NewCache(db, *logrus.New())
+	appTest := NewCache(db, Options{}, *logrus.New())
 
 	return structTest{
 		db:      db,
@@ -31,13 +34,17 @@ func setUpTest() structTest {
 
 func TestGet(t *testing.T) {
 	ctx := context.Background()
-	t.Run("This test simulates an error when retrieving data from the cache", func(t *testing.T) {
+	t.Run("This test simulates a cache miss", func(t *testing.T) {
 		testObj := setUpTest()
 
 		testObj.mock.ExpectGet("test").RedisNil()
 
 		var value *model.TransactionResponse
-		testObj.appTest.Get(ctx, "test", &value)
+		found, err := testObj.appTest.Get(ctx, "test", &value)
+
+		assert.False(t, found)
+		assert.NoError(t, err)
+		assert.Nil(t, value)
 	})
 
 	t.Run("This test simulates an error when retrieving data from the cache", func(t *testing.T) {
@@ -46,18 +53,238 @@ func TestGet(t *testing.T) {
 		testObj.mock.ExpectGet("test").SetErr(errors.New("an erro has ocurred"))
 
 		var value *model.TransactionResponse
-		testObj.appTest.Get(ctx, "test", &value)
+		found, err := testObj.appTest.Get(ctx, "test", &value)
+
+		assert.False(t, found)
+		assert.Error(t, err)
+	})
+
+	t.Run("This test simulates an invalid value in the cache", func(t *testing.T) {
+		testObj := setUpTest()
+
+		testObj.mock.ExpectGet("test").SetVal("{")
+
+		var value *model.TransactionResponse
+		found, err := testObj.appTest.Get(ctx, "test", &value)
+
+		assert.False(t, found)
+		assert.Error(t, err)
 	})
 
 	t.Run("This test simulates retrieving data from the cache", func(t *testing.T) {
 		testObj := setUpTest()
-		expectedValue := "{\"key\":\"test-value\"}"
+		expectedValue := "{\"id\":\"test-value\"}"
 		testObj.mock.ExpectGet("test").SetVal(expectedValue)
 
-		var value interface{}
-		testObj.appTest.Get(ctx, "test", &value)
+		var value *model.TransactionResponse
+		found, err := testObj.appTest.Get(ctx, "test", &value)
 
-		assert.NotNil(t, value, expectedValue)
+		assert.True(t, found)
+		assert.NoError(t, err)
+		assert.Equal(t, value.Id, "test-value")
+	})
+}
+
+func TestMGet(t *testing.T) {
+	ctx := context.Background()
+	t.Run("This test simulates retrieving hits and invalid values from the cache", func(t *testing.T) {
+		testObj := setUpTest()
+
+		testObj.mock.ExpectGet("a").SetVal("{\"id\":\"a\"}")
+		testObj.mock.ExpectGet("b").SetVal("{")
+
+		values := make([]*model.TransactionResponse, 2)
+		found, err := testObj.appTest.MGet(ctx, []string{"a", "b"}, func(i int) interface{} {
+			return &values[i]
+		})
+
+		assert.Error(t, err)
+		assert.Equal(t, found, []bool{true, false})
+		assert.Equal(t, values[0].Id, "a")
+		assert.NoError(t, testObj.mock.ExpectationsWereMet())
+	})
+
+	t.Run("This test simulates a cache miss", func(t *testing.T) {
+		testObj := setUpTest()
+
+		testObj.mock.ExpectGet("a").RedisNil()
+
+		found, err := testObj.appTest.MGet(ctx, []string{"a"}, func(i int) interface{} {
+			return new(interface{})
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, found, []bool{false})
+	})
+
+	t.Run("This test simulates an error when retrieving data from the cache", func(t *testing.T) {
+		testObj := setUpTest()
+
+		testObj.mock.ExpectGet("a").SetErr(errors.New("an erro has ocurred"))
+
+		found, err := testObj.appTest.MGet(ctx, []string{"a"}, func(i int) interface{} {
+			return new(interface{})
+		})
+
+		assert.Nil(t, found)
+		assert.Error(t, err)
+	})
+}
+
+func TestDelete(t *testing.T) {
+	ctx := context.Background()
+	t.Run("This test simulates deleting keys from the cache", func(t *testing.T) {
+		testObj := setUpTest()
+
+		testObj.mock.ExpectDel("a", "b").SetVal(2)
+
+		err := testObj.appTest.Delete(ctx, "a", "b")
+		assert.NoError(t, err)
+	})
+
+	t.Run("This test simulates deleting the keys of a pattern", func(t *testing.T) {
+		testObj := setUpTest()
+
+		testObj.mock.ExpectScan(0, "account:*", scanCount).SetVal([]string{"account:a"}, 10)
+		testObj.mock.ExpectDel("account:a").SetVal(1)
+		testObj.mock.ExpectScan(10, "account:*", scanCount).SetVal([]string{"account:b", "account:c"}, 0)
+		testObj.mock.ExpectDel("account:b", "account:c").SetVal(2)
+
+		deleted, err := testObj.appTest.DeleteByPattern(ctx, "account:*")
+		assert.NoError(t, err)
+		assert.Equal(t, deleted, int64(3))
+	})
+
+	t.Run("This test simulates an error when scanning the cache", func(t *testing.T) {
+		testObj := setUpTest()
+
+		testObj.mock.ExpectScan(0, "account:*", scanCount).SetErr(errors.New("an erro has ocurred"))
+
+		_, err := testObj.appTest.DeleteByPattern(ctx, "account:*")
+		assert.Error(t, err)
+	})
+}
+
+func TestCounter(t *testing.T) {
+	ctx := context.Background()
+	t.Run("This test simulates incrementing and reading a counter", func(t *testing.T) {
+		testObj := setUpTest()
+
+		testObj.mock.ExpectGet("generation").RedisNil()
+		testObj.mock.ExpectIncr("generation").SetVal(1)
+		testObj.mock.ExpectGet("generation").SetVal("1")
+
+		before, err := testObj.appTest.Counter(ctx, "generation")
+		assert.NoError(t, err)
+		assert.Equal(t, before, int64(0))
+
+		value, err := testObj.appTest.Incr(ctx, "generation")
+		assert.NoError(t, err)
+		assert.Equal(t, value, int64(1))
+
+		after, err := testObj.appTest.Counter(ctx, "generation")
+		assert.NoError(t, err)
+		assert.Equal(t, after, int64(1))
+	})
+
+	t.Run("This test simulates a counter kept in process without Redis", func(t *testing.T) {
+		appTest := NewCache(nil, Options{}, *logrus.New())
+
+		appTest.Incr(ctx, "generation")
+		value, err := appTest.Incr(ctx, "generation")
+		assert.NoError(t, err)
+		assert.Equal(t, value, int64(2))
+
+		counter, err := appTest.Counter(ctx, "generation")
+		assert.NoError(t, err)
+		assert.Equal(t, counter, int64(2))
+	})
+
+	t.Run("This test simulates an error when incrementing a counter", func(t *testing.T) {
+		testObj := setUpTest()
+
+		testObj.mock.ExpectIncr("generation").SetErr(errors.New("an erro has ocurred"))
+
+		_, err := testObj.appTest.Incr(ctx, "generation")
+		assert.Error(t, err)
+	})
+}
+
+func TestGetOrSet(t *testing.T) {
+	ctx := context.Background()
+	t.Run("This test simulates a value found in the cache", func(t *testing.T) {
+		testObj := setUpTest()
+
+		testObj.mock.ExpectGet("key").SetVal("\"cached\"")
+
+		var value string
+		err := testObj.appTest.GetOrSet(ctx, "key", &value, time.Minute, func(ctx context.Context) (interface{}, error) {
+			t.Fatal("unexpected load")
+			return nil, nil
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, value, "cached")
+	})
+
+	t.Run("This test simulates loading a missing value", func(t *testing.T) {
+		testObj := setUpTest()
+
+		testObj.mock.ExpectGet("key").RedisNil()
+		testObj.mock.ExpectSet("key", []byte("\"loaded\""), time.Minute).SetVal("OK")
+
+		var value string
+		err := testObj.appTest.GetOrSet(ctx, "key", &value, time.Minute, func(ctx context.Context) (interface{}, error) {
+			return "loaded", nil
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, value, "loaded")
+		assert.NoError(t, testObj.mock.ExpectationsWereMet())
+	})
+
+	t.Run("This test simulates an error when loading a missing value", func(t *testing.T) {
+		testObj := setUpTest()
+
+		testObj.mock.ExpectGet("key").SetErr(errors.New("an erro has ocurred"))
+
+		var value string
+		err := testObj.appTest.GetOrSet(ctx, "key", &value, time.Minute, func(ctx context.Context) (interface{}, error) {
+			return nil, errors.New("an erro has ocurred")
+		})
+
+		assert.Error(t, err)
+	})
+
+	t.Run("This test simulates concurrent loads of the same key", func(t *testing.T) {
+		db, _ := redismock.NewClientMock()
+		cache := &cacheImpl{redis: db, log: *logrus.New()}
+		release := make(chan struct{})
+		var loads int32
+
+		var wg sync.WaitGroup
+		values := make([]string, 5)
+		for i := range values {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				cache.GetOrSet(ctx, "key", &values[i], time.Minute, func(ctx context.Context) (interface{}, error) {
+					atomic.AddInt32(&loads, 1)
+					<-release
+					return "loaded", nil
+				})
+			}(i)
+		}
+
+		assert.Eventually(t, func() bool { return atomic.LoadInt32(&loads) == 1 }, time.Second, time.Millisecond)
+		time.Sleep(20 * time.Millisecond)
+		close(release)
+		wg.Wait()
+
+		assert.Equal(t, atomic.LoadInt32(&loads), int32(1))
+		for _, value := range values {
+			assert.Equal(t, value, "loaded")
+		}
 	})
 }
 
@@ -91,3 +318,25 @@ func TestSet(t *testing.T) {
 		assert.NoError(t, err)
 	})
 }
+
+func TestMSet(t *testing.T) {
+	ctx := context.Background()
+	t.Run("This test simulates setting many keys in the cache", func(t *testing.T) {
+		testObj := setUpTest()
+
+		testObj.mock.ExpectSet("a", []byte("\"value\""), time.Minute).SetVal("OK")
+
+		err := testObj.appTest.MSet(ctx, map[string]interface{}{"a": "value"}, time.Minute)
+		assert.NoError(t, err)
+		assert.NoError(t, testObj.mock.ExpectationsWereMet())
+	})
+
+	t.Run("This test simulates an error when setting many keys in the cache", func(t *testing.T) {
+		testObj := setUpTest()
+
+		testObj.mock.ExpectSet("a", []byte("\"value\""), time.Minute).SetErr(errors.New("an erro has ocurred"))
+
+		err := testObj.appTest.MSet(ctx, map[string]interface{}{"a": "value"}, time.Minute)
+		assert.Error(t, err)
+	})
+}
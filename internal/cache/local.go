@@ -0,0 +1,119 @@
+package cache
+
+import (
+	"container/list"
+	"path"
+	"sync"
+	"time"
+)
+
+type localEntry struct {
+	key       string
+	data      []byte
+	expiresAt time.Time
+}
+
+// local is the in-process tier, a least recently used cache bounded by entries and bytes
+type local struct {
+	mu         sync.Mutex
+	maxEntries int
+	maxBytes   int64
+	bytes      int64
+	entries    *list.List
+	items      map[string]*list.Element
+	now        func() time.Time
+}
+
+func newLocal(maxEntries int, maxBytes int64) *local {
+	return &local{
+		maxEntries: maxEntries,
+		maxBytes:   maxBytes,
+		entries:    list.New(),
+		items:      make(map[string]*list.Element),
+		now:        time.Now,
+	}
+}
+
+func (l *local) get(key string) ([]byte, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	element, ok := l.items[key]
+	if !ok {
+		return nil, false
+	}
+
+	entry := element.Value.(*localEntry)
+	if !entry.expiresAt.IsZero() && !l.now().Before(entry.expiresAt) {
+		l.remove(element)
+		return nil, false
+	}
+
+	l.entries.MoveToFront(element)
+	return entry.data, true
+}
+
+// set stores the value until ttl, a zero ttl keeps it until evicted. A value larger than maxBytes is not stored.
+func (l *local) set(key string, data []byte, ttl time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if element, ok := l.items[key]; ok {
+		l.remove(element)
+	}
+	if l.maxBytes > 0 && int64(len(data)) > l.maxBytes {
+		return
+	}
+
+	entry := &localEntry{key: key, data: data}
+	if ttl > 0 {
+		entry.expiresAt = l.now().Add(ttl)
+	}
+	l.items[key] = l.entries.PushFront(entry)
+	l.bytes += int64(len(data))
+
+	for l.entries.Len() > l.maxEntries || (l.maxBytes > 0 && l.bytes > l.maxBytes) {
+		l.remove(l.entries.Back())
+		metrics.Add(metricEvictions, 1)
+	}
+}
+
+func (l *local) delete(keys ...string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for _, key := range keys {
+		if element, ok := l.items[key]; ok {
+			l.remove(element)
+		}
+	}
+}
+
+// deletePattern deletes the keys matching a glob pattern, with the same syntax as Redis SCAN for * and ?
+func (l *local) deletePattern(pattern string) int64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	var deleted int64
+	for key, element := range l.items {
+		if ok, _ := path.Match(pattern, key); ok {
+			l.remove(element)
+			deleted++
+		}
+	}
+
+	return deleted
+}
+
+func (l *local) len() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.entries.Len()
+}
+
+func (l *local) remove(element *list.Element) {
+	entry := l.entries.Remove(element).(*localEntry)
+	delete(l.items, entry.key)
+	l.bytes -= int64(len(entry.data))
+}
@@ -0,0 +1,127 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-redis/redismock/v8"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLocal(t *testing.T) {
+	t.Run("This test simulates the eviction of the least recently used entry", func(t *testing.T) {
+		l := newLocal(2, 0)
+		l.set("a", []byte("1"), 0)
+		l.set("b", []byte("2"), 0)
+		l.get("a")
+		l.set("c", []byte("3"), 0)
+
+		_, found := l.get("b")
+		assert.False(t, found)
+		_, found = l.get("a")
+		assert.True(t, found)
+		assert.Equal(t, l.len(), 2)
+	})
+
+	t.Run("This test simulates the eviction of entries over the bytes limit", func(t *testing.T) {
+		l := newLocal(10, 4)
+		l.set("a", []byte("12"), 0)
+		l.set("b", []byte("34"), 0)
+		l.set("c", []byte("5"), 0)
+		l.set("d", []byte("too large"), 0)
+
+		_, found := l.get("a")
+		assert.False(t, found)
+		_, found = l.get("d")
+		assert.False(t, found)
+		assert.Equal(t, l.bytes, int64(3))
+	})
+
+	t.Run("This test simulates an expired entry", func(t *testing.T) {
+		now := time.Now()
+		l := newLocal(10, 0)
+		l.now = func() time.Time { return now }
+		l.set("a", []byte("1"), time.Second)
+
+		now = now.Add(time.Second)
+		_, found := l.get("a")
+		assert.False(t, found)
+		assert.Equal(t, l.len(), 0)
+	})
+
+	t.Run("This test simulates deleting the entries of a pattern", func(t *testing.T) {
+		l := newLocal(10, 0)
+		l.set("account:transaction:a", []byte("1"), 0)
+		l.set("account:transaction:b", []byte("2"), 0)
+		l.set("other:transaction:a", []byte("3"), 0)
+
+		assert.Equal(t, l.deletePattern("account:*"), int64(2))
+		assert.Equal(t, l.len(), 1)
+	})
+}
+
+func TestTiered(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("This test simulates a cache without Redis", func(t *testing.T) {
+		appTest := NewCache(nil, Options{}, *logrus.New())
+
+		assert.NoError(t, appTest.Set(ctx, "key", "value", time.Minute))
+
+		var value string
+		found, err := appTest.Get(ctx, "key", &value)
+		assert.True(t, found)
+		assert.NoError(t, err)
+		assert.Equal(t, value, "value")
+
+		assert.NoError(t, appTest.Delete(ctx, "key"))
+		found, _ = appTest.Get(ctx, "key", &value)
+		assert.False(t, found)
+	})
+
+	t.Run("This test simulates a value served by the local tier after a Redis hit", func(t *testing.T) {
+		db, mock := redismock.NewClientMock()
+		appTest := &cacheImpl{redis: db, local: newLocal(10, 0), opts: Options{LocalTTL: time.Minute}, log: *logrus.New()}
+
+		mock.ExpectGet("a").SetVal("\"cached\"")
+
+		values := make([]string, 1)
+		for i := 0; i < 2; i++ {
+			found, err := appTest.MGet(ctx, []string{"a"}, func(i int) interface{} {
+				return &values[i]
+			})
+			assert.NoError(t, err)
+			assert.Equal(t, found, []bool{true})
+		}
+
+		assert.Equal(t, values[0], "cached")
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("This test simulates a set publishing the invalidation of the key", func(t *testing.T) {
+		db, mock := redismock.NewClientMock()
+		appTest := &cacheImpl{redis: db, local: newLocal(10, 0), opts: Options{LocalTTL: time.Minute}, origin: "a", log: *logrus.New()}
+
+		mock.ExpectSet("key", []byte("\"value\""), time.Hour).SetVal("OK")
+		mock.ExpectPublish(invalidationChannel, []byte(`{"origin":"a","keys":["key"]}`)).SetVal(1)
+
+		err := appTest.Set(ctx, "key", "value", time.Hour)
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("This test simulates the invalidations published by the instances", func(t *testing.T) {
+		appTest := &cacheImpl{local: newLocal(10, 0), origin: "a", log: *logrus.New()}
+		appTest.local.set("key", []byte("1"), 0)
+		appTest.local.set("account:key", []byte("2"), 0)
+
+		appTest.invalidate(`{"origin":"a","keys":["key"]}`)
+		assert.Equal(t, appTest.local.len(), 2)
+
+		appTest.invalidate(`{"origin":"b","keys":["key"]}`)
+		appTest.invalidate(`{"origin":"b","pattern":"account:*"}`)
+		assert.Equal(t, appTest.local.len(), 0)
+	})
+}
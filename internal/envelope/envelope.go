@@ -0,0 +1,138 @@
+package envelope
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/jcpribeiro/TransactionApp/model"
+
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	contextKey = "envelope"
+
+	// headers of the ratelimit middleware, which writes its errors through this package
+	headerRateLimit     = "RateLimit-Limit"
+	headerRateRemaining = "RateLimit-Remaining"
+	headerRateReset     = "RateLimit-Reset"
+
+	// CodeRuleViolation is the code of the errors of a broken business rule
+	CodeRuleViolation = "rule_violation"
+)
+
+// Middleware makes the routes of a group write their errors, including the ones of the
+// authentication, rate limit and ErrorHandler, in the envelope
+func Middleware() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			c.Set(contextKey, true)
+			return next(c)
+		}
+	}
+}
+
+// Enabled reports whether the route answers with the envelope
+func Enabled(c echo.Context) bool {
+	enabled, _ := c.Get(contextKey).(bool)
+	return enabled
+}
+
+// Body wraps data in the envelope, adding the rate limit of the route to meta
+func Body(c echo.Context, data interface{}, meta *model.Meta) *model.Envelope {
+	if rateLimit := rateLimitOf(c.Response().Header()); rateLimit != nil {
+		if meta == nil {
+			meta = &model.Meta{}
+		}
+		meta.RateLimit = rateLimit
+	}
+
+	return &model.Envelope{
+		Data: data,
+		Meta: meta,
+	}
+}
+
+// JSON writes data in the envelope
+func JSON(c echo.Context, status int, data interface{}, meta *model.Meta) error {
+	return c.JSON(status, Body(c, data, meta))
+}
+
+// Created writes the created resource in the envelope with 201 and its Location
+func Created(c echo.Context, location string, data interface{}) error {
+	c.Response().Header().Set(echo.HeaderLocation, location)
+	return JSON(c, http.StatusCreated, data, nil)
+}
+
+// NewError returns an error whose code is the status text, e.g. not_found
+func NewError(status int, message string) *model.APIError {
+	return &model.APIError{
+		Code:    strings.ReplaceAll(strings.ToLower(http.StatusText(status)), " ", "_"),
+		Message: message,
+	}
+}
+
+// Errors writes the errors in the envelope
+func Errors(c echo.Context, status int, errs ...*model.APIError) error {
+	body := Body(c, nil, nil)
+	body.Errors = errs
+	return c.JSON(status, body)
+}
+
+// Error writes an error in the envelope in the routes of Middleware, and as {"error": message} in the others
+func Error(c echo.Context, status int, message string) error {
+	if !Enabled(c) {
+		return c.JSON(status, map[string]string{
+			"error": message,
+		})
+	}
+
+	return Errors(c, status, NewError(status, message))
+}
+
+// ErrorHandler writes the errors returned by the routes of Middleware in the envelope,
+// the errors of the other routes are handled by next
+func ErrorHandler(next echo.HTTPErrorHandler) echo.HTTPErrorHandler {
+	return func(err error, c echo.Context) {
+		if c.Response().Committed || !Enabled(c) {
+			next(err, c)
+			return
+		}
+
+		status := http.StatusInternalServerError
+		message := http.StatusText(status)
+		if he, ok := err.(*echo.HTTPError); ok {
+			status = he.Code
+			message = fmt.Sprint(he.Message)
+		} else {
+			c.Logger().Error(err)
+		}
+
+		if c.Request().Method == http.MethodHead {
+			err = c.NoContent(status)
+		} else {
+			err = Errors(c, status, NewError(status, message))
+		}
+		if err != nil {
+			c.Logger().Error(err)
+		}
+	}
+}
+
+// rateLimitOf reads the RateLimit headers set by the rate limit middleware
+func rateLimitOf(header http.Header) *model.RateLimitMeta {
+	limit, err := strconv.ParseInt(header.Get(headerRateLimit), 10, 64)
+	if err != nil {
+		return nil
+	}
+
+	remaining, _ := strconv.ParseInt(header.Get(headerRateRemaining), 10, 64)
+	reset, _ := strconv.ParseInt(header.Get(headerRateReset), 10, 64)
+	return &model.RateLimitMeta{
+		Limit:     limit,
+		Remaining: remaining,
+		Reset:     reset,
+	}
+}
@@ -0,0 +1,108 @@
+package envelope
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jcpribeiro/TransactionApp/model"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestError(t *testing.T) {
+	t.Run("this test simulate an error of a route without the envelope", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		ctx := echo.New().NewContext(httptest.NewRequest(http.MethodGet, "/v1/transaction", nil), rec)
+
+		err := Error(ctx, http.StatusUnauthorized, "unauthorized")
+
+		assert.NoError(t, err)
+		assert.Equal(t, rec.Code, http.StatusUnauthorized)
+		assert.JSONEq(t, rec.Body.String(), `{"error": "unauthorized"}`)
+	})
+
+	t.Run("this test simulate an error of a route with the envelope", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		ctx := echo.New().NewContext(httptest.NewRequest(http.MethodGet, "/v2/transactions", nil), rec)
+		rec.Header().Set(headerRateLimit, "10")
+		rec.Header().Set(headerRateRemaining, "0")
+		rec.Header().Set(headerRateReset, "30")
+
+		err := Middleware()(func(c echo.Context) error {
+			return Error(c, http.StatusTooManyRequests, "rate limit exceeded")
+		})(ctx)
+
+		var resp model.Envelope
+		json.Unmarshal(rec.Body.Bytes(), &resp)
+		assert.NoError(t, err)
+		assert.Equal(t, rec.Code, http.StatusTooManyRequests)
+		assert.Nil(t, resp.Data)
+		assert.Equal(t, resp.Errors, []*model.APIError{{Code: "too_many_requests", Message: "rate limit exceeded"}})
+		assert.Equal(t, resp.Meta.RateLimit, &model.RateLimitMeta{Limit: 10, Remaining: 0, Reset: 30})
+	})
+}
+
+func TestJSON(t *testing.T) {
+	t.Run("this test simulate a response without rate limit", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		ctx := echo.New().NewContext(httptest.NewRequest(http.MethodGet, "/v2/keys", nil), rec)
+
+		err := JSON(ctx, http.StatusOK, []string{"key"}, nil)
+
+		assert.NoError(t, err)
+		assert.JSONEq(t, rec.Body.String(), `{"data": ["key"]}`)
+	})
+
+	t.Run("this test simulate a created response", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		ctx := echo.New().NewContext(httptest.NewRequest(http.MethodPost, "/v2/transactions", nil), rec)
+
+		err := Created(ctx, "/v2/transactions/652d34910a8fc425116b84d9", map[string]string{"id": "652d34910a8fc425116b84d9"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, rec.Code, http.StatusCreated)
+		assert.Equal(t, rec.Header().Get(echo.HeaderLocation), "/v2/transactions/652d34910a8fc425116b84d9")
+		assert.JSONEq(t, rec.Body.String(), `{"data": {"id": "652d34910a8fc425116b84d9"}}`)
+	})
+}
+
+func TestErrorHandler(t *testing.T) {
+	e := echo.New()
+	handler := ErrorHandler(e.DefaultHTTPErrorHandler)
+
+	t.Run("this test simulate an echo error of a route with the envelope", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		ctx := e.NewContext(httptest.NewRequest(http.MethodGet, "/v2/unknown", nil), rec)
+		ctx.Set(contextKey, true)
+
+		handler(echo.ErrNotFound, ctx)
+
+		assert.Equal(t, rec.Code, http.StatusNotFound)
+		assert.JSONEq(t, rec.Body.String(), `{"errors": [{"code": "not_found", "message": "Not Found"}]}`)
+	})
+
+	t.Run("this test simulate an unexpected error of a route with the envelope", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		ctx := e.NewContext(httptest.NewRequest(http.MethodGet, "/v2/transactions", nil), rec)
+		ctx.Set(contextKey, true)
+
+		handler(errors.New("connection refused"), ctx)
+
+		assert.Equal(t, rec.Code, http.StatusInternalServerError)
+		assert.JSONEq(t, rec.Body.String(), `{"errors": [{"code": "internal_server_error", "message": "Internal Server Error"}]}`)
+	})
+
+	t.Run("this test simulate an echo error of a route without the envelope", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		ctx := e.NewContext(httptest.NewRequest(http.MethodGet, "/v1/unknown", nil), rec)
+
+		handler(echo.ErrNotFound, ctx)
+
+		assert.Equal(t, rec.Code, http.StatusNotFound)
+		assert.JSONEq(t, rec.Body.String(), `{"message": "Not Found"}`)
+	})
+}
@@ -0,0 +1,136 @@
+package export
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/jcpribeiro/TransactionApp/model"
+)
+
+const (
+	FormatCSV    = "csv"
+	FormatNDJSON = "ndjson"
+	FormatXLSX   = "xlsx"
+)
+
+var contentTypes = map[string]string{
+	FormatCSV:    "text/csv",
+	FormatNDJSON: "application/x-ndjson",
+	FormatXLSX:   "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+}
+
+var header = []string{
+	"id",
+	"description",
+	"purchase_date",
+	"purchase_amount",
+	"source_currency",
+	"currency",
+	"exchange_rate",
+	"record_date",
+	"source_exchange_rate",
+	"source_record_date",
+	"converted_purchase_amount",
+	"error",
+}
+
+// Writer encodes export rows as they are produced, Close must be called to flush the file
+type Writer interface {
+	Write(row *model.ExportRow) error
+	Close() error
+}
+
+// NewWriter creates a Writer for the given format
+func NewWriter(format string, w io.Writer) (Writer, error) {
+	switch format {
+	case FormatCSV:
+		return newCSVWriter(w)
+	case FormatNDJSON:
+		return newNDJSONWriter(w), nil
+	case FormatXLSX:
+		return newXLSXWriter(w)
+	default:
+		return nil, fmt.Errorf("unsupported export format: %s", format)
+	}
+}
+
+// ContentType returns the mime type of the format
+func ContentType(format string) string {
+	return contentTypes[format]
+}
+
+func formatFloat(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
+
+type csvWriter struct {
+	w *csv.Writer
+}
+
+func newCSVWriter(w io.Writer) (Writer, error) {
+	c := &csvWriter{
+		w: csv.NewWriter(w),
+	}
+
+	if err := c.w.Write(header); err != nil {
+		return nil, fmt.Errorf("failed to write csv header: %w", err)
+	}
+
+	return c, nil
+}
+
+func (c *csvWriter) Write(row *model.ExportRow) error {
+	record := []string{
+		row.Id,
+		row.Description,
+		row.PurchaseDate,
+		formatFloat(row.PurchaseAmount),
+		row.SourceCurrency,
+		row.Currency,
+		"",
+		row.RecordDate,
+		"",
+		row.SourceRecordDate,
+		"",
+		row.Error,
+	}
+	if len(row.Error) == 0 {
+		record[6] = formatFloat(row.ExchangeRate)
+		record[10] = formatFloat(row.ConvertedPurchaseAmount)
+	}
+	if row.SourceExchangeRate > 0 {
+		record[8] = formatFloat(row.SourceExchangeRate)
+	}
+
+	return c.w.Write(record)
+}
+
+func (c *csvWriter) Close() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+type ndjsonWriter struct {
+	buffer  *bufio.Writer
+	encoder *json.Encoder
+}
+
+func newNDJSONWriter(w io.Writer) Writer {
+	buffer := bufio.NewWriter(w)
+	return &ndjsonWriter{
+		buffer:  buffer,
+		encoder: json.NewEncoder(buffer),
+	}
+}
+
+func (n *ndjsonWriter) Write(row *model.ExportRow) error {
+	return n.encoder.Encode(row)
+}
+
+func (n *ndjsonWriter) Close() error {
+	return n.buffer.Flush()
+}
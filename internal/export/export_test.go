@@ -0,0 +1,112 @@
+package export
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/jcpribeiro/TransactionApp/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var rows = []*model.ExportRow{
+	0: {
+		Id:                      "652d34910a8fc425116b84d9",
+		Description:             "Test, \"quoted\" & <tagged>",
+		PurchaseDate:            "2023-10-15",
+		PurchaseAmount:          23.7,
+		SourceCurrency:          "Euro Zone-Euro",
+		Currency:                "Canada-Dollar",
+		ExchangeRate:            1.34,
+		RecordDate:              "2023-09-30",
+		SourceExchangeRate:      0.94,
+		SourceRecordDate:        "2023-09-30",
+		ConvertedPurchaseAmount: 33.79,
+	},
+	1: {
+		Id:             "652d34910a8fc425116b84d8",
+		Description:    "Test2",
+		PurchaseDate:   "2023-10-14",
+		PurchaseAmount: 25,
+		SourceCurrency: "USD",
+		Currency:       "Canada-Dollar",
+		Error:          "failed to get rates exchange",
+	},
+}
+
+func writeRows(t *testing.T, format string) []byte {
+	buffer := &bytes.Buffer{}
+	writer, err := NewWriter(format, buffer)
+	assert.NoError(t, err)
+
+	for _, row := range rows {
+		assert.NoError(t, writer.Write(row))
+	}
+	assert.NoError(t, writer.Close())
+
+	return buffer.Bytes()
+}
+
+func TestCSVWriter(t *testing.T) {
+	t.Run("this test simulate a csv export", func(t *testing.T) {
+		data := writeRows(t, FormatCSV)
+
+		assert.Equal(t, strings.Join([]string{
+			"id,description,purchase_date,purchase_amount,source_currency,currency,exchange_rate,record_date,source_exchange_rate,source_record_date,converted_purchase_amount,error",
+			`652d34910a8fc425116b84d9,"Test, ""quoted"" & <tagged>",2023-10-15,23.7,Euro Zone-Euro,Canada-Dollar,1.34,2023-09-30,0.94,2023-09-30,33.79,`,
+			"652d34910a8fc425116b84d8,Test2,2023-10-14,25,USD,Canada-Dollar,,,,,,failed to get rates exchange",
+			"",
+		}, "\n"), string(data))
+	})
+}
+
+func TestNDJSONWriter(t *testing.T) {
+	t.Run("this test simulate a ndjson export", func(t *testing.T) {
+		data := writeRows(t, FormatNDJSON)
+
+		lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+		assert.Len(t, lines, 2)
+
+		var row model.ExportRow
+		assert.NoError(t, json.Unmarshal([]byte(lines[1]), &row))
+		assert.Equal(t, rows[1], &row)
+	})
+}
+
+func TestXLSXWriter(t *testing.T) {
+	t.Run("this test simulate a xlsx export", func(t *testing.T) {
+		data := writeRows(t, FormatXLSX)
+
+		reader, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+		assert.NoError(t, err)
+
+		files := map[string]string{}
+		for _, f := range reader.File {
+			r, err := f.Open()
+			assert.NoError(t, err)
+			content, _ := io.ReadAll(r)
+			files[f.Name] = string(content)
+		}
+
+		assert.Contains(t, files, "[Content_Types].xml")
+		assert.Contains(t, files, "_rels/.rels")
+		assert.Contains(t, files["xl/workbook.xml"], `<sheet name="Transactions 1" sheetId="1" r:id="rId1"/>`)
+		assert.Contains(t, files["xl/_rels/workbook.xml.rels"], `Target="worksheets/sheet1.xml"`)
+		assert.Contains(t, files["xl/worksheets/sheet1.xml"], "Test, &#34;quoted&#34; &amp; &lt;tagged&gt;")
+		assert.Contains(t, files["xl/worksheets/sheet1.xml"], `<row r="3">`)
+		assert.Contains(t, files["xl/worksheets/sheet1.xml"], "<c><v>33.79</v></c>")
+	})
+}
+
+func TestNewWriter(t *testing.T) {
+	t.Run("this test simulate an unsupported export format", func(t *testing.T) {
+		writer, err := NewWriter("pdf", &bytes.Buffer{})
+
+		assert.Nil(t, writer)
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,183 @@
+package export
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/jcpribeiro/TransactionApp/model"
+)
+
+const (
+	// xlsxMaxRows is the spreadsheet row limit, the header included
+	xlsxMaxRows = 1048576
+
+	xlsxMainNamespace = "http://schemas.openxmlformats.org/spreadsheetml/2006/main"
+	xlsxRelNamespace  = "http://schemas.openxmlformats.org/officeDocument/2006/relationships"
+	xlsxPkgNamespace  = "http://schemas.openxmlformats.org/package/2006/relationships"
+)
+
+// xlsxWriter streams the rows into worksheets, starting a new one when the row limit is reached.
+// The workbook parts that list the worksheets are written on Close, when their number is known.
+type xlsxWriter struct {
+	zip    *zip.Writer
+	sheet  *bufio.Writer
+	sheets int
+	rows   int
+}
+
+func newXLSXWriter(w io.Writer) (Writer, error) {
+	x := &xlsxWriter{
+		zip: zip.NewWriter(w),
+	}
+
+	if err := x.nextSheet(); err != nil {
+		return nil, err
+	}
+
+	return x, nil
+}
+
+func (x *xlsxWriter) nextSheet() error {
+	if err := x.closeSheet(); err != nil {
+		return err
+	}
+
+	x.sheets++
+	part, err := x.zip.Create(fmt.Sprintf("xl/worksheets/sheet%d.xml", x.sheets))
+	if err != nil {
+		return fmt.Errorf("failed to create worksheet: %w", err)
+	}
+
+	x.sheet = bufio.NewWriter(part)
+	x.rows = 0
+	fmt.Fprintf(x.sheet, `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>`+"\n"+`<worksheet xmlns="%s"><sheetData>`, xlsxMainNamespace)
+
+	cells := make([]interface{}, 0, len(header))
+	for _, name := range header {
+		cells = append(cells, name)
+	}
+	return x.writeRow(cells)
+}
+
+func (x *xlsxWriter) closeSheet() error {
+	if x.sheet == nil {
+		return nil
+	}
+
+	x.sheet.WriteString("</sheetData></worksheet>")
+	return x.sheet.Flush()
+}
+
+func (x *xlsxWriter) writeRow(cells []interface{}) error {
+	x.rows++
+	fmt.Fprintf(x.sheet, `<row r="%d">`, x.rows)
+	for _, cell := range cells {
+		switch value := cell.(type) {
+		case float64:
+			fmt.Fprintf(x.sheet, "<c><v>%s</v></c>", formatFloat(value))
+		case string:
+			if len(value) == 0 {
+				x.sheet.WriteString("<c/>")
+				continue
+			}
+			x.sheet.WriteString(`<c t="inlineStr"><is><t xml:space="preserve">`)
+			if err := xml.EscapeText(x.sheet, []byte(value)); err != nil {
+				return err
+			}
+			x.sheet.WriteString("</t></is></c>")
+		}
+	}
+	_, err := x.sheet.WriteString("</row>")
+
+	return err
+}
+
+func (x *xlsxWriter) Write(row *model.ExportRow) error {
+	if x.rows == xlsxMaxRows {
+		if err := x.nextSheet(); err != nil {
+			return err
+		}
+	}
+
+	cells := []interface{}{
+		row.Id,
+		row.Description,
+		row.PurchaseDate,
+		row.PurchaseAmount,
+		row.SourceCurrency,
+		row.Currency,
+		"",
+		row.RecordDate,
+		"",
+		row.SourceRecordDate,
+		"",
+		row.Error,
+	}
+	if len(row.Error) == 0 {
+		cells[6] = row.ExchangeRate
+		cells[10] = row.ConvertedPurchaseAmount
+	}
+	if row.SourceExchangeRate > 0 {
+		cells[8] = row.SourceExchangeRate
+	}
+
+	return x.writeRow(cells)
+}
+
+func (x *xlsxWriter) Close() error {
+	if err := x.closeSheet(); err != nil {
+		return err
+	}
+
+	var sheets, sheetRels, sheetTypes strings.Builder
+	for i := 1; i <= x.sheets; i++ {
+		fmt.Fprintf(&sheets, `<sheet name="Transactions %d" sheetId="%d" r:id="rId%d"/>`, i, i, i)
+		fmt.Fprintf(&sheetRels, `<Relationship Id="rId%d" Type="%s/worksheet" Target="worksheets/sheet%d.xml"/>`, i, xlsxRelNamespace, i)
+		fmt.Fprintf(&sheetTypes, `<Override PartName="/xl/worksheets/sheet%d.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>`, i)
+	}
+
+	parts := []struct {
+		name    string
+		content string
+	}{
+		{
+			name: "xl/workbook.xml",
+			content: fmt.Sprintf(`<workbook xmlns="%s" xmlns:r="%s"><sheets>%s</sheets></workbook>`,
+				xlsxMainNamespace, xlsxRelNamespace, sheets.String()),
+		},
+		{
+			name:    "xl/_rels/workbook.xml.rels",
+			content: fmt.Sprintf(`<Relationships xmlns="%s">%s</Relationships>`, xlsxPkgNamespace, sheetRels.String()),
+		},
+		{
+			name: "_rels/.rels",
+			content: fmt.Sprintf(`<Relationships xmlns="%s"><Relationship Id="rId1" Type="%s/officeDocument" Target="xl/workbook.xml"/></Relationships>`,
+				xlsxPkgNamespace, xlsxRelNamespace),
+		},
+		{
+			name: "[Content_Types].xml",
+			content: `<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+				`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+				`<Default Extension="xml" ContentType="application/xml"/>` +
+				`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+				sheetTypes.String() + `</Types>`,
+		},
+	}
+
+	for _, p := range parts {
+		part, err := x.zip.Create(p.name)
+		if err != nil {
+			return fmt.Errorf("failed to create %s: %w", p.name, err)
+		}
+
+		if _, err := io.WriteString(part, `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>`+"\n"+p.content); err != nil {
+			return fmt.Errorf("failed to write %s: %w", p.name, err)
+		}
+	}
+
+	return x.zip.Close()
+}
@@ -0,0 +1,68 @@
+package httpcache
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	HeaderETag        = "ETag"
+	HeaderIfNoneMatch = "If-None-Match"
+)
+
+// JSON writes body with its ETag, Cache-Control and Last-Modified headers, or only the headers
+// with 304 when the request If-None-Match has the ETag. A zero lastModified omits Last-Modified.
+func JSON(c echo.Context, body interface{}, maxAge time.Duration, lastModified time.Time) error {
+	b, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("failed to marshal response: %w", err)
+	}
+
+	etag := ETag(b)
+	header := c.Response().Header()
+	header.Set(HeaderETag, etag)
+	header.Set(echo.HeaderCacheControl, CacheControl(maxAge))
+	if !lastModified.IsZero() {
+		header.Set(echo.HeaderLastModified, lastModified.UTC().Format(http.TimeFormat))
+	}
+
+	if Match(c.Request().Header.Get(HeaderIfNoneMatch), etag) {
+		return c.NoContent(http.StatusNotModified)
+	}
+
+	return c.JSONBlob(http.StatusOK, b)
+}
+
+// ETag is a strong validator of a response body
+func ETag(body []byte) string {
+	sum := sha256.Sum256(body)
+	return fmt.Sprintf("\"%x\"", sum[:16])
+}
+
+// CacheControl keeps the responses private as they belong to an account. Without maxAge the
+// clients must revalidate with the ETag before reusing a response.
+func CacheControl(maxAge time.Duration) string {
+	if maxAge <= 0 {
+		return "private, no-cache"
+	}
+
+	return fmt.Sprintf("private, max-age=%d", int64(maxAge.Seconds()))
+}
+
+// Match reports whether an If-None-Match header has the etag, using the weak comparison
+func Match(ifNoneMatch, etag string) bool {
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(etag, "W/") {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,55 @@
+package httpcache
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJSON(t *testing.T) {
+	body := map[string]string{"id": "652d34910a8fc425116b84d9"}
+	lastModified := time.Date(2023, 10, 16, 12, 0, 0, 0, time.UTC)
+
+	t.Run("this test simulate a response with cache headers", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		ctx := echo.New().NewContext(httptest.NewRequest(http.MethodGet, "/", nil), rec)
+
+		err := JSON(ctx, body, 5*time.Minute, lastModified)
+
+		assert.NoError(t, err)
+		assert.Equal(t, rec.Code, http.StatusOK)
+		assert.Equal(t, rec.Header().Get(HeaderETag), ETag(rec.Body.Bytes()))
+		assert.Equal(t, rec.Header().Get(echo.HeaderCacheControl), "private, max-age=300")
+		assert.Equal(t, rec.Header().Get(echo.HeaderLastModified), "Mon, 16 Oct 2023 12:00:00 GMT")
+	})
+
+	t.Run("this test simulate a conditional request with the current etag", func(t *testing.T) {
+		first := httptest.NewRecorder()
+		JSON(echo.New().NewContext(httptest.NewRequest(http.MethodGet, "/", nil), first), body, 0, time.Time{})
+
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set(HeaderIfNoneMatch, `"other", W/`+first.Header().Get(HeaderETag))
+		rec := httptest.NewRecorder()
+		err := JSON(echo.New().NewContext(req, rec), body, 0, time.Time{})
+
+		assert.NoError(t, err)
+		assert.Equal(t, rec.Code, http.StatusNotModified)
+		assert.Empty(t, rec.Body.Bytes())
+		assert.Equal(t, rec.Header().Get(echo.HeaderCacheControl), "private, no-cache")
+		assert.Empty(t, rec.Header().Get(echo.HeaderLastModified))
+	})
+
+	t.Run("this test simulate a conditional request with a stale etag", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set(HeaderIfNoneMatch, `"stale"`)
+		rec := httptest.NewRecorder()
+		err := JSON(echo.New().NewContext(req, rec), body, 0, time.Time{})
+
+		assert.NoError(t, err)
+		assert.Equal(t, rec.Code, http.StatusOK)
+	})
+}
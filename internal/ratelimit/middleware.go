@@ -0,0 +1,93 @@
+package ratelimit
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/jcpribeiro/TransactionApp/internal/auth"
+	"github.com/jcpribeiro/TransactionApp/internal/envelope"
+
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+)
+
+// Route classes, each one with its own limit
+const (
+	ClassRead       = "read"
+	ClassWrite      = "write"
+	ClassConversion = "conversion"
+)
+
+const (
+	HeaderLimit      = "RateLimit-Limit"
+	HeaderRemaining  = "RateLimit-Remaining"
+	HeaderReset      = "RateLimit-Reset"
+	HeaderRetryAfter = "Retry-After"
+)
+
+// Policy applies the limit of each route class per client
+type Policy struct {
+	limiter Limiter
+	limits  map[string]Limit
+	log     logrus.Logger
+}
+
+// NewPolicy creates a policy. A nil limiter or a class without limit lets every request through.
+func NewPolicy(limiter Limiter, limits map[string]Limit, log logrus.Logger) *Policy {
+	return &Policy{
+		limiter: limiter,
+		limits:  limits,
+		log:     log,
+	}
+}
+
+// clientKey identifies the client by its API key or token subject, falling back to the IP
+func clientKey(c echo.Context) string {
+	if principal := auth.PrincipalFrom(c); principal != nil && len(principal.Subject) > 0 {
+		return fmt.Sprintf("%s:%s", principal.AccountId, principal.Subject)
+	}
+
+	return "ip:" + c.RealIP()
+}
+
+func seconds(d time.Duration) string {
+	return strconv.FormatInt(int64(math.Ceil(d.Seconds())), 10)
+}
+
+// Middleware limits the requests of a route class. It must run after the authentication,
+// and lets the requests through when the limiter is not available.
+func (p *Policy) Middleware(class string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		if p == nil || p.limiter == nil {
+			return next
+		}
+		limit, ok := p.limits[class]
+		if !ok || limit.Requests <= 0 || limit.Window <= 0 {
+			return next
+		}
+
+		return func(c echo.Context) error {
+			key := fmt.Sprintf("ratelimit:%s:%s", class, clientKey(c))
+			result, err := p.limiter.Allow(c.Request().Context(), key, limit)
+			if err != nil {
+				p.log.Error(fmt.Errorf("rate limit is not applied: %w", err))
+				return next(c)
+			}
+
+			header := c.Response().Header()
+			header.Set(HeaderLimit, strconv.FormatInt(result.Limit, 10))
+			header.Set(HeaderRemaining, strconv.FormatInt(result.Remaining, 10))
+			header.Set(HeaderReset, seconds(result.Reset))
+
+			if !result.Allowed {
+				header.Set(HeaderRetryAfter, seconds(result.Reset))
+				return envelope.Error(c, http.StatusTooManyRequests, "rate limit exceeded")
+			}
+
+			return next(c)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package ratelimit
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/jcpribeiro/TransactionApp/internal/auth"
+
+	"github.com/golang/mock/gomock"
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func serve(policy *Policy, class string, principal *auth.Principal) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, "/v1/transaction/period", nil)
+	req.Header.Set(echo.HeaderXRealIP, "10.0.0.1")
+	rec := httptest.NewRecorder()
+	ctx := echo.New().NewContext(req, rec)
+	if principal != nil {
+		auth.SetPrincipal(ctx, principal)
+	}
+
+	handler := policy.Middleware(class)(func(c echo.Context) error {
+		return c.NoContent(http.StatusOK)
+	})
+	if err := handler(ctx); err != nil {
+		ctx.Error(err)
+	}
+
+	return rec
+}
+
+func TestMiddleware(t *testing.T) {
+	limit := Limit{Requests: 10, Window: time.Minute}
+
+	t.Run("this test simulate a request under the limit", func(t *testing.T) {
+		limiter := NewMockLimiter(gomock.NewController(t))
+		limiter.EXPECT().Allow(gomock.Any(), "ratelimit:conversion:account-1:key-1", limit).
+			Return(&Result{Allowed: true, Limit: 10, Remaining: 9, Reset: 1500 * time.Millisecond}, nil)
+		policy := NewPolicy(limiter, map[string]Limit{ClassConversion: limit}, *logrus.New())
+
+		rec := serve(policy, ClassConversion, &auth.Principal{AccountId: "account-1", Subject: "key-1"})
+
+		assert.Equal(t, rec.Code, http.StatusOK)
+		assert.Equal(t, rec.Header().Get(HeaderLimit), "10")
+		assert.Equal(t, rec.Header().Get(HeaderRemaining), "9")
+		assert.Equal(t, rec.Header().Get(HeaderReset), "2")
+		assert.Empty(t, rec.Header().Get(HeaderRetryAfter))
+	})
+
+	t.Run("this test simulate a request over the limit", func(t *testing.T) {
+		limiter := NewMockLimiter(gomock.NewController(t))
+		limiter.EXPECT().Allow(gomock.Any(), "ratelimit:read:ip:10.0.0.1", limit).
+			Return(&Result{Allowed: false, Limit: 10, Remaining: 0, Reset: 30 * time.Second}, nil)
+		policy := NewPolicy(limiter, map[string]Limit{ClassRead: limit}, *logrus.New())
+
+		rec := serve(policy, ClassRead, nil)
+
+		assert.Equal(t, rec.Code, http.StatusTooManyRequests)
+		assert.Equal(t, rec.Header().Get(HeaderRemaining), "0")
+		assert.Equal(t, rec.Header().Get(HeaderRetryAfter), "30")
+	})
+
+	t.Run("this test simulate the limiter being unavailable", func(t *testing.T) {
+		limiter := NewMockLimiter(gomock.NewController(t))
+		limiter.EXPECT().Allow(gomock.Any(), gomock.Any(), limit).Return(nil, errors.New("an error has ocurred"))
+		policy := NewPolicy(limiter, map[string]Limit{ClassWrite: limit}, *logrus.New())
+
+		rec := serve(policy, ClassWrite, nil)
+
+		assert.Equal(t, rec.Code, http.StatusOK)
+		assert.Empty(t, rec.Header().Get(HeaderLimit))
+	})
+
+	t.Run("this test simulate a route class without limit", func(t *testing.T) {
+		limiter := NewMockLimiter(gomock.NewController(t))
+		policy := NewPolicy(limiter, map[string]Limit{}, *logrus.New())
+
+		assert.Equal(t, serve(policy, ClassWrite, nil).Code, http.StatusOK)
+		assert.Equal(t, serve(nil, ClassWrite, nil).Code, http.StatusOK)
+	})
+}
@@ -0,0 +1,95 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+//go:generate mockgen -source=$GOFILE -destination=ratelimit_mock.go -package=$GOPACKAGE
+
+// Limit allows Requests per client in any Window
+type Limit struct {
+	Requests int64
+	Window   time.Duration
+}
+
+// Result is the state of the client window after a request
+type Result struct {
+	Allowed   bool
+	Limit     int64
+	Remaining int64
+	// Reset is the time until the oldest request leaves the window and a request is allowed again
+	Reset time.Duration
+}
+
+type Limiter interface {
+	Allow(ctx context.Context, key string, limit Limit) (*Result, error)
+}
+
+// slidingWindow keeps the timestamps of the requests in the window in a sorted set.
+// A denied request is not added, so a client over the limit recovers once its window slides.
+var slidingWindow = redis.NewScript(`
+local now = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+local limit = tonumber(ARGV[3])
+
+redis.call('ZREMRANGEBYSCORE', KEYS[1], '-inf', now - window)
+local count = redis.call('ZCARD', KEYS[1])
+local allowed = 0
+if count < limit then
+	redis.call('ZADD', KEYS[1], now, ARGV[4])
+	count = count + 1
+	allowed = 1
+end
+redis.call('PEXPIRE', KEYS[1], math.ceil(window / 1000))
+
+local reset = window
+local oldest = redis.call('ZRANGE', KEYS[1], 0, 0, 'WITHSCORES')
+if oldest[2] then
+	reset = tonumber(oldest[2]) + window - now
+end
+
+return {allowed, count, reset}
+`)
+
+type redisLimiter struct {
+	redis *redis.Client
+	now   func() time.Time
+}
+
+// NewRedisLimiter creates a sliding window limiter shared by every instance using the same Redis
+func NewRedisLimiter(redis *redis.Client) Limiter {
+	return &redisLimiter{
+		redis: redis,
+		now:   time.Now,
+	}
+}
+
+func (l *redisLimiter) Allow(ctx context.Context, key string, limit Limit) (*Result, error) {
+	now := l.now().UnixMicro()
+	member := fmt.Sprintf("%d-%d", now, rand.Int63())
+
+	values, err := slidingWindow.Run(ctx, l.redis, []string{key}, now, limit.Window.Microseconds(), limit.Requests, member).Int64Slice()
+	if err != nil {
+		return nil, fmt.Errorf("failed to run rate limit script: %w", err)
+	}
+	if len(values) != 3 {
+		return nil, fmt.Errorf("unexpected rate limit script result: %v", values)
+	}
+
+	remaining := limit.Requests - values[1]
+	if remaining < 0 {
+		remaining = 0
+	}
+
+	return &Result{
+		Allowed:   values[0] == 1,
+		Limit:     limit.Requests,
+		Remaining: remaining,
+		Reset:     time.Duration(values[2]) * time.Microsecond,
+	}, nil
+}
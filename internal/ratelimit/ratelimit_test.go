@@ -0,0 +1,85 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-redis/redismock/v8"
+	"github.com/stretchr/testify/assert"
+)
+
+type structTest struct {
+	mock    redismock.ClientMock
+	appTest *redisLimiter
+}
+
+func setUpTest() structTest {
+	db, mock := redismock.NewClientMock()
+
+	return structTest{
+		mock: mock,
+		appTest: &redisLimiter{
+			redis: db,
+			now: func() time.Time {
+				return time.UnixMicro(1_000_000)
+			},
+		},
+	}
+}
+
+// ignoreMember matches the script call without the random member argument
+func ignoreMember(expected, actual []interface{}) error {
+	if len(expected) != len(actual) {
+		return errors.New("unexpected args")
+	}
+	for i := range expected[:len(expected)-1] {
+		if expected[i] != actual[i] {
+			return errors.New("unexpected args")
+		}
+	}
+
+	return nil
+}
+
+func TestAllow(t *testing.T) {
+	ctx := context.Background()
+	limit := Limit{Requests: 2, Window: time.Minute}
+
+	t.Run("this test simulate a request under the limit", func(t *testing.T) {
+		testObj := setUpTest()
+		testObj.mock.CustomMatch(ignoreMember).
+			ExpectEvalSha(slidingWindow.Hash(), []string{"key"}, int64(1_000_000), int64(60_000_000), int64(2), "").
+			SetVal([]interface{}{int64(1), int64(1), int64(60_000_000)})
+
+		result, err := testObj.appTest.Allow(ctx, "key", limit)
+
+		assert.NoError(t, err)
+		assert.Equal(t, result, &Result{Allowed: true, Limit: 2, Remaining: 1, Reset: time.Minute})
+	})
+
+	t.Run("this test simulate a request over the limit", func(t *testing.T) {
+		testObj := setUpTest()
+		testObj.mock.CustomMatch(ignoreMember).
+			ExpectEvalSha(slidingWindow.Hash(), []string{"key"}, int64(1_000_000), int64(60_000_000), int64(2), "").
+			SetVal([]interface{}{int64(0), int64(2), int64(30_000_000)})
+
+		result, err := testObj.appTest.Allow(ctx, "key", limit)
+
+		assert.NoError(t, err)
+		assert.Equal(t, result, &Result{Allowed: false, Limit: 2, Remaining: 0, Reset: 30 * time.Second})
+	})
+
+	t.Run("this test simulate an error running the script", func(t *testing.T) {
+		testObj := setUpTest()
+		testObj.mock.CustomMatch(ignoreMember).
+			ExpectEvalSha(slidingWindow.Hash(), []string{"key"}, int64(1_000_000), int64(60_000_000), int64(2), "").
+			SetErr(errors.New("an error has ocurred"))
+
+		result, err := testObj.appTest.Allow(ctx, "key", limit)
+
+		assert.Nil(t, result)
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,52 @@
+package tenant
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/jcpribeiro/TransactionApp/internal/envelope"
+
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	contextKey = "account_id"
+)
+
+// ErrMissingAccount is returned when a query or a write is not scoped to an account
+var ErrMissingAccount = errors.New("missing account")
+
+// Resolver returns the account of an authenticated request, or an empty string
+// when the request carries no valid credentials
+type Resolver func(c echo.Context) (string, error)
+
+// Middleware rejects the requests that are not resolved to an account and
+// stores the account in the echo context for the handlers
+func Middleware(resolve Resolver) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			accountId, err := resolve(c)
+			if err != nil {
+				return err
+			}
+
+			if len(accountId) == 0 {
+				return envelope.Error(c, http.StatusUnauthorized, "unauthorized")
+			}
+
+			SetAccount(c, accountId)
+			return next(c)
+		}
+	}
+}
+
+// SetAccount stores the account of the request
+func SetAccount(c echo.Context, accountId string) {
+	c.Set(contextKey, accountId)
+}
+
+// Account returns the account of the request, or an empty string when it was not resolved
+func Account(c echo.Context) string {
+	accountId, _ := c.Get(contextKey).(string)
+	return accountId
+}
@@ -0,0 +1,72 @@
+package tenant
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMiddleware(t *testing.T) {
+	resolve := func(c echo.Context) (string, error) {
+		return map[string]string{
+			"key-1": "account-1",
+			"key-2": "account-2",
+		}[c.Request().Header.Get("X-API-Key")], nil
+	}
+	next := func(c echo.Context) error {
+		return c.String(http.StatusOK, Account(c))
+	}
+
+	t.Run("this test simulate a request with a valid api key", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/v1/transaction", nil)
+		req.Header.Set("X-API-Key", "key-2")
+		rec := httptest.NewRecorder()
+		ctx := echo.New().NewContext(req, rec)
+
+		err := Middleware(resolve)(next)(ctx)
+
+		assert.NoError(t, err)
+		assert.Equal(t, rec.Code, http.StatusOK)
+		assert.Equal(t, rec.Body.String(), "account-2")
+	})
+
+	t.Run("this test simulate a request without an api key", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/v1/transaction", nil)
+		rec := httptest.NewRecorder()
+		ctx := echo.New().NewContext(req, rec)
+
+		err := Middleware(resolve)(next)(ctx)
+
+		assert.NoError(t, err)
+		assert.Equal(t, rec.Code, http.StatusUnauthorized)
+	})
+
+	t.Run("this test simulate a request with an unknown api key", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/v1/transaction", nil)
+		req.Header.Set("X-API-Key", "key-3")
+		rec := httptest.NewRecorder()
+		ctx := echo.New().NewContext(req, rec)
+
+		err := Middleware(resolve)(next)(ctx)
+
+		assert.NoError(t, err)
+		assert.Equal(t, rec.Code, http.StatusUnauthorized)
+	})
+
+	t.Run("this test simulate an error resolving the account", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/v1/transaction", nil)
+		rec := httptest.NewRecorder()
+		ctx := echo.New().NewContext(req, rec)
+
+		err := Middleware(func(c echo.Context) (string, error) {
+			return "", errors.New("an error has ocurred")
+		})(next)(ctx)
+
+		assert.Error(t, err)
+		assert.Empty(t, Account(ctx))
+	})
+}
@@ -4,6 +4,7 @@ import (
 	"os"
 	"os/signal"
 	"syscall"
+	_ "time/tzdata"
 	"github.com/jcpribeiro/TransactionApp/config"
 	"github.com/jcpribeiro/TransactionApp/server"
 
@@ -22,6 +23,15 @@ func loadConfig(path string) error {
 	return viper.Unmarshal(&config.GlobalConfig)
 }
 
+// @securityDefinitions.apikey ApiKeyAuth
+// @in header
+// @name X-API-Key
+// @description API key created through POST /v1/keys
+
+// @securityDefinitions.apikey BearerAuth
+// @in header
+// @name Authorization
+// @description JWT bearer token with the account_id claim and the scope claim. E.g. Bearer eyJhbGciOi...
 func main() {
 	if os.Getenv("APP_ENV") == "prod"{
 		err := loadConfig("./config_prod.json")
@@ -0,0 +1,27 @@
+package model
+
+type APIKey struct {
+	Id        string   `json:"id" bson:"_id,omitempty"`
+	AccountId string   `json:"account_id" bson:"account_id"`
+	Name      string   `json:"name" bson:"name"`
+	Prefix    string   `json:"prefix" bson:"prefix"`
+	Hash      string   `json:"-" bson:"hash"`
+	Scopes    []string `json:"scopes" bson:"scopes"`
+	CreatedAt int64    `json:"created_at" bson:"created_at"`
+	RevokedAt int64    `json:"revoked_at,omitempty" bson:"revoked_at,omitempty"`
+}
+
+// CreatedAPIKey carries the key itself, which is returned only once when it is created
+type CreatedAPIKey struct {
+	*APIKey
+	Key string `json:"key"`
+}
+
+type CreateAPIKeyParams struct {
+	Name   string   `json:"name" validate:"required"`
+	Scopes []string `json:"scopes" validate:"required,min=1,dive,oneof=transaction:read transaction:write admin"`
+}
+
+type APIKeyParams struct {
+	Id string `param:"id" validate:"required"`
+}
@@ -0,0 +1,26 @@
+package model
+
+// ConversionRequest is an amount of the source currency, USD by default, to convert to currency on a date
+type ConversionRequest struct {
+	Amount         float64 `json:"amount" validate:"required,gt=0"`
+	SourceCurrency string  `json:"source_currency,omitempty" validate:"omitempty,max=100"`
+	Date           string  `json:"date" validate:"required,datetime=2006-01-02"`
+	Currency       string  `json:"currency" validate:"required,max=100"`
+}
+
+type ConvertAmountsParams struct {
+	Conversions []*ConversionRequest `json:"conversions" validate:"required,min=1,max=100,dive,required"`
+}
+
+// ConversionResult is a converted amount, a batch has one result per request in the same order
+type ConversionResult struct {
+	Amount         float64 `json:"amount"`
+	SourceCurrency string  `json:"source_currency"`
+	Date           string  `json:"date"`
+	Currency       string  `json:"currency"`
+	*Conversion
+}
+
+type ConvertAmountsResponse struct {
+	Conversions []*ConversionResult `json:"conversions"`
+}
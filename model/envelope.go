@@ -0,0 +1,35 @@
+package model
+
+// Envelope is the body of every v2 response. Data is absent when the request failed, Errors
+// is absent when it succeeded.
+type Envelope struct {
+	Data   interface{} `json:"data,omitempty"`
+	Meta   *Meta       `json:"meta,omitempty"`
+	Errors []*APIError `json:"errors,omitempty"`
+}
+
+type Meta struct {
+	Page      *PageMeta      `json:"page,omitempty"`
+	RateLimit *RateLimitMeta `json:"rate_limit,omitempty"`
+}
+
+type PageMeta struct {
+	Page     int64 `json:"page"`
+	PageSize int64 `json:"page_size"`
+	// Count is the number of items in the page, a page not full is the last one
+	Count int `json:"count"`
+}
+
+// RateLimitMeta mirrors the RateLimit headers of the route class, Reset is in seconds
+type RateLimitMeta struct {
+	Limit     int64 `json:"limit"`
+	Remaining int64 `json:"remaining"`
+	Reset     int64 `json:"reset"`
+}
+
+type APIError struct {
+	// Code is the snake cased status text, or rule_violation when a business rule failed
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	Rule    string `json:"rule,omitempty"`
+}
@@ -0,0 +1,35 @@
+package model
+
+const (
+	JobStatusPending   = "pending"
+	JobStatusRunning   = "running"
+	JobStatusSucceeded = "succeeded"
+	JobStatusFailed    = "failed"
+	JobStatusCanceled  = "canceled"
+)
+
+type Job struct {
+	Id              string            `json:"id" bson:"_id,omitempty"`
+	AccountId       string            `json:"-" bson:"account_id"`
+	Type            string            `json:"type" bson:"type"`
+	Status          string            `json:"status" bson:"status"`
+	Params          map[string]string `json:"params,omitempty" bson:"params,omitempty"`
+	Progress        int64             `json:"progress" bson:"progress"`
+	Total           int64             `json:"total,omitempty" bson:"total,omitempty"`
+	Result          string            `json:"result,omitempty" bson:"result,omitempty"`
+	Error           string            `json:"error,omitempty" bson:"error,omitempty"`
+	CancelRequested bool              `json:"cancel_requested,omitempty" bson:"cancel_requested,omitempty"`
+	Owner           string            `json:"-" bson:"owner,omitempty"`
+	LockedUntil     int64             `json:"-" bson:"locked_until,omitempty"`
+	CreatedAt       int64             `json:"created_at" bson:"created_at"`
+	UpdatedAt       int64             `json:"updated_at" bson:"updated_at"`
+}
+
+type SubmitJobParams struct {
+	Type   string            `json:"type" validate:"required"`
+	Params map[string]string `json:"params"`
+}
+
+type JobParams struct {
+	Id string `param:"id" validate:"required"`
+}
@@ -0,0 +1,9 @@
+package model
+
+type Migration struct {
+	Id          string `bson:"_id"`
+	StartedAt   int64  `bson:"started_at,omitempty"`
+	LockedUntil int64  `bson:"locked_until,omitempty"`
+	FinishedAt  int64  `bson:"finished_at,omitempty"`
+	Updated     int64  `bson:"updated,omitempty"`
+}
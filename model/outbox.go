@@ -0,0 +1,28 @@
+package model
+
+import "encoding/json"
+
+const (
+	EventTransactionCreated = "transaction.created"
+
+	OutboxStatusPending = "pending"
+	OutboxStatusRunning = "running"
+)
+
+// OutboxEvent is an event stored with the change that caused it and published by the relay.
+// Its id is kept by every sink, so the consumers can drop the events published more than once.
+type OutboxEvent struct {
+	Id            string          `json:"id" bson:"_id,omitempty"`
+	AccountId     string          `json:"account_id" bson:"account_id"`
+	Type          string          `json:"type" bson:"type"`
+	AggregateId   string          `json:"aggregate_id" bson:"aggregate_id"`
+	Payload       json.RawMessage `json:"payload" bson:"payload"`
+	Status        string          `json:"-" bson:"status"`
+	Attempts      int             `json:"-" bson:"attempts"`
+	NextAttemptAt int64           `json:"-" bson:"next_attempt_at"`
+	Error         string          `json:"-" bson:"error,omitempty"`
+	Owner         string          `json:"-" bson:"owner,omitempty"`
+	LockedUntil   int64           `json:"-" bson:"locked_until,omitempty"`
+	CreatedAt     int64           `json:"created_at" bson:"created_at"`
+	UpdatedAt     int64           `json:"-" bson:"updated_at"`
+}
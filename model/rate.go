@@ -0,0 +1,34 @@
+package model
+
+// GetRateParams reads the rate chosen for date, or the series recorded between startDate and endDate
+type GetRateParams struct {
+	Currency  string `query:"currency" validate:"required,max=100"`
+	Date      string `query:"date" validate:"omitempty,datetime=2006-01-02"`
+	StartDate string `query:"startDate" validate:"omitempty,datetime=2006-01-02"`
+	EndDate   string `query:"endDate" validate:"omitempty,datetime=2006-01-02"`
+}
+
+// IsSeries reports whether the params ask for the series between two dates
+func (p *GetRateParams) IsSeries() bool {
+	return len(p.StartDate) > 0 || len(p.EndDate) > 0
+}
+
+// Rate is a Treasury rate, the amount of currency for one USD
+type Rate struct {
+	ExchangeRate float64 `json:"exchange_rate"`
+	RecordDate   string  `json:"record_date"`
+}
+
+// RateResponse is the rate a conversion on date uses, the latest one up to 6 months before it
+type RateResponse struct {
+	Currency string `json:"currency"`
+	Date     string `json:"date"`
+	Rate
+}
+
+type RateSeriesResponse struct {
+	Currency  string  `json:"currency"`
+	StartDate string  `json:"start_date"`
+	EndDate   string  `json:"end_date"`
+	Rates     []*Rate `json:"rates"`
+}
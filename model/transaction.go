@@ -1,21 +1,163 @@
 package model
 
+import "time"
+
+const (
+	// CurrencyUSD is the currency the exchange rates are quoted against and the default source currency
+	CurrencyUSD = "USD"
+
+	// DateLayout is the layout of purchase_date and of the date query params
+	DateLayout = "2006-01-02"
+
+	// PeriodFieldPurchase filters a period on the purchase time, it is the default
+	PeriodFieldPurchase = "purchase"
+	// PeriodFieldCreated filters a period on the time the transaction was stored
+	PeriodFieldCreated = "created"
+)
+
+// Transaction is a purchase as sent by the client. Its stored form, with the times as BSON dates,
+// belongs to the store.
 type Transaction struct {
-	Id             string  `json:"-" bson:"_id,omitempty"`
-	PurchaseAmount float64 `json:"purchase_amount,omitempty" bson:"purchase_amount,omitempty" validate:"required"`
-	Description    string  `json:"description,omitempty" bson:"description,omitempty" validate:"required"`
-	CreatedAt      int64   `json:"-" bson:"created_at,omitempty"`
-	PurchaseDate   string  `json:"purchase_date" bson:"purchase_date,omitempty"`
+	Id             string            `json:"-"`
+	AccountId      string            `json:"-"`
+	SourceId       string            `json:"-"`
+	PurchaseAmount float64           `json:"purchase_amount,omitempty" validate:"required"`
+	SourceCurrency string            `json:"source_currency,omitempty" validate:"omitempty,max=100"`
+	Description    string            `json:"description,omitempty" validate:"required,max=50"`
+	PurchaseDate   string            `json:"purchase_date" validate:"omitempty,datetime=2006-01-02"`
+	PurchasedAt    string            `json:"purchased_at,omitempty" validate:"omitempty,datetime=2006-01-02T15:04:05Z07:00"`
+	Category       string            `json:"category,omitempty" validate:"omitempty,max=50"`
+	Tags           []string          `json:"tags,omitempty" validate:"omitempty,max=20,dive,required,max=30"`
+	Merchant       string            `json:"merchant,omitempty" validate:"omitempty,max=100"`
+	Metadata       map[string]string `json:"metadata,omitempty" validate:"omitempty,max=20,dive,keys,required,max=40,excludesall=.$,endkeys,max=200"`
 }
 
+// TransactionResponse is a stored transaction. CreatedAt is when it was stored, in UTC.
 type TransactionResponse struct {
-	Id                      string  `json:"id,omitempty" bson:"_id,omitempty"`
-	PurchaseAmount          float64 `json:"purchase_amount,omitempty" bson:"purchase_amount,omitempty" validate:"required"`
-	Description             string  `json:"description,omitempty" bson:"description,omitempty" validate:"required"`
-	CreatedAt               int64   `json:"-" bson:"created_at,omitempty"`
-	PurchaseDate            string  `json:"purchase_date" bson:"purchase_date,omitempty"`
-	ExchangeRate            float64 `json:"exchange_rate" bson:"-"`
-	ConvertedPurchaseAmount float64 `json:"converted_purchase_amount" bson:"-"`
+	Id                      string                 `json:"id,omitempty"`
+	AccountId               string                 `json:"-"`
+	PurchaseAmount          float64                `json:"purchase_amount,omitempty"`
+	SourceCurrency          string                 `json:"source_currency,omitempty"`
+	Description             string                 `json:"description,omitempty"`
+	CreatedAt               string                 `json:"created_at,omitempty"`
+	PurchaseDate            string                 `json:"purchase_date"`
+	PurchasedAt             string                 `json:"purchased_at,omitempty"`
+	Category                string                 `json:"category,omitempty"`
+	Tags                    []string               `json:"tags,omitempty"`
+	Merchant                string                 `json:"merchant,omitempty"`
+	Metadata                map[string]string      `json:"metadata,omitempty"`
+	ExchangeRate            float64                `json:"exchange_rate,omitempty"`
+	RecordDate              string                 `json:"record_date,omitempty"`
+	SourceExchangeRate      float64                `json:"source_exchange_rate,omitempty"`
+	SourceRecordDate        string                 `json:"source_record_date,omitempty"`
+	ConvertedPurchaseAmount float64                `json:"converted_purchase_amount,omitempty"`
+	Conversions             map[string]*Conversion `json:"conversions,omitempty"`
+}
+
+// GetTransactionsResponse keeps the order of the requested ids and lists the ids that do not exist
+type GetTransactionsResponse struct {
+	Ids      []*TransactionResponse `json:"ids"`
+	NotFound []string               `json:"not_found,omitempty"`
+}
+
+// Conversion of a purchase amount. A source currency other than USD is converted to USD first,
+// ExchangeRate and RecordDate are the USD to target leg and the Source fields the source to USD leg.
+type Conversion struct {
+	ExchangeRate            float64 `json:"exchange_rate,omitempty"`
+	RecordDate              string  `json:"record_date,omitempty"`
+	SourceExchangeRate      float64 `json:"source_exchange_rate,omitempty"`
+	SourceRecordDate        string  `json:"source_record_date,omitempty"`
+	ConvertedPurchaseAmount float64 `json:"converted_purchase_amount,omitempty"`
+	Error                   string  `json:"error,omitempty"`
+}
+
+type TransactionAggregate struct {
+	Period         string  `bson:"period"`
+	PurchaseDate   string  `bson:"purchase_date"`
+	SourceCurrency string  `bson:"source_currency"`
+	Count          int64   `bson:"count"`
+	Sum            float64 `bson:"sum"`
+	Min            float64 `bson:"min"`
+	Max            float64 `bson:"max"`
+}
+
+type TransactionSummary struct {
+	Period    string        `json:"period,omitempty"`
+	Currency  string        `json:"currency"`
+	USD       SummaryValues `json:"usd"`
+	Converted SummaryValues `json:"converted"`
+}
+
+type SummaryValues struct {
+	Count   int64   `json:"count"`
+	Sum     float64 `json:"sum"`
+	Min     float64 `json:"min"`
+	Max     float64 `json:"max"`
+	Average float64 `json:"average"`
+}
+
+// ImportReport is the result of an import, JobId is set when it ran as a job
+type ImportReport struct {
+	JobId    string         `json:"job_id,omitempty"`
+	Inserted int            `json:"inserted"`
+	Failed   int            `json:"failed"`
+	Errors   []*ImportError `json:"errors"`
+}
+
+// ImportError is a row that was not imported, Rule names the business rule it broke
+type ImportError struct {
+	Line  int    `json:"line"`
+	Error string `json:"error"`
+	Rule  string `json:"rule,omitempty"`
+}
+
+type ExportRow struct {
+	Id                      string  `json:"id"`
+	Description             string  `json:"description"`
+	PurchaseDate            string  `json:"purchase_date"`
+	PurchaseAmount          float64 `json:"purchase_amount"`
+	SourceCurrency          string  `json:"source_currency"`
+	Currency                string  `json:"currency"`
+	ExchangeRate            float64 `json:"exchange_rate,omitempty"`
+	RecordDate              string  `json:"record_date,omitempty"`
+	SourceExchangeRate      float64 `json:"source_exchange_rate,omitempty"`
+	SourceRecordDate        string  `json:"source_record_date,omitempty"`
+	ConvertedPurchaseAmount float64 `json:"converted_purchase_amount,omitempty"`
+	Error                   string  `json:"error,omitempty"`
+}
+
+// AttributeFilter matches the transactions having all of the given attributes, zero values are ignored
+type AttributeFilter struct {
+	Category string
+	Tags     []string
+	Merchant string
+	Metadata map[string]string
+}
+
+// Period selects the transactions whose Field time is from Start to End, End excluded, a zero time leaves
+// that side open. On the purchase field a transaction stored without purchased_at has no time of its own,
+// when FirstDate or LastDate are set it is selected by its purchase date between them, both included, instead.
+// Timezone is the IANA name the periods of a summary are grouped in, empty is UTC.
+type Period struct {
+	Field     string
+	Start     time.Time
+	End       time.Time
+	FirstDate time.Time
+	LastDate  time.Time
+	Timezone  string
+}
+
+// TransactionFilter combines the search criteria, zero values are ignored
+type TransactionFilter struct {
+	Text              string
+	MinAmount         float64
+	MaxAmount         float64
+	Period            Period
+	PurchaseStartDate time.Time
+	PurchaseEndDate   time.Time
+	Skip              int64
+	Limit             int64
+	AttributeFilter
 }
 
 type GetTransactionParams struct {
@@ -23,14 +165,75 @@ type GetTransactionParams struct {
 	Currency string `query:"currency" validate:"required"`
 }
 
+type GetTransactionByIdParams struct {
+	Id       string `param:"id" validate:"required"`
+	Currency string `query:"currency" validate:"required"`
+}
+
+type CreatedTransaction struct {
+	Id string `json:"id"`
+}
+
+type CreatedTransactions struct {
+	Ids []string `json:"ids"`
+}
+
+// AttributeParams are the attribute filters of the list and search endpoints.
+// Tags is a comma separated list and metadata a comma separated list of key:value pairs.
+type AttributeParams struct {
+	Category string `query:"category"`
+	Tags     string `query:"tags"`
+	Merchant string `query:"merchant"`
+	Metadata string `query:"metadata"`
+}
+
 type GetTransactionParamsByPeriod struct {
 	StartDate string `query:"startDate" validate:"required"`
 	EndDate   string `query:"endDate" validate:"required"`
+	Timezone  string `query:"timezone"`
+	Field     string `query:"field" validate:"omitempty,oneof=purchase created"`
 	Currency  string `query:"currency" validate:"required"`
+	AttributeParams
 }
 
 type GetTransactionParamsByPeriodEpoch struct {
 	StartDate int64  `query:"startDate" validate:"required"`
 	EndDate   int64  `query:"endDate" validate:"required"`
+	Field     string `query:"field" validate:"omitempty,oneof=purchase created"`
+	Currency  string `query:"currency" validate:"required"`
+	AttributeParams
+}
+
+type GetTransactionSummaryParams struct {
+	StartDate string `query:"startDate" validate:"required"`
+	EndDate   string `query:"endDate" validate:"required"`
+	Timezone  string `query:"timezone"`
+	Field     string `query:"field" validate:"omitempty,oneof=purchase created"`
+	Currency  string `query:"currency" validate:"required"`
+	GroupBy   string `query:"groupBy" validate:"omitempty,oneof=day week month"`
+}
+
+type SearchTransactionParams struct {
+	Text              string  `query:"text"`
+	MinAmount         float64 `query:"minAmount" validate:"gte=0"`
+	MaxAmount         float64 `query:"maxAmount" validate:"gte=0"`
+	StartDate         string  `query:"startDate"`
+	EndDate           string  `query:"endDate"`
+	Timezone          string  `query:"timezone"`
+	Field             string  `query:"field" validate:"omitempty,oneof=purchase created"`
+	PurchaseStartDate string  `query:"purchaseStartDate"`
+	PurchaseEndDate   string  `query:"purchaseEndDate"`
+	Currency          string  `query:"currency" validate:"required"`
+	Page              int64   `query:"page" validate:"gte=0"`
+	PageSize          int64   `query:"pageSize" validate:"gte=0,lte=100"`
+	AttributeParams
+}
+
+type ExportTransactionParams struct {
+	StartDate string `query:"startDate" validate:"required"`
+	EndDate   string `query:"endDate" validate:"required"`
+	Timezone  string `query:"timezone"`
+	Field     string `query:"field" validate:"omitempty,oneof=purchase created"`
 	Currency  string `query:"currency" validate:"required"`
+	Format    string `query:"format" validate:"required,oneof=csv ndjson xlsx"`
 }
@@ -0,0 +1,58 @@
+package model
+
+import "encoding/json"
+
+const (
+	DeliveryStatusPending   = "pending"
+	DeliveryStatusRunning   = "running"
+	DeliveryStatusSucceeded = "succeeded"
+	// DeliveryStatusDead is a delivery that failed every attempt, it is not retried
+	DeliveryStatusDead = "dead"
+)
+
+// Webhook is a subscription of an account to events. The secret signs the payloads and is never returned.
+type Webhook struct {
+	Id        string   `json:"id" bson:"_id,omitempty"`
+	AccountId string   `json:"-" bson:"account_id"`
+	URL       string   `json:"url" bson:"url"`
+	Secret    string   `json:"-" bson:"secret"`
+	Events    []string `json:"events" bson:"events"`
+	CreatedAt int64    `json:"created_at" bson:"created_at"`
+}
+
+// WebhookEvent is the payload posted to the subscribed urls
+type WebhookEvent struct {
+	Id        string      `json:"id"`
+	Type      string      `json:"type"`
+	CreatedAt string      `json:"created_at"`
+	Data      interface{} `json:"data"`
+}
+
+// WebhookDelivery is an attempt log of an event sent to a webhook
+type WebhookDelivery struct {
+	Id            string          `json:"id" bson:"_id,omitempty"`
+	AccountId     string          `json:"-" bson:"account_id"`
+	WebhookId     string          `json:"webhook_id" bson:"webhook_id"`
+	EventId       string          `json:"event_id" bson:"event_id"`
+	Event         string          `json:"event" bson:"event"`
+	Payload       json.RawMessage `json:"payload" bson:"payload"`
+	Status        string          `json:"status" bson:"status"`
+	Attempts      int             `json:"attempts" bson:"attempts"`
+	NextAttemptAt int64           `json:"next_attempt_at,omitempty" bson:"next_attempt_at,omitempty"`
+	StatusCode    int             `json:"status_code,omitempty" bson:"status_code,omitempty"`
+	Error         string          `json:"error,omitempty" bson:"error,omitempty"`
+	Owner         string          `json:"-" bson:"owner,omitempty"`
+	LockedUntil   int64           `json:"-" bson:"locked_until,omitempty"`
+	CreatedAt     int64           `json:"created_at" bson:"created_at"`
+	UpdatedAt     int64           `json:"updated_at" bson:"updated_at"`
+}
+
+type CreateWebhookParams struct {
+	URL    string   `json:"url" validate:"required,url,startswith=http"`
+	Secret string   `json:"secret" validate:"required,min=16,max=200"`
+	Events []string `json:"events" validate:"required,min=1,dive,oneof=transaction.created"`
+}
+
+type WebhookParams struct {
+	Id string `param:"id" validate:"required"`
+}
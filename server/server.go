@@ -1,14 +1,32 @@
 package server
 
 import (
+	"context"
+	"errors"
+	"expvar"
+	"net"
+	"net/http"
 	"os"
+	"regexp"
+	"strings"
 	"time"
 	"github.com/jcpribeiro/TransactionApp/api"
+	"github.com/jcpribeiro/TransactionApp/api/rpc"
+	v1transaction "github.com/jcpribeiro/TransactionApp/api/v1/transaction"
 	"github.com/jcpribeiro/TransactionApp/app"
+	"github.com/jcpribeiro/TransactionApp/app/ingest"
+	"github.com/jcpribeiro/TransactionApp/app/job"
+	"github.com/jcpribeiro/TransactionApp/app/migration"
+	"github.com/jcpribeiro/TransactionApp/app/outbox"
+	"github.com/jcpribeiro/TransactionApp/app/transaction"
+	"github.com/jcpribeiro/TransactionApp/app/webhook"
 	"github.com/jcpribeiro/TransactionApp/config"
 
+	"github.com/jcpribeiro/TransactionApp/internal/auth"
 	"github.com/jcpribeiro/TransactionApp/internal/cache"
+	"github.com/jcpribeiro/TransactionApp/internal/envelope"
 	"github.com/jcpribeiro/TransactionApp/internal/mongodb"
+	"github.com/jcpribeiro/TransactionApp/internal/ratelimit"
 	"github.com/jcpribeiro/TransactionApp/internal/validate"
 
 	"github.com/jcpribeiro/TransactionApp/store"
@@ -21,6 +39,15 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
+// migrationTimeout bounds the startup migrations, a migration that does not finish runs again on the next start
+const migrationTimeout = 10 * time.Minute
+
+// bootstrapKeyEnv and bootstrapAccountEnv provide the admin api key stored at startup and its account
+const (
+	bootstrapKeyEnv     = "APP_BOOTSTRAP_API_KEY"
+	bootstrapAccountEnv = "APP_BOOTSTRAP_ACCOUNT_ID"
+)
+
 // Server is a interface to define contract to server up
 type Server interface {
 	Start()
@@ -33,9 +60,12 @@ type server struct {
 	log         logrus.Logger
 	app         *app.Container
 	redis       *redis.Client
+	cache       cache.Cache
 	mongoReader mongodb.MongoDB
 	mongoWriter mongodb.MongoDB
 	stores      *store.Container
+	grpc        *rpc.Server
+	metrics     *http.Server
 }
 
 func setLogLevel() logrus.Level {
@@ -64,22 +94,37 @@ func (s *server) Start() {
 	s.echo.Validator = validate.New()
 	s.echo.Debug = config.GlobalConfig.ENV != "prod"
 	s.echo.HideBanner = true
+	s.echo.HTTPErrorHandler = envelope.ErrorHandler(s.echo.DefaultHTTPErrorHandler)
 
 	// ---- setup middlewares ----
 	s.echo.Use(emiddleware.Logger())
-	s.echo.Use(emiddleware.BodyLimit("2M"))
+	s.echo.Use(emiddleware.BodyLimitWithConfig(emiddleware.BodyLimitConfig{
+		Limit: "2M",
+		// import routes stream the body and set their own limit
+		Skipper: func(c echo.Context) bool {
+			return strings.HasSuffix(c.Path(), "/import")
+		},
+	}))
 	s.echo.Use(emiddleware.Recover())
 	s.echo.Use(emiddleware.RequestID())
 	s.echo.Use(emiddleware.Secure())
 
 	// ---- setup Redis ----
-	s.redis = redis.NewClient(&redis.Options{
-		Addr:     config.GlobalConfig.Redis.URL,
-		Password: config.GlobalConfig.Redis.Password,
-		DB:       config.GlobalConfig.Redis.DB,
-	})
+	if len(config.GlobalConfig.Redis.URL) > 0 {
+		s.redis = redis.NewClient(&redis.Options{
+			Addr:     config.GlobalConfig.Redis.URL,
+			Password: config.GlobalConfig.Redis.Password,
+			DB:       config.GlobalConfig.Redis.DB,
+		})
+	} else {
+		s.log.Warn("redis is disabled, the cache is kept in process")
+	}
 
-	cache := cache.NewCache(s.redis, s.log)
+	s.cache = cache.NewCache(s.redis, cache.Options{
+		LocalSize:     config.GlobalConfig.Cache.LocalSize,
+		LocalMaxBytes: config.GlobalConfig.Cache.LocalMaxBytes,
+		LocalTTL:      config.GlobalConfig.Cache.LocalTTL,
+	}, s.log)
 
 	// ---- setup Mongodb ----
 	s.mongoReader = mongodb.NewMongoDB(
@@ -101,20 +146,182 @@ func (s *server) Start() {
 		Log:              s.log,
 	})
 
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	if err := s.stores.Transaction.CreateIndexes(ctx); err != nil {
+		s.log.Error("cannot create transaction indexes ", err.Error())
+	}
+	if err := s.stores.Job.CreateIndexes(ctx); err != nil {
+		s.log.Error("cannot create job indexes ", err.Error())
+	}
+	if err := s.stores.APIKey.CreateIndexes(ctx); err != nil {
+		s.log.Error("cannot create api key indexes ", err.Error())
+	}
+	if err := s.stores.Webhook.CreateIndexes(ctx); err != nil {
+		s.log.Error("cannot create webhook indexes ", err.Error())
+	}
+	if err := s.stores.Outbox.CreateIndexes(ctx); err != nil {
+		s.log.Error("cannot create outbox indexes ", err.Error())
+	}
+	cancel()
+
 	// ---- setup App ----
+	rules := transaction.Rules{
+		MaxPastDays:   config.GlobalConfig.Rules.MaxPastDays,
+		MaxFutureDays: config.GlobalConfig.Rules.MaxFutureDays,
+		MinAmount:     config.GlobalConfig.Rules.MinAmount,
+		MaxAmount:     config.GlobalConfig.Rules.MaxAmount,
+	}
+	if pattern := config.GlobalConfig.Rules.DescriptionPattern; len(pattern) > 0 {
+		descriptionPattern, err := regexp.Compile(pattern)
+		if err != nil {
+			s.log.Fatal("cannot compile rules description_pattern ", err.Error())
+		}
+		rules.DescriptionPattern = descriptionPattern
+	}
+
+	if len(config.GlobalConfig.Jobs.Dir) == 0 {
+		s.log.Fatal("jobs.dir is required, it must be a persistent volume shared by every instance")
+	}
+
 	s.app = app.NewApp(app.Options{
 		Log:    s.log,
 		URL:    config.GlobalConfig.FiscalData.URL,
 		Stores: s.stores,
+		Redis:  s.redis,
+		Jobs: job.Options{
+			Workers: config.GlobalConfig.Jobs.Workers,
+			Dir:     config.GlobalConfig.Jobs.Dir,
+		},
+		Migration: migration.Options{
+			DefaultAccount: config.GlobalConfig.Migrations.DefaultAccount,
+		},
+		Rules: rules,
+		Webhooks: webhook.Options{
+			Workers:      config.GlobalConfig.Webhooks.Workers,
+			Timeout:      config.GlobalConfig.Webhooks.Timeout,
+			MaxAttempts:  config.GlobalConfig.Webhooks.MaxAttempts,
+			MinBackoff:   config.GlobalConfig.Webhooks.MinBackoff,
+			MaxBackoff:   config.GlobalConfig.Webhooks.MaxBackoff,
+			AllowPrivate: config.GlobalConfig.Webhooks.AllowPrivate,
+			RequireHTTPS: config.GlobalConfig.Webhooks.RequireHTTPS,
+		},
+		Outbox: outbox.Options{
+			Workers: config.GlobalConfig.Outbox.Workers,
+		},
+		Ingest: ingest.Options{
+			Stream:           config.GlobalConfig.Ingest.Stream,
+			Group:            config.GlobalConfig.Ingest.Group,
+			DeadLetterStream: config.GlobalConfig.Ingest.DeadLetterStream,
+			Workers:          config.GlobalConfig.Ingest.Workers,
+			Batch:            config.GlobalConfig.Ingest.Batch,
+			ClaimIdle:        config.GlobalConfig.Ingest.ClaimIdle,
+			MaxDeliveries:    config.GlobalConfig.Ingest.MaxDeliveries,
+			Inserted: func(ctx context.Context, accountId string) {
+				v1transaction.InvalidatePeriods(ctx, s.cache, accountId)
+			},
+		},
 	})
+	s.registerSinks()
+
+	// ---- run migrations ----
+	// the service starts without them on failure, a migration is retried on the next start
+	ctx, cancel = context.WithTimeout(context.Background(), migrationTimeout)
+	if err := s.app.Migration.Run(ctx); err != nil {
+		s.log.Error("cannot run migrations ", err.Error())
+	}
+	cancel()
+
+	// ---- bootstrap api key ----
+	// the first admin key of a deployment without a jwt secret, the other keys are created with it
+	if key := os.Getenv(bootstrapKeyEnv); len(key) > 0 {
+		ctx, cancel = context.WithTimeout(context.Background(), 15*time.Second)
+		if err := s.app.APIKey.BootstrapAPIKey(ctx, os.Getenv(bootstrapAccountEnv), key); err != nil {
+			s.log.Fatal("cannot bootstrap api key ", err.Error())
+		}
+		cancel()
+	}
 
 	// ---- setup Api ----
+	jwtVerifier, err := auth.NewJWTVerifier(auth.JWTOptions{
+		Secret:        config.GlobalConfig.Auth.JWT.Secret,
+		PublicKeyFile: config.GlobalConfig.Auth.JWT.PublicKeyFile,
+		JWKSFile:      config.GlobalConfig.Auth.JWT.JWKSFile,
+		Issuer:        config.GlobalConfig.Auth.JWT.Issuer,
+		Audience:      config.GlobalConfig.Auth.JWT.Audience,
+		AccountClaim:  config.GlobalConfig.Auth.JWT.AccountClaim,
+	})
+	if err != nil {
+		s.log.Fatal("cannot load jwt keys ", err.Error())
+	}
+
+	authenticator := auth.NewAuthenticator(s.app.APIKey.VerifyAPIKey, jwtVerifier, s.log)
+
+	var rateLimit *ratelimit.Policy
+	if limits := config.GlobalConfig.RateLimit; limits.Enabled && s.redis != nil {
+		rateLimit = ratelimit.NewPolicy(ratelimit.NewRedisLimiter(s.redis), map[string]ratelimit.Limit{
+			ratelimit.ClassRead:       {Requests: limits.Read.Requests, Window: limits.Read.Window},
+			ratelimit.ClassWrite:      {Requests: limits.Write.Requests, Window: limits.Write.Window},
+			ratelimit.ClassConversion: {Requests: limits.Conversion.Requests, Window: limits.Conversion.Window},
+		}, s.log)
+	}
+
+	cachePolicies := make(map[string]cache.Policy, len(config.GlobalConfig.Cache.Endpoints))
+	for endpoint, policy := range config.GlobalConfig.Cache.Endpoints {
+		cachePolicies[endpoint] = cache.Policy{TTL: policy.TTL, MaxAge: policy.MaxAge}
+	}
+
 	api.Register(api.Options{
-		Group: s.echo.Group(""),
-		Apps:  s.app,
-		Cache: cache,
+		Group:         s.echo.Group(""),
+		Apps:          s.app,
+		Cache:         s.cache,
+		CachePolicies: cachePolicies,
+		Resolver:      authenticator.Resolve,
+		RateLimit:     rateLimit,
 	})
 
+	// ---- setup gRPC ----
+	if port := config.GlobalConfig.Server.GRPCPort; len(port) > 0 {
+		listener, err := net.Listen("tcp", port)
+		if err != nil {
+			s.log.Fatal("cannot listen gRPC ", err.Error())
+		}
+
+		s.grpc = rpc.NewServer(rpc.Options{
+			Apps:         s.app,
+			Cache:        s.cache,
+			Authenticate: authenticator.Authenticate,
+			Log:          s.log,
+		})
+		go func() {
+			if err := s.grpc.Serve(listener); err != nil {
+				s.log.Error("cannot starting gRPC server ", err.Error())
+			}
+		}()
+	}
+
+	// ---- start job, webhook, outbox and stream workers ----
+	s.app.Job.Start()
+	s.app.Webhook.Start()
+	s.app.Outbox.Start()
+	s.app.Ingest.Start()
+
+	// ---- setup metrics ----
+	// served on an internal listener, the counters and the command line must not reach the api clients
+	if port := config.GlobalConfig.Server.MetricsPort; len(port) > 0 {
+		mux := http.NewServeMux()
+		mux.Handle("/debug/vars", expvar.Handler())
+		s.metrics = &http.Server{
+			Addr:              port,
+			Handler:           mux,
+			ReadHeaderTimeout: 10 * time.Second,
+		}
+		go func() {
+			if err := s.metrics.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				s.log.Error("cannot starting metrics server ", err.Error())
+			}
+		}()
+	}
+
 	// ---- setup documentation ----
 	swagger.Register(swagger.Options{
 		Group: s.echo.Group("/swagger"),
@@ -127,8 +334,45 @@ func (s *server) Start() {
 	}
 }
 
+// registerSinks adds the configured sinks to the outbox relay, the redis sink needs redis enabled
+func (s *server) registerSinks() {
+	for _, name := range config.GlobalConfig.Outbox.Sinks {
+		switch name {
+		case "webhook":
+			s.app.Outbox.RegisterSink(outbox.NewWebhookSink(s.app.Webhook))
+		case "redis":
+			if s.redis == nil {
+				s.log.Warn("redis is disabled, the outbox redis sink is not registered")
+				continue
+			}
+			s.app.Outbox.RegisterSink(outbox.NewRedisSink(s.redis, config.GlobalConfig.Outbox.RedisStream, config.GlobalConfig.Outbox.RedisMaxLen))
+		default:
+			s.log.Warn("unknown outbox sink ", name)
+		}
+	}
+}
+
 func (s *server) Stop() {
 	if err := s.echo.Close(); err != nil {
 		s.log.Error("cannot close echo ", err.Error())
 	}
+
+	if s.grpc != nil {
+		s.grpc.Stop()
+	}
+
+	if s.metrics != nil {
+		if err := s.metrics.Close(); err != nil {
+			s.log.Error("cannot close metrics server ", err.Error())
+		}
+	}
+
+	s.app.Ingest.Stop()
+	s.app.Outbox.Stop()
+	s.app.Job.Stop()
+	s.app.Webhook.Stop()
+
+	if err := s.cache.Close(); err != nil {
+		s.log.Error("cannot close cache ", err.Error())
+	}
 }
@@ -0,0 +1,147 @@
+package apikey
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jcpribeiro/TransactionApp/internal/tenant"
+	"github.com/jcpribeiro/TransactionApp/model"
+
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+//go:generate mockgen -source=$GOFILE -destination=apikey_mock.go -package=$GOPACKAGE
+
+// ErrAPIKeyNotFound is returned when the key does not exist, was revoked or belongs to another account
+var ErrAPIKeyNotFound = errors.New("api key not found")
+
+// ErrAPIKeyExists is returned when a key with the same hash was already stored, revoked or not
+var ErrAPIKeyExists = errors.New("api key already exists")
+
+type Store interface {
+	InsertAPIKey(ctx context.Context, key *model.APIKey) (string, error)
+	GetAPIKeyByHash(ctx context.Context, hash string) (*model.APIKey, error)
+	ListAPIKeys(ctx context.Context, accountId string) ([]*model.APIKey, error)
+	RevokeAPIKey(ctx context.Context, accountId, id string, now int64) error
+	CreateIndexes(ctx context.Context) error
+}
+
+type storeImpl struct {
+	mongodbConReader *mongo.Database
+	mongodbConWriter *mongo.Database
+	log              logrus.Logger
+}
+
+func NewStoreAPIKey(mongodbConReader, mongodbConWriter *mongo.Database, log logrus.Logger) Store {
+	return &storeImpl{
+		mongodbConReader: mongodbConReader,
+		mongodbConWriter: mongodbConWriter,
+		log:              log,
+	}
+}
+
+// Create the index used to authenticate the keys
+func (s storeImpl) CreateIndexes(ctx context.Context) error {
+	_, err := s.mongodbConWriter.Collection("api_key").Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    primitive.D{{Key: "hash", Value: 1}},
+			Options: options.Index().SetName("hash").SetUnique(true),
+		},
+		{
+			Keys:    primitive.D{{Key: "account_id", Value: 1}, {Key: "created_at", Value: 1}},
+			Options: options.Index().SetName("account_id_created_at"),
+		},
+	})
+
+	return err
+}
+
+// Insert a new key
+func (s storeImpl) InsertAPIKey(ctx context.Context, key *model.APIKey) (string, error) {
+	if len(key.AccountId) == 0 {
+		return "", tenant.ErrMissingAccount
+	}
+
+	insertedId, err := s.mongodbConWriter.Collection("api_key").InsertOne(ctx, key)
+	if mongo.IsDuplicateKeyError(err) {
+		return "", ErrAPIKeyExists
+	}
+	if err != nil {
+		return "", err
+	}
+
+	return insertedId.InsertedID.(primitive.ObjectID).Hex(), nil
+}
+
+// Get an active key, filtering by the hash of the key. It reads from the writer so a revoke is seen at once.
+func (s storeImpl) GetAPIKeyByHash(ctx context.Context, hash string) (*model.APIKey, error) {
+	filter := primitive.M{
+		"hash":       hash,
+		"revoked_at": primitive.M{"$exists": false},
+	}
+
+	var key *model.APIKey
+	err := s.mongodbConWriter.Collection("api_key").FindOne(ctx, filter).Decode(&key)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, ErrAPIKeyNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return key, nil
+}
+
+// Get the keys of an account, including the revoked ones
+func (s storeImpl) ListAPIKeys(ctx context.Context, accountId string) ([]*model.APIKey, error) {
+	if len(accountId) == 0 {
+		return nil, tenant.ErrMissingAccount
+	}
+
+	opts := options.Find().SetSort(primitive.D{{Key: "created_at", Value: 1}, {Key: "_id", Value: 1}})
+	keys := make([]*model.APIKey, 0)
+	cursor, err := s.mongodbConReader.Collection("api_key").Find(ctx, primitive.M{"account_id": accountId}, opts)
+	if err != nil {
+		return nil, err
+	}
+	err = cursor.All(ctx, &keys)
+	if err != nil {
+		return nil, err
+	}
+
+	return keys, nil
+}
+
+// Revoke an active key of an account
+func (s storeImpl) RevokeAPIKey(ctx context.Context, accountId, id string, now int64) error {
+	if len(accountId) == 0 {
+		return tenant.ErrMissingAccount
+	}
+
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return ErrAPIKeyNotFound
+	}
+
+	filter := primitive.M{
+		"_id":        objectID,
+		"account_id": accountId,
+		"revoked_at": primitive.M{"$exists": false},
+	}
+	update := primitive.M{
+		"$set": primitive.M{"revoked_at": now},
+	}
+
+	result, err := s.mongodbConWriter.Collection("api_key").UpdateOne(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrAPIKeyNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,187 @@
+package apikey
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jcpribeiro/TransactionApp/internal/tenant"
+	"github.com/jcpribeiro/TransactionApp/model"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+const testAccountId = "652d34910a8fc425116b84aa"
+
+type structTest struct {
+	mt *mtest.T
+}
+
+func prepareTest(t *testing.T) *structTest {
+	mt := mtest.New(t, mtest.NewOptions().DatabaseName("test").ClientType(mtest.Mock))
+	return &structTest{
+		mt: mt,
+	}
+}
+
+func TestInsertAPIKey(t *testing.T) {
+	testObj := prepareTest(t)
+	ctx := context.Background()
+
+	testObj.mt.Run("this test simulate a successful api key insert", func(t *mtest.T) {
+		t.AddMockResponses(mtest.CreateSuccessResponse())
+		storeTest := NewStoreAPIKey(t.DB, t.DB, *logrus.New())
+
+		id, err := storeTest.InsertAPIKey(ctx, &model.APIKey{
+			AccountId: testAccountId,
+			Name:      "integration",
+			Hash:      "hash",
+			Scopes:    []string{"transaction:read"},
+		})
+
+		assert.NoError(t, err)
+		assert.NotEmpty(t, id)
+	})
+
+	testObj.mt.Run("this test simulate an api key insert with a stored hash", func(t *mtest.T) {
+		t.AddMockResponses(mtest.CreateWriteErrorsResponse(mtest.WriteError{
+			Index:   0,
+			Code:    11000,
+			Message: "duplicate key error",
+		}))
+		storeTest := NewStoreAPIKey(t.DB, t.DB, *logrus.New())
+
+		id, err := storeTest.InsertAPIKey(ctx, &model.APIKey{AccountId: testAccountId, Hash: "hash"})
+
+		assert.ErrorIs(t, err, ErrAPIKeyExists)
+		assert.Empty(t, id)
+	})
+
+	testObj.mt.Run("this test simulate an api key insert without an account", func(t *mtest.T) {
+		storeTest := NewStoreAPIKey(t.DB, t.DB, *logrus.New())
+
+		id, err := storeTest.InsertAPIKey(ctx, &model.APIKey{Hash: "hash"})
+
+		assert.ErrorIs(t, err, tenant.ErrMissingAccount)
+		assert.Empty(t, id)
+	})
+}
+
+func TestGetAPIKeyByHash(t *testing.T) {
+	testObj := prepareTest(t)
+	ctx := context.Background()
+
+	testObj.mt.Run("This test simulates the process for obtaining an api key", func(t *mtest.T) {
+		id := primitive.NewObjectID()
+		t.AddMockResponses(mtest.CreateCursorResponse(1, "foo.bar", mtest.FirstBatch, bson.D{
+			{Key: "_id", Value: id},
+			{Key: "account_id", Value: testAccountId},
+			{Key: "hash", Value: "hash"},
+			{Key: "scopes", Value: bson.A{"admin"}},
+		}))
+		storeTest := NewStoreAPIKey(t.DB, t.DB, *logrus.New())
+
+		key, err := storeTest.GetAPIKeyByHash(ctx, "hash")
+
+		assert.NoError(t, err)
+		assert.Equal(t, key, &model.APIKey{
+			Id:        id.Hex(),
+			AccountId: testAccountId,
+			Hash:      "hash",
+			Scopes:    []string{"admin"},
+		})
+	})
+
+	testObj.mt.Run("This test simulates an unknown api key", func(t *mtest.T) {
+		t.AddMockResponses(mtest.CreateCursorResponse(0, "foo.bar", mtest.FirstBatch))
+		storeTest := NewStoreAPIKey(t.DB, t.DB, *logrus.New())
+
+		key, err := storeTest.GetAPIKeyByHash(ctx, "hash")
+
+		assert.ErrorIs(t, err, ErrAPIKeyNotFound)
+		assert.Nil(t, key)
+	})
+}
+
+func TestListAPIKeys(t *testing.T) {
+	testObj := prepareTest(t)
+	ctx := context.Background()
+
+	testObj.mt.Run("This test simulates the process for listing the api keys", func(t *mtest.T) {
+		first := mtest.CreateCursorResponse(1, "foo.bar", mtest.FirstBatch, bson.D{
+			{Key: "_id", Value: primitive.NewObjectID()},
+			{Key: "account_id", Value: testAccountId},
+			{Key: "name", Value: "integration"},
+		})
+		killCursors := mtest.CreateCursorResponse(0, "foo.bar", mtest.NextBatch)
+		t.AddMockResponses(first, killCursors)
+		storeTest := NewStoreAPIKey(t.DB, t.DB, *logrus.New())
+
+		keys, err := storeTest.ListAPIKeys(ctx, testAccountId)
+
+		assert.NoError(t, err)
+		assert.Len(t, keys, 1)
+		assert.Equal(t, keys[0].Name, "integration")
+	})
+
+	testObj.mt.Run("This test simulates an error when listing the api keys", func(t *mtest.T) {
+		t.AddMockResponses(mtest.CreateCommandErrorResponse(mtest.CommandError{
+			Code: 2,
+		}))
+		storeTest := NewStoreAPIKey(t.DB, t.DB, *logrus.New())
+
+		keys, err := storeTest.ListAPIKeys(ctx, testAccountId)
+
+		assert.Error(t, err)
+		assert.Nil(t, keys)
+	})
+}
+
+func TestRevokeAPIKey(t *testing.T) {
+	testObj := prepareTest(t)
+	ctx := context.Background()
+
+	testObj.mt.Run("This test simulates revoking an api key", func(t *mtest.T) {
+		t.AddMockResponses(bson.D{
+			{Key: "ok", Value: 1},
+			{Key: "n", Value: 1},
+			{Key: "nModified", Value: 1},
+		})
+		storeTest := NewStoreAPIKey(t.DB, t.DB, *logrus.New())
+
+		err := storeTest.RevokeAPIKey(ctx, testAccountId, primitive.NewObjectID().Hex(), 1697150153)
+
+		assert.NoError(t, err)
+	})
+
+	testObj.mt.Run("This test simulates revoking an api key of another account", func(t *mtest.T) {
+		t.AddMockResponses(bson.D{
+			{Key: "ok", Value: 1},
+			{Key: "n", Value: 0},
+			{Key: "nModified", Value: 0},
+		})
+		storeTest := NewStoreAPIKey(t.DB, t.DB, *logrus.New())
+
+		err := storeTest.RevokeAPIKey(ctx, testAccountId, primitive.NewObjectID().Hex(), 1697150153)
+
+		assert.ErrorIs(t, err, ErrAPIKeyNotFound)
+	})
+}
+
+func TestCreateIndexes(t *testing.T) {
+	testObj := prepareTest(t)
+	ctx := context.Background()
+
+	testObj.mt.Run("this test simulate the indexes creation", func(t *mtest.T) {
+		t.AddMockResponses(mtest.CreateSuccessResponse())
+		storeTest := NewStoreAPIKey(t.DB, t.DB, *logrus.New())
+
+		err := storeTest.CreateIndexes(ctx)
+
+		assert.NoError(t, err)
+	})
+}
@@ -0,0 +1,253 @@
+package job
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jcpribeiro/TransactionApp/internal/tenant"
+	"github.com/jcpribeiro/TransactionApp/model"
+
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+//go:generate mockgen -source=$GOFILE -destination=job_mock.go -package=$GOPACKAGE
+
+// ErrJobNotFound is returned when the job does not exist or is not owned by the caller
+var ErrJobNotFound = errors.New("job not found")
+
+type Store interface {
+	InsertJob(ctx context.Context, job *model.Job) (string, error)
+	GetJobById(ctx context.Context, accountId, id string) (*model.Job, error)
+	ClaimJob(ctx context.Context, owner string, now, lockedUntil int64) (*model.Job, error)
+	UpdateJobProgress(ctx context.Context, id, owner string, progress, total, lockedUntil, now int64) (*model.Job, error)
+	FinishJob(ctx context.Context, id, owner string, job *model.Job, now int64) error
+	ReleaseJob(ctx context.Context, id, owner string, now int64) error
+	CancelJob(ctx context.Context, accountId, id string, now int64) (*model.Job, error)
+	AssignAccount(ctx context.Context, accountId string) (int64, error)
+	CreateIndexes(ctx context.Context) error
+}
+
+type storeImpl struct {
+	mongodbConReader *mongo.Database
+	mongodbConWriter *mongo.Database
+	log              logrus.Logger
+}
+
+func NewStoreJob(mongodbConReader, mongodbConWriter *mongo.Database, log logrus.Logger) Store {
+	return &storeImpl{
+		mongodbConReader: mongodbConReader,
+		mongodbConWriter: mongodbConWriter,
+		log:              log,
+	}
+}
+
+func jobFilter(id string) (primitive.M, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, ErrJobNotFound
+	}
+
+	return primitive.M{"_id": objectID}, nil
+}
+
+// accountJobFilter matches a job only for the account that submitted it
+func accountJobFilter(accountId, id string) (primitive.M, error) {
+	if len(accountId) == 0 {
+		return nil, tenant.ErrMissingAccount
+	}
+
+	filter, err := jobFilter(id)
+	if err != nil {
+		return nil, err
+	}
+	filter["account_id"] = accountId
+
+	return filter, nil
+}
+
+func decodeJob(result *mongo.SingleResult) (*model.Job, error) {
+	var job *model.Job
+	err := result.Decode(&job)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, ErrJobNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return job, nil
+}
+
+// Create the index used to claim jobs
+func (s storeImpl) CreateIndexes(ctx context.Context) error {
+	_, err := s.mongodbConWriter.Collection("job").Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    primitive.D{{Key: "status", Value: 1}, {Key: "created_at", Value: 1}},
+		Options: options.Index().SetName("status_created_at"),
+	})
+
+	return err
+}
+
+// Insert a new job
+func (s storeImpl) InsertJob(ctx context.Context, job *model.Job) (string, error) {
+	insertedId, err := s.mongodbConWriter.Collection("job").InsertOne(ctx, job)
+	if err != nil {
+		return "", err
+	}
+
+	return insertedId.InsertedID.(primitive.ObjectID).Hex(), nil
+}
+
+// Get a single job, filtering by account and id. It reads from the writer so a poll sees its own updates.
+func (s storeImpl) GetJobById(ctx context.Context, accountId, id string) (*model.Job, error) {
+	filter, err := accountJobFilter(accountId, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeJob(s.mongodbConWriter.Collection("job").FindOne(ctx, filter))
+}
+
+// Claim the oldest pending job, or a running job whose lease has expired because its worker stopped.
+// It returns nil when there is no job to run.
+func (s storeImpl) ClaimJob(ctx context.Context, owner string, now, lockedUntil int64) (*model.Job, error) {
+	filter := primitive.M{
+		"$or": primitive.A{
+			primitive.M{"status": model.JobStatusPending},
+			primitive.M{"status": model.JobStatusRunning, "locked_until": primitive.M{"$lt": now}},
+		},
+	}
+	update := primitive.M{
+		"$set": primitive.M{
+			"status":       model.JobStatusRunning,
+			"owner":        owner,
+			"locked_until": lockedUntil,
+			"updated_at":   now,
+		},
+	}
+	opts := options.FindOneAndUpdate().
+		SetSort(primitive.D{{Key: "created_at", Value: 1}, {Key: "_id", Value: 1}}).
+		SetReturnDocument(options.After)
+
+	job, err := decodeJob(s.mongodbConWriter.Collection("job").FindOneAndUpdate(ctx, filter, update, opts))
+	if errors.Is(err, ErrJobNotFound) {
+		return nil, nil
+	}
+
+	return job, err
+}
+
+// Save the progress of a running job and extend its lease
+func (s storeImpl) UpdateJobProgress(ctx context.Context, id, owner string, progress, total, lockedUntil, now int64) (*model.Job, error) {
+	filter, err := jobFilter(id)
+	if err != nil {
+		return nil, err
+	}
+	filter["owner"] = owner
+	filter["status"] = model.JobStatusRunning
+
+	update := primitive.M{
+		"$set": primitive.M{
+			"progress":     progress,
+			"total":        total,
+			"locked_until": lockedUntil,
+			"updated_at":   now,
+		},
+	}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	return decodeJob(s.mongodbConWriter.Collection("job").FindOneAndUpdate(ctx, filter, update, opts))
+}
+
+// Save the final status, progress, result and error of a running job
+func (s storeImpl) FinishJob(ctx context.Context, id, owner string, job *model.Job, now int64) error {
+	filter, err := jobFilter(id)
+	if err != nil {
+		return err
+	}
+	filter["owner"] = owner
+	filter["status"] = model.JobStatusRunning
+
+	update := primitive.M{
+		"$set": primitive.M{
+			"status":     job.Status,
+			"progress":   job.Progress,
+			"total":      job.Total,
+			"result":     job.Result,
+			"error":      job.Error,
+			"updated_at": now,
+		},
+		"$unset": primitive.M{"owner": "", "locked_until": ""},
+	}
+
+	result, err := s.mongodbConWriter.Collection("job").UpdateOne(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrJobNotFound
+	}
+
+	return nil
+}
+
+// Put a running job back in the queue, so another worker can run it
+func (s storeImpl) ReleaseJob(ctx context.Context, id, owner string, now int64) error {
+	filter, err := jobFilter(id)
+	if err != nil {
+		return err
+	}
+	filter["owner"] = owner
+	filter["status"] = model.JobStatusRunning
+
+	update := primitive.M{
+		"$set":   primitive.M{"status": model.JobStatusPending, "updated_at": now},
+		"$unset": primitive.M{"owner": "", "locked_until": ""},
+	}
+
+	_, err = s.mongodbConWriter.Collection("job").UpdateOne(ctx, filter, update)
+	return err
+}
+
+// AssignAccount scopes the jobs submitted before the accounts to accountId.
+// It is safe to run again, it returns the number of updated jobs.
+func (s storeImpl) AssignAccount(ctx context.Context, accountId string) (int64, error) {
+	if len(accountId) == 0 {
+		return 0, tenant.ErrMissingAccount
+	}
+
+	result, err := s.mongodbConWriter.Collection("job").UpdateMany(ctx,
+		primitive.M{"account_id": primitive.M{"$exists": false}},
+		primitive.M{"$set": primitive.M{"account_id": accountId}})
+	if err != nil {
+		return 0, err
+	}
+
+	return result.ModifiedCount, nil
+}
+
+// Cancel a pending job, or flag a running job so its worker stops it
+func (s storeImpl) CancelJob(ctx context.Context, accountId, id string, now int64) (*model.Job, error) {
+	filter, err := accountJobFilter(accountId, id)
+	if err != nil {
+		return nil, err
+	}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$set", Value: primitive.M{
+			"status": primitive.M{"$cond": primitive.A{
+				primitive.M{"$eq": primitive.A{"$status", model.JobStatusPending}},
+				model.JobStatusCanceled,
+				"$status",
+			}},
+			"cancel_requested": primitive.M{"$eq": primitive.A{"$status", model.JobStatusRunning}},
+			"updated_at":       now,
+		}}},
+	}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	return decodeJob(s.mongodbConWriter.Collection("job").FindOneAndUpdate(ctx, filter, pipeline, opts))
+}
@@ -0,0 +1,168 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: job.go
+
+// Package job is a generated GoMock package.
+package job
+
+import (
+	context "context"
+	reflect "reflect"
+	model "github.com/jcpribeiro/TransactionApp/model"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockStore is a mock of Store interface.
+type MockStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockStoreMockRecorder
+}
+
+// MockStoreMockRecorder is the mock recorder for MockStore.
+type MockStoreMockRecorder struct {
+	mock *MockStore
+}
+
+// NewMockStore creates a new mock instance.
+func NewMockStore(ctrl *gomock.Controller) *MockStore {
+	mock := &MockStore{ctrl: ctrl}
+	mock.recorder = &MockStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockStore) EXPECT() *MockStoreMockRecorder {
+	return m.recorder
+}
+
+// AssignAccount mocks base method.
+func (m *MockStore) AssignAccount(ctx context.Context, accountId string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AssignAccount", ctx, accountId)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AssignAccount indicates an expected call of AssignAccount.
+func (mr *MockStoreMockRecorder) AssignAccount(ctx, accountId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AssignAccount", reflect.TypeOf((*MockStore)(nil).AssignAccount), ctx, accountId)
+}
+
+// CancelJob mocks base method.
+func (m *MockStore) CancelJob(ctx context.Context, accountId, id string, now int64) (*model.Job, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CancelJob", ctx, accountId, id, now)
+	ret0, _ := ret[0].(*model.Job)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CancelJob indicates an expected call of CancelJob.
+func (mr *MockStoreMockRecorder) CancelJob(ctx, accountId, id, now interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelJob", reflect.TypeOf((*MockStore)(nil).CancelJob), ctx, accountId, id, now)
+}
+
+// ClaimJob mocks base method.
+func (m *MockStore) ClaimJob(ctx context.Context, owner string, now, lockedUntil int64) (*model.Job, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimJob", ctx, owner, now, lockedUntil)
+	ret0, _ := ret[0].(*model.Job)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimJob indicates an expected call of ClaimJob.
+func (mr *MockStoreMockRecorder) ClaimJob(ctx, owner, now, lockedUntil interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimJob", reflect.TypeOf((*MockStore)(nil).ClaimJob), ctx, owner, now, lockedUntil)
+}
+
+// CreateIndexes mocks base method.
+func (m *MockStore) CreateIndexes(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateIndexes", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateIndexes indicates an expected call of CreateIndexes.
+func (mr *MockStoreMockRecorder) CreateIndexes(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateIndexes", reflect.TypeOf((*MockStore)(nil).CreateIndexes), ctx)
+}
+
+// FinishJob mocks base method.
+func (m *MockStore) FinishJob(ctx context.Context, id, owner string, job *model.Job, now int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FinishJob", ctx, id, owner, job, now)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// FinishJob indicates an expected call of FinishJob.
+func (mr *MockStoreMockRecorder) FinishJob(ctx, id, owner, job, now interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FinishJob", reflect.TypeOf((*MockStore)(nil).FinishJob), ctx, id, owner, job, now)
+}
+
+// GetJobById mocks base method.
+func (m *MockStore) GetJobById(ctx context.Context, accountId, id string) (*model.Job, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetJobById", ctx, accountId, id)
+	ret0, _ := ret[0].(*model.Job)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetJobById indicates an expected call of GetJobById.
+func (mr *MockStoreMockRecorder) GetJobById(ctx, accountId, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetJobById", reflect.TypeOf((*MockStore)(nil).GetJobById), ctx, accountId, id)
+}
+
+// InsertJob mocks base method.
+func (m *MockStore) InsertJob(ctx context.Context, job *model.Job) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertJob", ctx, job)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertJob indicates an expected call of InsertJob.
+func (mr *MockStoreMockRecorder) InsertJob(ctx, job interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertJob", reflect.TypeOf((*MockStore)(nil).InsertJob), ctx, job)
+}
+
+// ReleaseJob mocks base method.
+func (m *MockStore) ReleaseJob(ctx context.Context, id, owner string, now int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReleaseJob", ctx, id, owner, now)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReleaseJob indicates an expected call of ReleaseJob.
+func (mr *MockStoreMockRecorder) ReleaseJob(ctx, id, owner, now interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReleaseJob", reflect.TypeOf((*MockStore)(nil).ReleaseJob), ctx, id, owner, now)
+}
+
+// UpdateJobProgress mocks base method.
+func (m *MockStore) UpdateJobProgress(ctx context.Context, id, owner string, progress, total, lockedUntil, now int64) (*model.Job, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateJobProgress", ctx, id, owner, progress, total, lockedUntil, now)
+	ret0, _ := ret[0].(*model.Job)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateJobProgress indicates an expected call of UpdateJobProgress.
+func (mr *MockStoreMockRecorder) UpdateJobProgress(ctx, id, owner, progress, total, lockedUntil, now interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateJobProgress", reflect.TypeOf((*MockStore)(nil).UpdateJobProgress), ctx, id, owner, progress, total, lockedUntil, now)
+}
@@ -0,0 +1,216 @@
+package job
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jcpribeiro/TransactionApp/internal/tenant"
+	"github.com/jcpribeiro/TransactionApp/model"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+const testAccountId = "652d34910a8fc425116b84aa"
+
+type structTest struct {
+	mt *mtest.T
+}
+
+func prepareTest(t *testing.T) *structTest {
+	mt := mtest.New(t, mtest.NewOptions().DatabaseName("test").ClientType(mtest.Mock))
+	return &structTest{
+		mt: mt,
+	}
+}
+
+func TestInsertJob(t *testing.T) {
+	testObj := prepareTest(t)
+	ctx := context.Background()
+
+	testObj.mt.Run("this test simulate a successful job insert", func(t *mtest.T) {
+		t.AddMockResponses(mtest.CreateSuccessResponse())
+		storeTest := NewStoreJob(t.DB, t.DB, *logrus.New())
+
+		id, err := storeTest.InsertJob(ctx, &model.Job{
+			Type:   "transaction.export",
+			Status: model.JobStatusPending,
+		})
+
+		assert.NoError(t, err)
+		assert.NotEmpty(t, id)
+	})
+
+	testObj.mt.Run("this test simulate an error during a job insert", func(t *mtest.T) {
+		t.AddMockResponses(mtest.CreateCommandErrorResponse(mtest.CommandError{
+			Code: 2,
+		}))
+		storeTest := NewStoreJob(t.DB, t.DB, *logrus.New())
+
+		id, err := storeTest.InsertJob(ctx, &model.Job{})
+
+		assert.Error(t, err)
+		assert.Empty(t, id)
+	})
+}
+
+func TestGetJobById(t *testing.T) {
+	testObj := prepareTest(t)
+	ctx := context.Background()
+
+	testObj.mt.Run("This test simulates the process for obtaining a job", func(t *mtest.T) {
+		id := primitive.NewObjectID()
+		t.AddMockResponses(mtest.CreateCursorResponse(1, "foo.bar", mtest.FirstBatch, bson.D{
+			{Key: "_id", Value: id},
+			{Key: "type", Value: "transaction.export"},
+			{Key: "status", Value: model.JobStatusRunning},
+			{Key: "progress", Value: int64(10)},
+		}))
+		storeTest := NewStoreJob(t.DB, t.DB, *logrus.New())
+
+		job, err := storeTest.GetJobById(ctx, testAccountId, id.Hex())
+
+		assert.NoError(t, err)
+		assert.Equal(t, job, &model.Job{
+			Id:       id.Hex(),
+			Type:     "transaction.export",
+			Status:   model.JobStatusRunning,
+			Progress: 10,
+		})
+
+		filter := t.GetStartedEvent().Command.Lookup("filter").Document()
+		assert.Equal(t, filter.Lookup("account_id").StringValue(), testAccountId)
+	})
+
+	testObj.mt.Run("This test simulates a job that does not exist", func(t *mtest.T) {
+		t.AddMockResponses(mtest.CreateCursorResponse(0, "foo.bar", mtest.FirstBatch))
+		storeTest := NewStoreJob(t.DB, t.DB, *logrus.New())
+
+		job, err := storeTest.GetJobById(ctx, testAccountId, primitive.NewObjectID().Hex())
+
+		assert.ErrorIs(t, err, ErrJobNotFound)
+		assert.Nil(t, job)
+	})
+
+	testObj.mt.Run("This test simulates obtaining a job without an account", func(t *mtest.T) {
+		storeTest := NewStoreJob(t.DB, t.DB, *logrus.New())
+
+		job, err := storeTest.GetJobById(ctx, "", primitive.NewObjectID().Hex())
+
+		assert.ErrorIs(t, err, tenant.ErrMissingAccount)
+		assert.Nil(t, job)
+	})
+
+	testObj.mt.Run("This test simulates an invalid job id", func(t *mtest.T) {
+		storeTest := NewStoreJob(t.DB, t.DB, *logrus.New())
+
+		job, err := storeTest.GetJobById(ctx, testAccountId, "test")
+
+		assert.ErrorIs(t, err, ErrJobNotFound)
+		assert.Nil(t, job)
+	})
+}
+
+func TestClaimJob(t *testing.T) {
+	testObj := prepareTest(t)
+	ctx := context.Background()
+
+	testObj.mt.Run("This test simulates claiming a pending job", func(t *mtest.T) {
+		id := primitive.NewObjectID()
+		t.AddMockResponses(bson.D{
+			{Key: "ok", Value: 1},
+			{Key: "value", Value: bson.D{
+				{Key: "_id", Value: id},
+				{Key: "type", Value: "transaction.export"},
+				{Key: "status", Value: model.JobStatusRunning},
+				{Key: "owner", Value: "worker"},
+			}},
+		})
+		storeTest := NewStoreJob(t.DB, t.DB, *logrus.New())
+
+		job, err := storeTest.ClaimJob(ctx, "worker", 1697150153, 1697150183)
+
+		assert.NoError(t, err)
+		assert.Equal(t, job.Id, id.Hex())
+		assert.Equal(t, job.Owner, "worker")
+	})
+
+	testObj.mt.Run("This test simulates claiming without pending jobs", func(t *mtest.T) {
+		t.AddMockResponses(bson.D{
+			{Key: "ok", Value: 1},
+			{Key: "value", Value: nil},
+		})
+		storeTest := NewStoreJob(t.DB, t.DB, *logrus.New())
+
+		job, err := storeTest.ClaimJob(ctx, "worker", 1697150153, 1697150183)
+
+		assert.NoError(t, err)
+		assert.Nil(t, job)
+	})
+}
+
+func TestFinishJob(t *testing.T) {
+	testObj := prepareTest(t)
+	ctx := context.Background()
+
+	testObj.mt.Run("This test simulates finishing a job", func(t *mtest.T) {
+		t.AddMockResponses(bson.D{{Key: "ok", Value: 1}, {Key: "n", Value: 1}, {Key: "nModified", Value: 1}})
+		storeTest := NewStoreJob(t.DB, t.DB, *logrus.New())
+
+		err := storeTest.FinishJob(ctx, primitive.NewObjectID().Hex(), "worker", &model.Job{
+			Status: model.JobStatusSucceeded,
+			Result: "result.csv",
+		}, 1697150153)
+
+		assert.NoError(t, err)
+	})
+
+	testObj.mt.Run("This test simulates finishing a job owned by another worker", func(t *mtest.T) {
+		t.AddMockResponses(bson.D{{Key: "ok", Value: 1}, {Key: "n", Value: 0}, {Key: "nModified", Value: 0}})
+		storeTest := NewStoreJob(t.DB, t.DB, *logrus.New())
+
+		err := storeTest.FinishJob(ctx, primitive.NewObjectID().Hex(), "worker", &model.Job{
+			Status: model.JobStatusSucceeded,
+		}, 1697150153)
+
+		assert.ErrorIs(t, err, ErrJobNotFound)
+	})
+}
+
+func TestCancelJob(t *testing.T) {
+	testObj := prepareTest(t)
+	ctx := context.Background()
+
+	testObj.mt.Run("This test simulates canceling a job", func(t *mtest.T) {
+		id := primitive.NewObjectID()
+		t.AddMockResponses(bson.D{
+			{Key: "ok", Value: 1},
+			{Key: "value", Value: bson.D{
+				{Key: "_id", Value: id},
+				{Key: "status", Value: model.JobStatusCanceled},
+			}},
+		})
+		storeTest := NewStoreJob(t.DB, t.DB, *logrus.New())
+
+		job, err := storeTest.CancelJob(ctx, testAccountId, id.Hex(), 1697150153)
+
+		assert.NoError(t, err)
+		assert.Equal(t, job.Status, model.JobStatusCanceled)
+	})
+
+	testObj.mt.Run("This test simulates an error when canceling a job", func(t *mtest.T) {
+		t.AddMockResponses(mtest.CreateCommandErrorResponse(mtest.CommandError{
+			Code: 2,
+		}))
+		storeTest := NewStoreJob(t.DB, t.DB, *logrus.New())
+
+		job, err := storeTest.CancelJob(ctx, testAccountId, primitive.NewObjectID().Hex(), 1697150153)
+
+		assert.Error(t, err)
+		assert.Nil(t, job)
+	})
+}
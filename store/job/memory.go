@@ -0,0 +1,210 @@
+package job
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/jcpribeiro/TransactionApp/internal/tenant"
+	"github.com/jcpribeiro/TransactionApp/model"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// memoryStore keeps the jobs in process, it is meant for tests and local development
+type memoryStore struct {
+	mu   sync.Mutex
+	jobs map[string]*model.Job
+}
+
+func NewMemoryStoreJob() Store {
+	return &memoryStore{
+		jobs: make(map[string]*model.Job),
+	}
+}
+
+func copyJob(job *model.Job) *model.Job {
+	c := *job
+	if job.Params != nil {
+		c.Params = make(map[string]string, len(job.Params))
+		for k, v := range job.Params {
+			c.Params[k] = v
+		}
+	}
+
+	return &c
+}
+
+func (m *memoryStore) InsertJob(ctx context.Context, job *model.Job) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	c := copyJob(job)
+	c.Id = primitive.NewObjectID().Hex()
+	m.jobs[c.Id] = c
+
+	return c.Id, nil
+}
+
+func (m *memoryStore) accountJob(accountId, id string) (*model.Job, error) {
+	if len(accountId) == 0 {
+		return nil, tenant.ErrMissingAccount
+	}
+
+	job, ok := m.jobs[id]
+	if !ok || job.AccountId != accountId {
+		return nil, ErrJobNotFound
+	}
+
+	return job, nil
+}
+
+func (m *memoryStore) GetJobById(ctx context.Context, accountId, id string) (*model.Job, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	job, err := m.accountJob(accountId, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return copyJob(job), nil
+}
+
+func (m *memoryStore) ClaimJob(ctx context.Context, owner string, now, lockedUntil int64) (*model.Job, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	candidates := make([]*model.Job, 0)
+	for _, job := range m.jobs {
+		if job.Status == model.JobStatusPending || (job.Status == model.JobStatusRunning && job.LockedUntil < now) {
+			candidates = append(candidates, job)
+		}
+	}
+	if len(candidates) == 0 {
+		return nil, nil
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].CreatedAt != candidates[j].CreatedAt {
+			return candidates[i].CreatedAt < candidates[j].CreatedAt
+		}
+		return candidates[i].Id < candidates[j].Id
+	})
+
+	job := candidates[0]
+	job.Status = model.JobStatusRunning
+	job.Owner = owner
+	job.LockedUntil = lockedUntil
+	job.UpdatedAt = now
+
+	return copyJob(job), nil
+}
+
+func (m *memoryStore) ownedJob(id, owner string) (*model.Job, error) {
+	job, ok := m.jobs[id]
+	if !ok || job.Owner != owner || job.Status != model.JobStatusRunning {
+		return nil, ErrJobNotFound
+	}
+
+	return job, nil
+}
+
+func (m *memoryStore) UpdateJobProgress(ctx context.Context, id, owner string, progress, total, lockedUntil, now int64) (*model.Job, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	job, err := m.ownedJob(id, owner)
+	if err != nil {
+		return nil, err
+	}
+
+	job.Progress = progress
+	job.Total = total
+	job.LockedUntil = lockedUntil
+	job.UpdatedAt = now
+
+	return copyJob(job), nil
+}
+
+func (m *memoryStore) FinishJob(ctx context.Context, id, owner string, finished *model.Job, now int64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	job, err := m.ownedJob(id, owner)
+	if err != nil {
+		return err
+	}
+
+	job.Status = finished.Status
+	job.Progress = finished.Progress
+	job.Total = finished.Total
+	job.Result = finished.Result
+	job.Error = finished.Error
+	job.Owner = ""
+	job.LockedUntil = 0
+	job.UpdatedAt = now
+
+	return nil
+}
+
+func (m *memoryStore) ReleaseJob(ctx context.Context, id, owner string, now int64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	job, err := m.ownedJob(id, owner)
+	if err != nil {
+		// the job was already finished or taken over by another worker
+		return nil
+	}
+
+	job.Status = model.JobStatusPending
+	job.Owner = ""
+	job.LockedUntil = 0
+	job.UpdatedAt = now
+
+	return nil
+}
+
+func (m *memoryStore) CancelJob(ctx context.Context, accountId, id string, now int64) (*model.Job, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	job, err := m.accountJob(accountId, id)
+	if err != nil {
+		return nil, err
+	}
+
+	switch job.Status {
+	case model.JobStatusPending:
+		job.Status = model.JobStatusCanceled
+	case model.JobStatusRunning:
+		job.CancelRequested = true
+	}
+	job.UpdatedAt = now
+
+	return copyJob(job), nil
+}
+
+func (m *memoryStore) AssignAccount(ctx context.Context, accountId string) (int64, error) {
+	if len(accountId) == 0 {
+		return 0, tenant.ErrMissingAccount
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var updated int64
+	for _, job := range m.jobs {
+		if len(job.AccountId) == 0 {
+			job.AccountId = accountId
+			updated++
+		}
+	}
+
+	return updated, nil
+}
+
+func (m *memoryStore) CreateIndexes(ctx context.Context) error {
+	return nil
+}
@@ -0,0 +1,90 @@
+package migration
+
+import (
+	"context"
+
+	"github.com/jcpribeiro/TransactionApp/model"
+
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+//go:generate mockgen -source=$GOFILE -destination=migration_mock.go -package=$GOPACKAGE
+
+type Store interface {
+	GetFinishedMigrations(ctx context.Context) (map[string]bool, error)
+	ClaimMigration(ctx context.Context, id string, now, lockedUntil int64) (bool, error)
+	FinishMigration(ctx context.Context, id string, updated, now int64) error
+}
+
+type storeImpl struct {
+	mongodbConReader *mongo.Database
+	mongodbConWriter *mongo.Database
+	log              logrus.Logger
+}
+
+func NewStoreMigration(mongodbConReader, mongodbConWriter *mongo.Database, log logrus.Logger) Store {
+	return &storeImpl{
+		mongodbConReader: mongodbConReader,
+		mongodbConWriter: mongodbConWriter,
+		log:              log,
+	}
+}
+
+// Get the ids of the finished migrations. It reads from the writer so a restart sees the last finish.
+func (s storeImpl) GetFinishedMigrations(ctx context.Context) (map[string]bool, error) {
+	cursor, err := s.mongodbConWriter.Collection("migration").Find(ctx, primitive.M{"finished_at": primitive.M{"$exists": true}})
+	if err != nil {
+		return nil, err
+	}
+
+	var migrations []*model.Migration
+	if err := cursor.All(ctx, &migrations); err != nil {
+		return nil, err
+	}
+
+	finished := make(map[string]bool, len(migrations))
+	for _, m := range migrations {
+		finished[m.Id] = true
+	}
+
+	return finished, nil
+}
+
+// Claim a migration until lockedUntil. It returns false when the migration is finished or
+// another instance holds it, the upsert then collides with the existing document.
+func (s storeImpl) ClaimMigration(ctx context.Context, id string, now, lockedUntil int64) (bool, error) {
+	filter := primitive.M{
+		"_id":          id,
+		"finished_at":  primitive.M{"$exists": false},
+		"locked_until": primitive.M{"$lt": now},
+	}
+	update := primitive.M{
+		"$set": primitive.M{
+			"started_at":   now,
+			"locked_until": lockedUntil,
+		},
+	}
+
+	_, err := s.mongodbConWriter.Collection("migration").UpdateOne(ctx, filter, update, options.Update().SetUpsert(true))
+	if mongo.IsDuplicateKeyError(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// Mark a migration as finished with the number of documents it updated
+func (s storeImpl) FinishMigration(ctx context.Context, id string, updated, now int64) error {
+	_, err := s.mongodbConWriter.Collection("migration").UpdateOne(ctx, primitive.M{"_id": id}, primitive.M{
+		"$set":   primitive.M{"finished_at": now, "updated": updated},
+		"$unset": primitive.M{"locked_until": ""},
+	})
+
+	return err
+}
@@ -0,0 +1,79 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: migration.go
+
+// Package migration is a generated GoMock package.
+package migration
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockStore is a mock of Store interface.
+type MockStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockStoreMockRecorder
+}
+
+// MockStoreMockRecorder is the mock recorder for MockStore.
+type MockStoreMockRecorder struct {
+	mock *MockStore
+}
+
+// NewMockStore creates a new mock instance.
+func NewMockStore(ctrl *gomock.Controller) *MockStore {
+	mock := &MockStore{ctrl: ctrl}
+	mock.recorder = &MockStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockStore) EXPECT() *MockStoreMockRecorder {
+	return m.recorder
+}
+
+// ClaimMigration mocks base method.
+func (m *MockStore) ClaimMigration(ctx context.Context, id string, now, lockedUntil int64) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimMigration", ctx, id, now, lockedUntil)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimMigration indicates an expected call of ClaimMigration.
+func (mr *MockStoreMockRecorder) ClaimMigration(ctx, id, now, lockedUntil interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimMigration", reflect.TypeOf((*MockStore)(nil).ClaimMigration), ctx, id, now, lockedUntil)
+}
+
+// FinishMigration mocks base method.
+func (m *MockStore) FinishMigration(ctx context.Context, id string, updated, now int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FinishMigration", ctx, id, updated, now)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// FinishMigration indicates an expected call of FinishMigration.
+func (mr *MockStoreMockRecorder) FinishMigration(ctx, id, updated, now interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FinishMigration", reflect.TypeOf((*MockStore)(nil).FinishMigration), ctx, id, updated, now)
+}
+
+// GetFinishedMigrations mocks base method.
+func (m *MockStore) GetFinishedMigrations(ctx context.Context) (map[string]bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFinishedMigrations", ctx)
+	ret0, _ := ret[0].(map[string]bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFinishedMigrations indicates an expected call of GetFinishedMigrations.
+func (mr *MockStoreMockRecorder) GetFinishedMigrations(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFinishedMigrations", reflect.TypeOf((*MockStore)(nil).GetFinishedMigrations), ctx)
+}
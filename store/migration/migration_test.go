@@ -0,0 +1,109 @@
+package migration
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+type structTest struct {
+	mt *mtest.T
+}
+
+func prepareTest(t *testing.T) *structTest {
+	mt := mtest.New(t, mtest.NewOptions().DatabaseName("test").ClientType(mtest.Mock))
+	return &structTest{
+		mt: mt,
+	}
+}
+
+func TestGetFinishedMigrations(t *testing.T) {
+	testObj := prepareTest(t)
+	ctx := context.Background()
+
+	testObj.mt.Run("this test simulate obtaining the finished migrations", func(t *mtest.T) {
+		first := mtest.CreateCursorResponse(0, "test.migration", mtest.FirstBatch, bson.D{
+			{Key: "_id", Value: "0001_normalize_purchase_dates"},
+			{Key: "finished_at", Value: int64(1697409353)},
+		})
+		t.AddMockResponses(first)
+		storeTest := NewStoreMigration(t.DB, t.DB, *logrus.New())
+
+		finished, err := storeTest.GetFinishedMigrations(ctx)
+
+		assert.NoError(t, err)
+		assert.Equal(t, finished, map[string]bool{"0001_normalize_purchase_dates": true})
+	})
+
+	testObj.mt.Run("this test simulate an error obtaining the finished migrations", func(t *mtest.T) {
+		t.AddMockResponses(mtest.CreateCommandErrorResponse(mtest.CommandError{
+			Code: 2,
+		}))
+		storeTest := NewStoreMigration(t.DB, t.DB, *logrus.New())
+
+		finished, err := storeTest.GetFinishedMigrations(ctx)
+
+		assert.Error(t, err)
+		assert.Nil(t, finished)
+	})
+}
+
+func TestClaimMigration(t *testing.T) {
+	testObj := prepareTest(t)
+	ctx := context.Background()
+
+	testObj.mt.Run("this test simulate a claimed migration", func(t *mtest.T) {
+		t.AddMockResponses(mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}))
+		storeTest := NewStoreMigration(t.DB, t.DB, *logrus.New())
+
+		claimed, err := storeTest.ClaimMigration(ctx, "0001_normalize_purchase_dates", 1697409353, 1697411153)
+
+		assert.NoError(t, err)
+		assert.True(t, claimed)
+	})
+
+	testObj.mt.Run("this test simulate a migration held by another instance", func(t *mtest.T) {
+		t.AddMockResponses(mtest.CreateWriteErrorsResponse(mtest.WriteError{
+			Index:   0,
+			Code:    11000,
+			Message: "duplicate key error",
+		}))
+		storeTest := NewStoreMigration(t.DB, t.DB, *logrus.New())
+
+		claimed, err := storeTest.ClaimMigration(ctx, "0001_normalize_purchase_dates", 1697409353, 1697411153)
+
+		assert.NoError(t, err)
+		assert.False(t, claimed)
+	})
+
+	testObj.mt.Run("this test simulate an error claiming a migration", func(t *mtest.T) {
+		t.AddMockResponses(mtest.CreateCommandErrorResponse(mtest.CommandError{
+			Code: 2,
+		}))
+		storeTest := NewStoreMigration(t.DB, t.DB, *logrus.New())
+
+		claimed, err := storeTest.ClaimMigration(ctx, "0001_normalize_purchase_dates", 1697409353, 1697411153)
+
+		assert.Error(t, err)
+		assert.False(t, claimed)
+	})
+}
+
+func TestFinishMigration(t *testing.T) {
+	testObj := prepareTest(t)
+	ctx := context.Background()
+
+	testObj.mt.Run("this test simulate a finished migration", func(t *mtest.T) {
+		t.AddMockResponses(mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}))
+		storeTest := NewStoreMigration(t.DB, t.DB, *logrus.New())
+
+		err := storeTest.FinishMigration(ctx, "0001_normalize_purchase_dates", 12, 1697409353)
+
+		assert.NoError(t, err)
+	})
+}
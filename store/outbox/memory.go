@@ -0,0 +1,119 @@
+package outbox
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/jcpribeiro/TransactionApp/model"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// memoryStore keeps the events in process, it is meant for tests and local development
+type memoryStore struct {
+	mu     sync.Mutex
+	events map[string]*model.OutboxEvent
+}
+
+func NewMemoryStoreOutbox() Store {
+	return &memoryStore{
+		events: make(map[string]*model.OutboxEvent),
+	}
+}
+
+func copyEvent(event *model.OutboxEvent) *model.OutboxEvent {
+	c := *event
+	c.Payload = append([]byte(nil), event.Payload...)
+
+	return &c
+}
+
+func (m *memoryStore) InsertEvents(ctx context.Context, events []*model.OutboxEvent) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, event := range events {
+		c := copyEvent(event)
+		c.Id = primitive.NewObjectID().Hex()
+		m.events[c.Id] = c
+	}
+
+	return nil
+}
+
+func (m *memoryStore) ClaimEvent(ctx context.Context, owner string, now, lockedUntil int64) (*model.OutboxEvent, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	candidates := make([]*model.OutboxEvent, 0)
+	for _, e := range m.events {
+		if (e.Status == model.OutboxStatusPending && e.NextAttemptAt <= now) ||
+			(e.Status == model.OutboxStatusRunning && e.LockedUntil < now) {
+			candidates = append(candidates, e)
+		}
+	}
+	if len(candidates) == 0 {
+		return nil, nil
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].NextAttemptAt != candidates[j].NextAttemptAt {
+			return candidates[i].NextAttemptAt < candidates[j].NextAttemptAt
+		}
+		return candidates[i].Id < candidates[j].Id
+	})
+
+	event := candidates[0]
+	event.Status = model.OutboxStatusRunning
+	event.Owner = owner
+	event.LockedUntil = lockedUntil
+	event.UpdatedAt = now
+
+	return copyEvent(event), nil
+}
+
+func (m *memoryStore) ownedEvent(id, owner string) (*model.OutboxEvent, error) {
+	event, ok := m.events[id]
+	if !ok || event.Owner != owner || event.Status != model.OutboxStatusRunning {
+		return nil, ErrEventNotFound
+	}
+
+	return event, nil
+}
+
+func (m *memoryStore) DeleteEvent(ctx context.Context, id, owner string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, err := m.ownedEvent(id, owner); err != nil {
+		return err
+	}
+	delete(m.events, id)
+
+	return nil
+}
+
+func (m *memoryStore) RetryEvent(ctx context.Context, id, owner string, retried *model.OutboxEvent, now int64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	event, err := m.ownedEvent(id, owner)
+	if err != nil {
+		return err
+	}
+
+	event.Status = model.OutboxStatusPending
+	event.Attempts = retried.Attempts
+	event.NextAttemptAt = retried.NextAttemptAt
+	event.Error = retried.Error
+	event.Owner = ""
+	event.LockedUntil = 0
+	event.UpdatedAt = now
+
+	return nil
+}
+
+func (m *memoryStore) CreateIndexes(ctx context.Context) error {
+	return nil
+}
@@ -0,0 +1,181 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jcpribeiro/TransactionApp/internal/tenant"
+	"github.com/jcpribeiro/TransactionApp/model"
+
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+//go:generate mockgen -source=$GOFILE -destination=outbox_mock.go -package=$GOPACKAGE
+
+// ErrEventNotFound is returned when the event does not exist or is no longer owned by the relay
+var ErrEventNotFound = errors.New("outbox event not found")
+
+type Store interface {
+	InsertEvents(ctx context.Context, events []*model.OutboxEvent) error
+	ClaimEvent(ctx context.Context, owner string, now, lockedUntil int64) (*model.OutboxEvent, error)
+	DeleteEvent(ctx context.Context, id, owner string) error
+	RetryEvent(ctx context.Context, id, owner string, event *model.OutboxEvent, now int64) error
+	CreateIndexes(ctx context.Context) error
+}
+
+type storeImpl struct {
+	mongodbConReader *mongo.Database
+	mongodbConWriter *mongo.Database
+	log              logrus.Logger
+}
+
+func NewStoreOutbox(mongodbConReader, mongodbConWriter *mongo.Database, log logrus.Logger) Store {
+	return &storeImpl{
+		mongodbConReader: mongodbConReader,
+		mongodbConWriter: mongodbConWriter,
+		log:              log,
+	}
+}
+
+// NewEvent returns a pending event of the account about aggregateId, with data as its payload
+func NewEvent(accountId, eventType, aggregateId string, data interface{}, now time.Time) (*model.OutboxEvent, error) {
+	if len(accountId) == 0 {
+		return nil, tenant.ErrMissingAccount
+	}
+
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode %s: %w", eventType, err)
+	}
+
+	return &model.OutboxEvent{
+		AccountId:     accountId,
+		Type:          eventType,
+		AggregateId:   aggregateId,
+		Payload:       payload,
+		Status:        model.OutboxStatusPending,
+		NextAttemptAt: now.Unix(),
+		CreatedAt:     now.Unix(),
+		UpdatedAt:     now.Unix(),
+	}, nil
+}
+
+func ownedEventFilter(id, owner string) (primitive.M, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, ErrEventNotFound
+	}
+
+	return primitive.M{"_id": objectID, "owner": owner, "status": model.OutboxStatusRunning}, nil
+}
+
+// Create the index used to claim the events
+func (s storeImpl) CreateIndexes(ctx context.Context) error {
+	_, err := s.mongodbConWriter.Collection("outbox").Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    primitive.D{{Key: "status", Value: 1}, {Key: "next_attempt_at", Value: 1}},
+		Options: options.Index().SetName("status_next_attempt_at"),
+	})
+
+	return err
+}
+
+// Insert the events of a change. With a mongo.SessionContext they are written in its transaction.
+func (s storeImpl) InsertEvents(ctx context.Context, events []*model.OutboxEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	docs := make([]interface{}, 0, len(events))
+	for _, e := range events {
+		docs = append(docs, e)
+	}
+
+	_, err := s.mongodbConWriter.Collection("outbox").InsertMany(ctx, docs)
+	return err
+}
+
+// Claim the pending event due the longest, or a running event whose lease has expired because its relay stopped.
+// It returns nil when there is no event due.
+func (s storeImpl) ClaimEvent(ctx context.Context, owner string, now, lockedUntil int64) (*model.OutboxEvent, error) {
+	filter := primitive.M{
+		"$or": primitive.A{
+			primitive.M{"status": model.OutboxStatusPending, "next_attempt_at": primitive.M{"$lte": now}},
+			primitive.M{"status": model.OutboxStatusRunning, "locked_until": primitive.M{"$lt": now}},
+		},
+	}
+	update := primitive.M{
+		"$set": primitive.M{
+			"status":       model.OutboxStatusRunning,
+			"owner":        owner,
+			"locked_until": lockedUntil,
+			"updated_at":   now,
+		},
+	}
+	opts := options.FindOneAndUpdate().
+		SetSort(primitive.D{{Key: "next_attempt_at", Value: 1}, {Key: "_id", Value: 1}}).
+		SetReturnDocument(options.After)
+
+	var event *model.OutboxEvent
+	err := s.mongodbConWriter.Collection("outbox").FindOneAndUpdate(ctx, filter, update, opts).Decode(&event)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return event, nil
+}
+
+// Delete a published event
+func (s storeImpl) DeleteEvent(ctx context.Context, id, owner string) error {
+	filter, err := ownedEventFilter(id, owner)
+	if err != nil {
+		return err
+	}
+
+	result, err := s.mongodbConWriter.Collection("outbox").DeleteOne(ctx, filter)
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return ErrEventNotFound
+	}
+
+	return nil
+}
+
+// Put an event that failed to publish back in the queue, until its next attempt
+func (s storeImpl) RetryEvent(ctx context.Context, id, owner string, event *model.OutboxEvent, now int64) error {
+	filter, err := ownedEventFilter(id, owner)
+	if err != nil {
+		return err
+	}
+
+	update := primitive.M{
+		"$set": primitive.M{
+			"status":          model.OutboxStatusPending,
+			"attempts":        event.Attempts,
+			"next_attempt_at": event.NextAttemptAt,
+			"error":           event.Error,
+			"updated_at":      now,
+		},
+		"$unset": primitive.M{"owner": "", "locked_until": ""},
+	}
+
+	result, err := s.mongodbConWriter.Collection("outbox").UpdateOne(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrEventNotFound
+	}
+
+	return nil
+}
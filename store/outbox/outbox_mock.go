@@ -0,0 +1,107 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: outbox.go
+
+// Package outbox is a generated GoMock package.
+package outbox
+
+import (
+	context "context"
+	reflect "reflect"
+	model "github.com/jcpribeiro/TransactionApp/model"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockStore is a mock of Store interface.
+type MockStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockStoreMockRecorder
+}
+
+// MockStoreMockRecorder is the mock recorder for MockStore.
+type MockStoreMockRecorder struct {
+	mock *MockStore
+}
+
+// NewMockStore creates a new mock instance.
+func NewMockStore(ctrl *gomock.Controller) *MockStore {
+	mock := &MockStore{ctrl: ctrl}
+	mock.recorder = &MockStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockStore) EXPECT() *MockStoreMockRecorder {
+	return m.recorder
+}
+
+// ClaimEvent mocks base method.
+func (m *MockStore) ClaimEvent(ctx context.Context, owner string, now, lockedUntil int64) (*model.OutboxEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimEvent", ctx, owner, now, lockedUntil)
+	ret0, _ := ret[0].(*model.OutboxEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimEvent indicates an expected call of ClaimEvent.
+func (mr *MockStoreMockRecorder) ClaimEvent(ctx, owner, now, lockedUntil interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimEvent", reflect.TypeOf((*MockStore)(nil).ClaimEvent), ctx, owner, now, lockedUntil)
+}
+
+// CreateIndexes mocks base method.
+func (m *MockStore) CreateIndexes(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateIndexes", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateIndexes indicates an expected call of CreateIndexes.
+func (mr *MockStoreMockRecorder) CreateIndexes(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateIndexes", reflect.TypeOf((*MockStore)(nil).CreateIndexes), ctx)
+}
+
+// DeleteEvent mocks base method.
+func (m *MockStore) DeleteEvent(ctx context.Context, id, owner string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteEvent", ctx, id, owner)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteEvent indicates an expected call of DeleteEvent.
+func (mr *MockStoreMockRecorder) DeleteEvent(ctx, id, owner interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteEvent", reflect.TypeOf((*MockStore)(nil).DeleteEvent), ctx, id, owner)
+}
+
+// InsertEvents mocks base method.
+func (m *MockStore) InsertEvents(ctx context.Context, events []*model.OutboxEvent) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertEvents", ctx, events)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InsertEvents indicates an expected call of InsertEvents.
+func (mr *MockStoreMockRecorder) InsertEvents(ctx, events interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertEvents", reflect.TypeOf((*MockStore)(nil).InsertEvents), ctx, events)
+}
+
+// RetryEvent mocks base method.
+func (m *MockStore) RetryEvent(ctx context.Context, id, owner string, event *model.OutboxEvent, now int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RetryEvent", ctx, id, owner, event, now)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RetryEvent indicates an expected call of RetryEvent.
+func (mr *MockStoreMockRecorder) RetryEvent(ctx, id, owner, event, now interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RetryEvent", reflect.TypeOf((*MockStore)(nil).RetryEvent), ctx, id, owner, event, now)
+}
@@ -0,0 +1,172 @@
+package outbox
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jcpribeiro/TransactionApp/internal/tenant"
+	"github.com/jcpribeiro/TransactionApp/model"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+const testAccountId = "652d34910a8fc425116b84aa"
+
+type structTest struct {
+	mt *mtest.T
+}
+
+func prepareTest(t *testing.T) *structTest {
+	mt := mtest.New(t, mtest.NewOptions().DatabaseName("test").ClientType(mtest.Mock))
+	return &structTest{
+		mt: mt,
+	}
+}
+
+func TestNewEvent(t *testing.T) {
+	now := time.Unix(1697150153, 0)
+
+	t.Run("this test simulate a new pending event", func(t *testing.T) {
+		event, err := NewEvent(testAccountId, model.EventTransactionCreated, "652d34910a8fc425116b84d9", map[string]string{"id": "652d34910a8fc425116b84d9"}, now)
+
+		assert.NoError(t, err)
+		assert.Equal(t, event.Status, model.OutboxStatusPending)
+		assert.Equal(t, event.NextAttemptAt, int64(1697150153))
+		assert.Equal(t, string(event.Payload), `{"id":"652d34910a8fc425116b84d9"}`)
+	})
+
+	t.Run("this test simulate a new event without an account", func(t *testing.T) {
+		event, err := NewEvent("", model.EventTransactionCreated, "652d34910a8fc425116b84d9", nil, now)
+
+		assert.ErrorIs(t, err, tenant.ErrMissingAccount)
+		assert.Nil(t, event)
+	})
+}
+
+func TestInsertEvents(t *testing.T) {
+	testObj := prepareTest(t)
+	ctx := context.Background()
+
+	testObj.mt.Run("this test simulate a successful events insert", func(t *mtest.T) {
+		t.AddMockResponses(mtest.CreateSuccessResponse())
+		storeTest := NewStoreOutbox(t.DB, t.DB, *logrus.New())
+
+		err := storeTest.InsertEvents(ctx, []*model.OutboxEvent{
+			{AccountId: testAccountId, Type: model.EventTransactionCreated, Status: model.OutboxStatusPending},
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, t.GetStartedEvent().CommandName, "insert")
+	})
+
+	testObj.mt.Run("this test simulate an insert without events", func(t *mtest.T) {
+		storeTest := NewStoreOutbox(t.DB, t.DB, *logrus.New())
+
+		assert.NoError(t, storeTest.InsertEvents(ctx, nil))
+		assert.Nil(t, t.GetStartedEvent())
+	})
+}
+
+func TestClaimEvent(t *testing.T) {
+	testObj := prepareTest(t)
+	ctx := context.Background()
+
+	testObj.mt.Run("This test simulates claiming a due event", func(t *mtest.T) {
+		id := primitive.NewObjectID()
+		t.AddMockResponses(bson.D{
+			{Key: "ok", Value: 1},
+			{Key: "value", Value: bson.D{
+				{Key: "_id", Value: id},
+				{Key: "account_id", Value: testAccountId},
+				{Key: "type", Value: model.EventTransactionCreated},
+				{Key: "payload", Value: primitive.Binary{Data: []byte(`{"id":"652d34910a8fc425116b84d9"}`)}},
+				{Key: "status", Value: model.OutboxStatusRunning},
+				{Key: "owner", Value: "relay"},
+			}},
+		})
+		storeTest := NewStoreOutbox(t.DB, t.DB, *logrus.New())
+
+		event, err := storeTest.ClaimEvent(ctx, "relay", 1697150153, 1697150183)
+
+		assert.NoError(t, err)
+		assert.Equal(t, event.Id, id.Hex())
+		assert.Equal(t, event.Type, model.EventTransactionCreated)
+		assert.Equal(t, string(event.Payload), `{"id":"652d34910a8fc425116b84d9"}`)
+	})
+
+	testObj.mt.Run("This test simulates claiming without due events", func(t *mtest.T) {
+		t.AddMockResponses(bson.D{
+			{Key: "ok", Value: 1},
+			{Key: "value", Value: nil},
+		})
+		storeTest := NewStoreOutbox(t.DB, t.DB, *logrus.New())
+
+		event, err := storeTest.ClaimEvent(ctx, "relay", 1697150153, 1697150183)
+
+		assert.NoError(t, err)
+		assert.Nil(t, event)
+	})
+}
+
+func TestDeleteEvent(t *testing.T) {
+	testObj := prepareTest(t)
+	ctx := context.Background()
+
+	testObj.mt.Run("This test simulates deleting a published event", func(t *mtest.T) {
+		t.AddMockResponses(bson.D{{Key: "ok", Value: 1}, {Key: "n", Value: 1}})
+		storeTest := NewStoreOutbox(t.DB, t.DB, *logrus.New())
+
+		assert.NoError(t, storeTest.DeleteEvent(ctx, primitive.NewObjectID().Hex(), "relay"))
+	})
+
+	testObj.mt.Run("This test simulates deleting an event owned by another relay", func(t *mtest.T) {
+		t.AddMockResponses(bson.D{{Key: "ok", Value: 1}, {Key: "n", Value: 0}})
+		storeTest := NewStoreOutbox(t.DB, t.DB, *logrus.New())
+
+		err := storeTest.DeleteEvent(ctx, primitive.NewObjectID().Hex(), "relay")
+		assert.ErrorIs(t, err, ErrEventNotFound)
+	})
+
+	testObj.mt.Run("This test simulates an invalid event id", func(t *mtest.T) {
+		storeTest := NewStoreOutbox(t.DB, t.DB, *logrus.New())
+
+		err := storeTest.DeleteEvent(ctx, "invalid", "relay")
+		assert.ErrorIs(t, err, ErrEventNotFound)
+	})
+}
+
+func TestRetryEvent(t *testing.T) {
+	testObj := prepareTest(t)
+	ctx := context.Background()
+
+	testObj.mt.Run("This test simulates scheduling the next attempt of an event", func(t *mtest.T) {
+		t.AddMockResponses(bson.D{{Key: "ok", Value: 1}, {Key: "n", Value: 1}, {Key: "nModified", Value: 1}})
+		storeTest := NewStoreOutbox(t.DB, t.DB, *logrus.New())
+
+		err := storeTest.RetryEvent(ctx, primitive.NewObjectID().Hex(), "relay", &model.OutboxEvent{
+			Attempts:      1,
+			NextAttemptAt: 1697150163,
+			Error:         "redis: connection refused",
+		}, 1697150153)
+
+		assert.NoError(t, err)
+
+		set := t.GetStartedEvent().Command.Lookup("updates").Array().Index(0).Value().Document().Lookup("u", "$set").Document()
+		assert.Equal(t, set.Lookup("status").StringValue(), model.OutboxStatusPending)
+		assert.Equal(t, set.Lookup("next_attempt_at").Int64(), int64(1697150163))
+	})
+
+	testObj.mt.Run("This test simulates retrying an event owned by another relay", func(t *mtest.T) {
+		t.AddMockResponses(bson.D{{Key: "ok", Value: 1}, {Key: "n", Value: 0}, {Key: "nModified", Value: 0}})
+		storeTest := NewStoreOutbox(t.DB, t.DB, *logrus.New())
+
+		err := storeTest.RetryEvent(ctx, primitive.NewObjectID().Hex(), "relay", &model.OutboxEvent{}, 1697150153)
+		assert.ErrorIs(t, err, ErrEventNotFound)
+	})
+}
@@ -1,7 +1,12 @@
 package store
 
 import (
+	"github.com/jcpribeiro/TransactionApp/store/apikey"
+	"github.com/jcpribeiro/TransactionApp/store/job"
+	"github.com/jcpribeiro/TransactionApp/store/migration"
+	"github.com/jcpribeiro/TransactionApp/store/outbox"
 	"github.com/jcpribeiro/TransactionApp/store/transaction"
+	"github.com/jcpribeiro/TransactionApp/store/webhook"
 
 	"github.com/sirupsen/logrus"
 	"go.mongodb.org/mongo-driver/mongo"
@@ -9,6 +14,11 @@ import (
 
 type Container struct {
 	Transaction transaction.Store
+	Job         job.Store
+	APIKey      apikey.Store
+	Migration   migration.Store
+	Webhook     webhook.Store
+	Outbox      outbox.Store
 }
 
 type Options struct {
@@ -21,5 +31,10 @@ func NewStore(opts Options) *Container {
 	logrus.Info("Registered STORE")
 	return &Container{
 		Transaction: transaction.NewStoreTransaction(opts.MongodbConReader, opts.MongodbConWriter, opts.Log),
+		Job:         job.NewStoreJob(opts.MongodbConReader, opts.MongodbConWriter, opts.Log),
+		APIKey:      apikey.NewStoreAPIKey(opts.MongodbConReader, opts.MongodbConWriter, opts.Log),
+		Migration:   migration.NewStoreMigration(opts.MongodbConReader, opts.MongodbConWriter, opts.Log),
+		Webhook:     webhook.NewStoreWebhook(opts.MongodbConReader, opts.MongodbConWriter, opts.Log),
+		Outbox:      outbox.NewStoreOutbox(opts.MongodbConReader, opts.MongodbConWriter, opts.Log),
 	}
 }
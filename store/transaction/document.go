@@ -0,0 +1,96 @@
+package transaction
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/jcpribeiro/TransactionApp/model"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// document is the stored form of a transaction. CreatedAt is when it was stored, PurchaseDate is the
+// UTC midnight of the purchase date and PurchasedAt the purchase time, with the offset it was sent with.
+type document struct {
+	Id             primitive.ObjectID `bson:"_id,omitempty"`
+	AccountId      string             `bson:"account_id,omitempty"`
+	SourceId       string             `bson:"source_id,omitempty"`
+	PurchaseAmount float64            `bson:"purchase_amount,omitempty"`
+	SourceCurrency string             `bson:"source_currency,omitempty"`
+	Description    string             `bson:"description,omitempty"`
+	CreatedAt      time.Time          `bson:"created_at"`
+	PurchaseDate   time.Time          `bson:"purchase_date"`
+	PurchasedAt    *time.Time         `bson:"purchased_at,omitempty"`
+	PurchaseOffset int                `bson:"purchase_offset,omitempty"`
+	Category       string             `bson:"category,omitempty"`
+	Tags           []string           `bson:"tags,omitempty"`
+	Merchant       string             `bson:"merchant,omitempty"`
+	Metadata       map[string]string  `bson:"metadata,omitempty"`
+}
+
+// newDocument converts a transaction whose purchase date and time were already validated
+func newDocument(accountId string, transaction *model.Transaction, createdAt time.Time) (*document, error) {
+	purchaseDate, err := time.ParseInLocation(model.DateLayout, transaction.PurchaseDate, time.UTC)
+	if err != nil {
+		return nil, fmt.Errorf("invalid purchase_date %q: %w", transaction.PurchaseDate, err)
+	}
+
+	d := &document{
+		AccountId:      accountId,
+		SourceId:       transaction.SourceId,
+		PurchaseAmount: transaction.PurchaseAmount,
+		SourceCurrency: transaction.SourceCurrency,
+		Description:    transaction.Description,
+		CreatedAt:      createdAt.UTC(),
+		PurchaseDate:   purchaseDate,
+		Category:       transaction.Category,
+		Tags:           transaction.Tags,
+		Merchant:       transaction.Merchant,
+		Metadata:       transaction.Metadata,
+	}
+
+	if len(transaction.PurchasedAt) > 0 {
+		purchasedAt, err := time.Parse(time.RFC3339, transaction.PurchasedAt)
+		if err != nil {
+			return nil, fmt.Errorf("invalid purchased_at %q: %w", transaction.PurchasedAt, err)
+		}
+		_, d.PurchaseOffset = purchasedAt.Zone()
+		utc := purchasedAt.UTC()
+		d.PurchasedAt = &utc
+	}
+
+	return d, nil
+}
+
+func (d *document) response() *model.TransactionResponse {
+	response := &model.TransactionResponse{
+		Id:             d.Id.Hex(),
+		AccountId:      d.AccountId,
+		PurchaseAmount: d.PurchaseAmount,
+		SourceCurrency: d.SourceCurrency,
+		Description:    d.Description,
+		PurchaseDate:   d.PurchaseDate.UTC().Format(model.DateLayout),
+		Category:       d.Category,
+		Tags:           d.Tags,
+		Merchant:       d.Merchant,
+		Metadata:       d.Metadata,
+	}
+
+	if !d.CreatedAt.IsZero() {
+		response.CreatedAt = d.CreatedAt.UTC().Format(time.RFC3339)
+	}
+	if d.PurchasedAt != nil {
+		response.PurchasedAt = d.PurchasedAt.In(time.FixedZone("", d.PurchaseOffset)).Format(time.RFC3339)
+	}
+
+	return response
+}
+
+func responses(documents []*document) []*model.TransactionResponse {
+	transactions := make([]*model.TransactionResponse, 0, len(documents))
+	for _, d := range documents {
+		transactions = append(transactions, d.response())
+	}
+
+	return transactions
+}
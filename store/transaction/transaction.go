@@ -2,104 +2,250 @@ package transaction
 
 import (
 	"context"
+	"errors"
+	"time"
 
+	"github.com/jcpribeiro/TransactionApp/internal/tenant"
 	"github.com/jcpribeiro/TransactionApp/model"
+	"github.com/jcpribeiro/TransactionApp/store/outbox"
 
 	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
 )
 
 //go:generate mockgen -source=$GOFILE -destination=transaction_mock.go -package=$GOPACKAGE
 
 type Store interface {
-	InsertTransaction(ctx context.Context, transaction *model.Transaction) (string, error)
-	InsertTransactions(ctx context.Context, transaction []*model.Transaction) ([]string, error)
-	GetTransactionById(ctx context.Context, id string) (*model.TransactionResponse, error)
-	GetTransactionByIds(ctx context.Context, ids []string) ([]*model.TransactionResponse, error)
-	GetTransactionByDate(ctx context.Context, startDate, endDate int64) ([]*model.TransactionResponse, error)
+	InsertTransaction(ctx context.Context, accountId string, transaction *model.Transaction) (string, error)
+	InsertTransactions(ctx context.Context, accountId string, transaction []*model.Transaction) ([]string, error)
+	GetTransactionById(ctx context.Context, accountId, id string) (*model.TransactionResponse, error)
+	GetTransactionByIds(ctx context.Context, accountId string, ids []string) ([]*model.TransactionResponse, error)
+	GetTransactionByDate(ctx context.Context, accountId string, period *model.Period, attributes *model.AttributeFilter) ([]*model.TransactionResponse, error)
+	GetTransactionSummary(ctx context.Context, accountId string, period *model.Period, groupBy string) ([]*model.TransactionAggregate, error)
+	SearchTransactions(ctx context.Context, accountId string, filter *model.TransactionFilter) ([]*model.TransactionResponse, error)
+	StreamTransactionByDate(ctx context.Context, accountId string, period *model.Period, fn func(*model.TransactionResponse) error) error
+	NormalizePurchaseDates(ctx context.Context) (int64, error)
+	ConvertDateFields(ctx context.Context) (int64, error)
+	CountWithoutAccount(ctx context.Context) (int64, error)
+	AssignAccount(ctx context.Context, accountId string) (int64, error)
+	CreateIndexes(ctx context.Context) error
 }
 
 type storeImpl struct {
 	mongodbConReader *mongo.Database
 	mongodbConWriter *mongo.Database
+	outbox           outbox.Store
 	log              logrus.Logger
 }
 
 const (
-	descriptionMaxLength = 50
+	indexNotFoundCode = 27
+	migrationBatch    = 500
 )
 
+// unpaddedPurchaseDate matches the purchase dates stored without the zero padding of the month or day, like 2023-10-5
+var unpaddedPurchaseDate = primitive.Regex{Pattern: `^\d{4}-(\d-\d{1,2}|\d{2}-\d)$`}
+
+// legacyIndexes are the indexes created before the transactions were scoped to an account
+var legacyIndexes = []string{"description_text", "created_at", "purchase_date"}
+
+// periodFormats maps a summary grouping to the $dateToString format of its period
+var periodFormats = map[string]string{
+	"day":   "%Y-%m-%d",
+	"week":  "%G-W%V",
+	"month": "%Y-%m",
+}
+
 func NewStoreTransaction(mongodbConReader, mongodbConWriter *mongo.Database, log logrus.Logger) Store {
 	return &storeImpl{
 		mongodbConReader: mongodbConReader,
 		mongodbConWriter: mongodbConWriter,
+		outbox:           outbox.NewStoreOutbox(mongodbConReader, mongodbConWriter, log),
 		log:              log,
 	}
 }
 
-func setDescriptionMaxLength(description string) string {
-	if len(description) > descriptionMaxLength {
-		return description[:descriptionMaxLength]
+// accountFilter starts every query with the account, so a transaction is never read by another account
+func accountFilter(accountId string) (primitive.M, error) {
+	if len(accountId) == 0 {
+		return nil, tenant.ErrMissingAccount
+	}
+
+	return primitive.M{"account_id": accountId}, nil
+}
+
+// insertWithEvents inserts the documents and their transaction.created outbox events in one
+// Mongo transaction, so an event is stored if and only if its transaction is. A document whose
+// source was already inserted is skipped and gets the id of the stored one.
+func (s storeImpl) insertWithEvents(ctx context.Context, accountId string, documents []*document, now time.Time) error {
+	err := s.insertInTransaction(ctx, accountId, documents, now)
+	if mongo.IsDuplicateKeyError(err) {
+		// a source was inserted concurrently, it is skipped when inserting again
+		err = s.insertInTransaction(ctx, accountId, documents, now)
 	}
 
-	return description
+	return err
+}
+
+func (s storeImpl) insertInTransaction(ctx context.Context, accountId string, documents []*document, now time.Time) error {
+	session, err := s.mongodbConWriter.Client().StartSession()
+	if err != nil {
+		return err
+	}
+	defer session.EndSession(ctx)
+
+	_, err = session.WithTransaction(ctx, func(sc mongo.SessionContext) (interface{}, error) {
+		pending, err := s.skipInserted(sc, accountId, documents)
+		if err != nil || len(pending) == 0 {
+			return nil, err
+		}
+
+		docs := make([]interface{}, 0, len(pending))
+		events := make([]*model.OutboxEvent, 0, len(pending))
+		for _, d := range pending {
+			d.Id = primitive.NewObjectID()
+			docs = append(docs, d)
+
+			event, err := outbox.NewEvent(accountId, model.EventTransactionCreated, d.Id.Hex(), d.response(), now)
+			if err != nil {
+				return nil, err
+			}
+			events = append(events, event)
+		}
+
+		if _, err := s.mongodbConWriter.Collection("transaction").InsertMany(sc, docs); err != nil {
+			return nil, err
+		}
+
+		return nil, s.outbox.InsertEvents(sc, events)
+	}, options.Transaction().SetReadPreference(readpref.Primary()))
+
+	return err
 }
 
-// Insert a new transaction
-func (s storeImpl) InsertTransaction(ctx context.Context, transaction *model.Transaction) (string, error) {
-	transaction.Description = setDescriptionMaxLength(transaction.Description)
-	insertedId, err := s.mongodbConWriter.Collection("transaction").InsertOne(ctx, transaction)
+// skipInserted sets the id of the documents whose source_id is already stored and returns the others,
+// so a source inserted again, like a resumed import or a redelivered message, is not duplicated
+func (s storeImpl) skipInserted(ctx context.Context, accountId string, documents []*document) ([]*document, error) {
+	sources := make([]string, 0, len(documents))
+	for _, d := range documents {
+		if len(d.SourceId) > 0 {
+			sources = append(sources, d.SourceId)
+		}
+	}
+	if len(sources) == 0 {
+		return documents, nil
+	}
+
+	cursor, err := s.mongodbConWriter.Collection("transaction").Find(ctx,
+		primitive.M{"account_id": accountId, "source_id": primitive.M{"$in": sources}},
+		options.Find().SetProjection(primitive.M{"source_id": 1}))
 	if err != nil {
+		return nil, err
+	}
+
+	var inserted []*document
+	if err := cursor.All(ctx, &inserted); err != nil {
+		return nil, err
+	}
+
+	ids := make(map[string]primitive.ObjectID, len(inserted))
+	for _, d := range inserted {
+		ids[d.SourceId] = d.Id
+	}
+
+	pending := make([]*document, 0, len(documents))
+	for _, d := range documents {
+		if id, ok := ids[d.SourceId]; ok && len(d.SourceId) > 0 {
+			d.Id = id
+			continue
+		}
+		pending = append(pending, d)
+	}
+
+	return pending, nil
+}
+
+// Insert a new transaction and its created event, its created_at is the insert time
+func (s storeImpl) InsertTransaction(ctx context.Context, accountId string, transaction *model.Transaction) (string, error) {
+	if len(accountId) == 0 {
+		return "", tenant.ErrMissingAccount
+	}
+
+	now := time.Now()
+	d, err := newDocument(accountId, transaction, now)
+	if err != nil {
+		return "", err
+	}
+
+	if err := s.insertWithEvents(ctx, accountId, []*document{d}, now); err != nil {
 		return "", err
 	}
 
-	return insertedId.InsertedID.(primitive.ObjectID).Hex(), nil
+	return d.Id.Hex(), nil
 }
 
-// Insert an array of new transactions
-func (s storeImpl) InsertTransactions(ctx context.Context, transaction []*model.Transaction) ([]string, error) {
-	var exec []interface{}
+// Insert an array of new transactions and their created events, they share the insert time as created_at
+func (s storeImpl) InsertTransactions(ctx context.Context, accountId string, transaction []*model.Transaction) ([]string, error) {
+	if len(accountId) == 0 {
+		return []string{}, tenant.ErrMissingAccount
+	}
+
+	now := time.Now()
+	documents := make([]*document, 0, len(transaction))
 	for _, t := range transaction {
-		t.Description = setDescriptionMaxLength(t.Description)
-		exec = append(exec, t)
+		d, err := newDocument(accountId, t, now)
+		if err != nil {
+			return []string{}, err
+		}
+		documents = append(documents, d)
 	}
 
-	insertedId, err := s.mongodbConWriter.Collection("transaction").InsertMany(ctx, exec)
-	if err != nil {
+	if err := s.insertWithEvents(ctx, accountId, documents, now); err != nil {
 		return []string{}, err
 	}
 
 	ids := []string{}
-	for _, id := range insertedId.InsertedIDs {
-		ids = append(ids, id.(primitive.ObjectID).Hex())
+	for _, d := range documents {
+		ids = append(ids, d.Id.Hex())
 	}
 
 	return ids, nil
 }
 
 // Get a single transaction info, filtering by id
-func (s storeImpl) GetTransactionById(ctx context.Context, id string) (*model.TransactionResponse, error) {
-	objectID, err := primitive.ObjectIDFromHex(id)
+func (s storeImpl) GetTransactionById(ctx context.Context, accountId, id string) (*model.TransactionResponse, error) {
+	filter, err := accountFilter(accountId)
 	if err != nil {
 		return nil, err
 	}
 
-	filter := primitive.M{
-		"_id": objectID,
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, err
 	}
+	filter["_id"] = objectID
 
-	var transaction *model.TransactionResponse
-	err = s.mongodbConReader.Collection("transaction").FindOne(ctx, filter).Decode(&transaction)
+	var d document
+	err = s.mongodbConReader.Collection("transaction").FindOne(ctx, filter).Decode(&d)
 	if err != nil {
 		return nil, err
 	}
 
-	return transaction, nil
+	return d.response(), nil
 }
 
 // Get a multiple transactions info, filtering by the ids array
-func (s storeImpl) GetTransactionByIds(ctx context.Context, ids []string) ([]*model.TransactionResponse, error) {
+func (s storeImpl) GetTransactionByIds(ctx context.Context, accountId string, ids []string) ([]*model.TransactionResponse, error) {
+	filter, err := accountFilter(accountId)
+	if err != nil {
+		return nil, err
+	}
+
 	arrObjectIDs := make([]primitive.ObjectID, 0, len(ids))
 	for _, id := range ids {
 		objectID, err := primitive.ObjectIDFromHex(id)
@@ -109,39 +255,505 @@ func (s storeImpl) GetTransactionByIds(ctx context.Context, ids []string) ([]*mo
 		arrObjectIDs = append(arrObjectIDs, objectID)
 	}
 
-	filter := primitive.M{
-		"_id": primitive.M{"$in": arrObjectIDs},
-	}
+	filter["_id"] = primitive.M{"$in": arrObjectIDs}
 
-	transaction := make([]*model.TransactionResponse, 0, len(ids))
+	documents := make([]*document, 0, len(ids))
 	cursor, err := s.mongodbConReader.Collection("transaction").Find(ctx, filter)
 	if err != nil {
 		return nil, err
 	}
 
-	err = cursor.All(ctx, &transaction)
+	err = cursor.All(ctx, &documents)
 	if err != nil {
 		return nil, err
 	}
 
-	return transaction, nil
+	return responses(documents), nil
 }
 
-// Get a multiple transactions info, filtering by date
-func (s storeImpl) GetTransactionByDate(ctx context.Context, startDate, endDate int64) ([]*model.TransactionResponse, error) {
-	filter := primitive.M{
-		"created_at": primitive.M{"$gte": startDate, "$lt": endDate},
+// timeRange matches the times from start to end, end excluded, a zero time leaves that side open
+func timeRange(start, end time.Time) primitive.M {
+	between := primitive.M{}
+	if !start.IsZero() {
+		between["$gte"] = start
+	}
+	if !end.IsZero() {
+		between["$lt"] = end
 	}
 
-	var transaction []*model.TransactionResponse
-	cursor, err := s.mongodbConReader.Collection("transaction").Find(ctx, filter)
+	return between
+}
+
+// periodFilter adds the period to a query. On the purchase field the transactions without purchased_at
+// are matched by purchase date, between the period dates when it has them and in the time range otherwise.
+func periodFilter(query primitive.M, period *model.Period) primitive.M {
+	between := timeRange(period.Start, period.End)
+	if period.Field == model.PeriodFieldCreated {
+		if len(between) > 0 {
+			query["created_at"] = between
+		}
+		return query
+	}
+
+	days := between
+	if !period.FirstDate.IsZero() || !period.LastDate.IsZero() {
+		days = primitive.M{}
+		if !period.FirstDate.IsZero() {
+			days["$gte"] = period.FirstDate
+		}
+		if !period.LastDate.IsZero() {
+			days["$lte"] = period.LastDate
+		}
+	}
+	if len(between) == 0 && len(days) == 0 {
+		return query
+	}
+
+	timed := primitive.M{"purchased_at": primitive.M{"$exists": true}}
+	if len(between) > 0 {
+		timed = primitive.M{"purchased_at": between}
+	}
+	dated := primitive.M{"purchased_at": primitive.M{"$exists": false}}
+	if len(days) > 0 {
+		dated["purchase_date"] = days
+	}
+
+	query["$or"] = primitive.A{timed, dated}
+
+	return query
+}
+
+// periodSort orders the transactions by the time of the period field, then by insertion
+func periodSort(field string) primitive.D {
+	if field == model.PeriodFieldCreated {
+		return primitive.D{{Key: "created_at", Value: 1}, {Key: "_id", Value: 1}}
+	}
+
+	return primitive.D{{Key: "purchase_date", Value: 1}, {Key: "purchased_at", Value: 1}, {Key: "_id", Value: 1}}
+}
+
+// Get a multiple transactions info, filtering by period and optionally by attributes
+func (s storeImpl) GetTransactionByDate(ctx context.Context, accountId string, period *model.Period, attributes *model.AttributeFilter) ([]*model.TransactionResponse, error) {
+	filter, err := accountFilter(accountId)
+	if err != nil {
+		return nil, err
+	}
+	periodFilter(filter, period)
+	if attributes != nil {
+		buildAttributeFilter(filter, attributes)
+	}
+
+	var documents []*document
+	cursor, err := s.mongodbConReader.Collection("transaction").Find(ctx, filter, options.Find().SetSort(periodSort(period.Field)))
+	if err != nil {
+		return nil, err
+	}
+	err = cursor.All(ctx, &documents)
+	if err != nil {
+		return nil, err
+	}
+
+	return responses(documents), nil
+}
+
+// Get the purchase amount totals by period, purchase date and source currency, filtering by period.
+// The totals are split by purchase date and source currency because they are the exchange rate keys.
+// The periods are grouped in the period timezone by the time of the period field,
+// on the purchase field a transaction without purchased_at falls on its purchase date.
+func (s storeImpl) GetTransactionSummary(ctx context.Context, accountId string, period *model.Period, groupBy string) ([]*model.TransactionAggregate, error) {
+	match, err := accountFilter(accountId)
+	if err != nil {
+		return nil, err
+	}
+	periodFilter(match, period)
+
+	timezone := period.Timezone
+	if len(timezone) == 0 {
+		timezone = "UTC"
+	}
+
+	var group interface{} = ""
+	if format, ok := periodFormats[groupBy]; ok {
+		group = primitive.M{
+			"$dateToString": primitive.M{
+				"format":   format,
+				"timezone": timezone,
+				"date":     groupDate(period.Field, timezone),
+			},
+		}
+	}
+
+	pipeline := primitive.A{
+		primitive.M{"$match": match},
+		primitive.M{"$group": primitive.M{
+			"_id":   primitive.M{"period": group, "purchase_date": primitive.M{"$dateToString": primitive.M{"format": "%Y-%m-%d", "date": "$purchase_date"}}, "source_currency": "$source_currency"},
+			"count": primitive.M{"$sum": 1},
+			"sum":   primitive.M{"$sum": "$purchase_amount"},
+			"min":   primitive.M{"$min": "$purchase_amount"},
+			"max":   primitive.M{"$max": "$purchase_amount"},
+		}},
+		primitive.M{"$project": primitive.M{
+			"_id":             0,
+			"period":          "$_id.period",
+			"purchase_date":   "$_id.purchase_date",
+			"source_currency": "$_id.source_currency",
+			"count":           1,
+			"sum":             1,
+			"min":             1,
+			"max":             1,
+		}},
+		primitive.M{"$sort": primitive.D{
+			{Key: "period", Value: 1},
+			{Key: "purchase_date", Value: 1},
+			{Key: "source_currency", Value: 1},
+		}},
+	}
+
+	var aggregates []*model.TransactionAggregate
+	cursor, err := s.mongodbConReader.Collection("transaction").Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	err = cursor.All(ctx, &aggregates)
 	if err != nil {
 		return nil, err
 	}
-	err = cursor.All(ctx, &transaction)
+
+	return aggregates, nil
+}
+
+// groupDate is the time a summary groups a transaction by. A purchase date stands for the midnight
+// starting that day in the timezone, so it falls on the same day whatever the timezone.
+func groupDate(field, timezone string) interface{} {
+	if field == model.PeriodFieldCreated {
+		return "$created_at"
+	}
+
+	return primitive.M{"$ifNull": primitive.A{
+		"$purchased_at",
+		primitive.M{"$dateFromString": primitive.M{
+			"dateString": primitive.M{"$dateToString": primitive.M{"format": "%Y-%m-%d", "date": "$purchase_date"}},
+			"format":     "%Y-%m-%d",
+			"timezone":   timezone,
+		}},
+	}}
+}
+
+// Create the indexes used by the transaction queries, dropping the ones they replace.
+// A collection has a single text index, so the old one must go before the new one is created.
+func (s storeImpl) CreateIndexes(ctx context.Context) error {
+	for _, name := range legacyIndexes {
+		_, err := s.mongodbConWriter.Collection("transaction").Indexes().DropOne(ctx, name)
+		var cmdErr mongo.CommandError
+		if err != nil && !(errors.As(err, &cmdErr) && cmdErr.Code == indexNotFoundCode) {
+			return err
+		}
+	}
+
+	_, err := s.mongodbConWriter.Collection("transaction").Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    primitive.D{{Key: "account_id", Value: 1}, {Key: "description", Value: "text"}},
+			Options: options.Index().SetName("account_id_description_text"),
+		},
+		{
+			Keys:    primitive.D{{Key: "account_id", Value: 1}, {Key: "created_at", Value: 1}},
+			Options: options.Index().SetName("account_id_created_at"),
+		},
+		{
+			Keys:    primitive.D{{Key: "account_id", Value: 1}, {Key: "purchase_date", Value: 1}},
+			Options: options.Index().SetName("account_id_purchase_date"),
+		},
+		{
+			Keys:    primitive.D{{Key: "account_id", Value: 1}, {Key: "purchased_at", Value: 1}},
+			Options: options.Index().SetName("account_id_purchased_at"),
+		},
+		{
+			Keys:    primitive.D{{Key: "account_id", Value: 1}, {Key: "category", Value: 1}},
+			Options: options.Index().SetName("account_id_category"),
+		},
+		{
+			Keys:    primitive.D{{Key: "account_id", Value: 1}, {Key: "tags", Value: 1}},
+			Options: options.Index().SetName("account_id_tags"),
+		},
+		{
+			Keys:    primitive.D{{Key: "account_id", Value: 1}, {Key: "merchant", Value: 1}},
+			Options: options.Index().SetName("account_id_merchant"),
+		},
+		{
+			Keys:    primitive.D{{Key: "metadata.$**", Value: 1}},
+			Options: options.Index().SetName("metadata_wildcard"),
+		},
+		{
+			Keys: primitive.D{{Key: "account_id", Value: 1}, {Key: "source_id", Value: 1}},
+			Options: options.Index().SetName("account_id_source_id").SetUnique(true).
+				SetPartialFilterExpression(primitive.M{"source_id": primitive.M{"$exists": true}}),
+		},
+	})
+
+	return err
+}
+
+func buildSearchFilter(query primitive.M, filter *model.TransactionFilter) primitive.M {
+	if len(filter.Text) > 0 {
+		query["$text"] = primitive.M{"$search": filter.Text}
+	}
+
+	amount := primitive.M{}
+	if filter.MinAmount > 0 {
+		amount["$gte"] = filter.MinAmount
+	}
+	if filter.MaxAmount > 0 {
+		amount["$lte"] = filter.MaxAmount
+	}
+	if len(amount) > 0 {
+		query["purchase_amount"] = amount
+	}
+
+	periodFilter(query, &filter.Period)
+
+	purchaseDate := primitive.M{}
+	if !filter.PurchaseStartDate.IsZero() {
+		purchaseDate["$gte"] = filter.PurchaseStartDate
+	}
+	if !filter.PurchaseEndDate.IsZero() {
+		purchaseDate["$lte"] = filter.PurchaseEndDate
+	}
+	if len(purchaseDate) > 0 {
+		query["purchase_date"] = purchaseDate
+	}
+
+	return buildAttributeFilter(query, &filter.AttributeFilter)
+}
+
+// buildAttributeFilter matches the transactions having the category, merchant, every tag and every metadata value
+func buildAttributeFilter(query primitive.M, filter *model.AttributeFilter) primitive.M {
+	if len(filter.Category) > 0 {
+		query["category"] = filter.Category
+	}
+	if len(filter.Merchant) > 0 {
+		query["merchant"] = filter.Merchant
+	}
+	if len(filter.Tags) > 0 {
+		query["tags"] = primitive.M{"$all": filter.Tags}
+	}
+	for key, value := range filter.Metadata {
+		query["metadata."+key] = value
+	}
+
+	return query
+}
+
+// Get a page of transactions info, filtering by the combined search criteria
+func (s storeImpl) SearchTransactions(ctx context.Context, accountId string, filter *model.TransactionFilter) ([]*model.TransactionResponse, error) {
+	query, err := accountFilter(accountId)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := options.Find().SetSkip(filter.Skip).SetLimit(filter.Limit)
+	if len(filter.Text) > 0 {
+		opts.SetSort(primitive.D{{Key: "score", Value: primitive.M{"$meta": "textScore"}}, {Key: "_id", Value: 1}})
+	} else {
+		opts.SetSort(periodSort(filter.Period.Field))
+	}
+
+	documents := make([]*document, 0, filter.Limit)
+	cursor, err := s.mongodbConReader.Collection("transaction").Find(ctx, buildSearchFilter(query, filter), opts)
+	if err != nil {
+		return nil, err
+	}
+	err = cursor.All(ctx, &documents)
 	if err != nil {
 		return nil, err
 	}
 
-	return transaction, nil
+	return responses(documents), nil
+}
+
+// Iterate over the transactions filtering by period, one document at a time,
+// so memory does not grow with the period size. It stops at the first fn error.
+func (s storeImpl) StreamTransactionByDate(ctx context.Context, accountId string, period *model.Period, fn func(*model.TransactionResponse) error) error {
+	filter, err := accountFilter(accountId)
+	if err != nil {
+		return err
+	}
+	periodFilter(filter, period)
+
+	opts := options.Find().SetSort(periodSort(period.Field))
+	cursor, err := s.mongodbConReader.Collection("transaction").Find(ctx, filter, opts)
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var d document
+		if err := cursor.Decode(&d); err != nil {
+			return err
+		}
+
+		if err := fn(d.response()); err != nil {
+			return err
+		}
+	}
+
+	return cursor.Err()
+}
+
+// updateEach writes the update fn returns for each document of the cursor, in unordered batches.
+// A nil update leaves the document as is. It returns the number of updated documents.
+func updateEach(ctx context.Context, collection *mongo.Collection, cursor *mongo.Cursor, fn func(cursor *mongo.Cursor) (mongo.WriteModel, error)) (int64, error) {
+	defer cursor.Close(ctx)
+
+	var updated int64
+	writes := make([]mongo.WriteModel, 0, migrationBatch)
+	flush := func() error {
+		if len(writes) == 0 {
+			return nil
+		}
+		result, err := collection.BulkWrite(ctx, writes, options.BulkWrite().SetOrdered(false))
+		if err != nil {
+			return err
+		}
+		updated += result.ModifiedCount
+		writes = writes[:0]
+		return nil
+	}
+
+	for cursor.Next(ctx) {
+		write, err := fn(cursor)
+		if err != nil {
+			return updated, err
+		}
+		if write == nil {
+			continue
+		}
+		writes = append(writes, write)
+
+		if len(writes) == migrationBatch {
+			if err := flush(); err != nil {
+				return updated, err
+			}
+		}
+	}
+	if err := cursor.Err(); err != nil {
+		return updated, err
+	}
+
+	return updated, flush()
+}
+
+// withoutAccount matches the documents stored before they were scoped to an account
+var withoutAccount = primitive.M{"account_id": primitive.M{"$exists": false}}
+
+// CountWithoutAccount counts the transactions stored before they were scoped to an account
+func (s storeImpl) CountWithoutAccount(ctx context.Context) (int64, error) {
+	return s.mongodbConReader.Collection("transaction").CountDocuments(ctx, withoutAccount)
+}
+
+// AssignAccount scopes the transactions stored before the accounts to accountId.
+// It is safe to run again, it returns the number of updated transactions.
+func (s storeImpl) AssignAccount(ctx context.Context, accountId string) (int64, error) {
+	if len(accountId) == 0 {
+		return 0, tenant.ErrMissingAccount
+	}
+
+	result, err := s.mongodbConWriter.Collection("transaction").UpdateMany(ctx, withoutAccount, primitive.M{
+		"$set": primitive.M{"account_id": accountId},
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return result.ModifiedCount, nil
+}
+
+// Pad the month and day of the purchase dates stored without them.
+// It is safe to run again, it returns the number of updated transactions.
+func (s storeImpl) NormalizePurchaseDates(ctx context.Context) (int64, error) {
+	collection := s.mongodbConWriter.Collection("transaction")
+	opts := options.Find().SetProjection(primitive.M{"purchase_date": 1})
+	cursor, err := collection.Find(ctx, primitive.M{"purchase_date": unpaddedPurchaseDate}, opts)
+	if err != nil {
+		return 0, err
+	}
+
+	return updateEach(ctx, collection, cursor, func(cursor *mongo.Cursor) (mongo.WriteModel, error) {
+		var transaction struct {
+			Id           primitive.ObjectID `bson:"_id"`
+			PurchaseDate string             `bson:"purchase_date"`
+		}
+		if err := cursor.Decode(&transaction); err != nil {
+			return nil, err
+		}
+
+		date, err := time.ParseInLocation("2006-1-2", transaction.PurchaseDate, time.UTC)
+		if err != nil {
+			s.log.Warnf("transaction %s has an invalid purchase date: %s", transaction.Id.Hex(), transaction.PurchaseDate)
+			return nil, nil
+		}
+
+		return mongo.NewUpdateOneModel().
+			SetFilter(primitive.M{"_id": transaction.Id}).
+			SetUpdate(primitive.M{"$set": primitive.M{"purchase_date": date.Format(model.DateLayout)}}), nil
+	})
+}
+
+// Convert the purchase_date and purchased_at strings to BSON dates and replace the created_at
+// unix seconds, which held the purchase time, with the insertion time of the id.
+// It is safe to run again, it returns the number of updated transactions.
+func (s storeImpl) ConvertDateFields(ctx context.Context) (int64, error) {
+	collection := s.mongodbConWriter.Collection("transaction")
+	opts := options.Find().SetProjection(primitive.M{"created_at": 1, "purchase_date": 1, "purchased_at": 1})
+	cursor, err := collection.Find(ctx, primitive.M{"$or": primitive.A{
+		primitive.M{"created_at": primitive.M{"$not": primitive.M{"$type": "date"}}},
+		primitive.M{"purchase_date": primitive.M{"$type": "string"}},
+		primitive.M{"purchased_at": primitive.M{"$type": "string"}},
+	}}, opts)
+	if err != nil {
+		return 0, err
+	}
+
+	return updateEach(ctx, collection, cursor, func(cursor *mongo.Cursor) (mongo.WriteModel, error) {
+		var transaction struct {
+			Id           primitive.ObjectID `bson:"_id"`
+			CreatedAt    bson.RawValue      `bson:"created_at"`
+			PurchaseDate bson.RawValue      `bson:"purchase_date"`
+			PurchasedAt  bson.RawValue      `bson:"purchased_at"`
+		}
+		if err := cursor.Decode(&transaction); err != nil {
+			return nil, err
+		}
+
+		set := primitive.M{}
+		if transaction.CreatedAt.Type != bsontype.DateTime {
+			set["created_at"] = transaction.Id.Timestamp()
+		}
+
+		if value, ok := transaction.PurchaseDate.StringValueOK(); ok {
+			date, err := time.ParseInLocation("2006-1-2", value, time.UTC)
+			if err != nil {
+				s.log.Warnf("transaction %s has an invalid purchase date: %s", transaction.Id.Hex(), value)
+			} else {
+				set["purchase_date"] = date
+			}
+		}
+
+		if value, ok := transaction.PurchasedAt.StringValueOK(); ok {
+			purchasedAt, err := time.Parse(time.RFC3339, value)
+			if err != nil {
+				s.log.Warnf("transaction %s has an invalid purchase time: %s", transaction.Id.Hex(), value)
+			} else {
+				_, offset := purchasedAt.Zone()
+				set["purchased_at"] = purchasedAt.UTC()
+				set["purchase_offset"] = offset
+			}
+		}
+
+		if len(set) == 0 {
+			return nil, nil
+		}
+
+		return mongo.NewUpdateOneModel().SetFilter(primitive.M{"_id": transaction.Id}).SetUpdate(primitive.M{"$set": set}), nil
+	})
 }
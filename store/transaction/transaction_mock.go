@@ -35,77 +35,195 @@ func (m *MockStore) EXPECT() *MockStoreMockRecorder {
 	return m.recorder
 }
 
+// AssignAccount mocks base method.
+func (m *MockStore) AssignAccount(ctx context.Context, accountId string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AssignAccount", ctx, accountId)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AssignAccount indicates an expected call of AssignAccount.
+func (mr *MockStoreMockRecorder) AssignAccount(ctx, accountId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AssignAccount", reflect.TypeOf((*MockStore)(nil).AssignAccount), ctx, accountId)
+}
+
+// ConvertDateFields mocks base method.
+func (m *MockStore) ConvertDateFields(ctx context.Context) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConvertDateFields", ctx)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ConvertDateFields indicates an expected call of ConvertDateFields.
+func (mr *MockStoreMockRecorder) ConvertDateFields(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConvertDateFields", reflect.TypeOf((*MockStore)(nil).ConvertDateFields), ctx)
+}
+
+// CountWithoutAccount mocks base method.
+func (m *MockStore) CountWithoutAccount(ctx context.Context) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountWithoutAccount", ctx)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountWithoutAccount indicates an expected call of CountWithoutAccount.
+func (mr *MockStoreMockRecorder) CountWithoutAccount(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountWithoutAccount", reflect.TypeOf((*MockStore)(nil).CountWithoutAccount), ctx)
+}
+
+// CreateIndexes mocks base method.
+func (m *MockStore) CreateIndexes(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateIndexes", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateIndexes indicates an expected call of CreateIndexes.
+func (mr *MockStoreMockRecorder) CreateIndexes(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateIndexes", reflect.TypeOf((*MockStore)(nil).CreateIndexes), ctx)
+}
+
 // GetTransactionByDate mocks base method.
-func (m *MockStore) GetTransactionByDate(ctx context.Context, startDate, endDate int64) ([]*model.TransactionResponse, error) {
+func (m *MockStore) GetTransactionByDate(ctx context.Context, accountId string, period *model.Period, attributes *model.AttributeFilter) ([]*model.TransactionResponse, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetTransactionByDate", ctx, startDate, endDate)
+	ret := m.ctrl.Call(m, "GetTransactionByDate", ctx, accountId, period, attributes)
 	ret0, _ := ret[0].([]*model.TransactionResponse)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetTransactionByDate indicates an expected call of GetTransactionByDate.
-func (mr *MockStoreMockRecorder) GetTransactionByDate(ctx, startDate, endDate interface{}) *gomock.Call {
+func (mr *MockStoreMockRecorder) GetTransactionByDate(ctx, accountId, period, attributes interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransactionByDate", reflect.TypeOf((*MockStore)(nil).GetTransactionByDate), ctx, startDate, endDate)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransactionByDate", reflect.TypeOf((*MockStore)(nil).GetTransactionByDate), ctx, accountId, period, attributes)
 }
 
 // GetTransactionById mocks base method.
-func (m *MockStore) GetTransactionById(ctx context.Context, id string) (*model.TransactionResponse, error) {
+func (m *MockStore) GetTransactionById(ctx context.Context, accountId, id string) (*model.TransactionResponse, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetTransactionById", ctx, id)
+	ret := m.ctrl.Call(m, "GetTransactionById", ctx, accountId, id)
 	ret0, _ := ret[0].(*model.TransactionResponse)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetTransactionById indicates an expected call of GetTransactionById.
-func (mr *MockStoreMockRecorder) GetTransactionById(ctx, id interface{}) *gomock.Call {
+func (mr *MockStoreMockRecorder) GetTransactionById(ctx, accountId, id interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransactionById", reflect.TypeOf((*MockStore)(nil).GetTransactionById), ctx, id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransactionById", reflect.TypeOf((*MockStore)(nil).GetTransactionById), ctx, accountId, id)
 }
 
 // GetTransactionByIds mocks base method.
-func (m *MockStore) GetTransactionByIds(ctx context.Context, ids []string) ([]*model.TransactionResponse, error) {
+func (m *MockStore) GetTransactionByIds(ctx context.Context, accountId string, ids []string) ([]*model.TransactionResponse, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetTransactionByIds", ctx, ids)
+	ret := m.ctrl.Call(m, "GetTransactionByIds", ctx, accountId, ids)
 	ret0, _ := ret[0].([]*model.TransactionResponse)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetTransactionByIds indicates an expected call of GetTransactionByIds.
-func (mr *MockStoreMockRecorder) GetTransactionByIds(ctx, ids interface{}) *gomock.Call {
+func (mr *MockStoreMockRecorder) GetTransactionByIds(ctx, accountId, ids interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransactionByIds", reflect.TypeOf((*MockStore)(nil).GetTransactionByIds), ctx, ids)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransactionByIds", reflect.TypeOf((*MockStore)(nil).GetTransactionByIds), ctx, accountId, ids)
+}
+
+// GetTransactionSummary mocks base method.
+func (m *MockStore) GetTransactionSummary(ctx context.Context, accountId string, period *model.Period, groupBy string) ([]*model.TransactionAggregate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTransactionSummary", ctx, accountId, period, groupBy)
+	ret0, _ := ret[0].([]*model.TransactionAggregate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTransactionSummary indicates an expected call of GetTransactionSummary.
+func (mr *MockStoreMockRecorder) GetTransactionSummary(ctx, accountId, period, groupBy interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransactionSummary", reflect.TypeOf((*MockStore)(nil).GetTransactionSummary), ctx, accountId, period, groupBy)
 }
 
 // InsertTransaction mocks base method.
-func (m *MockStore) InsertTransaction(ctx context.Context, transaction *model.Transaction) (string, error) {
+func (m *MockStore) InsertTransaction(ctx context.Context, accountId string, transaction *model.Transaction) (string, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "InsertTransaction", ctx, transaction)
+	ret := m.ctrl.Call(m, "InsertTransaction", ctx, accountId, transaction)
 	ret0, _ := ret[0].(string)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // InsertTransaction indicates an expected call of InsertTransaction.
-func (mr *MockStoreMockRecorder) InsertTransaction(ctx, transaction interface{}) *gomock.Call {
+func (mr *MockStoreMockRecorder) InsertTransaction(ctx, accountId, transaction interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertTransaction", reflect.TypeOf((*MockStore)(nil).InsertTransaction), ctx, transaction)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertTransaction", reflect.TypeOf((*MockStore)(nil).InsertTransaction), ctx, accountId, transaction)
 }
 
 // InsertTransactions mocks base method.
-func (m *MockStore) InsertTransactions(ctx context.Context, transaction []*model.Transaction) ([]string, error) {
+func (m *MockStore) InsertTransactions(ctx context.Context, accountId string, transaction []*model.Transaction) ([]string, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "InsertTransactions", ctx, transaction)
+	ret := m.ctrl.Call(m, "InsertTransactions", ctx, accountId, transaction)
 	ret0, _ := ret[0].([]string)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // InsertTransactions indicates an expected call of InsertTransactions.
-func (mr *MockStoreMockRecorder) InsertTransactions(ctx, transaction interface{}) *gomock.Call {
+func (mr *MockStoreMockRecorder) InsertTransactions(ctx, accountId, transaction interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertTransactions", reflect.TypeOf((*MockStore)(nil).InsertTransactions), ctx, accountId, transaction)
+}
+
+// NormalizePurchaseDates mocks base method.
+func (m *MockStore) NormalizePurchaseDates(ctx context.Context) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NormalizePurchaseDates", ctx)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// NormalizePurchaseDates indicates an expected call of NormalizePurchaseDates.
+func (mr *MockStoreMockRecorder) NormalizePurchaseDates(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NormalizePurchaseDates", reflect.TypeOf((*MockStore)(nil).NormalizePurchaseDates), ctx)
+}
+
+// SearchTransactions mocks base method.
+func (m *MockStore) SearchTransactions(ctx context.Context, accountId string, filter *model.TransactionFilter) ([]*model.TransactionResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchTransactions", ctx, accountId, filter)
+	ret0, _ := ret[0].([]*model.TransactionResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchTransactions indicates an expected call of SearchTransactions.
+func (mr *MockStoreMockRecorder) SearchTransactions(ctx, accountId, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchTransactions", reflect.TypeOf((*MockStore)(nil).SearchTransactions), ctx, accountId, filter)
+}
+
+// StreamTransactionByDate mocks base method.
+func (m *MockStore) StreamTransactionByDate(ctx context.Context, accountId string, period *model.Period, fn func(*model.TransactionResponse) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StreamTransactionByDate", ctx, accountId, period, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// StreamTransactionByDate indicates an expected call of StreamTransactionByDate.
+func (mr *MockStoreMockRecorder) StreamTransactionByDate(ctx, accountId, period, fn interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertTransactions", reflect.TypeOf((*MockStore)(nil).InsertTransactions), ctx, transaction)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StreamTransactionByDate", reflect.TypeOf((*MockStore)(nil).StreamTransactionByDate), ctx, accountId, period, fn)
 }
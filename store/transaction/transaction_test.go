@@ -2,8 +2,10 @@ package transaction
 
 import (
 	"context"
+	"errors"
 	"testing"
 	"time"
+	"github.com/jcpribeiro/TransactionApp/internal/tenant"
 	"github.com/jcpribeiro/TransactionApp/model"
 
 	"github.com/sirupsen/logrus"
@@ -14,6 +16,13 @@ import (
 	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
 )
 
+const testAccountId = "652d34910a8fc425116b84aa"
+
+var (
+	testCreatedAt    = time.Date(2023, 10, 16, 13, 3, 13, 0, time.UTC)
+	testPurchaseDate = time.Date(2023, 10, 15, 0, 0, 0, 0, time.UTC)
+)
+
 type structTest struct {
 	mt *mtest.T
 }
@@ -25,15 +34,20 @@ func prepareTest(t *testing.T) *structTest {
 	}
 }
 
+func objectID(hex string) primitive.ObjectID {
+	id, _ := primitive.ObjectIDFromHex(hex)
+	return id
+}
+
 func TestInsertTransaction(t *testing.T) {
 	testObj := prepareTest(t)
 	ctx := context.Background()
 
 	testObj.mt.Run("this test simulate a successful transaction insert", func(t *mtest.T) {
-		t.AddMockResponses(mtest.CreateSuccessResponse())
+		t.AddMockResponses(mtest.CreateSuccessResponse(), mtest.CreateSuccessResponse(), mtest.CreateSuccessResponse())
 		storeTest := NewStoreTransaction(t.DB, t.DB, *logrus.New())
 
-		id, err := storeTest.InsertTransaction(ctx, &model.Transaction{
+		id, err := storeTest.InsertTransaction(ctx, testAccountId, &model.Transaction{
 			PurchaseAmount: 23.70,
 			Description:    "Test - 12345678910111213141516171819202122324252627282930313233",
 			PurchaseDate:   "2023-10-15",
@@ -41,6 +55,39 @@ func TestInsertTransaction(t *testing.T) {
 
 		assert.NoError(t, err)
 		assert.NotEmpty(t, id)
+
+		insert := t.GetStartedEvent().Command
+		assert.Equal(t, insert.Lookup("insert").StringValue(), "transaction")
+		assert.True(t, insert.Lookup("startTransaction").Boolean())
+
+		event := t.GetStartedEvent().Command
+		assert.Equal(t, event.Lookup("insert").StringValue(), "outbox")
+		document := event.Lookup("documents").Array().Index(0).Value().Document()
+		assert.Equal(t, document.Lookup("type").StringValue(), model.EventTransactionCreated)
+		assert.Equal(t, document.Lookup("aggregate_id").StringValue(), id)
+		assert.Equal(t, document.Lookup("account_id").StringValue(), testAccountId)
+
+		assert.Equal(t, t.GetStartedEvent().CommandName, "commitTransaction")
+	})
+
+	testObj.mt.Run("this test simulate an error writing the event of a transaction insert", func(t *mtest.T) {
+		t.AddMockResponses(mtest.CreateSuccessResponse(), mtest.CreateCommandErrorResponse(mtest.CommandError{
+			Code: 2,
+		}), mtest.CreateSuccessResponse())
+		storeTest := NewStoreTransaction(t.DB, t.DB, *logrus.New())
+
+		id, err := storeTest.InsertTransaction(ctx, testAccountId, &model.Transaction{
+			PurchaseAmount: 23.70,
+			Description:    "Test",
+			PurchaseDate:   "2023-10-15",
+		})
+
+		assert.Error(t, err)
+		assert.Empty(t, id)
+
+		t.GetStartedEvent()
+		t.GetStartedEvent()
+		assert.Equal(t, t.GetStartedEvent().CommandName, "abortTransaction")
 	})
 
 	testObj.mt.Run("this test simulate an error during a transaction insert", func(t *mtest.T) {
@@ -49,7 +96,7 @@ func TestInsertTransaction(t *testing.T) {
 		}))
 		storeTest := NewStoreTransaction(t.DB, t.DB, *logrus.New())
 
-		id, err := storeTest.InsertTransaction(ctx, &model.Transaction{
+		id, err := storeTest.InsertTransaction(ctx, testAccountId, &model.Transaction{
 			PurchaseAmount: 23.70,
 			Description:    "Test",
 			PurchaseDate:   "2023-10-15",
@@ -65,10 +112,10 @@ func TestInsertTransactions(t *testing.T) {
 	ctx := context.Background()
 
 	testObj.mt.Run("this test simulate a successful transactions insert", func(t *mtest.T) {
-		t.AddMockResponses(mtest.CreateSuccessResponse())
+		t.AddMockResponses(mtest.CreateSuccessResponse(), mtest.CreateSuccessResponse(), mtest.CreateSuccessResponse())
 		storeTest := NewStoreTransaction(t.DB, t.DB, *logrus.New())
 
-		ids, err := storeTest.InsertTransactions(ctx, []*model.Transaction{
+		ids, err := storeTest.InsertTransactions(ctx, testAccountId, []*model.Transaction{
 			0: {
 				PurchaseAmount: 23.70,
 				Description:    "Test1",
@@ -91,7 +138,7 @@ func TestInsertTransactions(t *testing.T) {
 		}))
 		storeTest := NewStoreTransaction(t.DB, t.DB, *logrus.New())
 
-		id, err := storeTest.InsertTransactions(ctx, []*model.Transaction{
+		id, err := storeTest.InsertTransactions(ctx, testAccountId, []*model.Transaction{
 			0: {
 				PurchaseAmount: 23.70,
 				Description:    "Test1",
@@ -107,6 +154,73 @@ func TestInsertTransactions(t *testing.T) {
 		assert.Error(t, err)
 		assert.Empty(t, id)
 	})
+
+	testObj.mt.Run("this test simulate an insert skipping a source already inserted", func(t *mtest.T) {
+		t.AddMockResponses(
+			mtest.CreateCursorResponse(0, "test.transaction", mtest.FirstBatch, bson.D{
+				{Key: "_id", Value: objectID("652d34910a8fc425116b84d9")},
+				{Key: "source_id", Value: "job:652d34910a8fc425116b84c1:2"},
+			}),
+			mtest.CreateSuccessResponse(),
+			mtest.CreateSuccessResponse(),
+			mtest.CreateSuccessResponse(),
+		)
+		storeTest := NewStoreTransaction(t.DB, t.DB, *logrus.New())
+
+		ids, err := storeTest.InsertTransactions(ctx, testAccountId, []*model.Transaction{
+			0: {
+				SourceId:       "job:652d34910a8fc425116b84c1:2",
+				PurchaseAmount: 23.70,
+				Description:    "Test1",
+				PurchaseDate:   "2023-10-15",
+			},
+			1: {
+				SourceId:       "job:652d34910a8fc425116b84c1:3",
+				PurchaseAmount: 25.00,
+				Description:    "Test2",
+				PurchaseDate:   "2023-10-14",
+			},
+		})
+
+		assert.NoError(t, err)
+		assert.Len(t, ids, 2)
+		assert.Equal(t, ids[0], "652d34910a8fc425116b84d9")
+		assert.NotEqual(t, ids[1], "652d34910a8fc425116b84d9")
+
+		inserts := t.GetAllStartedEvents()
+		assert.Equal(t, inserts[1].CommandName, "insert")
+		documents, _ := inserts[1].Command.Lookup("documents").Array().Values()
+		assert.Len(t, documents, 1)
+		assert.Equal(t, documents[0].Document().Lookup("source_id").StringValue(), "job:652d34910a8fc425116b84c1:3")
+	})
+
+	testObj.mt.Run("this test simulate an insert of a source inserted concurrently", func(t *mtest.T) {
+		t.AddMockResponses(
+			mtest.CreateCursorResponse(0, "test.transaction", mtest.FirstBatch),
+			mtest.CreateWriteErrorsResponse(mtest.WriteError{
+				Index:   0,
+				Code:    11000,
+				Message: "duplicate key error",
+			}),
+			mtest.CreateSuccessResponse(),
+			mtest.CreateCursorResponse(0, "test.transaction", mtest.FirstBatch, bson.D{
+				{Key: "_id", Value: objectID("652d34910a8fc425116b84d9")},
+				{Key: "source_id", Value: "stream:transactions:1697371200000-0"},
+			}),
+			mtest.CreateSuccessResponse(),
+		)
+		storeTest := NewStoreTransaction(t.DB, t.DB, *logrus.New())
+
+		id, err := storeTest.InsertTransaction(ctx, testAccountId, &model.Transaction{
+			SourceId:       "stream:transactions:1697371200000-0",
+			PurchaseAmount: 23.70,
+			Description:    "Test1",
+			PurchaseDate:   "2023-10-15",
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, id, "652d34910a8fc425116b84d9")
+	})
 }
 
 func TestGetTransactionById(t *testing.T) {
@@ -115,26 +229,30 @@ func TestGetTransactionById(t *testing.T) {
 
 	testObj.mt.Run("This test simulates the process for obtaining transaction information", func(t *mtest.T) {
 		id := primitive.NewObjectID()
-		ct := time.Now()
 		expected := model.TransactionResponse{
 			Id:             id.Hex(),
 			PurchaseAmount: 25.00,
 			Description:    "Test",
-			CreatedAt:      ct.Unix(),
+			CreatedAt:      "2023-10-16T13:03:13Z",
+			PurchaseDate:   "2023-10-15",
 		}
 		t.AddMockResponses(mtest.CreateCursorResponse(1, "foo.bar", mtest.FirstBatch, bson.D{
-			{Key: "_id", Value: expected.Id},
+			{Key: "_id", Value: objectID(expected.Id)},
 			{Key: "purchase_amount", Value: expected.PurchaseAmount},
 			{Key: "description", Value: expected.Description},
-			{Key: "created_at", Value: expected.CreatedAt},
+			{Key: "created_at", Value: testCreatedAt},
+			{Key: "purchase_date", Value: testPurchaseDate},
 		}))
 
 		storeTest := NewStoreTransaction(t.DB, t.DB, *logrus.New())
 
-		transactionTest, err := storeTest.GetTransactionById(ctx, id.Hex())
+		transactionTest, err := storeTest.GetTransactionById(ctx, testAccountId, id.Hex())
 
 		assert.NoError(t, err)
 		assert.Equal(t, transactionTest, &expected)
+
+		filter := t.GetStartedEvent().Command.Lookup("filter").Document()
+		assert.Equal(t, filter.Lookup("account_id").StringValue(), testAccountId)
 	})
 
 	testObj.mt.Run("This test simulates an error when obtaining transaction information", func(t *mtest.T) {
@@ -145,16 +263,25 @@ func TestGetTransactionById(t *testing.T) {
 
 		storeTest := NewStoreTransaction(t.DB, t.DB, *logrus.New())
 
-		transactionTest, err := storeTest.GetTransactionById(ctx, id.Hex())
+		transactionTest, err := storeTest.GetTransactionById(ctx, testAccountId, id.Hex())
 
 		assert.Error(t, err)
 		assert.Nil(t, transactionTest)
 	})
 
+	testObj.mt.Run("This test simulates obtaining a transaction without an account", func(t *mtest.T) {
+		storeTest := NewStoreTransaction(t.DB, t.DB, *logrus.New())
+
+		transactionTest, err := storeTest.GetTransactionById(ctx, "", primitive.NewObjectID().Hex())
+
+		assert.ErrorIs(t, err, tenant.ErrMissingAccount)
+		assert.Nil(t, transactionTest)
+	})
+
 	testObj.mt.Run("This test simulates an error when obtaining transaction information - invalid hex id", func(t *mtest.T) {
 		storeTest := NewStoreTransaction(t.DB, t.DB, *logrus.New())
 
-		transactionTest, err := storeTest.GetTransactionById(ctx, "test")
+		transactionTest, err := storeTest.GetTransactionById(ctx, testAccountId, "test")
 
 		assert.Error(t, err)
 		assert.Nil(t, transactionTest)
@@ -169,33 +296,36 @@ func TestGetTransactionByIds(t *testing.T) {
 		id_1 := primitive.NewObjectID()
 		id_2 := primitive.NewObjectID()
 		idList := []string{id_1.Hex(), id_2.Hex()}
-		ct := time.Now()
 		expected := []*model.TransactionResponse{
 			0: {
 				Id:             id_1.Hex(),
 				PurchaseAmount: 25.00,
 				Description:    "Test_1",
-				CreatedAt:      ct.Unix(),
+				CreatedAt:      "2023-10-16T13:03:13Z",
+				PurchaseDate:   "2023-10-15",
 			},
 			1: {
 				Id:             id_2.Hex(),
 				PurchaseAmount: 30.00,
 				Description:    "Test_2",
-				CreatedAt:      ct.Unix(),
+				CreatedAt:      "2023-10-16T13:03:13Z",
+				PurchaseDate:   "2023-10-15",
 			},
 		}
 		first := mtest.CreateCursorResponse(1, "foo.bar", mtest.FirstBatch, bson.D{
-			{Key: "_id", Value: expected[0].Id},
+			{Key: "_id", Value: objectID(expected[0].Id)},
 			{Key: "purchase_amount", Value: expected[0].PurchaseAmount},
 			{Key: "description", Value: expected[0].Description},
-			{Key: "created_at", Value: expected[0].CreatedAt},
+			{Key: "created_at", Value: testCreatedAt},
+			{Key: "purchase_date", Value: testPurchaseDate},
 		})
 
 		second := mtest.CreateCursorResponse(1, "foo.bar", mtest.NextBatch, bson.D{
-			{Key: "_id", Value: expected[1].Id},
+			{Key: "_id", Value: objectID(expected[1].Id)},
 			{Key: "purchase_amount", Value: expected[1].PurchaseAmount},
 			{Key: "description", Value: expected[1].Description},
-			{Key: "created_at", Value: expected[1].CreatedAt},
+			{Key: "created_at", Value: testCreatedAt},
+			{Key: "purchase_date", Value: testPurchaseDate},
 		})
 
 		killCursors := mtest.CreateCursorResponse(0, "foo.bar", mtest.NextBatch)
@@ -203,7 +333,7 @@ func TestGetTransactionByIds(t *testing.T) {
 
 		storeTest := NewStoreTransaction(t.DB, t.DB, *logrus.New())
 
-		transactionTest, err := storeTest.GetTransactionByIds(ctx, idList)
+		transactionTest, err := storeTest.GetTransactionByIds(ctx, testAccountId, idList)
 
 		assert.NoError(t, err)
 		assert.Equal(t, transactionTest, expected)
@@ -219,7 +349,7 @@ func TestGetTransactionByIds(t *testing.T) {
 
 		storeTest := NewStoreTransaction(t.DB, t.DB, *logrus.New())
 
-		transactionTest, err := storeTest.GetTransactionByIds(ctx, idList)
+		transactionTest, err := storeTest.GetTransactionByIds(ctx, testAccountId, idList)
 
 		assert.Error(t, err)
 		assert.Nil(t, transactionTest)
@@ -231,7 +361,7 @@ func TestGetTransactionByIds(t *testing.T) {
 
 		storeTest := NewStoreTransaction(t.DB, t.DB, *logrus.New())
 
-		transactionTest, err := storeTest.GetTransactionByIds(ctx, idList)
+		transactionTest, err := storeTest.GetTransactionByIds(ctx, testAccountId, idList)
 
 		assert.Error(t, err)
 		assert.Nil(t, transactionTest)
@@ -248,27 +378,31 @@ func TestGetTransactionByDate(t *testing.T) {
 				Id:             primitive.NewObjectID().Hex(),
 				PurchaseAmount: 25.00,
 				Description:    "Test_1",
-				CreatedAt:      time.Now().Unix(),
+				CreatedAt:      "2023-10-16T13:03:13Z",
+				PurchaseDate:   "2023-10-15",
 			},
 			1: {
 				Id:             primitive.NewObjectID().Hex(),
 				PurchaseAmount: 30.00,
 				Description:    "Test_2",
-				CreatedAt:      time.Now().Unix(),
+				CreatedAt:      "2023-10-16T13:03:13Z",
+				PurchaseDate:   "2023-10-15",
 			},
 		}
 		first := mtest.CreateCursorResponse(1, "foo.bar", mtest.FirstBatch, bson.D{
-			{Key: "_id", Value: expected[0].Id},
+			{Key: "_id", Value: objectID(expected[0].Id)},
 			{Key: "purchase_amount", Value: expected[0].PurchaseAmount},
 			{Key: "description", Value: expected[0].Description},
-			{Key: "created_at", Value: expected[0].CreatedAt},
+			{Key: "created_at", Value: testCreatedAt},
+			{Key: "purchase_date", Value: testPurchaseDate},
 		})
 
 		second := mtest.CreateCursorResponse(1, "foo.bar", mtest.NextBatch, bson.D{
-			{Key: "_id", Value: expected[1].Id},
+			{Key: "_id", Value: objectID(expected[1].Id)},
 			{Key: "purchase_amount", Value: expected[1].PurchaseAmount},
 			{Key: "description", Value: expected[1].Description},
-			{Key: "created_at", Value: expected[1].CreatedAt},
+			{Key: "created_at", Value: testCreatedAt},
+			{Key: "purchase_date", Value: testPurchaseDate},
 		})
 
 		killCursors := mtest.CreateCursorResponse(0, "foo.bar", mtest.NextBatch)
@@ -276,7 +410,7 @@ func TestGetTransactionByDate(t *testing.T) {
 
 		storeTest := NewStoreTransaction(t.DB, t.DB, *logrus.New())
 
-		transactionTest, err := storeTest.GetTransactionByDate(ctx, 1697150153, 1697409353)
+		transactionTest, err := storeTest.GetTransactionByDate(ctx, testAccountId, &model.Period{Start: time.Unix(1697150153, 0), End: time.Unix(1697409353, 0)}, nil)
 
 		assert.NoError(t, err)
 		assert.Equal(t, transactionTest, expected)
@@ -289,9 +423,480 @@ func TestGetTransactionByDate(t *testing.T) {
 
 		storeTest := NewStoreTransaction(t.DB, t.DB, *logrus.New())
 
-		transactionTest, err := storeTest.GetTransactionByDate(ctx, 1697150153, 1697409353)
+		transactionTest, err := storeTest.GetTransactionByDate(ctx, testAccountId, &model.Period{Start: time.Unix(1697150153, 0), End: time.Unix(1697409353, 0)}, nil)
 
 		assert.Error(t, err)
 		assert.Nil(t, transactionTest)
 	})
 }
+
+func TestGetTransactionSummary(t *testing.T) {
+	testObj := prepareTest(t)
+	ctx := context.Background()
+
+	testObj.mt.Run("This test simulates the process for obtaining the transactions summary", func(t *mtest.T) {
+		expected := []*model.TransactionAggregate{
+			0: {
+				Period:       "2023-10",
+				PurchaseDate: "2023-10-14",
+				Count:        1,
+				Sum:          25.00,
+				Min:          25.00,
+				Max:          25.00,
+			},
+			1: {
+				Period:       "2023-10",
+				PurchaseDate: "2023-10-15",
+				Count:        2,
+				Sum:          55.00,
+				Min:          25.00,
+				Max:          30.00,
+			},
+		}
+		first := mtest.CreateCursorResponse(1, "foo.bar", mtest.FirstBatch, bson.D{
+			{Key: "period", Value: expected[0].Period},
+			{Key: "purchase_date", Value: expected[0].PurchaseDate},
+			{Key: "count", Value: int32(expected[0].Count)},
+			{Key: "sum", Value: expected[0].Sum},
+			{Key: "min", Value: expected[0].Min},
+			{Key: "max", Value: expected[0].Max},
+		})
+
+		second := mtest.CreateCursorResponse(1, "foo.bar", mtest.NextBatch, bson.D{
+			{Key: "period", Value: expected[1].Period},
+			{Key: "purchase_date", Value: expected[1].PurchaseDate},
+			{Key: "count", Value: int32(expected[1].Count)},
+			{Key: "sum", Value: expected[1].Sum},
+			{Key: "min", Value: expected[1].Min},
+			{Key: "max", Value: expected[1].Max},
+		})
+
+		killCursors := mtest.CreateCursorResponse(0, "foo.bar", mtest.NextBatch)
+		t.AddMockResponses(first, second, killCursors)
+
+		storeTest := NewStoreTransaction(t.DB, t.DB, *logrus.New())
+
+		summaryTest, err := storeTest.GetTransactionSummary(ctx, testAccountId, &model.Period{Start: time.Unix(1696118400, 0), End: time.Unix(1698796800, 0)}, "month")
+
+		assert.NoError(t, err)
+		assert.Equal(t, summaryTest, expected)
+	})
+
+	testObj.mt.Run("This test simulates an error when obtaining the transactions summary", func(t *mtest.T) {
+		t.AddMockResponses(mtest.CreateCommandErrorResponse(mtest.CommandError{
+			Code: 2,
+		}))
+
+		storeTest := NewStoreTransaction(t.DB, t.DB, *logrus.New())
+
+		summaryTest, err := storeTest.GetTransactionSummary(ctx, testAccountId, &model.Period{Start: time.Unix(1696118400, 0), End: time.Unix(1698796800, 0)}, "")
+
+		assert.Error(t, err)
+		assert.Nil(t, summaryTest)
+	})
+}
+
+func TestSearchTransactions(t *testing.T) {
+	testObj := prepareTest(t)
+	ctx := context.Background()
+
+	testObj.mt.Run("This test simulates the process for searching transactions", func(t *mtest.T) {
+		expected := []*model.TransactionResponse{
+			0: {
+				Id:             primitive.NewObjectID().Hex(),
+				PurchaseAmount: 25.00,
+				Description:    "Coffee",
+				PurchaseDate:   "2023-10-15",
+			},
+		}
+		first := mtest.CreateCursorResponse(1, "foo.bar", mtest.FirstBatch, bson.D{
+			{Key: "_id", Value: objectID(expected[0].Id)},
+			{Key: "purchase_amount", Value: expected[0].PurchaseAmount},
+			{Key: "description", Value: expected[0].Description},
+			{Key: "purchase_date", Value: testPurchaseDate},
+		})
+
+		killCursors := mtest.CreateCursorResponse(0, "foo.bar", mtest.NextBatch)
+		t.AddMockResponses(first, killCursors)
+
+		storeTest := NewStoreTransaction(t.DB, t.DB, *logrus.New())
+
+		transactionTest, err := storeTest.SearchTransactions(ctx, testAccountId, &model.TransactionFilter{
+			Text:  "coffee",
+			Limit: 20,
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, transactionTest, expected)
+	})
+
+	testObj.mt.Run("This test simulates an error when searching transactions", func(t *mtest.T) {
+		t.AddMockResponses(mtest.CreateCommandErrorResponse(mtest.CommandError{
+			Code: 2,
+		}))
+
+		storeTest := NewStoreTransaction(t.DB, t.DB, *logrus.New())
+
+		transactionTest, err := storeTest.SearchTransactions(ctx, testAccountId, &model.TransactionFilter{Limit: 20})
+
+		assert.Error(t, err)
+		assert.Nil(t, transactionTest)
+	})
+}
+
+func TestBuildSearchFilter(t *testing.T) {
+	t.Run("this test simulate an empty search filter", func(t *testing.T) {
+		assert.Equal(t, buildSearchFilter(primitive.M{}, &model.TransactionFilter{}), primitive.M{})
+	})
+
+	t.Run("this test simulate a combined search filter", func(t *testing.T) {
+		filter := buildSearchFilter(primitive.M{}, &model.TransactionFilter{
+			Text:            "coffee",
+			MinAmount:       10,
+			Period:          model.Period{Field: model.PeriodFieldCreated, End: time.Unix(1697409353, 0)},
+			PurchaseEndDate: testPurchaseDate,
+		})
+
+		assert.Equal(t, filter, primitive.M{
+			"$text":           primitive.M{"$search": "coffee"},
+			"purchase_amount": primitive.M{"$gte": float64(10)},
+			"created_at":      primitive.M{"$lt": time.Unix(1697409353, 0)},
+			"purchase_date":   primitive.M{"$lte": testPurchaseDate},
+		})
+	})
+
+	t.Run("this test simulate an attribute search filter", func(t *testing.T) {
+		filter := buildSearchFilter(primitive.M{}, &model.TransactionFilter{
+			AttributeFilter: model.AttributeFilter{
+				Category: "food",
+				Tags:     []string{"work", "travel"},
+				Merchant: "Coffee Shop",
+				Metadata: map[string]string{"project": "alpha"},
+			},
+		})
+
+		assert.Equal(t, filter, primitive.M{
+			"category":         "food",
+			"tags":             primitive.M{"$all": []string{"work", "travel"}},
+			"merchant":         "Coffee Shop",
+			"metadata.project": "alpha",
+		})
+	})
+}
+
+func TestPeriodFilter(t *testing.T) {
+	start := time.Date(2023, 10, 12, 3, 0, 0, 0, time.UTC)
+	end := time.Date(2023, 10, 16, 3, 0, 0, 0, time.UTC)
+
+	t.Run("this test simulate an epoch period filter", func(t *testing.T) {
+		filter := periodFilter(primitive.M{}, &model.Period{Field: model.PeriodFieldPurchase, Start: start, End: end})
+
+		assert.Equal(t, filter, primitive.M{
+			"$or": primitive.A{
+				primitive.M{"purchased_at": primitive.M{"$gte": start, "$lt": end}},
+				primitive.M{
+					"purchased_at":  primitive.M{"$exists": false},
+					"purchase_date": primitive.M{"$gte": start, "$lt": end},
+				},
+			},
+		})
+	})
+
+	t.Run("this test simulate a date period filter", func(t *testing.T) {
+		filter := periodFilter(primitive.M{}, &model.Period{
+			Field:     model.PeriodFieldPurchase,
+			Start:     start,
+			End:       end,
+			FirstDate: time.Date(2023, 10, 12, 0, 0, 0, 0, time.UTC),
+			LastDate:  time.Date(2023, 10, 15, 0, 0, 0, 0, time.UTC),
+			Timezone:  "America/Sao_Paulo",
+		})
+
+		assert.Equal(t, filter, primitive.M{
+			"$or": primitive.A{
+				primitive.M{"purchased_at": primitive.M{"$gte": start, "$lt": end}},
+				primitive.M{
+					"purchased_at": primitive.M{"$exists": false},
+					"purchase_date": primitive.M{
+						"$gte": time.Date(2023, 10, 12, 0, 0, 0, 0, time.UTC),
+						"$lte": time.Date(2023, 10, 15, 0, 0, 0, 0, time.UTC),
+					},
+				},
+			},
+		})
+	})
+
+	t.Run("this test simulate a created period filter", func(t *testing.T) {
+		filter := periodFilter(primitive.M{}, &model.Period{Field: model.PeriodFieldCreated, Start: start})
+
+		assert.Equal(t, filter, primitive.M{
+			"created_at": primitive.M{"$gte": start},
+		})
+	})
+
+	t.Run("this test simulate an open period filter", func(t *testing.T) {
+		assert.Equal(t, periodFilter(primitive.M{}, &model.Period{}), primitive.M{})
+	})
+}
+
+func TestDocument(t *testing.T) {
+	t.Run("this test simulate a transaction stored with its purchase time", func(t *testing.T) {
+		d, err := newDocument(testAccountId, &model.Transaction{
+			PurchaseAmount: 23.70,
+			Description:    "Test",
+			PurchaseDate:   "2023-10-14",
+			PurchasedAt:    "2023-10-14T23:30:00-03:00",
+		}, testCreatedAt)
+
+		assert.NoError(t, err)
+		assert.Equal(t, d.PurchaseDate, time.Date(2023, 10, 14, 0, 0, 0, 0, time.UTC))
+		assert.Equal(t, *d.PurchasedAt, time.Date(2023, 10, 15, 2, 30, 0, 0, time.UTC))
+		assert.Equal(t, d.PurchaseOffset, -3*60*60)
+
+		response := d.response()
+		assert.Equal(t, response.PurchaseDate, "2023-10-14")
+		assert.Equal(t, response.PurchasedAt, "2023-10-14T23:30:00-03:00")
+		assert.Equal(t, response.CreatedAt, "2023-10-16T13:03:13Z")
+	})
+
+	t.Run("this test simulate a transaction with an invalid purchase date", func(t *testing.T) {
+		d, err := newDocument(testAccountId, &model.Transaction{PurchaseDate: "2023-10-5"}, testCreatedAt)
+
+		assert.Error(t, err)
+		assert.Nil(t, d)
+	})
+}
+
+func TestNormalizePurchaseDates(t *testing.T) {
+	testObj := prepareTest(t)
+	ctx := context.Background()
+
+	testObj.mt.Run("this test simulate the normalization of unpadded purchase dates", func(t *mtest.T) {
+		first := mtest.CreateCursorResponse(1, "foo.bar", mtest.FirstBatch, bson.D{
+			{Key: "_id", Value: primitive.NewObjectID()},
+			{Key: "purchase_date", Value: "2023-10-5"},
+		}, bson.D{
+			{Key: "_id", Value: primitive.NewObjectID()},
+			{Key: "purchase_date", Value: "2023-1-15"},
+		})
+		killCursors := mtest.CreateCursorResponse(0, "foo.bar", mtest.NextBatch)
+		t.AddMockResponses(first, killCursors, bson.D{
+			{Key: "ok", Value: 1},
+			{Key: "n", Value: 2},
+			{Key: "nModified", Value: 2},
+		})
+		storeTest := NewStoreTransaction(t.DB, t.DB, *logrus.New())
+
+		updated, err := storeTest.NormalizePurchaseDates(ctx)
+
+		assert.NoError(t, err)
+		assert.Equal(t, updated, int64(2))
+	})
+
+	testObj.mt.Run("this test simulate an error finding the purchase dates", func(t *mtest.T) {
+		t.AddMockResponses(mtest.CreateCommandErrorResponse(mtest.CommandError{
+			Code: 2,
+		}))
+		storeTest := NewStoreTransaction(t.DB, t.DB, *logrus.New())
+
+		updated, err := storeTest.NormalizePurchaseDates(ctx)
+
+		assert.Error(t, err)
+		assert.Equal(t, updated, int64(0))
+	})
+}
+
+func TestConvertDateFields(t *testing.T) {
+	testObj := prepareTest(t)
+	ctx := context.Background()
+
+	testObj.mt.Run("this test simulate the conversion of the date fields", func(t *mtest.T) {
+		first := mtest.CreateCursorResponse(1, "foo.bar", mtest.FirstBatch, bson.D{
+			{Key: "_id", Value: primitive.NewObjectID()},
+			{Key: "purchase_date", Value: "2023-10-15"},
+			{Key: "created_at", Value: int64(1697328000)},
+		}, bson.D{
+			{Key: "_id", Value: primitive.NewObjectID()},
+			{Key: "purchase_date", Value: "2023-10-14"},
+			{Key: "purchased_at", Value: "2023-10-14T23:30:00-03:00"},
+			{Key: "created_at", Value: int64(1697337000)},
+		})
+		killCursors := mtest.CreateCursorResponse(0, "foo.bar", mtest.NextBatch)
+		t.AddMockResponses(first, killCursors, bson.D{
+			{Key: "ok", Value: 1},
+			{Key: "n", Value: 2},
+			{Key: "nModified", Value: 2},
+		})
+		storeTest := NewStoreTransaction(t.DB, t.DB, *logrus.New())
+
+		updated, err := storeTest.ConvertDateFields(ctx)
+
+		assert.NoError(t, err)
+		assert.Equal(t, updated, int64(2))
+
+		event := t.GetStartedEvent()
+		for event.CommandName != "update" {
+			event = t.GetStartedEvent()
+		}
+		updates, _ := event.Command.Lookup("updates").Array().Values()
+		set := updates[1].Document().Lookup("u", "$set").Document()
+		assert.Equal(t, set.Lookup("purchased_at").Time().UTC(), time.Date(2023, 10, 15, 2, 30, 0, 0, time.UTC))
+		assert.Equal(t, set.Lookup("purchase_offset").Int32(), int32(-3*60*60))
+		assert.Equal(t, set.Lookup("purchase_date").Time().UTC(), time.Date(2023, 10, 14, 0, 0, 0, 0, time.UTC))
+	})
+
+	testObj.mt.Run("this test simulate an error finding the date fields", func(t *mtest.T) {
+		t.AddMockResponses(mtest.CreateCommandErrorResponse(mtest.CommandError{
+			Code: 2,
+		}))
+		storeTest := NewStoreTransaction(t.DB, t.DB, *logrus.New())
+
+		updated, err := storeTest.ConvertDateFields(ctx)
+
+		assert.Error(t, err)
+		assert.Equal(t, updated, int64(0))
+	})
+}
+
+func TestAssignAccount(t *testing.T) {
+	testObj := prepareTest(t)
+	ctx := context.Background()
+
+	testObj.mt.Run("this test simulate assigning an account to the legacy transactions", func(t *mtest.T) {
+		t.AddMockResponses(bson.D{
+			{Key: "ok", Value: 1},
+			{Key: "n", Value: 2},
+			{Key: "nModified", Value: 2},
+		})
+		storeTest := NewStoreTransaction(t.DB, t.DB, *logrus.New())
+
+		updated, err := storeTest.AssignAccount(ctx, testAccountId)
+
+		assert.NoError(t, err)
+		assert.Equal(t, updated, int64(2))
+		event := t.GetStartedEvent()
+		updates, _ := event.Command.Lookup("updates").Array().Values()
+		assert.Equal(t, updates[0].Document().Lookup("u", "$set", "account_id").StringValue(), testAccountId)
+	})
+
+	testObj.mt.Run("this test simulate assigning an empty account", func(t *mtest.T) {
+		storeTest := NewStoreTransaction(t.DB, t.DB, *logrus.New())
+
+		_, err := storeTest.AssignAccount(ctx, "")
+
+		assert.ErrorIs(t, err, tenant.ErrMissingAccount)
+	})
+}
+
+func TestCreateIndexes(t *testing.T) {
+	testObj := prepareTest(t)
+	ctx := context.Background()
+
+	testObj.mt.Run("this test simulate the indexes creation", func(t *mtest.T) {
+		t.AddMockResponses(
+			mtest.CreateSuccessResponse(),
+			mtest.CreateCommandErrorResponse(mtest.CommandError{Code: 27, Message: "index not found"}),
+			mtest.CreateCommandErrorResponse(mtest.CommandError{Code: 27, Message: "index not found"}),
+			mtest.CreateSuccessResponse(),
+		)
+		storeTest := NewStoreTransaction(t.DB, t.DB, *logrus.New())
+
+		err := storeTest.CreateIndexes(ctx)
+
+		assert.NoError(t, err)
+	})
+
+	testObj.mt.Run("this test simulate an error during the indexes creation", func(t *mtest.T) {
+		t.AddMockResponses(mtest.CreateCommandErrorResponse(mtest.CommandError{
+			Code: 2,
+		}))
+		storeTest := NewStoreTransaction(t.DB, t.DB, *logrus.New())
+
+		err := storeTest.CreateIndexes(ctx)
+
+		assert.Error(t, err)
+	})
+}
+
+func TestStreamTransactionByDate(t *testing.T) {
+	testObj := prepareTest(t)
+	ctx := context.Background()
+
+	testObj.mt.Run("This test simulates the process for iterating over transactions by date", func(t *mtest.T) {
+		expected := []*model.TransactionResponse{
+			0: {
+				Id:             primitive.NewObjectID().Hex(),
+				PurchaseAmount: 25.00,
+				Description:    "Test_1",
+				CreatedAt:      "2023-10-16T13:03:13Z",
+				PurchaseDate:   "2023-10-15",
+			},
+			1: {
+				Id:             primitive.NewObjectID().Hex(),
+				PurchaseAmount: 30.00,
+				Description:    "Test_2",
+				CreatedAt:      "2023-10-16T13:03:13Z",
+				PurchaseDate:   "2023-10-15",
+			},
+		}
+		first := mtest.CreateCursorResponse(1, "foo.bar", mtest.FirstBatch, bson.D{
+			{Key: "_id", Value: objectID(expected[0].Id)},
+			{Key: "purchase_amount", Value: expected[0].PurchaseAmount},
+			{Key: "description", Value: expected[0].Description},
+			{Key: "created_at", Value: testCreatedAt},
+			{Key: "purchase_date", Value: testPurchaseDate},
+		})
+
+		second := mtest.CreateCursorResponse(1, "foo.bar", mtest.NextBatch, bson.D{
+			{Key: "_id", Value: objectID(expected[1].Id)},
+			{Key: "purchase_amount", Value: expected[1].PurchaseAmount},
+			{Key: "description", Value: expected[1].Description},
+			{Key: "created_at", Value: testCreatedAt},
+			{Key: "purchase_date", Value: testPurchaseDate},
+		})
+
+		killCursors := mtest.CreateCursorResponse(0, "foo.bar", mtest.NextBatch)
+		t.AddMockResponses(first, second, killCursors)
+
+		storeTest := NewStoreTransaction(t.DB, t.DB, *logrus.New())
+
+		var transactionTest []*model.TransactionResponse
+		err := storeTest.StreamTransactionByDate(ctx, testAccountId, &model.Period{Start: time.Unix(1697150153, 0), End: time.Unix(1697409353, 0)}, func(transaction *model.TransactionResponse) error {
+			transactionTest = append(transactionTest, transaction)
+			return nil
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, transactionTest, expected)
+	})
+
+	testObj.mt.Run("This test simulates an error returned while iterating over transactions by date", func(t *mtest.T) {
+		first := mtest.CreateCursorResponse(1, "foo.bar", mtest.FirstBatch, bson.D{
+			{Key: "_id", Value: primitive.NewObjectID()},
+			{Key: "purchase_amount", Value: 25.00},
+		})
+		t.AddMockResponses(first, mtest.CreateSuccessResponse())
+
+		storeTest := NewStoreTransaction(t.DB, t.DB, *logrus.New())
+
+		err := storeTest.StreamTransactionByDate(ctx, testAccountId, &model.Period{Start: time.Unix(1697150153, 0), End: time.Unix(1697409353, 0)}, func(transaction *model.TransactionResponse) error {
+			return errors.New("an error has ocurred")
+		})
+
+		assert.Error(t, err)
+	})
+
+	testObj.mt.Run("This test simulates an error when iterating over transactions by date", func(t *mtest.T) {
+		t.AddMockResponses(mtest.CreateCommandErrorResponse(mtest.CommandError{
+			Code: 2,
+		}))
+
+		storeTest := NewStoreTransaction(t.DB, t.DB, *logrus.New())
+
+		err := storeTest.StreamTransactionByDate(ctx, testAccountId, &model.Period{Start: time.Unix(1697150153, 0), End: time.Unix(1697409353, 0)}, func(transaction *model.TransactionResponse) error {
+			return nil
+		})
+
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,243 @@
+package webhook
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/jcpribeiro/TransactionApp/internal/tenant"
+	"github.com/jcpribeiro/TransactionApp/model"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// memoryStore keeps the webhooks and their deliveries in process, it is meant for tests and local development
+type memoryStore struct {
+	mu         sync.Mutex
+	webhooks   map[string]*model.Webhook
+	deliveries map[string]*model.WebhookDelivery
+}
+
+func NewMemoryStoreWebhook() Store {
+	return &memoryStore{
+		webhooks:   make(map[string]*model.Webhook),
+		deliveries: make(map[string]*model.WebhookDelivery),
+	}
+}
+
+func copyWebhook(webhook *model.Webhook) *model.Webhook {
+	c := *webhook
+	c.Events = append([]string(nil), webhook.Events...)
+
+	return &c
+}
+
+func copyDelivery(delivery *model.WebhookDelivery) *model.WebhookDelivery {
+	c := *delivery
+	c.Payload = append([]byte(nil), delivery.Payload...)
+
+	return &c
+}
+
+func (m *memoryStore) InsertWebhook(ctx context.Context, webhook *model.Webhook) (string, error) {
+	if len(webhook.AccountId) == 0 {
+		return "", tenant.ErrMissingAccount
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	c := copyWebhook(webhook)
+	c.Id = primitive.NewObjectID().Hex()
+	m.webhooks[c.Id] = c
+
+	return c.Id, nil
+}
+
+func (m *memoryStore) accountWebhook(accountId, id string) (*model.Webhook, error) {
+	if len(accountId) == 0 {
+		return nil, tenant.ErrMissingAccount
+	}
+
+	webhook, ok := m.webhooks[id]
+	if !ok || webhook.AccountId != accountId {
+		return nil, ErrWebhookNotFound
+	}
+
+	return webhook, nil
+}
+
+func (m *memoryStore) GetWebhookById(ctx context.Context, accountId, id string) (*model.Webhook, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	webhook, err := m.accountWebhook(accountId, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return copyWebhook(webhook), nil
+}
+
+func (m *memoryStore) findWebhooks(accountId string, match func(*model.Webhook) bool) ([]*model.Webhook, error) {
+	if len(accountId) == 0 {
+		return nil, tenant.ErrMissingAccount
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	webhooks := make([]*model.Webhook, 0)
+	for _, webhook := range m.webhooks {
+		if webhook.AccountId == accountId && match(webhook) {
+			webhooks = append(webhooks, copyWebhook(webhook))
+		}
+	}
+
+	sort.Slice(webhooks, func(i, j int) bool {
+		if webhooks[i].CreatedAt != webhooks[j].CreatedAt {
+			return webhooks[i].CreatedAt < webhooks[j].CreatedAt
+		}
+		return webhooks[i].Id < webhooks[j].Id
+	})
+
+	return webhooks, nil
+}
+
+func (m *memoryStore) ListWebhooks(ctx context.Context, accountId string) ([]*model.Webhook, error) {
+	return m.findWebhooks(accountId, func(*model.Webhook) bool { return true })
+}
+
+func (m *memoryStore) ListWebhooksByEvent(ctx context.Context, accountId, event string) ([]*model.Webhook, error) {
+	return m.findWebhooks(accountId, func(webhook *model.Webhook) bool {
+		for _, e := range webhook.Events {
+			if e == event {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+func (m *memoryStore) DeleteWebhook(ctx context.Context, accountId, id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, err := m.accountWebhook(accountId, id); err != nil {
+		return err
+	}
+	delete(m.webhooks, id)
+
+	return nil
+}
+
+func (m *memoryStore) delivered(webhookId, eventId string) bool {
+	for _, d := range m.deliveries {
+		if d.WebhookId == webhookId && d.EventId == eventId {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (m *memoryStore) InsertDeliveries(ctx context.Context, deliveries []*model.WebhookDelivery) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, delivery := range deliveries {
+		if m.delivered(delivery.WebhookId, delivery.EventId) {
+			continue
+		}
+
+		c := copyDelivery(delivery)
+		c.Id = primitive.NewObjectID().Hex()
+		m.deliveries[c.Id] = c
+	}
+
+	return nil
+}
+
+func (m *memoryStore) ClaimDelivery(ctx context.Context, owner string, now, lockedUntil int64) (*model.WebhookDelivery, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	candidates := make([]*model.WebhookDelivery, 0)
+	for _, d := range m.deliveries {
+		if (d.Status == model.DeliveryStatusPending && d.NextAttemptAt <= now) ||
+			(d.Status == model.DeliveryStatusRunning && d.LockedUntil < now) {
+			candidates = append(candidates, d)
+		}
+	}
+	if len(candidates) == 0 {
+		return nil, nil
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].NextAttemptAt != candidates[j].NextAttemptAt {
+			return candidates[i].NextAttemptAt < candidates[j].NextAttemptAt
+		}
+		return candidates[i].Id < candidates[j].Id
+	})
+
+	delivery := candidates[0]
+	delivery.Status = model.DeliveryStatusRunning
+	delivery.Owner = owner
+	delivery.LockedUntil = lockedUntil
+	delivery.UpdatedAt = now
+
+	return copyDelivery(delivery), nil
+}
+
+func (m *memoryStore) FinishDelivery(ctx context.Context, id, owner string, finished *model.WebhookDelivery, now int64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delivery, ok := m.deliveries[id]
+	if !ok || delivery.Owner != owner || delivery.Status != model.DeliveryStatusRunning {
+		return ErrDeliveryNotFound
+	}
+
+	delivery.Status = finished.Status
+	delivery.Attempts = finished.Attempts
+	delivery.NextAttemptAt = finished.NextAttemptAt
+	delivery.StatusCode = finished.StatusCode
+	delivery.Error = finished.Error
+	delivery.Owner = ""
+	delivery.LockedUntil = 0
+	delivery.UpdatedAt = now
+
+	return nil
+}
+
+func (m *memoryStore) ListDeliveries(ctx context.Context, accountId, webhookId string, limit int64) ([]*model.WebhookDelivery, error) {
+	if len(accountId) == 0 {
+		return nil, tenant.ErrMissingAccount
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	deliveries := make([]*model.WebhookDelivery, 0)
+	for _, d := range m.deliveries {
+		if d.AccountId == accountId && d.WebhookId == webhookId {
+			deliveries = append(deliveries, copyDelivery(d))
+		}
+	}
+
+	sort.Slice(deliveries, func(i, j int) bool {
+		if deliveries[i].CreatedAt != deliveries[j].CreatedAt {
+			return deliveries[i].CreatedAt > deliveries[j].CreatedAt
+		}
+		return deliveries[i].Id > deliveries[j].Id
+	})
+	if limit > 0 && int64(len(deliveries)) > limit {
+		deliveries = deliveries[:limit]
+	}
+
+	return deliveries, nil
+}
+
+func (m *memoryStore) CreateIndexes(ctx context.Context) error {
+	return nil
+}
@@ -0,0 +1,314 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jcpribeiro/TransactionApp/internal/tenant"
+	"github.com/jcpribeiro/TransactionApp/model"
+
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+//go:generate mockgen -source=$GOFILE -destination=webhook_mock.go -package=$GOPACKAGE
+
+var (
+	// ErrWebhookNotFound is returned when the webhook does not exist or belongs to another account
+	ErrWebhookNotFound = errors.New("webhook not found")
+	// ErrDeliveryNotFound is returned when the delivery does not exist or is no longer owned by the worker
+	ErrDeliveryNotFound = errors.New("webhook delivery not found")
+)
+
+const duplicateKeyCode = 11000
+
+type Store interface {
+	InsertWebhook(ctx context.Context, webhook *model.Webhook) (string, error)
+	GetWebhookById(ctx context.Context, accountId, id string) (*model.Webhook, error)
+	ListWebhooks(ctx context.Context, accountId string) ([]*model.Webhook, error)
+	ListWebhooksByEvent(ctx context.Context, accountId, event string) ([]*model.Webhook, error)
+	DeleteWebhook(ctx context.Context, accountId, id string) error
+	InsertDeliveries(ctx context.Context, deliveries []*model.WebhookDelivery) error
+	ClaimDelivery(ctx context.Context, owner string, now, lockedUntil int64) (*model.WebhookDelivery, error)
+	FinishDelivery(ctx context.Context, id, owner string, delivery *model.WebhookDelivery, now int64) error
+	ListDeliveries(ctx context.Context, accountId, webhookId string, limit int64) ([]*model.WebhookDelivery, error)
+	CreateIndexes(ctx context.Context) error
+}
+
+type storeImpl struct {
+	mongodbConReader *mongo.Database
+	mongodbConWriter *mongo.Database
+	log              logrus.Logger
+}
+
+func NewStoreWebhook(mongodbConReader, mongodbConWriter *mongo.Database, log logrus.Logger) Store {
+	return &storeImpl{
+		mongodbConReader: mongodbConReader,
+		mongodbConWriter: mongodbConWriter,
+		log:              log,
+	}
+}
+
+// accountWebhookFilter matches a webhook only for the account that registered it
+func accountWebhookFilter(accountId, id string) (primitive.M, error) {
+	if len(accountId) == 0 {
+		return nil, tenant.ErrMissingAccount
+	}
+
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, ErrWebhookNotFound
+	}
+
+	return primitive.M{"_id": objectID, "account_id": accountId}, nil
+}
+
+func decodeDelivery(result *mongo.SingleResult) (*model.WebhookDelivery, error) {
+	var delivery *model.WebhookDelivery
+	err := result.Decode(&delivery)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, ErrDeliveryNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return delivery, nil
+}
+
+// Create the indexes used to find the subscribers of an event, to claim the deliveries and to list them
+func (s storeImpl) CreateIndexes(ctx context.Context) error {
+	_, err := s.mongodbConWriter.Collection("webhook").Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    primitive.D{{Key: "account_id", Value: 1}, {Key: "events", Value: 1}},
+		Options: options.Index().SetName("account_id_events"),
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = s.mongodbConWriter.Collection("webhook_delivery").Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    primitive.D{{Key: "status", Value: 1}, {Key: "next_attempt_at", Value: 1}},
+			Options: options.Index().SetName("status_next_attempt_at"),
+		},
+		{
+			Keys:    primitive.D{{Key: "webhook_id", Value: 1}, {Key: "event_id", Value: 1}},
+			Options: options.Index().SetName("webhook_id_event_id").SetUnique(true),
+		},
+		{
+			Keys:    primitive.D{{Key: "account_id", Value: 1}, {Key: "webhook_id", Value: 1}, {Key: "created_at", Value: -1}},
+			Options: options.Index().SetName("account_id_webhook_id_created_at"),
+		},
+	})
+
+	return err
+}
+
+// Insert a new webhook
+func (s storeImpl) InsertWebhook(ctx context.Context, webhook *model.Webhook) (string, error) {
+	if len(webhook.AccountId) == 0 {
+		return "", tenant.ErrMissingAccount
+	}
+
+	insertedId, err := s.mongodbConWriter.Collection("webhook").InsertOne(ctx, webhook)
+	if err != nil {
+		return "", err
+	}
+
+	return insertedId.InsertedID.(primitive.ObjectID).Hex(), nil
+}
+
+// Get a single webhook, filtering by account and id
+func (s storeImpl) GetWebhookById(ctx context.Context, accountId, id string) (*model.Webhook, error) {
+	filter, err := accountWebhookFilter(accountId, id)
+	if err != nil {
+		return nil, err
+	}
+
+	var webhook *model.Webhook
+	err = s.mongodbConReader.Collection("webhook").FindOne(ctx, filter).Decode(&webhook)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, ErrWebhookNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return webhook, nil
+}
+
+func (s storeImpl) findWebhooks(ctx context.Context, filter primitive.M) ([]*model.Webhook, error) {
+	opts := options.Find().SetSort(primitive.D{{Key: "created_at", Value: 1}, {Key: "_id", Value: 1}})
+	webhooks := make([]*model.Webhook, 0)
+	cursor, err := s.mongodbConReader.Collection("webhook").Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	err = cursor.All(ctx, &webhooks)
+	if err != nil {
+		return nil, err
+	}
+
+	return webhooks, nil
+}
+
+// Get the webhooks of an account
+func (s storeImpl) ListWebhooks(ctx context.Context, accountId string) ([]*model.Webhook, error) {
+	if len(accountId) == 0 {
+		return nil, tenant.ErrMissingAccount
+	}
+
+	return s.findWebhooks(ctx, primitive.M{"account_id": accountId})
+}
+
+// Get the webhooks of an account subscribed to an event
+func (s storeImpl) ListWebhooksByEvent(ctx context.Context, accountId, event string) ([]*model.Webhook, error) {
+	if len(accountId) == 0 {
+		return nil, tenant.ErrMissingAccount
+	}
+
+	return s.findWebhooks(ctx, primitive.M{"account_id": accountId, "events": event})
+}
+
+// Delete a webhook of an account, its pending deliveries become dead when they are claimed
+func (s storeImpl) DeleteWebhook(ctx context.Context, accountId, id string) error {
+	filter, err := accountWebhookFilter(accountId, id)
+	if err != nil {
+		return err
+	}
+
+	result, err := s.mongodbConWriter.Collection("webhook").DeleteOne(ctx, filter)
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return ErrWebhookNotFound
+	}
+
+	return nil
+}
+
+// Insert the deliveries of an event. An event published again keeps its first delivery to each webhook.
+func (s storeImpl) InsertDeliveries(ctx context.Context, deliveries []*model.WebhookDelivery) error {
+	if len(deliveries) == 0 {
+		return nil
+	}
+
+	docs := make([]interface{}, 0, len(deliveries))
+	for _, d := range deliveries {
+		docs = append(docs, d)
+	}
+
+	_, err := s.mongodbConWriter.Collection("webhook_delivery").InsertMany(ctx, docs, options.InsertMany().SetOrdered(false))
+	if onlyDuplicates(err) {
+		return nil
+	}
+
+	return err
+}
+
+// onlyDuplicates reports whether every write of a bulk insert failed with a duplicate key
+func onlyDuplicates(err error) bool {
+	var bulkErr mongo.BulkWriteException
+	if !errors.As(err, &bulkErr) || bulkErr.WriteConcernError != nil || len(bulkErr.WriteErrors) == 0 {
+		return false
+	}
+
+	for _, writeErr := range bulkErr.WriteErrors {
+		if writeErr.Code != duplicateKeyCode {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Claim the pending delivery due the longest, or a running delivery whose lease has expired because its worker stopped.
+// It returns nil when there is no delivery due.
+func (s storeImpl) ClaimDelivery(ctx context.Context, owner string, now, lockedUntil int64) (*model.WebhookDelivery, error) {
+	filter := primitive.M{
+		"$or": primitive.A{
+			primitive.M{"status": model.DeliveryStatusPending, "next_attempt_at": primitive.M{"$lte": now}},
+			primitive.M{"status": model.DeliveryStatusRunning, "locked_until": primitive.M{"$lt": now}},
+		},
+	}
+	update := primitive.M{
+		"$set": primitive.M{
+			"status":       model.DeliveryStatusRunning,
+			"owner":        owner,
+			"locked_until": lockedUntil,
+			"updated_at":   now,
+		},
+	}
+	opts := options.FindOneAndUpdate().
+		SetSort(primitive.D{{Key: "next_attempt_at", Value: 1}, {Key: "_id", Value: 1}}).
+		SetReturnDocument(options.After)
+
+	delivery, err := decodeDelivery(s.mongodbConWriter.Collection("webhook_delivery").FindOneAndUpdate(ctx, filter, update, opts))
+	if errors.Is(err, ErrDeliveryNotFound) {
+		return nil, nil
+	}
+
+	return delivery, err
+}
+
+// Save the outcome of an attempt of a running delivery, a pending status schedules the next attempt
+func (s storeImpl) FinishDelivery(ctx context.Context, id, owner string, delivery *model.WebhookDelivery, now int64) error {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return ErrDeliveryNotFound
+	}
+
+	filter := primitive.M{
+		"_id":    objectID,
+		"owner":  owner,
+		"status": model.DeliveryStatusRunning,
+	}
+	update := primitive.M{
+		"$set": primitive.M{
+			"status":          delivery.Status,
+			"attempts":        delivery.Attempts,
+			"next_attempt_at": delivery.NextAttemptAt,
+			"status_code":     delivery.StatusCode,
+			"error":           delivery.Error,
+			"updated_at":      now,
+		},
+		"$unset": primitive.M{"owner": "", "locked_until": ""},
+	}
+
+	result, err := s.mongodbConWriter.Collection("webhook_delivery").UpdateOne(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrDeliveryNotFound
+	}
+
+	return nil
+}
+
+// Get the latest deliveries of a webhook of an account, the newest first
+func (s storeImpl) ListDeliveries(ctx context.Context, accountId, webhookId string, limit int64) ([]*model.WebhookDelivery, error) {
+	if len(accountId) == 0 {
+		return nil, tenant.ErrMissingAccount
+	}
+
+	filter := primitive.M{"account_id": accountId, "webhook_id": webhookId}
+	opts := options.Find().
+		SetSort(primitive.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}}).
+		SetLimit(limit)
+
+	deliveries := make([]*model.WebhookDelivery, 0)
+	cursor, err := s.mongodbConReader.Collection("webhook_delivery").Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	err = cursor.All(ctx, &deliveries)
+	if err != nil {
+		return nil, err
+	}
+
+	return deliveries, nil
+}
@@ -0,0 +1,266 @@
+package webhook
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jcpribeiro/TransactionApp/internal/tenant"
+	"github.com/jcpribeiro/TransactionApp/model"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+const testAccountId = "652d34910a8fc425116b84aa"
+
+type structTest struct {
+	mt *mtest.T
+}
+
+func prepareTest(t *testing.T) *structTest {
+	mt := mtest.New(t, mtest.NewOptions().DatabaseName("test").ClientType(mtest.Mock))
+	return &structTest{
+		mt: mt,
+	}
+}
+
+func TestInsertWebhook(t *testing.T) {
+	testObj := prepareTest(t)
+	ctx := context.Background()
+
+	testObj.mt.Run("this test simulate a successful webhook insert", func(t *mtest.T) {
+		t.AddMockResponses(mtest.CreateSuccessResponse())
+		storeTest := NewStoreWebhook(t.DB, t.DB, *logrus.New())
+
+		id, err := storeTest.InsertWebhook(ctx, &model.Webhook{
+			AccountId: testAccountId,
+			URL:       "https://example.com/hook",
+			Events:    []string{model.EventTransactionCreated},
+		})
+
+		assert.NoError(t, err)
+		assert.NotEmpty(t, id)
+	})
+
+	testObj.mt.Run("this test simulate a webhook insert without an account", func(t *mtest.T) {
+		storeTest := NewStoreWebhook(t.DB, t.DB, *logrus.New())
+
+		id, err := storeTest.InsertWebhook(ctx, &model.Webhook{})
+
+		assert.ErrorIs(t, err, tenant.ErrMissingAccount)
+		assert.Empty(t, id)
+	})
+}
+
+func TestListWebhooksByEvent(t *testing.T) {
+	testObj := prepareTest(t)
+	ctx := context.Background()
+
+	testObj.mt.Run("This test simulates listing the webhooks of an event", func(t *mtest.T) {
+		id := primitive.NewObjectID()
+		t.AddMockResponses(mtest.CreateCursorResponse(0, "foo.bar", mtest.FirstBatch, bson.D{
+			{Key: "_id", Value: id},
+			{Key: "account_id", Value: testAccountId},
+			{Key: "url", Value: "https://example.com/hook"},
+			{Key: "secret", Value: "0123456789abcdef"},
+			{Key: "events", Value: bson.A{model.EventTransactionCreated}},
+		}))
+		storeTest := NewStoreWebhook(t.DB, t.DB, *logrus.New())
+
+		webhooks, err := storeTest.ListWebhooksByEvent(ctx, testAccountId, model.EventTransactionCreated)
+
+		assert.NoError(t, err)
+		assert.Equal(t, webhooks, []*model.Webhook{{
+			Id:        id.Hex(),
+			AccountId: testAccountId,
+			URL:       "https://example.com/hook",
+			Secret:    "0123456789abcdef",
+			Events:    []string{model.EventTransactionCreated},
+		}})
+
+		filter := t.GetStartedEvent().Command.Lookup("filter").Document()
+		assert.Equal(t, filter.Lookup("account_id").StringValue(), testAccountId)
+		assert.Equal(t, filter.Lookup("events").StringValue(), model.EventTransactionCreated)
+	})
+
+	testObj.mt.Run("This test simulates listing webhooks without an account", func(t *mtest.T) {
+		storeTest := NewStoreWebhook(t.DB, t.DB, *logrus.New())
+
+		webhooks, err := storeTest.ListWebhooksByEvent(ctx, "", model.EventTransactionCreated)
+
+		assert.ErrorIs(t, err, tenant.ErrMissingAccount)
+		assert.Nil(t, webhooks)
+	})
+}
+
+func TestDeleteWebhook(t *testing.T) {
+	testObj := prepareTest(t)
+	ctx := context.Background()
+
+	testObj.mt.Run("This test simulates deleting a webhook", func(t *mtest.T) {
+		t.AddMockResponses(bson.D{{Key: "ok", Value: 1}, {Key: "n", Value: 1}})
+		storeTest := NewStoreWebhook(t.DB, t.DB, *logrus.New())
+
+		err := storeTest.DeleteWebhook(ctx, testAccountId, primitive.NewObjectID().Hex())
+
+		assert.NoError(t, err)
+	})
+
+	testObj.mt.Run("This test simulates deleting a webhook that does not exist", func(t *mtest.T) {
+		t.AddMockResponses(bson.D{{Key: "ok", Value: 1}, {Key: "n", Value: 0}})
+		storeTest := NewStoreWebhook(t.DB, t.DB, *logrus.New())
+
+		err := storeTest.DeleteWebhook(ctx, testAccountId, primitive.NewObjectID().Hex())
+
+		assert.ErrorIs(t, err, ErrWebhookNotFound)
+	})
+
+	testObj.mt.Run("This test simulates an invalid webhook id", func(t *mtest.T) {
+		storeTest := NewStoreWebhook(t.DB, t.DB, *logrus.New())
+
+		err := storeTest.DeleteWebhook(ctx, testAccountId, "test")
+
+		assert.ErrorIs(t, err, ErrWebhookNotFound)
+	})
+}
+
+func TestInsertDeliveries(t *testing.T) {
+	testObj := prepareTest(t)
+	ctx := context.Background()
+	deliveries := []*model.WebhookDelivery{
+		{AccountId: testAccountId, WebhookId: "652d34910a8fc425116b84d9", EventId: "652d34910a8fc425116b84e1"},
+		{AccountId: testAccountId, WebhookId: "652d34910a8fc425116b84da", EventId: "652d34910a8fc425116b84e1"},
+	}
+
+	testObj.mt.Run("This test simulates inserting the deliveries of an event", func(t *mtest.T) {
+		t.AddMockResponses(mtest.CreateSuccessResponse())
+		storeTest := NewStoreWebhook(t.DB, t.DB, *logrus.New())
+
+		assert.NoError(t, storeTest.InsertDeliveries(ctx, deliveries))
+	})
+
+	testObj.mt.Run("This test simulates inserting the deliveries of an event published again", func(t *mtest.T) {
+		t.AddMockResponses(mtest.CreateWriteErrorsResponse(
+			mtest.WriteError{Index: 0, Code: duplicateKeyCode, Message: "duplicate key error"},
+			mtest.WriteError{Index: 1, Code: duplicateKeyCode, Message: "duplicate key error"},
+		))
+		storeTest := NewStoreWebhook(t.DB, t.DB, *logrus.New())
+
+		assert.NoError(t, storeTest.InsertDeliveries(ctx, deliveries))
+	})
+
+	testObj.mt.Run("This test simulates a failed delivery insert", func(t *mtest.T) {
+		t.AddMockResponses(mtest.CreateWriteErrorsResponse(
+			mtest.WriteError{Index: 0, Code: duplicateKeyCode, Message: "duplicate key error"},
+			mtest.WriteError{Index: 1, Code: 2, Message: "bad value"},
+		))
+		storeTest := NewStoreWebhook(t.DB, t.DB, *logrus.New())
+
+		assert.Error(t, storeTest.InsertDeliveries(ctx, deliveries))
+	})
+}
+
+func TestClaimDelivery(t *testing.T) {
+	testObj := prepareTest(t)
+	ctx := context.Background()
+
+	testObj.mt.Run("This test simulates claiming a due delivery", func(t *mtest.T) {
+		id := primitive.NewObjectID()
+		t.AddMockResponses(bson.D{
+			{Key: "ok", Value: 1},
+			{Key: "value", Value: bson.D{
+				{Key: "_id", Value: id},
+				{Key: "event", Value: model.EventTransactionCreated},
+				{Key: "payload", Value: primitive.Binary{Data: []byte(`{"type":"transaction.created"}`)}},
+				{Key: "status", Value: model.DeliveryStatusRunning},
+				{Key: "attempts", Value: 1},
+				{Key: "owner", Value: "worker"},
+			}},
+		})
+		storeTest := NewStoreWebhook(t.DB, t.DB, *logrus.New())
+
+		delivery, err := storeTest.ClaimDelivery(ctx, "worker", 1697150153, 1697150183)
+
+		assert.NoError(t, err)
+		assert.Equal(t, delivery.Id, id.Hex())
+		assert.Equal(t, delivery.Attempts, 1)
+		assert.Equal(t, string(delivery.Payload), `{"type":"transaction.created"}`)
+	})
+
+	testObj.mt.Run("This test simulates claiming without due deliveries", func(t *mtest.T) {
+		t.AddMockResponses(bson.D{
+			{Key: "ok", Value: 1},
+			{Key: "value", Value: nil},
+		})
+		storeTest := NewStoreWebhook(t.DB, t.DB, *logrus.New())
+
+		delivery, err := storeTest.ClaimDelivery(ctx, "worker", 1697150153, 1697150183)
+
+		assert.NoError(t, err)
+		assert.Nil(t, delivery)
+	})
+}
+
+func TestFinishDelivery(t *testing.T) {
+	testObj := prepareTest(t)
+	ctx := context.Background()
+
+	testObj.mt.Run("This test simulates scheduling the next attempt of a delivery", func(t *mtest.T) {
+		t.AddMockResponses(bson.D{{Key: "ok", Value: 1}, {Key: "n", Value: 1}, {Key: "nModified", Value: 1}})
+		storeTest := NewStoreWebhook(t.DB, t.DB, *logrus.New())
+
+		err := storeTest.FinishDelivery(ctx, primitive.NewObjectID().Hex(), "worker", &model.WebhookDelivery{
+			Status:        model.DeliveryStatusPending,
+			Attempts:      1,
+			NextAttemptAt: 1697150163,
+			StatusCode:    500,
+			Error:         "unexpected status 500",
+		}, 1697150153)
+
+		assert.NoError(t, err)
+
+		set := t.GetStartedEvent().Command.Lookup("updates").Array().Index(0).Value().Document().Lookup("u", "$set").Document()
+		assert.Equal(t, set.Lookup("next_attempt_at").Int64(), int64(1697150163))
+	})
+
+	testObj.mt.Run("This test simulates finishing a delivery owned by another worker", func(t *mtest.T) {
+		t.AddMockResponses(bson.D{{Key: "ok", Value: 1}, {Key: "n", Value: 0}, {Key: "nModified", Value: 0}})
+		storeTest := NewStoreWebhook(t.DB, t.DB, *logrus.New())
+
+		err := storeTest.FinishDelivery(ctx, primitive.NewObjectID().Hex(), "worker", &model.WebhookDelivery{
+			Status: model.DeliveryStatusSucceeded,
+		}, 1697150153)
+
+		assert.ErrorIs(t, err, ErrDeliveryNotFound)
+	})
+}
+
+func TestListDeliveries(t *testing.T) {
+	testObj := prepareTest(t)
+	ctx := context.Background()
+
+	testObj.mt.Run("This test simulates listing the deliveries of a webhook", func(t *mtest.T) {
+		webhookId := primitive.NewObjectID().Hex()
+		t.AddMockResponses(mtest.CreateCursorResponse(0, "foo.bar", mtest.FirstBatch, bson.D{
+			{Key: "_id", Value: primitive.NewObjectID()},
+			{Key: "webhook_id", Value: webhookId},
+			{Key: "status", Value: model.DeliveryStatusDead},
+			{Key: "attempts", Value: 8},
+		}))
+		storeTest := NewStoreWebhook(t.DB, t.DB, *logrus.New())
+
+		deliveries, err := storeTest.ListDeliveries(ctx, testAccountId, webhookId, 100)
+
+		assert.NoError(t, err)
+		assert.Len(t, deliveries, 1)
+		assert.Equal(t, deliveries[0].Status, model.DeliveryStatusDead)
+
+		command := t.GetStartedEvent().Command
+		assert.Equal(t, command.Lookup("filter", "account_id").StringValue(), testAccountId)
+		assert.Equal(t, command.Lookup("limit").Int64(), int64(100))
+	})
+}